@@ -0,0 +1,192 @@
+package client
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/truora/minidyn/core"
+)
+
+// CreateBackup creates an on-demand backup with a copy of the items, indexes and settings of the table
+func (fd *Client) CreateBackup(input *dynamodb.CreateBackupInput) (*dynamodb.CreateBackupOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	tableName := aws.StringValue(input.TableName)
+
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	table, ok := fd.tables[tableName]
+	if !ok {
+		return nil, awserr.New(dynamodb.ErrCodeTableNotFoundException, "Table not found: "+tableName, nil)
+	}
+
+	table.RLock()
+	defer table.RUnlock()
+
+	if err := table.ValidateAvailable(); err != nil {
+		return nil, awserr.New(dynamodb.ErrCodeTableNotFoundException, "Table not found: "+tableName, nil)
+	}
+
+	backup, err := core.NewBackup(table, aws.StringValue(input.BackupName))
+	if err != nil {
+		return nil, err
+	}
+
+	fd.backups[backup.Arn] = backup
+
+	return &dynamodb.CreateBackupOutput{
+		BackupDetails: mapBackupDetailsToDynamodb(backup),
+	}, nil
+}
+
+// CreateBackupWithContext creates an on-demand backup of the table
+func (fd *Client) CreateBackupWithContext(ctx aws.Context, input *dynamodb.CreateBackupInput, opts ...request.Option) (*dynamodb.CreateBackupOutput, error) {
+	return fd.CreateBackup(input)
+}
+
+// DescribeBackup returns the details of the backup and of the table when the backup was created
+func (fd *Client) DescribeBackup(input *dynamodb.DescribeBackupInput) (*dynamodb.DescribeBackupOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	backup, err := fd.getBackupLocked(aws.StringValue(input.BackupArn))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.DescribeBackupOutput{
+		BackupDescription: mapBackupDescriptionToDynamodb(backup),
+	}, nil
+}
+
+// DescribeBackupWithContext returns the details of the backup
+func (fd *Client) DescribeBackupWithContext(ctx aws.Context, input *dynamodb.DescribeBackupInput, opts ...request.Option) (*dynamodb.DescribeBackupOutput, error) {
+	return fd.DescribeBackup(input)
+}
+
+// DeleteBackup deletes the backup, the returned description reports the DELETED status
+func (fd *Client) DeleteBackup(input *dynamodb.DeleteBackupInput) (*dynamodb.DeleteBackupOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	backup, err := fd.getBackupLocked(aws.StringValue(input.BackupArn))
+	if err != nil {
+		return nil, err
+	}
+
+	delete(fd.backups, backup.Arn)
+
+	backup.Status = core.BackupStatusDeleted
+
+	return &dynamodb.DeleteBackupOutput{
+		BackupDescription: mapBackupDescriptionToDynamodb(backup),
+	}, nil
+}
+
+// DeleteBackupWithContext deletes the backup
+func (fd *Client) DeleteBackupWithContext(ctx aws.Context, input *dynamodb.DeleteBackupInput, opts ...request.Option) (*dynamodb.DeleteBackupOutput, error) {
+	return fd.DeleteBackup(input)
+}
+
+// ListBackups returns the backups sorted by creation time, the backups are filtered by table name,
+// creation time and type and the pages are limited by the Limit and start after the ExclusiveStartBackupArn
+func (fd *Client) ListBackups(input *dynamodb.ListBackupsInput) (*dynamodb.ListBackupsOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	filter := core.BackupFilter{
+		TableName: aws.StringValue(input.TableName),
+		From:      aws.TimeValue(input.TimeRangeLowerBound),
+		To:        aws.TimeValue(input.TimeRangeUpperBound),
+		Type:      aws.StringValue(input.BackupType),
+	}
+
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	backups, last, err := core.ListBackups(fd.backups, filter, aws.StringValue(input.ExclusiveStartBackupArn), aws.Int64Value(input.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	output := &dynamodb.ListBackupsOutput{
+		BackupSummaries: make([]*dynamodb.BackupSummary, 0, len(backups)),
+	}
+
+	for _, backup := range backups {
+		output.BackupSummaries = append(output.BackupSummaries, mapBackupSummaryToDynamodb(backup))
+	}
+
+	if last != "" {
+		output.LastEvaluatedBackupArn = aws.String(last)
+	}
+
+	return output, nil
+}
+
+// ListBackupsWithContext returns the backups sorted by creation time
+func (fd *Client) ListBackupsWithContext(ctx aws.Context, input *dynamodb.ListBackupsInput, opts ...request.Option) (*dynamodb.ListBackupsOutput, error) {
+	return fd.ListBackups(input)
+}
+
+// RestoreTableFromBackup creates a new table with the items, indexes and settings of the backup,
+// the overrides of the input replace the billing mode, throughput, encryption and indexes of the backup
+func (fd *Client) RestoreTableFromBackup(input *dynamodb.RestoreTableFromBackupInput) (*dynamodb.RestoreTableFromBackupOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	tableName := aws.StringValue(input.TargetTableName)
+
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	backup, err := fd.getBackupLocked(aws.StringValue(input.BackupArn))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := fd.tables[tableName]; ok {
+		return nil, awserr.New(dynamodb.ErrCodeTableAlreadyExistsException, "Table already exists: "+tableName, nil)
+	}
+
+	table, err := backup.Restore(mapRestoreTableFromBackupInputToTypes(input))
+	if err != nil {
+		return nil, err
+	}
+
+	fd.setupTable(table)
+	fd.tables[tableName] = table
+
+	return &dynamodb.RestoreTableFromBackupOutput{
+		TableDescription: mapTableDescriptionToDynamodb(table.Description(tableName)),
+	}, nil
+}
+
+// RestoreTableFromBackupWithContext creates a new table from the backup
+func (fd *Client) RestoreTableFromBackupWithContext(ctx aws.Context, input *dynamodb.RestoreTableFromBackupInput, opts ...request.Option) (*dynamodb.RestoreTableFromBackupOutput, error) {
+	return fd.RestoreTableFromBackup(input)
+}
+
+// getBackupLocked looks up the backup, the caller must hold the client lock
+func (fd *Client) getBackupLocked(arn string) (*core.Backup, error) {
+	backup, ok := fd.backups[arn]
+	if !ok {
+		return nil, awserr.New(dynamodb.ErrCodeBackupNotFoundException, "Backup not found: "+arn, nil)
+	}
+
+	return backup, nil
+}
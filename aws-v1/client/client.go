@@ -3,9 +3,13 @@ package client
 import (
 	"errors"
 	"fmt"
+	"log"
+	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -22,6 +26,10 @@ const (
 	unusedExpressionAttributeValuesMsg = "Value provided in ExpressionAttributeValues unused in expressions"
 	invalidExpressionAttributeName     = "ExpressionAttributeNames contains invalid key"
 	invalidExpressionAttributeValue    = "ExpressionAttributeValues contains invalid key"
+	// revive:disable-next-line
+	undefinedExpressionAttributeNameMsg = "An expression attribute name used in the document path is not defined; attribute name"
+	// revive:disable-next-line
+	undefinedExpressionAttributeValueMsg = "An expression attribute value used in expression is not defined; attribute value"
 )
 
 var (
@@ -37,32 +45,88 @@ var (
 type Client struct {
 	dynamodbiface.DynamoDBAPI
 	tables                map[string]*core.Table
-	mu                    sync.Mutex
+	backups               map[string]*core.Backup
+	exports               map[string]*core.Export
+	mu                    sync.RWMutex
 	itemCollectionMetrics map[string][]*dynamodb.ItemCollectionMetrics
 	langInterpreter       *interpreter.Language
 	nativeInterpreter     *interpreter.Native
 	useNativeInterpreter  bool
+	mismatchReporter      interpreter.MismatchReporter
+	explainConditions     bool
 	forceFailureErr       error
+	limits                core.Limits
+	mutationDetection     bool
+	tableLifecycle        bool
+	transitionDescribes   int
+	clock                 func() time.Time
+	exportDirectory       string
 }
 
 // NewClient initializes dynamodb client with a mock
 func NewClient() *Client {
 	fake := Client{
 		tables:            map[string]*core.Table{},
-		mu:                sync.Mutex{},
+		backups:           map[string]*core.Backup{},
+		exports:           map[string]*core.Export{},
 		nativeInterpreter: interpreter.NewNativeInterpreter(),
 		langInterpreter:   &interpreter.Language{},
+		limits:            core.DefaultLimits,
+		clock:             time.Now,
 	}
 
 	return &fake
 }
 
-// ActivateDebug it activates the debug mode
+// ActivateDebug it activates the debug mode, the evaluated expressions are printed in the standard output
 func (fd *Client) ActivateDebug() {
+	fd.SetTracer(interpreter.NewLogTracer(log.New(os.Stdout, "", 0)))
+}
+
+// ActivateMutationDetection it makes the table operations fail with core.ErrItemMutated when a stored item is mutated outside of them
+func (fd *Client) ActivateMutationDetection() {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.mutationDetection = true
+
+	for _, table := range fd.tables {
+		table.Lock()
+		table.ActivateMutationDetection()
+		table.Unlock()
+	}
+}
+
+// SetTracer sets the tracer that receives the expressions evaluated by the interpreters,
+// a nil tracer disables the tracing
+func (fd *Client) SetTracer(tracer interpreter.Tracer) {
 	fd.mu.Lock()
 	defer fd.mu.Unlock()
 
-	fd.langInterpreter.Debug = true
+	fd.langInterpreter.Tracer = tracer
+	fd.nativeInterpreter.Tracer = tracer
+
+	for _, table := range fd.tables {
+		table.Lock()
+		table.LangInterpreter.Tracer = tracer
+		table.NativeInterpreter.Tracer = tracer
+		table.Unlock()
+	}
+}
+
+// ActivateConditionExplanations it adds the evaluation of the failed condition expressions
+// to the message of the ConditionalCheckFailedException errors
+func (fd *Client) ActivateConditionExplanations() {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.explainConditions = true
+
+	for _, table := range fd.tables {
+		table.Lock()
+		table.ExplainConditions = true
+		table.Unlock()
+	}
 }
 
 // ActivateNativeInterpreter it activates the debug mode
@@ -73,8 +137,67 @@ func (fd *Client) ActivateNativeInterpreter() {
 	fd.useNativeInterpreter = true
 
 	for _, table := range fd.tables {
+		table.Lock()
+		table.UseNativeInterpreter = true
+		table.Unlock()
+	}
+}
+
+// ActivateDifferentialInterpreter it activates the native interpreter and evaluates the expressions
+// with a native override with the language interpreter too, the differences are sent to the reporter
+func (fd *Client) ActivateDifferentialInterpreter(reporter interpreter.MismatchReporter) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.useNativeInterpreter = true
+	fd.mismatchReporter = reporter
+
+	for _, table := range fd.tables {
+		table.Lock()
 		table.UseNativeInterpreter = true
+		table.MismatchReporter = reporter
+		table.Unlock()
+	}
+}
+
+// ActivateTableLifecycle it makes the tables go through the CREATING, UPDATING and DELETING statuses,
+// each status is reported by the given number of DescribeTable calls before the table becomes ACTIVE or is removed
+func (fd *Client) ActivateTableLifecycle(describes int) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.tableLifecycle = true
+	fd.transitionDescribes = describes
+}
+
+// SetDeletionProtection enables or disables the deletion protection of the table, DeleteTable fails
+// while it is enabled. This version of the SDK has no DeletionProtectionEnabled parameter
+func (fd *Client) SetDeletionProtection(tableName string, enabled bool) error {
+	table, err := fd.getTable(tableName)
+	if err != nil {
+		return err
 	}
+
+	table.Lock()
+	defer table.Unlock()
+
+	table.DeletionProtectionEnabled = enabled
+
+	return nil
+}
+
+func (fd *Client) forcedFailure() error {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	return fd.forceFailureErr
+}
+
+func (fd *Client) getItemCollectionMetrics() map[string][]*dynamodb.ItemCollectionMetrics {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	return fd.itemCollectionMetrics
 }
 
 func (fd *Client) setFailureCondition(condition FailureCondition) {
@@ -91,15 +214,60 @@ func (fd *Client) SetInterpreter(i interpreter.Interpreter) {
 		panic("invalid interpreter type")
 	}
 
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
 	fd.nativeInterpreter = native
 
 	for _, table := range fd.tables {
+		table.Lock()
 		table.NativeInterpreter = *native
+		table.Unlock()
+	}
+}
+
+// SetClock sets the clock of the client and its tables, it stamps the creation of the tables and backups
+// and the history of writes used by the point in time recovery
+func (fd *Client) SetClock(clock func() time.Time) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.clock = clock
+
+	for _, table := range fd.tables {
+		table.Lock()
+		table.Clock = clock
+		table.Unlock()
+	}
+}
+
+// SetExportDirectory sets the local directory that replaces S3 in the exports, the buckets are its subdirectories
+func (fd *Client) SetExportDirectory(dir string) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.exportDirectory = dir
+}
+
+// SetLimits overrides the service limits enforced by the client and its tables
+func (fd *Client) SetLimits(limits core.Limits) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.limits = limits
+
+	for _, table := range fd.tables {
+		table.Lock()
+		table.Limits = limits
+		table.Unlock()
 	}
 }
 
 // GetNativeInterpreter returns native interpreter
 func (fd *Client) GetNativeInterpreter() *interpreter.Native {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
 	return fd.nativeInterpreter
 }
 
@@ -109,6 +277,9 @@ func (fd *Client) CreateTable(input *dynamodb.CreateTableInput) (*dynamodb.Creat
 		return nil, err
 	}
 
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
 	tableName := aws.StringValue(input.TableName)
 	if _, ok := fd.tables[tableName]; ok {
 		return nil, awserr.New(dynamodb.ErrCodeResourceInUseException, "Cannot create preexisting table", nil)
@@ -117,9 +288,7 @@ func (fd *Client) CreateTable(input *dynamodb.CreateTableInput) (*dynamodb.Creat
 	newTable := core.NewTable(tableName)
 	newTable.SetAttributeDefinition(mapAttributeDefinitionToTypes(input.AttributeDefinitions))
 	newTable.BillingMode = input.BillingMode
-	newTable.NativeInterpreter = *fd.nativeInterpreter
-	newTable.UseNativeInterpreter = fd.useNativeInterpreter
-	newTable.LangInterpreter = *fd.langInterpreter
+	fd.setupTable(newTable)
 
 	if err := newTable.CreatePrimaryIndex(mapCreateTableInputToTypes(input)); err != nil {
 		return nil, err
@@ -153,14 +322,32 @@ func (fd *Client) DeleteTable(input *dynamodb.DeleteTableInput) (*dynamodb.Delet
 
 	tableName := aws.StringValue(input.TableName)
 
-	table, err := fd.getTable(tableName)
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	table, err := fd.getTableLocked(tableName)
 	if err != nil {
 		return nil, err
 	}
 
-	desc := table.Description(tableName)
+	table.Lock()
+	defer table.Unlock()
+
+	if err := table.ValidateNotInUse(); err != nil {
+		return nil, err
+	}
+
+	if err := table.ValidateDeletable(); err != nil {
+		return nil, err
+	}
 
-	delete(fd.tables, tableName)
+	if fd.tableLifecycle {
+		table.StartTransition(core.TableStatusDeleting, fd.transitionDescribes)
+	} else {
+		delete(fd.tables, tableName)
+	}
+
+	desc := table.Description(tableName)
 
 	return &dynamodb.DeleteTableOutput{
 		TableDescription: mapTableDescriptionToDynamodb(desc),
@@ -180,21 +367,27 @@ func (fd *Client) UpdateTable(input *dynamodb.UpdateTableInput) (*dynamodb.Updat
 
 	tableName := aws.StringValue(input.TableName)
 
-	table, ok := fd.tables[tableName]
-	if !ok {
-		return nil, awserr.New(dynamodb.ErrCodeResourceNotFoundException, "Cannot do operations on a non-existent table", nil)
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	table, err := fd.getTableLocked(tableName)
+	if err != nil {
+		return nil, err
 	}
 
-	if input.AttributeDefinitions != nil {
-		table.SetAttributeDefinition(mapAttributeValueDefinitionToDynamodb(input.AttributeDefinitions))
+	table.Lock()
+	defer table.Unlock()
+
+	if err := table.ValidateNotInUse(); err != nil {
+		return nil, err
 	}
 
-	for _, change := range input.GlobalSecondaryIndexUpdates {
-		if err := table.ApplyIndexChange(mapGlobalSecondaryIndexUpdateToTypes(change)); err != nil {
-			return &dynamodb.UpdateTableOutput{
-				TableDescription: mapTableDescriptionToDynamodb(table.Description(tableName)),
-			}, err
-		}
+	if err := table.ApplyTableUpdate(mapUpdateTableInputToTypes(input)); err != nil {
+		return nil, err
+	}
+
+	if fd.tableLifecycle {
+		table.StartTransition(core.TableStatusUpdating, fd.transitionDescribes)
 	}
 
 	return &dynamodb.UpdateTableOutput{
@@ -207,15 +400,28 @@ func (fd *Client) UpdateTableWithContext(ctx aws.Context, input *dynamodb.Update
 	return fd.UpdateTable(input)
 }
 
-// DescribeTable returns information about the table
+// DescribeTable returns information about the table,
+// it completes the table transitions when the table lifecycle is active
 func (fd *Client) DescribeTable(input *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
 	tableName := aws.StringValue(input.TableName)
 
-	table, err := fd.getTable(tableName)
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	table, err := fd.getTableLocked(tableName)
 	if err != nil {
 		return nil, err
 	}
 
+	table.Lock()
+	defer table.Unlock()
+
+	if table.DescribeStatus() {
+		delete(fd.tables, tableName)
+
+		return nil, awserr.New(dynamodb.ErrCodeResourceNotFoundException, "Requested resource not found: Table: "+tableName+" not found", nil)
+	}
+
 	output := &dynamodb.DescribeTableOutput{
 		Table: mapTableDescriptionToDynamodb(table.Description(tableName)),
 	}
@@ -228,6 +434,61 @@ func (fd *Client) DescribeTableWithContext(ctx aws.Context, input *dynamodb.Desc
 	return fd.DescribeTable(input)
 }
 
+// ListTables returns the names of the tables sorted alphabetically,
+// the pages are limited by the Limit and start after the ExclusiveStartTableName
+func (fd *Client) ListTables(input *dynamodb.ListTablesInput) (*dynamodb.ListTablesOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	names, last, err := core.ListTableNames(fd.tables, aws.StringValue(input.ExclusiveStartTableName), input.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &dynamodb.ListTablesOutput{
+		TableNames: aws.StringSlice(names),
+	}
+
+	if last != "" {
+		output.LastEvaluatedTableName = aws.String(last)
+	}
+
+	return output, nil
+}
+
+// ListTablesWithContext returns the names of the tables sorted alphabetically
+func (fd *Client) ListTablesWithContext(ctx aws.Context, input *dynamodb.ListTablesInput, opts ...request.Option) (*dynamodb.ListTablesOutput, error) {
+	return fd.ListTables(input)
+}
+
+// ListTablesPages iterates over the pages of the table names
+func (fd *Client) ListTablesPages(input *dynamodb.ListTablesInput, fn func(*dynamodb.ListTablesOutput, bool) bool) error {
+	return fd.ListTablesPagesWithContext(aws.BackgroundContext(), input, fn)
+}
+
+// ListTablesPagesWithContext iterates over the pages of the table names
+func (fd *Client) ListTablesPagesWithContext(ctx aws.Context, input *dynamodb.ListTablesInput, fn func(*dynamodb.ListTablesOutput, bool) bool, opts ...request.Option) error {
+	page := *input
+
+	for {
+		output, err := fd.ListTables(&page)
+		if err != nil {
+			return err
+		}
+
+		lastPage := output.LastEvaluatedTableName == nil
+		if !fn(output, lastPage) || lastPage {
+			return nil
+		}
+
+		page.ExclusiveStartTableName = output.LastEvaluatedTableName
+	}
+}
+
 // PutItem mock response for dynamodb
 func (fd *Client) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
 	err := input.Validate()
@@ -235,23 +496,33 @@ func (fd *Client) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput
 		return nil, err
 	}
 
-	fd.mu.Lock()
-	defer fd.mu.Unlock()
+	if err := fd.forcedFailure(); err != nil {
+		return nil, err
+	}
 
-	if fd.forceFailureErr != nil {
-		return nil, fd.forceFailureErr
+	err = core.ValidateLegacyParameters(map[string]bool{
+		"Expected": len(input.Expected) > 0,
+	}, map[string]bool{
+		"ConditionExpression":       input.ConditionExpression != nil,
+		"ExpressionAttributeNames":  len(input.ExpressionAttributeNames) > 0,
+		"ExpressionAttributeValues": len(input.ExpressionAttributeValues) > 0,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	err = validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues, aws.StringValue(input.ConditionExpression))
+	err = validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues, expression{interpreter.ExpressionTypeConditional, aws.StringValue(input.ConditionExpression)})
 	if err != nil {
 		return nil, err
 	}
 
-	table, err := fd.getTable(aws.StringValue(input.TableName))
+	table, unlock, err := fd.lockAvailableTable(aws.StringValue(input.TableName), true)
 	if err != nil {
 		return nil, err
 	}
 
+	defer unlock()
+
 	item, err := table.Put(mapPutItemInputToTypes(input))
 
 	return &dynamodb.PutItemOutput{
@@ -271,35 +542,37 @@ func (fd *Client) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteI
 		return nil, err
 	}
 
-	fd.mu.Lock()
-	defer fd.mu.Unlock()
-
-	if fd.forceFailureErr != nil {
-		return nil, fd.forceFailureErr
+	if err := fd.forcedFailure(); err != nil {
+		return nil, err
 	}
 
-	err = validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues, aws.StringValue(input.ConditionExpression))
+	err = validateDeleteItemExpressions(input)
 	if err != nil {
 		return nil, err
 	}
 
-	table, err := fd.getTable(aws.StringValue(input.TableName))
+	table, unlock, err := fd.lockAvailableTable(aws.StringValue(input.TableName), true)
 	if err != nil {
 		return nil, err
 	}
 
+	defer unlock()
+
 	// support conditional writes
-	if input.ConditionExpression != nil {
-		items, _ := table.SearchData(core.QueryInput{
-			Index:                     core.PrimaryIndexName,
-			ExpressionAttributeValues: mapAttributeValueToTypes(input.ExpressionAttributeValues),
-			Aliases:                   aws.StringValueMap(input.ExpressionAttributeNames),
-			Limit:                     1,
-			ConditionExpression:       input.ConditionExpression,
-		})
-		if len(items) == 0 {
-			return &dynamodb.DeleteItemOutput{}, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, core.ErrConditionalRequestFailed.Error(), nil)
-		}
+	matched, err := matchDeleteCondition(table, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if !matched {
+		message := table.ConditionFailedMessage(
+			aws.StringValue(input.ConditionExpression),
+			aws.StringValueMap(input.ExpressionAttributeNames),
+			mapAttributeValueToTypes(input.ExpressionAttributeValues),
+			mapAttributeValueToTypes(input.Key),
+		)
+
+		return &dynamodb.DeleteItemOutput{}, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, message, nil)
 	}
 
 	item, err := table.Delete(mapDeleteItemInputToTypes(input))
@@ -321,6 +594,42 @@ func (fd *Client) DeleteItemWithContext(ctx aws.Context, input *dynamodb.DeleteI
 	return fd.DeleteItem(input)
 }
 
+// validateDeleteItemExpressions rejects the legacy parameters mixed with expressions and the unused or undefined placeholders
+func validateDeleteItemExpressions(input *dynamodb.DeleteItemInput) error {
+	err := core.ValidateLegacyParameters(map[string]bool{
+		"Expected": len(input.Expected) > 0,
+	}, map[string]bool{
+		"ConditionExpression":       input.ConditionExpression != nil,
+		"ExpressionAttributeNames":  len(input.ExpressionAttributeNames) > 0,
+		"ExpressionAttributeValues": len(input.ExpressionAttributeValues) > 0,
+	})
+	if err != nil {
+		return err
+	}
+
+	return validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues, expression{interpreter.ExpressionTypeConditional, aws.StringValue(input.ConditionExpression)})
+}
+
+// matchDeleteCondition reports whether the ConditionExpression of the delete matches, it is true without a condition
+func matchDeleteCondition(table *core.Table, input *dynamodb.DeleteItemInput) (bool, error) {
+	if input.ConditionExpression == nil {
+		return true, nil
+	}
+
+	items, _, err := table.SearchData(core.QueryInput{
+		Index:                     core.PrimaryIndexName,
+		ExpressionAttributeValues: mapAttributeValueToTypes(input.ExpressionAttributeValues),
+		Aliases:                   aws.StringValueMap(input.ExpressionAttributeNames),
+		Limit:                     1,
+		ConditionExpression:       input.ConditionExpression,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(items) > 0, nil
+}
+
 // UpdateItem mock response for dynamodb
 func (fd *Client) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
 	err := input.Validate()
@@ -328,29 +637,40 @@ func (fd *Client) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateI
 		return nil, err
 	}
 
-	fd.mu.Lock()
-	defer fd.mu.Unlock()
+	if err := fd.forcedFailure(); err != nil {
+		return nil, err
+	}
 
-	if fd.forceFailureErr != nil {
-		return nil, fd.forceFailureErr
+	err = core.ValidateLegacyParameters(map[string]bool{
+		"AttributeUpdates": len(input.AttributeUpdates) > 0,
+		"Expected":         len(input.Expected) > 0,
+	}, map[string]bool{
+		"UpdateExpression":          input.UpdateExpression != nil,
+		"ConditionExpression":       input.ConditionExpression != nil,
+		"ExpressionAttributeNames":  len(input.ExpressionAttributeNames) > 0,
+		"ExpressionAttributeValues": len(input.ExpressionAttributeValues) > 0,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	err = validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues, aws.StringValue(input.UpdateExpression), aws.StringValue(input.ConditionExpression))
+	err = validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues,
+		expression{interpreter.ExpressionTypeUpdate, aws.StringValue(input.UpdateExpression)},
+		expression{interpreter.ExpressionTypeConditional, aws.StringValue(input.ConditionExpression)},
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	table, err := fd.getTable(aws.StringValue(input.TableName))
+	table, unlock, err := fd.lockAvailableTable(aws.StringValue(input.TableName), true)
 	if err != nil {
 		return nil, err
 	}
 
+	defer unlock()
+
 	item, err := table.Update(mapUpdateItemInputToTypes(input))
 	if err != nil {
-		if errors.Is(err, interpreter.ErrSyntaxError) {
-			return nil, awserr.New("ValidationException", err.Error(), nil)
-		}
-
 		return nil, err
 	}
 
@@ -373,32 +693,39 @@ func (fd *Client) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput
 		return nil, err
 	}
 
-	fd.mu.Lock()
-	defer fd.mu.Unlock()
-
-	if fd.forceFailureErr != nil {
-		return nil, fd.forceFailureErr
+	if err := fd.forcedFailure(); err != nil {
+		return nil, err
 	}
 
-	err = validateExpressionAttributes(input.ExpressionAttributeNames, nil, aws.StringValue(input.ProjectionExpression))
+	err = core.ValidateLegacyParameters(map[string]bool{
+		"AttributesToGet": len(input.AttributesToGet) > 0,
+	}, map[string]bool{
+		"ProjectionExpression":     input.ProjectionExpression != nil,
+		"ExpressionAttributeNames": len(input.ExpressionAttributeNames) > 0,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	table, err := fd.getTable(aws.StringValue(input.TableName))
+	err = validateExpressionAttributes(input.ExpressionAttributeNames, nil, expression{interpreter.ExpressionTypeProjection, aws.StringValue(input.ProjectionExpression)})
 	if err != nil {
 		return nil, err
 	}
 
-	key, err := table.KeySchema.GetKey(table.AttributesDef, mapAttributeValueToTypes(input.Key))
+	table, unlock, err := fd.lockAvailableTable(aws.StringValue(input.TableName), false)
 	if err != nil {
-		return nil, awserr.New("ValidationException", err.Error(), nil)
+		return nil, err
 	}
 
-	item := copyItem(mapAttributeValueToDynamodb(table.Data[key]))
+	defer unlock()
+
+	item, err := table.GetItem(mapAttributeValueToTypes(input.Key))
+	if err != nil {
+		return nil, err
+	}
 
 	output := &dynamodb.GetItemOutput{
-		Item: item,
+		Item: mapAttributeValueToDynamodb(core.ProjectAttributes(item, aws.StringValueSlice(input.AttributesToGet))),
 	}
 
 	return output, nil
@@ -411,39 +738,71 @@ func (fd *Client) GetItemWithContext(ctx aws.Context, input *dynamodb.GetItemInp
 
 // Query mock response for dynamodb
 func (fd *Client) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
-	fd.mu.Lock()
-	defer fd.mu.Unlock()
+	if err := fd.forcedFailure(); err != nil {
+		return nil, err
+	}
 
-	if fd.forceFailureErr != nil {
-		return nil, fd.forceFailureErr
+	err := core.ValidateLegacyParameters(map[string]bool{
+		"AttributesToGet": len(input.AttributesToGet) > 0,
+		"KeyConditions":   len(input.KeyConditions) > 0,
+		"QueryFilter":     len(input.QueryFilter) > 0,
+	}, map[string]bool{
+		"KeyConditionExpression":    input.KeyConditionExpression != nil,
+		"FilterExpression":          input.FilterExpression != nil,
+		"ProjectionExpression":      input.ProjectionExpression != nil,
+		"ExpressionAttributeNames":  len(input.ExpressionAttributeNames) > 0,
+		"ExpressionAttributeValues": len(input.ExpressionAttributeValues) > 0,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	err := validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues, aws.StringValue(input.KeyConditionExpression), aws.StringValue(input.FilterExpression), aws.StringValue(input.ProjectionExpression))
+	err = validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues,
+		expression{interpreter.ExpressionTypeKey, aws.StringValue(input.KeyConditionExpression)},
+		expression{interpreter.ExpressionTypeFilter, aws.StringValue(input.FilterExpression)},
+		expression{interpreter.ExpressionTypeProjection, aws.StringValue(input.ProjectionExpression)},
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	table, err := fd.getTable(aws.StringValue(input.TableName))
+	table, unlock, err := fd.lockAvailableTable(aws.StringValue(input.TableName), false)
 	if err != nil {
 		return nil, err
 	}
 
+	defer unlock()
+
 	indexName := aws.StringValue(input.IndexName)
 
 	if input.ScanIndexForward == nil {
 		input.ScanIndexForward = aws.Bool(true)
 	}
 
-	items, lastKey := table.SearchData(core.QueryInput{
+	query := core.QueryInput{
 		Index:                     indexName,
 		ExpressionAttributeValues: mapAttributeValueToTypes(input.ExpressionAttributeValues),
 		Aliases:                   aws.StringValueMap(input.ExpressionAttributeNames),
 		Limit:                     aws.Int64Value(input.Limit),
 		ExclusiveStartKey:         mapAttributeValueToTypes(input.ExclusiveStartKey),
-		KeyConditionExpression:    *input.KeyConditionExpression,
+		KeyConditionExpression:    aws.StringValue(input.KeyConditionExpression),
 		FilterExpression:          aws.StringValue(input.FilterExpression),
 		ScanIndexForward:          aws.BoolValue(input.ScanIndexForward),
-	})
+		KeyConditions:             mapConditionToTypes(input.KeyConditions),
+		QueryFilter:               mapConditionToTypes(input.QueryFilter),
+		ConditionalOperator:       aws.StringValue(input.ConditionalOperator),
+		AttributesToGet:           aws.StringValueSlice(input.AttributesToGet),
+	}
+
+	err = table.ValidateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	items, lastKey, err := table.SearchData(query)
+	if err != nil {
+		return nil, err
+	}
 
 	count := int64(len(items))
 
@@ -463,26 +822,41 @@ func (fd *Client) QueryWithContext(ctx aws.Context, input *dynamodb.QueryInput,
 
 // Scan mock scan operation
 func (fd *Client) Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
-	fd.mu.Lock()
-	defer fd.mu.Unlock()
+	if err := fd.forcedFailure(); err != nil {
+		return nil, err
+	}
 
-	if fd.forceFailureErr != nil {
-		return nil, fd.forceFailureErr
+	err := core.ValidateLegacyParameters(map[string]bool{
+		"AttributesToGet": len(input.AttributesToGet) > 0,
+		"ScanFilter":      len(input.ScanFilter) > 0,
+	}, map[string]bool{
+		"FilterExpression":          input.FilterExpression != nil,
+		"ProjectionExpression":      input.ProjectionExpression != nil,
+		"ExpressionAttributeNames":  len(input.ExpressionAttributeNames) > 0,
+		"ExpressionAttributeValues": len(input.ExpressionAttributeValues) > 0,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	err := validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues, aws.StringValue(input.ProjectionExpression), aws.StringValue(input.FilterExpression))
+	err = validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues,
+		expression{interpreter.ExpressionTypeProjection, aws.StringValue(input.ProjectionExpression)},
+		expression{interpreter.ExpressionTypeFilter, aws.StringValue(input.FilterExpression)},
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	table, err := fd.getTable(aws.StringValue(input.TableName))
+	table, unlock, err := fd.lockAvailableTable(aws.StringValue(input.TableName), false)
 	if err != nil {
 		return nil, err
 	}
 
+	defer unlock()
+
 	indexName := aws.StringValue(input.IndexName)
 
-	items, lastKey := table.SearchData(core.QueryInput{
+	query := core.QueryInput{
 		Index:                     indexName,
 		ExpressionAttributeValues: mapAttributeValueToTypes(input.ExpressionAttributeValues),
 		Aliases:                   aws.StringValueMap(input.ExpressionAttributeNames),
@@ -491,7 +865,20 @@ func (fd *Client) Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error)
 		FilterExpression:          aws.StringValue(input.FilterExpression),
 		Scan:                      true,
 		ScanIndexForward:          true,
-	})
+		QueryFilter:               mapConditionToTypes(input.ScanFilter),
+		ConditionalOperator:       aws.StringValue(input.ConditionalOperator),
+		AttributesToGet:           aws.StringValueSlice(input.AttributesToGet),
+	}
+
+	err = table.ValidateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	items, lastKey, err := table.SearchData(query)
+	if err != nil {
+		return nil, err
+	}
 
 	count := int64(len(items))
 
@@ -511,6 +898,9 @@ func (fd *Client) ScanWithContext(ctx aws.Context, input *dynamodb.ScanInput, op
 
 // SetItemCollectionMetrics set the value of the property itemCollectionMetrics
 func (fd *Client) setItemCollectionMetrics(itemCollectionMetrics map[string][]*dynamodb.ItemCollectionMetrics) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
 	fd.itemCollectionMetrics = itemCollectionMetrics
 }
 
@@ -550,7 +940,7 @@ func (fd *Client) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb
 
 	return &dynamodb.BatchWriteItemOutput{
 		UnprocessedItems:      unprocessed,
-		ItemCollectionMetrics: fd.itemCollectionMetrics,
+		ItemCollectionMetrics: fd.getItemCollectionMetrics(),
 	}, nil
 }
 
@@ -639,10 +1029,18 @@ func handleBatchWriteRequestError(table string, req *dynamodb.WriteRequest, unpr
 
 // TransactWriteItems mock response for dynamodb
 func (fd *Client) TransactWriteItems(input *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
-	if fd.forceFailureErr != nil {
+	fd.mu.RLock()
+	forceFailureErr, limits := fd.forceFailureErr, fd.limits
+	fd.mu.RUnlock()
+
+	if forceFailureErr != nil {
 		return nil, ErrForcedFailure
 	}
 
+	if err := core.ValidateTransactWriteItems(limits, len(input.TransactItems)); err != nil {
+		return nil, err
+	}
+
 	//TODO: Implement transact write
 
 	return &dynamodb.TransactWriteItemsOutput{}, nil
@@ -653,7 +1051,66 @@ func (fd *Client) TransactWriteItemsWithContext(ctx aws.Context, input *dynamodb
 	return fd.TransactWriteItems(input)
 }
 
+// lockAvailableTable looks up a table that serves items and locks it, the tables being created or deleted are not found.
+// The client lock is held until the table is locked so a concurrent DeleteTable can not remove the table in between,
+// the returned function releases the table lock
+func (fd *Client) lockAvailableTable(tableName string, exclusive bool) (*core.Table, func(), error) {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	table, err := fd.getTableLocked(tableName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unlock := table.RUnlock
+
+	if exclusive {
+		table.Lock()
+
+		unlock = table.Unlock
+	} else {
+		table.RLock()
+	}
+
+	if err := table.ValidateAvailable(); err != nil {
+		unlock()
+
+		return nil, nil, err
+	}
+
+	return table, unlock, nil
+}
+
 func (fd *Client) getTable(tableName string) (*core.Table, error) {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	return fd.getTableLocked(tableName)
+}
+
+// setupTable applies the client settings to a new table, the caller must hold the client lock
+func (fd *Client) setupTable(table *core.Table) {
+	table.NativeInterpreter = *fd.nativeInterpreter
+	table.Limits = fd.limits
+	table.UseNativeInterpreter = fd.useNativeInterpreter
+	table.MismatchReporter = fd.mismatchReporter
+	table.ExplainConditions = fd.explainConditions
+	table.LangInterpreter = *fd.langInterpreter
+	table.Clock = fd.clock
+	table.CreationDateTime = fd.clock()
+
+	if fd.mutationDetection {
+		table.ActivateMutationDetection()
+	}
+
+	if fd.tableLifecycle {
+		table.StartTransition(core.TableStatusCreating, fd.transitionDescribes)
+	}
+}
+
+// getTableLocked looks up the table, the caller must hold the client lock
+func (fd *Client) getTableLocked(tableName string) (*core.Table, error) {
 	table, ok := fd.tables[tableName]
 	if !ok {
 		return nil, awserr.New(dynamodb.ErrCodeResourceNotFoundException, "Cannot do operations on a non-existent table", nil)
@@ -662,38 +1119,105 @@ func (fd *Client) getTable(tableName string) (*core.Table, error) {
 	return table, nil
 }
 
-func validateExpressionAttributes(exprNames map[string]*string, exprValues map[string]*dynamodb.AttributeValue, genericExpressions ...string) error {
-	genericExpression := strings.Join(genericExpressions, " ")
-	genericExpression = strings.TrimSpace(genericExpression)
+type expression struct {
+	typ   interpreter.ExpressionType
+	value string
+}
 
-	if genericExpression == "" && len(exprNames) == 0 && len(exprValues) == 0 {
+func validateExpressionAttributes(exprNames map[string]*string, exprValues map[string]*dynamodb.AttributeValue, expressions ...expression) error {
+	if isEmptyExpressions(expressions) && len(exprNames) == 0 && len(exprValues) == 0 {
 		return nil
 	}
 
 	flattenNames := getKeysFromExpressionNames(exprNames)
 	flattenValues := getKeysFromExpressionValues(exprValues)
 
-	missingNames := getMissingSubstrs(genericExpression, flattenNames)
-	missingValues := getMissingSubstrs(genericExpression, flattenValues)
+	err := validateSyntaxExpression(expressionAttributeNamesRegex, flattenNames, invalidExpressionAttributeName)
+	if err != nil {
+		return err
+	}
 
-	if len(missingNames) > 0 {
-		return awserr.New("ValidationException", fmt.Sprintf("%s: keys: {%s}", unusedExpressionAttributeNamesMsg, strings.Join(missingNames, ", ")), nil)
+	err = validateSyntaxExpression(expressionAttributeValuesRegex, flattenValues, invalidExpressionAttributeValue)
+	if err != nil {
+		return err
 	}
 
-	err := validateSyntaxExpression(expressionAttributeNamesRegex, flattenNames, invalidExpressionAttributeName)
+	usedNames, usedValues, err := collectUsedPlaceholders(exprNames, exprValues, expressions)
 	if err != nil {
 		return err
 	}
 
-	if len(missingValues) > 0 {
-		return awserr.New("ValidationException", fmt.Sprintf("%s: keys: {%s}", unusedExpressionAttributeValuesMsg, strings.Join(missingValues, ", ")), nil)
+	err = validateUnusedAttributes(flattenNames, usedNames, unusedExpressionAttributeNamesMsg)
+	if err != nil {
+		return err
 	}
 
-	err = validateSyntaxExpression(expressionAttributeValuesRegex, flattenValues, invalidExpressionAttributeValue)
+	return validateUnusedAttributes(flattenValues, usedValues, unusedExpressionAttributeValuesMsg)
+}
+
+// collectUsedPlaceholders returns the names and values used by the expressions,
+// it fails when a parsed expression uses a placeholder that is not defined
+func collectUsedPlaceholders(exprNames map[string]*string, exprValues map[string]*dynamodb.AttributeValue, expressions []expression) (map[string]bool, map[string]bool, error) {
+	usedNames := map[string]bool{}
+	usedValues := map[string]bool{}
+
+	for _, expr := range expressions {
+		if expr.value == "" {
+			continue
+		}
+
+		placeholders, parsed := interpreter.Placeholders(expr.value, expr.typ)
+		if parsed {
+			err := validateDefinedPlaceholders(expr.typ, placeholders.Names, placeholders.Values, exprNames, exprValues)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		mergeKeys(usedNames, placeholders.Names)
+		mergeKeys(usedValues, placeholders.Values)
+	}
+
+	return usedNames, usedValues, nil
+}
+
+func validateDefinedPlaceholders(typ interpreter.ExpressionType, names, values map[string]bool, exprNames map[string]*string, exprValues map[string]*dynamodb.AttributeValue) error {
+	err := validateUndefinedPlaceholders(typ, names, exprNames, undefinedExpressionAttributeNameMsg)
 	if err != nil {
 		return err
 	}
 
+	return validateUndefinedPlaceholders(typ, values, exprValues, undefinedExpressionAttributeValueMsg)
+}
+
+func validateUnusedAttributes(keys []string, used map[string]bool, msg string) error {
+	missing := getMissingKeys(keys, used)
+	if len(missing) > 0 {
+		return awserr.New("ValidationException", fmt.Sprintf("%s: keys: {%s}", msg, strings.Join(missing, ", ")), nil)
+	}
+
+	return nil
+}
+
+func isEmptyExpressions(expressions []expression) bool {
+	for _, expr := range expressions {
+		if strings.TrimSpace(expr.value) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func validateUndefinedPlaceholders[V any](typ interpreter.ExpressionType, used map[string]bool, defined map[string]V, errorMsg string) error {
+	for _, placeholder := range getSortedKeys(used) {
+		if _, ok := defined[placeholder]; ok {
+			continue
+		}
+
+		return awserr.New("ValidationException", fmt.Sprintf("Invalid %s: %s: %s", typ.ParameterName(), errorMsg, placeholder), nil)
+	}
+
 	return nil
 }
 
@@ -709,35 +1233,41 @@ func validateSyntaxExpression(regex *regexp.Regexp, expressions []string, errorM
 }
 
 func getKeysFromExpressionNames(m map[string]*string) []string {
-	keys := make([]string, 0, len(m))
-
-	for k := range m {
-		keys = append(keys, k)
-	}
-
-	return keys
+	return getSortedKeys(m)
 }
 
 func getKeysFromExpressionValues(m map[string]*dynamodb.AttributeValue) []string {
+	return getSortedKeys(m)
+}
+
+func getSortedKeys[V any](m map[string]V) []string {
 	keys := make([]string, 0, len(m))
 
 	for k := range m {
 		keys = append(keys, k)
 	}
 
+	sort.Strings(keys)
+
 	return keys
 }
 
-func getMissingSubstrs(s string, substrs []string) []string {
-	missingSubstrs := make([]string, 0, len(substrs))
+func mergeKeys(dst, src map[string]bool) {
+	for k := range src {
+		dst[k] = true
+	}
+}
+
+func getMissingKeys(keys []string, used map[string]bool) []string {
+	missing := make([]string, 0, len(keys))
 
-	for _, substr := range substrs {
-		if strings.Contains(s, substr) {
+	for _, key := range keys {
+		if used[key] {
 			continue
 		}
 
-		missingSubstrs = append(missingSubstrs, substr)
+		missing = append(missing, key)
 	}
 
-	return missingSubstrs
+	return missing
 }
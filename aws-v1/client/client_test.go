@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
 	"github.com/truora/minidyn/interpreter"
 	"github.com/truora/minidyn/types"
 )
@@ -151,12 +153,15 @@ func setupDynamoDBLocal(endpoint string) dynamodbiface.DynamoDBAPI {
 	return client
 }
 
-func setupNativeInterpreter(native *interpreter.Native, table string) {
-	native.AddUpdater(table, "SET second_type = :ntype", func(item map[string]*types.Item, updates map[string]*types.Item) {
+func setupNativeInterpreter(native *interpreter.Native, table string) error {
+	err := native.AddUpdater(table, "SET second_type = :ntype", func(item map[string]*types.Item, updates map[string]*types.Item) {
 		item["second_type"] = updates[":ntype"]
 	})
+	if err != nil {
+		return err
+	}
 
-	native.AddUpdater(table, "SET #type = :ntype", func(item map[string]*types.Item, updates map[string]*types.Item) {
+	return native.AddUpdater(table, "SET #type = :ntype", func(item map[string]*types.Item, updates map[string]*types.Item) {
 		item["type"] = updates[":ntype"]
 	})
 }
@@ -216,20 +221,750 @@ func TestSetInterpreter(t *testing.T) {
 	err := AddTable(client, "tests", "hk", "rk")
 	c.NoError(err)
 
-	native := interpreter.NewNativeInterpreter()
-	client.SetInterpreter(native)
+	native := interpreter.NewNativeInterpreter()
+	client.SetInterpreter(native)
+
+	_, err = client.getTable("tests")
+	c.NoError(err)
+}
+
+func TestActivateDebug(t *testing.T) {
+	c := require.New(t)
+	fake := NewClient()
+
+	fake.ActivateDebug()
+
+	c.NotNil(fake.langInterpreter.Tracer)
+	c.NotNil(fake.nativeInterpreter.Tracer)
+	c.False(fake.mutationDetection)
+}
+
+func TestActivateMutationDetection(t *testing.T) {
+	c := require.New(t)
+	fake := NewClient()
+
+	fake.ActivateMutationDetection()
+
+	c.True(fake.mutationDetection)
+	c.Nil(fake.langInterpreter.Tracer)
+}
+
+func TestActivateDifferentialInterpreter(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	err = createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	c.NoError(err)
+
+	mismatches := []interpreter.Mismatch{}
+	client.ActivateDifferentialInterpreter(func(m interpreter.Mismatch) {
+		mismatches = append(mismatches, m)
+	})
+
+	err = client.GetNativeInterpreter().AddUpdater(tableName, "SET second_type = :ntype", func(item map[string]*types.Item, updates map[string]*types.Item) {
+		item["second_type"] = &types.Item{S: types.ToString("fire")}
+	})
+	c.NoError(err)
+
+	_, err = client.UpdateItemWithContext(context.Background(), &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {S: aws.String("001")},
+		},
+		UpdateExpression: aws.String("SET second_type = :ntype"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":ntype": {S: aws.String("poison")},
+		},
+	})
+	c.NoError(err)
+
+	item, err := getPokemon(client, "001")
+	c.NoError(err)
+	c.Equal("fire", aws.StringValue(item["second_type"].S))
+
+	c.Len(mismatches, 1)
+	c.Equal("SET second_type = :ntype", mismatches[0].Expression)
+	c.Contains(mismatches[0].Language, `"second_type": {S: "poison"}`)
+}
+
+func TestSetTracer(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	err = createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	c.NoError(err)
+
+	traces := []interpreter.Trace{}
+	client.SetTracer(interpreter.TracerFunc(func(tr interpreter.Trace) {
+		traces = append(traces, tr)
+	}))
+
+	_, err = client.PutItemWithContext(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item: map[string]*dynamodb.AttributeValue{
+			"id": {S: aws.String("001")},
+		},
+		ConditionExpression: aws.String("attribute_exists(id) AND #type = :type"),
+		ExpressionAttributeNames: map[string]*string{
+			"#type": aws.String("type"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":type": {S: aws.String("fire")},
+		},
+	})
+	c.Error(err)
+
+	c.Len(traces, 1)
+	c.Equal(`language conditional expression "attribute_exists(id) AND #type = :type" in table "pokemons" with key {"id": {S: "001"}}: not matched at (#type = :type)`, traces[0].String())
+
+	client.SetTracer(nil)
+
+	_, err = getPokemon(client, "001")
+	c.NoError(err)
+	c.Len(traces, 1)
+}
+
+func TestActivateConditionExplanations(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	err = createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	c.NoError(err)
+
+	client.ActivateConditionExplanations()
+
+	_, err = client.DeleteItemWithContext(context.Background(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {S: aws.String("001")},
+		},
+		ConditionExpression: aws.String("#type = :type"),
+		ExpressionAttributeNames: map[string]*string{
+			"#type": aws.String("type"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":type": {S: aws.String("fire")},
+		},
+	})
+
+	var aerr awserr.Error
+	c.True(errors.As(err, &aerr))
+	c.Equal(dynamodb.ErrCodeConditionalCheckFailedException, aerr.Code())
+	c.Equal("conditional request failed\n#type = :type → false\n  #type → \"grass\"\n  :type → \"fire\"", aerr.Message())
+
+	_, err = client.UpdateItemWithContext(context.Background(), &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {S: aws.String("001")},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+		UpdateExpression:    aws.String("SET #type = :type"),
+		ExpressionAttributeNames: map[string]*string{
+			"#type": aws.String("type"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":type": {S: aws.String("fire")},
+		},
+	})
+	c.True(errors.As(err, &aerr))
+	c.Equal("conditional request failed\nattribute_not_exists(id) → false\n  id → \"001\"", aerr.Message())
+
+	c.NotPanics(func() {
+		_, err = client.PutItem(&dynamodb.PutItemInput{
+			TableName: aws.String(tableName),
+			Item: map[string]*dynamodb.AttributeValue{
+				"id": {S: aws.String("001")},
+			},
+			ConditionExpression: aws.String(""),
+		})
+	})
+	c.Error(err)
+}
+
+func TestListTables(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	for _, name := range []string{"pokemons", "moves", "abilities"} {
+		err := AddTable(client, name, "id", "")
+		c.NoError(err)
+	}
+
+	output, err := client.ListTables(&dynamodb.ListTablesInput{})
+	c.NoError(err)
+	c.Equal([]string{"abilities", "moves", "pokemons"}, aws.StringValueSlice(output.TableNames))
+	c.Nil(output.LastEvaluatedTableName)
+
+	pages := [][]string{}
+
+	err = client.ListTablesPages(&dynamodb.ListTablesInput{Limit: aws.Int64(2)}, func(page *dynamodb.ListTablesOutput, lastPage bool) bool {
+		pages = append(pages, aws.StringValueSlice(page.TableNames))
+
+		return true
+	})
+	c.NoError(err)
+	c.Equal([][]string{{"abilities", "moves"}, {"pokemons"}}, pages)
+
+	_, err = client.ListTables(&dynamodb.ListTablesInput{Limit: aws.Int64(101)})
+
+	var aerr awserr.Error
+	c.True(errors.As(err, &aerr))
+	c.Equal("ValidationException", aerr.Code())
+}
+
+func TestListTablesWithContext(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+	ctx := context.Background()
+
+	for _, name := range []string{"pokemons", "moves", "abilities"} {
+		err := AddTable(client, name, "id", "")
+		c.NoError(err)
+	}
+
+	output, err := client.ListTablesWithContext(ctx, &dynamodb.ListTablesInput{Limit: aws.Int64(1)})
+	c.NoError(err)
+	c.Equal([]string{"abilities"}, aws.StringValueSlice(output.TableNames))
+	c.Equal("abilities", aws.StringValue(output.LastEvaluatedTableName))
+
+	output, err = client.ListTablesWithContext(ctx, &dynamodb.ListTablesInput{ExclusiveStartTableName: output.LastEvaluatedTableName})
+	c.NoError(err)
+	c.Equal([]string{"moves", "pokemons"}, aws.StringValueSlice(output.TableNames))
+	c.Nil(output.LastEvaluatedTableName)
+
+	_, err = client.ListTablesWithContext(ctx, &dynamodb.ListTablesInput{Limit: aws.Int64(0)})
+
+	var aerr awserr.Error
+	c.True(errors.As(err, &aerr))
+	c.Equal("InvalidParameter", aerr.Code())
+}
+
+func TestDeleteTableDuringWrite(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	table, err := client.getTable(tableName)
+	c.NoError(err)
+
+	// the write waits for the table while the deletion waits behind it
+	table.Lock()
+
+	putErr := make(chan error, 1)
+
+	go func() {
+		putErr <- createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	deleteErr := make(chan error, 1)
+
+	var out *dynamodb.DeleteTableOutput
+
+	go func() {
+		var err error
+
+		out, err = client.DeleteTable(&dynamodb.DeleteTableInput{TableName: aws.String(tableName)})
+		deleteErr <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	table.Unlock()
+
+	c.NoError(<-putErr)
+	c.NoError(<-deleteErr)
+	c.Equal(int64(1), aws.Int64Value(out.TableDescription.ItemCount), "the item was written after the table was deleted")
+}
+
+func TestTableLifecycle(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+	client.ActivateTableLifecycle(0)
+
+	output, err := client.CreateTable(generateAddTableInput(tableName, "id", ""))
+	c.NoError(err)
+	c.Equal(dynamodb.TableStatusCreating, aws.StringValue(output.TableDescription.TableStatus))
+
+	err = createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+
+	var aerr awserr.Error
+	c.True(errors.As(err, &aerr))
+	c.Equal(dynamodb.ErrCodeResourceNotFoundException, aerr.Code())
+
+	_, err = client.UpdateTable(&dynamodb.UpdateTableInput{TableName: aws.String(tableName)})
+	c.True(errors.As(err, &aerr))
+	c.Equal(dynamodb.ErrCodeResourceInUseException, aerr.Code())
+
+	describeInput := &dynamodb.DescribeTableInput{TableName: aws.String(tableName)}
+
+	described, err := client.DescribeTable(describeInput)
+	c.NoError(err)
+	c.Equal(dynamodb.TableStatusActive, aws.StringValue(described.Table.TableStatus))
+
+	err = createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	c.NoError(err)
+
+	deleted, err := client.DeleteTable(&dynamodb.DeleteTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Equal(dynamodb.TableStatusDeleting, aws.StringValue(deleted.TableDescription.TableStatus))
+
+	_, err = client.DescribeTable(describeInput)
+	c.True(errors.As(err, &aerr))
+	c.Equal(dynamodb.ErrCodeResourceNotFoundException, aerr.Code())
+
+	tables, err := client.ListTables(&dynamodb.ListTablesInput{})
+	c.NoError(err)
+	c.Empty(tables.TableNames)
+}
+
+func TestDeletionProtection(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	err := client.SetDeletionProtection(tableName, true)
+	c.Equal("ResourceNotFoundException: Cannot do operations on a non-existent table", err.Error())
+
+	err = ensurePokemonTable(client)
+	c.NoError(err)
+
+	err = client.SetDeletionProtection(tableName, true)
+	c.NoError(err)
+
+	_, err = client.DeleteTable(&dynamodb.DeleteTableInput{TableName: aws.String(tableName)})
+
+	var aerr awserr.Error
+	c.True(errors.As(err, &aerr))
+	c.Equal("ValidationException", aerr.Code())
+	c.Equal("Resource cannot be deleted as it is currently protected against deletion. Disable deletion protection first.", aerr.Message())
+
+	err = client.SetDeletionProtection(tableName, false)
+	c.NoError(err)
+
+	_, err = client.DeleteTable(&dynamodb.DeleteTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+}
+
+func TestBackups(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(ensurePokemonTypeIndex(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"}))
+	c.NoError(createPokemon(client, pokemon{ID: "004", Type: "fire", Name: "Charmander"}))
+
+	_, err := client.CreateBackup(&dynamodb.CreateBackupInput{
+		TableName:  aws.String("trainers"),
+		BackupName: aws.String("trainers-backup"),
+	})
+	c.Equal("TableNotFoundException: Table not found: trainers", err.Error())
+
+	created, err := client.CreateBackup(&dynamodb.CreateBackupInput{
+		TableName:  aws.String(tableName),
+		BackupName: aws.String("pokemons-backup"),
+	})
+	c.NoError(err)
+	c.Equal(dynamodb.BackupStatusAvailable, aws.StringValue(created.BackupDetails.BackupStatus))
+
+	backupArn := created.BackupDetails.BackupArn
+
+	c.NoError(createPokemon(client, pokemon{ID: "007", Type: "water", Name: "Squirtle"}))
+
+	described, err := client.DescribeBackup(&dynamodb.DescribeBackupInput{BackupArn: backupArn})
+	c.NoError(err)
+	c.Equal(tableName, aws.StringValue(described.BackupDescription.SourceTableDetails.TableName))
+	c.Equal(int64(2), aws.Int64Value(described.BackupDescription.SourceTableDetails.ItemCount))
+	c.Len(described.BackupDescription.SourceTableFeatureDetails.GlobalSecondaryIndexes, 1)
+
+	listed, err := client.ListBackups(&dynamodb.ListBackupsInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Len(listed.BackupSummaries, 1)
+	c.Equal(backupArn, listed.BackupSummaries[0].BackupArn)
+
+	_, err = client.RestoreTableFromBackup(&dynamodb.RestoreTableFromBackupInput{
+		BackupArn:       backupArn,
+		TargetTableName: aws.String(tableName),
+	})
+	c.Equal("TableAlreadyExistsException: Table already exists: "+tableName, err.Error())
+
+	restored, err := client.RestoreTableFromBackup(&dynamodb.RestoreTableFromBackupInput{
+		BackupArn:           backupArn,
+		TargetTableName:     aws.String("pokemons-restored"),
+		BillingModeOverride: aws.String(dynamodb.BillingModeProvisioned),
+		ProvisionedThroughputOverride: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(5),
+		},
+		GlobalSecondaryIndexOverride: []*dynamodb.GlobalSecondaryIndex{},
+	})
+	c.NoError(err)
+	c.Equal(int64(2), aws.Int64Value(restored.TableDescription.ItemCount))
+	c.Equal(dynamodb.BillingModeProvisioned, aws.StringValue(restored.TableDescription.BillingModeSummary.BillingMode))
+	c.Empty(restored.TableDescription.GlobalSecondaryIndexes)
+	c.Equal(backupArn, restored.TableDescription.RestoreSummary.SourceBackupArn)
+
+	item, err := client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String("pokemons-restored"),
+		Key:       map[string]*dynamodb.AttributeValue{"id": {S: aws.String("004")}},
+	})
+	c.NoError(err)
+	c.Equal("Charmander", aws.StringValue(item.Item["name"].S))
+
+	deleted, err := client.DeleteBackup(&dynamodb.DeleteBackupInput{BackupArn: backupArn})
+	c.NoError(err)
+	c.Equal(dynamodb.BackupStatusDeleted, aws.StringValue(deleted.BackupDescription.BackupDetails.BackupStatus))
+
+	_, err = client.DescribeBackup(&dynamodb.DescribeBackupInput{BackupArn: backupArn})
+	c.Equal("BackupNotFoundException: Backup not found: "+aws.StringValue(backupArn), err.Error())
+}
+
+func TestBackupsWithContext(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+	ctx := context.Background()
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"}))
+
+	created, err := client.CreateBackupWithContext(ctx, &dynamodb.CreateBackupInput{
+		TableName:  aws.String(tableName),
+		BackupName: aws.String("pokemons-backup"),
+	})
+	c.NoError(err)
+
+	backupArn := created.BackupDetails.BackupArn
+
+	described, err := client.DescribeBackupWithContext(ctx, &dynamodb.DescribeBackupInput{BackupArn: backupArn})
+	c.NoError(err)
+	c.Equal(int64(1), aws.Int64Value(described.BackupDescription.SourceTableDetails.ItemCount))
+
+	listed, err := client.ListBackupsWithContext(ctx, &dynamodb.ListBackupsInput{})
+	c.NoError(err)
+	c.Len(listed.BackupSummaries, 1)
+
+	restored, err := client.RestoreTableFromBackupWithContext(ctx, &dynamodb.RestoreTableFromBackupInput{
+		BackupArn:       backupArn,
+		TargetTableName: aws.String("pokemons-restored"),
+	})
+	c.NoError(err)
+	c.Equal(int64(1), aws.Int64Value(restored.TableDescription.ItemCount))
+
+	deleted, err := client.DeleteBackupWithContext(ctx, &dynamodb.DeleteBackupInput{BackupArn: backupArn})
+	c.NoError(err)
+	c.Equal(dynamodb.BackupStatusDeleted, aws.StringValue(deleted.BackupDescription.BackupDetails.BackupStatus))
+
+	_, err = client.ListBackupsWithContext(ctx, &dynamodb.ListBackupsInput{Limit: aws.Int64(0)})
+	c.Error(err)
+}
+
+func TestPointInTimeRecovery(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.SetClock(func() time.Time { return now })
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"}))
+
+	_, err := client.RestoreTableToPointInTime(&dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableName:         aws.String(tableName),
+		TargetTableName:         aws.String("pokemons-restored"),
+		UseLatestRestorableTime: aws.Bool(true),
+	})
+	c.Equal("PointInTimeRecoveryUnavailableException: Point in time recovery is not enabled for table '"+tableName+"'", err.Error())
+
+	updated, err := client.UpdateContinuousBackups(&dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String(tableName),
+		PointInTimeRecoverySpecification: &dynamodb.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+	c.Equal(dynamodb.PointInTimeRecoveryStatusEnabled, aws.StringValue(updated.ContinuousBackupsDescription.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus))
+
+	now = now.Add(time.Hour)
+	healthy := now
+
+	now = now.Add(time.Hour)
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "MissingNo"}))
+
+	described, err := client.DescribeContinuousBackups(&dynamodb.DescribeContinuousBackupsInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Equal(now, aws.TimeValue(described.ContinuousBackupsDescription.PointInTimeRecoveryDescription.LatestRestorableDateTime))
+
+	tooEarly := healthy.Add(-2 * time.Hour)
+
+	_, err = client.RestoreTableToPointInTime(&dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableName: aws.String(tableName),
+		TargetTableName: aws.String("pokemons-restored"),
+		RestoreDateTime: &tooEarly,
+	})
+	c.Error(err)
+	c.Contains(err.Error(), dynamodb.ErrCodeInvalidRestoreTimeException)
+
+	source, err := client.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+
+	restored, err := client.RestoreTableToPointInTime(&dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableArn:  source.Table.TableArn,
+		TargetTableName: aws.String("pokemons-restored"),
+		RestoreDateTime: &healthy,
+	})
+	c.NoError(err)
+	c.Equal(healthy, aws.TimeValue(restored.TableDescription.RestoreSummary.RestoreDateTime))
+	c.Equal(now, aws.TimeValue(restored.TableDescription.CreationDateTime))
+
+	item, err := client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String("pokemons-restored"),
+		Key:       map[string]*dynamodb.AttributeValue{"id": {S: aws.String("001")}},
+	})
+	c.NoError(err)
+	c.Equal("Bulbasaur", aws.StringValue(item.Item["name"].S))
+
+	_, err = client.DescribeContinuousBackups(&dynamodb.DescribeContinuousBackupsInput{TableName: aws.String("trainers")})
+	c.Equal("TableNotFoundException: Table not found: trainers", err.Error())
+}
+
+func TestPointInTimeRecoveryWithContext(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+	ctx := context.Background()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.SetClock(func() time.Time { return now })
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"}))
+
+	updated, err := client.UpdateContinuousBackupsWithContext(ctx, &dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String(tableName),
+		PointInTimeRecoverySpecification: &dynamodb.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+	c.Equal(dynamodb.PointInTimeRecoveryStatusEnabled, aws.StringValue(updated.ContinuousBackupsDescription.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus))
+
+	now = now.Add(time.Hour)
+
+	described, err := client.DescribeContinuousBackupsWithContext(ctx, &dynamodb.DescribeContinuousBackupsInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Equal(now, aws.TimeValue(described.ContinuousBackupsDescription.PointInTimeRecoveryDescription.LatestRestorableDateTime))
+
+	restored, err := client.RestoreTableToPointInTimeWithContext(ctx, &dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableName:         aws.String(tableName),
+		TargetTableName:         aws.String("pokemons-restored"),
+		UseLatestRestorableTime: aws.Bool(true),
+	})
+	c.NoError(err)
+	c.Equal(int64(1), aws.Int64Value(restored.TableDescription.ItemCount))
+
+	_, err = client.DescribeContinuousBackupsWithContext(ctx, &dynamodb.DescribeContinuousBackupsInput{})
+	c.Error(err)
+}
+
+func TestExportTableToPointInTime(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"}))
+
+	source, err := client.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+
+	input := &dynamodb.ExportTableToPointInTimeInput{
+		TableArn: source.Table.TableArn,
+		S3Bucket: aws.String("analytics"),
+		S3Prefix: aws.String("pokemons"),
+	}
+
+	_, err = client.ExportTableToPointInTime(input)
+	c.Error(err)
+	c.Contains(err.Error(), "The export directory is not set")
+
+	dir := t.TempDir()
+	client.SetExportDirectory(dir)
+
+	_, err = client.ExportTableToPointInTime(input)
+	c.Error(err)
+	c.Contains(err.Error(), dynamodb.ErrCodePointInTimeRecoveryUnavailableException)
+
+	_, err = client.UpdateContinuousBackups(&dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String(tableName),
+		PointInTimeRecoverySpecification: &dynamodb.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+
+	exported, err := client.ExportTableToPointInTime(input)
+	c.NoError(err)
+	c.Equal(dynamodb.ExportStatusCompleted, aws.StringValue(exported.ExportDescription.ExportStatus))
+	c.Equal(dynamodb.ExportFormatDynamodbJson, aws.StringValue(exported.ExportDescription.ExportFormat))
+	c.Equal(int64(1), aws.Int64Value(exported.ExportDescription.ItemCount))
+
+	_, err = os.Stat(dir + "/analytics/" + aws.StringValue(exported.ExportDescription.ExportManifest))
+	c.NoError(err)
+
+	described, err := client.DescribeExport(&dynamodb.DescribeExportInput{ExportArn: exported.ExportDescription.ExportArn})
+	c.NoError(err)
+	c.Equal(exported.ExportDescription.ExportArn, described.ExportDescription.ExportArn)
+
+	unknownArn := aws.StringValue(source.Table.TableArn) + "/export/unknown"
+
+	_, err = client.DescribeExport(&dynamodb.DescribeExportInput{ExportArn: aws.String(unknownArn)})
+	c.Equal("ExportNotFoundException: Export not found: "+unknownArn, err.Error())
+}
+
+func TestExportTableToPointInTimeWithContext(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+	ctx := context.Background()
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"}))
+
+	client.SetExportDirectory(t.TempDir())
+
+	source, err := client.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+
+	_, err = client.UpdateContinuousBackups(&dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String(tableName),
+		PointInTimeRecoverySpecification: &dynamodb.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+
+	exported, err := client.ExportTableToPointInTimeWithContext(ctx, &dynamodb.ExportTableToPointInTimeInput{
+		TableArn:     source.Table.TableArn,
+		S3Bucket:     aws.String("analytics"),
+		ExportFormat: aws.String(dynamodb.ExportFormatIon),
+	})
+	c.NoError(err)
+	c.Equal(dynamodb.ExportFormatIon, aws.StringValue(exported.ExportDescription.ExportFormat))
+
+	described, err := client.DescribeExportWithContext(ctx, &dynamodb.DescribeExportInput{ExportArn: exported.ExportDescription.ExportArn})
+	c.NoError(err)
+	c.Equal(int64(1), aws.Int64Value(described.ExportDescription.ItemCount))
+
+	_, err = client.DescribeExportWithContext(ctx, &dynamodb.DescribeExportInput{})
+	c.Error(err)
+}
+
+func TestTagResource(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	input := generateAddTableInput(tableName, "id", "")
+	input.Tags = []*dynamodb.Tag{{Key: aws.String("team"), Value: aws.String("pokedex")}}
+
+	created, err := client.CreateTable(input)
+	c.NoError(err)
+
+	arn := created.TableDescription.TableArn
+
+	tags := make([]*dynamodb.Tag, 0, 11)
+	for n := 0; n < 11; n++ {
+		tags = append(tags, &dynamodb.Tag{Key: aws.String(fmt.Sprintf("key-%02d", n)), Value: aws.String("value")})
+	}
+
+	_, err = client.TagResource(&dynamodb.TagResourceInput{ResourceArn: arn, Tags: tags})
+	c.NoError(err)
+
+	listed, err := client.ListTagsOfResource(&dynamodb.ListTagsOfResourceInput{ResourceArn: arn})
+	c.NoError(err)
+	c.Len(listed.Tags, 10)
+	c.Equal("key-00", aws.StringValue(listed.Tags[0].Key))
+	c.NotNil(listed.NextToken)
+
+	listed, err = client.ListTagsOfResource(&dynamodb.ListTagsOfResourceInput{ResourceArn: arn, NextToken: listed.NextToken})
+	c.NoError(err)
+	c.Len(listed.Tags, 2)
+	c.Equal("team", aws.StringValue(listed.Tags[1].Key))
+	c.Nil(listed.NextToken)
+
+	_, err = client.UntagResource(&dynamodb.UntagResourceInput{ResourceArn: arn, TagKeys: []*string{aws.String("team")}})
+	c.NoError(err)
+
+	limits := core.DefaultLimits
+	limits.TagsPerResource = 11
+	client.SetLimits(limits)
+
+	_, err = client.TagResource(&dynamodb.TagResourceInput{
+		ResourceArn: arn,
+		Tags:        []*dynamodb.Tag{{Key: aws.String("owner"), Value: aws.String("oak")}},
+	})
+
+	var aerr awserr.Error
+	c.True(errors.As(err, &aerr))
+	c.Equal(dynamodb.ErrCodeLimitExceededException, aerr.Code())
+
+	_, err = client.ListTagsOfResource(&dynamodb.ListTagsOfResourceInput{ResourceArn: aws.String(aws.StringValue(arn) + "-unknown")})
+	c.True(errors.As(err, &aerr))
+	c.Equal(dynamodb.ErrCodeResourceNotFoundException, aerr.Code())
+}
+
+func TestTagResourceWithContext(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+	ctx := context.Background()
+
+	created, err := client.CreateTable(generateAddTableInput(tableName, "id", ""))
+	c.NoError(err)
+
+	arn := created.TableDescription.TableArn
+
+	_, err = client.TagResourceWithContext(ctx, &dynamodb.TagResourceInput{
+		ResourceArn: arn,
+		Tags:        []*dynamodb.Tag{{Key: aws.String("team"), Value: aws.String("pokedex")}},
+	})
+	c.NoError(err)
+
+	listed, err := client.ListTagsOfResourceWithContext(ctx, &dynamodb.ListTagsOfResourceInput{ResourceArn: arn})
+	c.NoError(err)
+	c.Len(listed.Tags, 1)
+
+	_, err = client.UntagResourceWithContext(ctx, &dynamodb.UntagResourceInput{ResourceArn: arn, TagKeys: []*string{aws.String("team")}})
+	c.NoError(err)
 
-	_, err = client.getTable("tests")
+	listed, err = client.ListTagsOfResourceWithContext(ctx, &dynamodb.ListTagsOfResourceInput{ResourceArn: arn})
 	c.NoError(err)
+	c.Empty(listed.Tags)
 }
 
-func TestActivateDebug(t *testing.T) {
+func TestUntagResourceErrors(t *testing.T) {
 	c := require.New(t)
-	fake := NewClient()
+	client := NewClient()
 
-	fake.ActivateDebug()
+	created, err := client.CreateTable(generateAddTableInput(tableName, "id", ""))
+	c.NoError(err)
+
+	arn := created.TableDescription.TableArn
+
+	var aerr awserr.Error
 
-	c.True(fake.langInterpreter.Debug)
+	_, err = client.UntagResource(&dynamodb.UntagResourceInput{ResourceArn: arn})
+	c.True(errors.As(err, &aerr))
+	c.Equal("InvalidParameter", aerr.Code())
+
+	_, err = client.UntagResource(&dynamodb.UntagResourceInput{
+		ResourceArn: aws.String(aws.StringValue(arn) + "-unknown"),
+		TagKeys:     []*string{aws.String("team")},
+	})
+	c.True(errors.As(err, &aerr))
+	c.Equal(dynamodb.ErrCodeResourceNotFoundException, aerr.Code())
+
+	_, err = client.UntagResource(&dynamodb.UntagResourceInput{ResourceArn: arn, TagKeys: []*string{aws.String("")}})
+	c.Error(err)
+	c.Contains(err.Error(), "ValidationException")
 }
 
 func TestCreateTable(t *testing.T) {
@@ -507,7 +1242,17 @@ func TestUpdateTable(t *testing.T) {
 		TableName: aws.String(tableName),
 	}
 	_, err = client.UpdateTableWithContext(context.Background(), input)
+	c.Contains(err.Error(), "ProvisionedThroughput should not be specified for index: newIndex when BillingMode is PAY_PER_REQUEST")
+
+	input.BillingMode = aws.String("PROVISIONED")
+	input.ProvisionedThroughput = &dynamodb.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(5),
+		WriteCapacityUnits: aws.Int64(5),
+	}
+
+	output, err = client.UpdateTableWithContext(context.Background(), input)
 	c.NoError(err)
+	c.Equal(int64(1), aws.Int64Value(output.TableDescription.GlobalSecondaryIndexes[0].ProvisionedThroughput.ReadCapacityUnits))
 
 	input = &dynamodb.UpdateTableInput{
 		GlobalSecondaryIndexUpdates: []*dynamodb.GlobalSecondaryIndexUpdate{
@@ -526,6 +1271,61 @@ func TestUpdateTable(t *testing.T) {
 	c.Equal("ResourceNotFoundException: Requested resource not found", err.Error())
 }
 
+func TestUpdateTableSettings(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	_, err = client.UpdateTable(&dynamodb.UpdateTableInput{
+		TableName: aws.String(tableName),
+	})
+	c.Contains(err.Error(), "ValidationException: At least one of ProvisionedThroughput")
+
+	output, err := client.UpdateTable(&dynamodb.UpdateTableInput{
+		TableName: aws.String(tableName),
+		StreamSpecification: &dynamodb.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: aws.String("NEW_IMAGE"),
+		},
+	})
+	c.NoError(err)
+	c.Equal("NEW_IMAGE", aws.StringValue(output.TableDescription.StreamSpecification.StreamViewType))
+
+	_, err = client.UpdateTable(&dynamodb.UpdateTableInput{
+		TableName: aws.String(tableName),
+		StreamSpecification: &dynamodb.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: aws.String("NEW_IMAGE"),
+		},
+	})
+	c.Contains(err.Error(), "Table already has an enabled stream")
+
+	output, err = client.UpdateTable(&dynamodb.UpdateTableInput{
+		TableName:   aws.String(tableName),
+		BillingMode: aws.String("PROVISIONED"),
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(5),
+		},
+		StreamSpecification: &dynamodb.StreamSpecification{StreamEnabled: aws.Bool(false)},
+	})
+	c.NoError(err)
+	c.Equal("PROVISIONED", aws.StringValue(output.TableDescription.BillingModeSummary.BillingMode))
+	c.Equal(int64(5), aws.Int64Value(output.TableDescription.ProvisionedThroughput.ReadCapacityUnits))
+	c.Nil(output.TableDescription.StreamSpecification)
+
+	_, err = client.UpdateTable(&dynamodb.UpdateTableInput{
+		TableName: aws.String(tableName),
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(5),
+		},
+	})
+	c.Contains(err.Error(), "The provisioned throughput for the table will not change")
+}
+
 func TestPutAndGetItem(t *testing.T) {
 	c := require.New(t)
 	client := setupClient(tableName)
@@ -571,6 +1371,49 @@ func TestPutAndGetItem(t *testing.T) {
 	c.Contains(err.Error(), "number of conditions on the keys is invalid")
 }
 
+func TestItemsAreNotSharedWithTheCaller(t *testing.T) {
+	c := require.New(t)
+	client := setupClient(tableName)
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	item := map[string]*dynamodb.AttributeValue{
+		"id":    {S: aws.String("001")},
+		"moves": {SS: []*string{aws.String("tackle")}},
+		"stats": {M: map[string]*dynamodb.AttributeValue{
+			"hp": {N: aws.String("45")},
+		}},
+	}
+
+	_, err = client.PutItemWithContext(context.Background(), &dynamodb.PutItemInput{
+		Item:      item,
+		TableName: aws.String(tableName),
+	})
+	c.NoError(err)
+
+	*item["moves"].SS[0] = "growl"
+	*item["stats"].M["hp"].N = "1"
+
+	getInput := &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {S: aws.String("001")},
+		},
+	}
+
+	out, err := client.GetItemWithContext(context.Background(), getInput)
+	c.NoError(err)
+	c.Equal("tackle", aws.StringValue(out.Item["moves"].SS[0]))
+	c.Equal("45", aws.StringValue(out.Item["stats"].M["hp"].N))
+
+	*out.Item["stats"].M["hp"].N = "2"
+
+	out, err = client.GetItemWithContext(context.Background(), getInput)
+	c.NoError(err)
+	c.Equal("45", aws.StringValue(out.Item["stats"].M["hp"].N))
+}
+
 func TestPutWithGSI(t *testing.T) {
 	c := require.New(t)
 	client := setupClient(tableName)
@@ -644,6 +1487,47 @@ func TestGetItemWithUnusedAttributes(t *testing.T) {
 	c.Contains(err.Error(), unusedExpressionAttributeNamesMsg)
 }
 
+func TestQueryWithUndefinedAttributes(t *testing.T) {
+	c := require.New(t)
+
+	client := setupClient(tableName)
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	input := &dynamodb.QueryInput{
+		TableName: aws.String(tableName),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":id": {S: aws.String("001")},
+		},
+		KeyConditionExpression: aws.String("id = :id2"),
+	}
+
+	_, err = client.Query(input)
+	c.NotNil(err)
+	c.Contains(err.Error(), "Invalid KeyConditionExpression: "+undefinedExpressionAttributeValueMsg+": :id2")
+
+	input.KeyConditionExpression = aws.String("#name = :id")
+	input.ExpressionAttributeNames = map[string]*string{
+		"#n": aws.String("id"),
+	}
+
+	_, err = client.Query(input)
+	c.NotNil(err)
+	c.Contains(err.Error(), "Invalid KeyConditionExpression: "+undefinedExpressionAttributeNameMsg+": #name")
+
+	input.KeyConditionExpression = aws.String("id = :id")
+	input.FilterExpression = aws.String("#name = :id")
+	input.ExpressionAttributeNames = map[string]*string{
+		"#name": aws.String("name"),
+		"#n":    aws.String("id"),
+	}
+
+	_, err = client.Query(input)
+	c.NotNil(err)
+	c.Contains(err.Error(), unusedExpressionAttributeNamesMsg+": keys: {#n}")
+}
+
 func TestGetItemWithInvalidExpressionAttributeNames(t *testing.T) {
 	c := require.New(t)
 
@@ -786,7 +1670,9 @@ func TestUpdateItemWithContext(t *testing.T) {
 		return
 	}
 
-	setupNativeInterpreter(minidynClient.GetNativeInterpreter(), tableName)
+	err = setupNativeInterpreter(minidynClient.GetNativeInterpreter(), tableName)
+	c.NoError(err)
+
 	minidynClient.ActivateNativeInterpreter()
 
 	input.Key = map[string]*dynamodb.AttributeValue{
@@ -827,7 +1713,7 @@ func TestUpdateItemWithConditionalExpression(t *testing.T) {
 
 	uexpr := "SET second_type = :ntype"
 	expr := map[string]*dynamodb.AttributeValue{
-		":ntyp": {
+		":ntype": {
 			S: aws.String(string("poison")),
 		},
 	}
@@ -1201,7 +2087,9 @@ func TestQueryWithContext(t *testing.T) {
 		return
 	}
 
-	setupNativeInterpreter(minidynClient.GetNativeInterpreter(), tableName)
+	err = setupNativeInterpreter(minidynClient.GetNativeInterpreter(), tableName)
+	c.NoError(err)
+
 	minidynClient.ActivateNativeInterpreter()
 
 	input = &dynamodb.QueryInput{
@@ -1393,9 +2281,8 @@ func TestQuerySyntaxError(t *testing.T) {
 		TableName:              aws.String(tableName),
 	}
 
-	c.Panics(func() {
-		_, _ = client.QueryWithContext(context.Background(), input)
-	})
+	_, err = client.Query(input)
+	c.EqualError(err, `ValidationException: Invalid KeyConditionExpression: Syntax error; token: "!", near: "#partition !="`)
 }
 
 func TestScanWithContext(t *testing.T) {
@@ -1704,6 +2591,74 @@ func TestDescribeTableFail(t *testing.T) {
 	c.Empty(output)
 }
 
+func TestDescribeTableSettings(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	_, err := client.CreateTable(&dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("type"), AttributeType: aws.String("S")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: aws.String("HASH")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(10),
+			WriteCapacityUnits: aws.Int64(5),
+		},
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			{
+				IndexName:  aws.String("by-type"),
+				KeySchema:  []*dynamodb.KeySchemaElement{{AttributeName: aws.String("type"), KeyType: aws.String("HASH")}},
+				Projection: &dynamodb.Projection{ProjectionType: aws.String("ALL")},
+				ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+					ReadCapacityUnits:  aws.Int64(2),
+					WriteCapacityUnits: aws.Int64(1),
+				},
+			},
+		},
+		StreamSpecification: &dynamodb.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: aws.String("KEYS_ONLY"),
+		},
+		SSESpecification: &dynamodb.SSESpecification{
+			Enabled:        aws.Bool(true),
+			KMSMasterKeyId: aws.String("arn:aws:kms:us-east-1:000000000000:key/my-key"),
+		},
+	})
+	c.NoError(err)
+
+	err = createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	c.NoError(err)
+
+	output, err := client.DescribeTable(&dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	c.NoError(err)
+
+	table := output.Table
+	c.Equal("arn:aws:dynamodb:ddblocal:000000000000:table/"+tableName, aws.StringValue(table.TableArn))
+	c.NotEmpty(aws.StringValue(table.TableId))
+	c.NotNil(table.CreationDateTime)
+	c.Len(table.AttributeDefinitions, 2)
+	c.Equal("PROVISIONED", aws.StringValue(table.BillingModeSummary.BillingMode))
+	c.Equal(int64(10), aws.Int64Value(table.ProvisionedThroughput.ReadCapacityUnits))
+	c.Equal(int64(5), aws.Int64Value(table.ProvisionedThroughput.WriteCapacityUnits))
+	c.Positive(aws.Int64Value(table.TableSizeBytes))
+	c.Equal("KEYS_ONLY", aws.StringValue(table.StreamSpecification.StreamViewType))
+	c.NotEmpty(aws.StringValue(table.LatestStreamArn))
+	c.Equal("arn:aws:kms:us-east-1:000000000000:key/my-key", aws.StringValue(table.SSEDescription.KMSMasterKeyArn))
+
+	c.Len(table.GlobalSecondaryIndexes, 1)
+	gsi := table.GlobalSecondaryIndexes[0]
+	c.Equal(aws.StringValue(table.TableArn)+"/index/by-type", aws.StringValue(gsi.IndexArn))
+	c.Equal("ACTIVE", aws.StringValue(gsi.IndexStatus))
+	c.Equal(int64(1), aws.Int64Value(gsi.ItemCount))
+	c.Equal(int64(2), aws.Int64Value(gsi.ProvisionedThroughput.ReadCapacityUnits))
+}
+
 func TestBatchWriteItemWithContext(t *testing.T) {
 	c := require.New(t)
 	client := setupClient(tableName)
@@ -1903,6 +2858,219 @@ func TestTransactWriteItemsWithContext(t *testing.T) {
 	c.Equal(ErrForcedFailure, err)
 }
 
+func TestSetLimits(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	limits := core.DefaultLimits
+	limits.ItemSize = 20
+	limits.TransactionItems = 0
+
+	client.SetLimits(limits)
+
+	err = createPokemon(client, pokemon{
+		ID:   "001",
+		Type: "grass",
+		Name: "Bulbasaur",
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "Item size has exceeded the maximum allowed size")
+
+	_, err = client.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{{}},
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "Member must have length less than or equal to 0")
+
+	client.SetLimits(core.DefaultLimits)
+
+	err = createPokemon(client, pokemon{
+		ID:   "001",
+		Type: "grass",
+		Name: "Bulbasaur",
+	})
+	c.NoError(err)
+}
+
+func TestLegacyParameters(t *testing.T) {
+	c := require.New(t)
+
+	client := setupClient(tableName)
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	err = createPokemon(client, pokemon{
+		ID:    "001",
+		Type:  "grass",
+		Name:  "Bulbasaur",
+		Level: 5,
+	})
+	c.NoError(err)
+
+	key := map[string]*dynamodb.AttributeValue{
+		"id": {S: aws.String("001")},
+	}
+
+	_, err = client.UpdateItemWithContext(context.Background(), &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key:       key,
+		AttributeUpdates: map[string]*dynamodb.AttributeValueUpdate{
+			"lvl": {
+				Action: aws.String(dynamodb.AttributeActionAdd),
+				Value:  &dynamodb.AttributeValue{N: aws.String("1")},
+			},
+			"second_type": {Action: aws.String(dynamodb.AttributeActionDelete)},
+		},
+		Expected: map[string]*dynamodb.ExpectedAttributeValue{
+			"type": {Value: &dynamodb.AttributeValue{S: aws.String("grass")}},
+		},
+	})
+	c.NoError(err)
+
+	out, err := client.QueryWithContext(context.Background(), &dynamodb.QueryInput{
+		TableName: aws.String(tableName),
+		KeyConditions: map[string]*dynamodb.Condition{
+			"id": {
+				ComparisonOperator: aws.String(dynamodb.ComparisonOperatorEq),
+				AttributeValueList: []*dynamodb.AttributeValue{{S: aws.String("001")}},
+			},
+		},
+		QueryFilter: map[string]*dynamodb.Condition{
+			"lvl": {
+				ComparisonOperator: aws.String(dynamodb.ComparisonOperatorGt),
+				AttributeValueList: []*dynamodb.AttributeValue{{N: aws.String("5")}},
+			},
+		},
+	})
+	c.NoError(err)
+	c.Len(out.Items, 1)
+	c.Equal("6", aws.StringValue(out.Items[0]["lvl"].N))
+	c.NotContains(out.Items[0], "second_type")
+
+	scan, err := client.ScanWithContext(context.Background(), &dynamodb.ScanInput{
+		TableName: aws.String(tableName),
+		ScanFilter: map[string]*dynamodb.Condition{
+			"type": {ComparisonOperator: aws.String(dynamodb.ComparisonOperatorNull)},
+		},
+	})
+	c.NoError(err)
+	c.Empty(scan.Items)
+
+	item, err := client.GetItemWithContext(context.Background(), &dynamodb.GetItemInput{
+		TableName:       aws.String(tableName),
+		Key:             key,
+		AttributesToGet: aws.StringSlice([]string{"lvl", "missing"}),
+	})
+	c.NoError(err)
+	c.Equal(map[string]*dynamodb.AttributeValue{"lvl": {N: aws.String("6")}}, item.Item)
+
+	out, err = client.QueryWithContext(context.Background(), &dynamodb.QueryInput{
+		TableName: aws.String(tableName),
+		KeyConditions: map[string]*dynamodb.Condition{
+			"id": {
+				ComparisonOperator: aws.String(dynamodb.ComparisonOperatorEq),
+				AttributeValueList: []*dynamodb.AttributeValue{{S: aws.String("001")}},
+			},
+		},
+		AttributesToGet: aws.StringSlice([]string{"id"}),
+	})
+	c.NoError(err)
+	c.Equal([]map[string]*dynamodb.AttributeValue{{"id": {S: aws.String("001")}}}, out.Items)
+
+	scan, err = client.ScanWithContext(context.Background(), &dynamodb.ScanInput{
+		TableName:       aws.String(tableName),
+		AttributesToGet: aws.StringSlice([]string{"id"}),
+	})
+	c.NoError(err)
+	c.Equal([]map[string]*dynamodb.AttributeValue{{"id": {S: aws.String("001")}}}, scan.Items)
+
+	_, err = client.DeleteItemWithContext(context.Background(), &dynamodb.DeleteItemInput{
+		TableName:           aws.String(tableName),
+		Key:                 key,
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		Expected: map[string]*dynamodb.ExpectedAttributeValue{
+			"id": {Exists: aws.Bool(true), Value: &dynamodb.AttributeValue{S: aws.String("001")}},
+		},
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "Can not use both expression and non-expression parameters in the same request: Non-expression parameters: {Expected} Expression parameters: {ConditionExpression}")
+
+	_, err = client.DeleteItemWithContext(context.Background(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableName),
+		Key:       key,
+		Expected: map[string]*dynamodb.ExpectedAttributeValue{
+			"id": {Exists: aws.Bool(false)},
+		},
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "ConditionalCheckFailedException")
+}
+
+func TestConcurrentOperations(t *testing.T) {
+	c := require.New(t)
+
+	client := NewClient()
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	err = AddTable(client, "trainers", "id", "")
+	c.NoError(err)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+
+		id := fmt.Sprintf("%03d", i)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, createPokemon(client, pokemon{ID: id, Type: "grass", Name: "Bulbasaur"}))
+
+			_, err := getPokemon(client, id)
+			assert.NoError(t, err)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_, err := client.ScanWithContext(context.Background(), &dynamodb.ScanInput{TableName: aws.String(tableName)})
+			assert.NoError(t, err)
+
+			_, err = client.DescribeTableWithContext(context.Background(), &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+			assert.NoError(t, err)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_, err := client.PutItemWithContext(context.Background(), &dynamodb.PutItemInput{
+				TableName: aws.String("trainers"),
+				Item: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String(id)},
+				},
+			})
+			assert.NoError(t, err)
+
+			assert.NoError(t, ClearTable(client, "trainers"))
+
+			_, _ = client.CreateTableWithContext(context.Background(), generateAddTableInput("gyms"+id, "id", ""))
+			_, _ = client.DeleteTableWithContext(context.Background(), &dynamodb.DeleteTableInput{TableName: aws.String("gyms" + id)})
+		}()
+	}
+
+	wg.Wait()
+
+	out, err := client.ScanWithContext(context.Background(), &dynamodb.ScanInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Len(out.Items, 10)
+}
+
 func TestCheckTableName(t *testing.T) {
 	c := require.New(t)
 
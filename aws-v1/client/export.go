@@ -0,0 +1,72 @@
+package client
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/truora/minidyn/core"
+)
+
+// ExportTableToPointInTime writes the items of the table at the export time to the export directory, the S3
+// bucket is a subdirectory of the directory set with SetExportDirectory and the export is completed when it returns
+func (fd *Client) ExportTableToPointInTime(input *dynamodb.ExportTableToPointInTimeInput) (*dynamodb.ExportTableToPointInTimeOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	tableName := core.TableNameFromArn(aws.StringValue(input.TableArn))
+
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	table, ok := fd.tables[tableName]
+	if !ok {
+		return nil, awserr.New(dynamodb.ErrCodeTableNotFoundException, "Table not found: "+tableName, nil)
+	}
+
+	table.RLock()
+	defer table.RUnlock()
+
+	export, err := core.NewExport(table, mapExportTableToPointInTimeInputToTypes(input), fd.exportDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	fd.exports[export.Arn] = export
+
+	return &dynamodb.ExportTableToPointInTimeOutput{
+		ExportDescription: mapExportDescriptionToDynamodb(export),
+	}, nil
+}
+
+// ExportTableToPointInTimeWithContext writes the items of the table at the export time to the export directory
+func (fd *Client) ExportTableToPointInTimeWithContext(ctx aws.Context, input *dynamodb.ExportTableToPointInTimeInput, opts ...request.Option) (*dynamodb.ExportTableToPointInTimeOutput, error) {
+	return fd.ExportTableToPointInTime(input)
+}
+
+// DescribeExport returns the description of the export
+func (fd *Client) DescribeExport(input *dynamodb.DescribeExportInput) (*dynamodb.DescribeExportOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	arn := aws.StringValue(input.ExportArn)
+
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	export, ok := fd.exports[arn]
+	if !ok {
+		return nil, awserr.New(dynamodb.ErrCodeExportNotFoundException, "Export not found: "+arn, nil)
+	}
+
+	return &dynamodb.DescribeExportOutput{
+		ExportDescription: mapExportDescriptionToDynamodb(export),
+	}, nil
+}
+
+// DescribeExportWithContext returns the description of the export
+func (fd *Client) DescribeExportWithContext(ctx aws.Context, input *dynamodb.DescribeExportInput, opts ...request.Option) (*dynamodb.DescribeExportOutput, error) {
+	return fd.DescribeExport(input)
+}
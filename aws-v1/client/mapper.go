@@ -3,6 +3,7 @@ package client
 import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/truora/minidyn/core"
 	"github.com/truora/minidyn/types"
 )
 
@@ -28,6 +29,21 @@ func mapCreateTableInputToTypes(input *dynamodb.CreateTableInput) *types.CreateT
 		KeySchema: mapKeySchemaToTypes(input.KeySchema),
 	}
 
+	if input.StreamSpecification != nil {
+		createTableInput.StreamSpecification = &types.StreamSpecification{
+			StreamEnabled:  input.StreamSpecification.StreamEnabled,
+			StreamViewType: input.StreamSpecification.StreamViewType,
+		}
+	}
+
+	if input.SSESpecification != nil {
+		createTableInput.SSESpecification = &types.SSESpecification{
+			Enabled:        input.SSESpecification.Enabled,
+			KMSMasterKeyID: input.SSESpecification.KMSMasterKeyId,
+			SSEType:        input.SSESpecification.SSEType,
+		}
+	}
+
 	if input.ProvisionedThroughput != nil {
 		createTableInput.ProvisionedThroughput = &types.ProvisionedThroughput{
 			ReadCapacityUnits:  aws.Int64Value(input.ProvisionedThroughput.ReadCapacityUnits),
@@ -35,9 +51,31 @@ func mapCreateTableInputToTypes(input *dynamodb.CreateTableInput) *types.CreateT
 		}
 	}
 
+	createTableInput.Tags = mapTagsToTypes(input.Tags)
+
 	return createTableInput
 }
 
+func mapTagsToTypes(tags []*dynamodb.Tag) []*types.Tag {
+	output := make([]*types.Tag, 0, len(tags))
+
+	for _, tag := range tags {
+		output = append(output, &types.Tag{Key: tag.Key, Value: tag.Value})
+	}
+
+	return output
+}
+
+func mapTagsToDynamodb(tags []*types.Tag) []*dynamodb.Tag {
+	output := make([]*dynamodb.Tag, 0, len(tags))
+
+	for _, tag := range tags {
+		output = append(output, &dynamodb.Tag{Key: tag.Key, Value: tag.Value})
+	}
+
+	return output
+}
+
 func mapKeySchemaToTypes(ks []*dynamodb.KeySchemaElement) []*types.KeySchemaElement {
 	keySchema := make([]*types.KeySchemaElement, len(ks))
 	for i, ks := range ks {
@@ -132,7 +170,12 @@ func mapGlobalSecondaryIndexDescriptionToDynamodb(input []types.GlobalSecondaryI
 				NonKeyAttributes: gs.Projection.NonKeyAttributes,
 				ProjectionType:   gs.Projection.ProjectionType,
 			},
-			KeySchema: mapKeySchemaToDynamodb(gs.KeySchema),
+			KeySchema:             mapKeySchemaToDynamodb(gs.KeySchema),
+			IndexArn:              gs.IndexArn,
+			IndexSizeBytes:        gs.IndexSizeBytes,
+			IndexStatus:           gs.IndexStatus,
+			ItemCount:             aws.Int64(gs.ItemCount),
+			ProvisionedThroughput: mapProvisionedThroughputDescriptionToDynamodb(gs.ProvisionedThroughput),
 		}
 	}
 
@@ -148,7 +191,10 @@ func mapLocalSecondaryIndexDescriptionToDynamodb(input []types.LocalSecondaryInd
 				NonKeyAttributes: si.Projection.NonKeyAttributes,
 				ProjectionType:   si.Projection.ProjectionType,
 			},
-			KeySchema: mapKeySchemaToDynamodb(si.KeySchema),
+			KeySchema:      mapKeySchemaToDynamodb(si.KeySchema),
+			IndexArn:       si.IndexArn,
+			IndexSizeBytes: si.IndexSizeBytes,
+			ItemCount:      aws.Int64(si.ItemCount),
 		}
 	}
 
@@ -158,15 +204,257 @@ func mapLocalSecondaryIndexDescriptionToDynamodb(input []types.LocalSecondaryInd
 func mapTableDescriptionToDynamodb(td *types.TableDescription) *dynamodb.TableDescription {
 	tableDescription := &dynamodb.TableDescription{
 		TableName:              aws.String(td.TableName),
+		TableStatus:            aws.String(td.TableStatus),
+		TableArn:               aws.String(td.TableArn),
+		TableId:                aws.String(td.TableID),
+		CreationDateTime:       aws.Time(td.CreationDateTime),
+		AttributeDefinitions:   mapAttributeDefinitionToDynamodb(td.AttributeDefinitions),
+		ProvisionedThroughput:  mapProvisionedThroughputDescriptionToDynamodb(td.ProvisionedThroughput),
 		ItemCount:              aws.Int64(td.ItemCount),
+		TableSizeBytes:         aws.Int64(td.TableSizeBytes),
 		KeySchema:              mapKeySchemaToDynamodb(td.KeySchema),
 		GlobalSecondaryIndexes: mapGlobalSecondaryIndexDescriptionToDynamodb(td.GlobalSecondaryIndexes),
 		LocalSecondaryIndexes:  mapLocalSecondaryIndexDescriptionToDynamodb(td.LocalSecondaryIndexes),
 	}
 
+	if td.BillingModeSummary != nil {
+		tableDescription.BillingModeSummary = &dynamodb.BillingModeSummary{
+			BillingMode:                       td.BillingModeSummary.BillingMode,
+			LastUpdateToPayPerRequestDateTime: td.BillingModeSummary.LastUpdateToPayPerRequestDateTime,
+		}
+	}
+
+	if td.StreamSpecification != nil {
+		tableDescription.StreamSpecification = &dynamodb.StreamSpecification{
+			StreamEnabled:  td.StreamSpecification.StreamEnabled,
+			StreamViewType: td.StreamSpecification.StreamViewType,
+		}
+	}
+
+	if td.LatestStreamLabel != "" {
+		tableDescription.LatestStreamLabel = aws.String(td.LatestStreamLabel)
+		tableDescription.LatestStreamArn = aws.String(td.LatestStreamArn)
+	}
+
+	if td.SSEDescription != nil {
+		tableDescription.SSEDescription = &dynamodb.SSEDescription{
+			KMSMasterKeyArn: td.SSEDescription.KMSMasterKeyArn,
+			SSEType:         td.SSEDescription.SSEType,
+			Status:          td.SSEDescription.Status,
+		}
+	}
+
+	if td.RestoreSummary != nil {
+		tableDescription.RestoreSummary = &dynamodb.RestoreSummary{
+			RestoreDateTime:   aws.Time(td.RestoreSummary.RestoreDateTime),
+			RestoreInProgress: aws.Bool(td.RestoreSummary.RestoreInProgress),
+			SourceBackupArn:   td.RestoreSummary.SourceBackupArn,
+			SourceTableArn:    td.RestoreSummary.SourceTableArn,
+		}
+	}
+
+	// the table class and the deletion protection are not modeled by this version of the SDK
 	return tableDescription
 }
 
+func mapRestoreTableFromBackupInputToTypes(input *dynamodb.RestoreTableFromBackupInput) *types.RestoreTableFromBackupInput {
+	output := &types.RestoreTableFromBackupInput{
+		BackupArn:                     input.BackupArn,
+		TargetTableName:               input.TargetTableName,
+		BillingModeOverride:           input.BillingModeOverride,
+		GlobalSecondaryIndexOverride:  mapGlobalSecondaryIndexesToTypes(input.GlobalSecondaryIndexOverride),
+		LocalSecondaryIndexOverride:   mapLocalSecondaryIndexesToTypes(input.LocalSecondaryIndexOverride),
+		ProvisionedThroughputOverride: mapProvisionedThroughputToTypes(input.ProvisionedThroughputOverride),
+	}
+
+	if input.SSESpecificationOverride != nil {
+		output.SSESpecificationOverride = &types.SSESpecification{
+			Enabled:        input.SSESpecificationOverride.Enabled,
+			KMSMasterKeyID: input.SSESpecificationOverride.KMSMasterKeyId,
+			SSEType:        input.SSESpecificationOverride.SSEType,
+		}
+	}
+
+	return output
+}
+
+func mapRestoreTableToPointInTimeInputToTypes(input *dynamodb.RestoreTableToPointInTimeInput) *types.RestoreTableToPointInTimeInput {
+	output := &types.RestoreTableToPointInTimeInput{
+		SourceTableArn:                input.SourceTableArn,
+		SourceTableName:               input.SourceTableName,
+		TargetTableName:               input.TargetTableName,
+		RestoreDateTime:               input.RestoreDateTime,
+		UseLatestRestorableTime:       input.UseLatestRestorableTime,
+		BillingModeOverride:           input.BillingModeOverride,
+		GlobalSecondaryIndexOverride:  mapGlobalSecondaryIndexesToTypes(input.GlobalSecondaryIndexOverride),
+		LocalSecondaryIndexOverride:   mapLocalSecondaryIndexesToTypes(input.LocalSecondaryIndexOverride),
+		ProvisionedThroughputOverride: mapProvisionedThroughputToTypes(input.ProvisionedThroughputOverride),
+	}
+
+	if input.SSESpecificationOverride != nil {
+		output.SSESpecificationOverride = &types.SSESpecification{
+			Enabled:        input.SSESpecificationOverride.Enabled,
+			KMSMasterKeyID: input.SSESpecificationOverride.KMSMasterKeyId,
+			SSEType:        input.SSESpecificationOverride.SSEType,
+		}
+	}
+
+	return output
+}
+
+func mapContinuousBackupsDescriptionToDynamodb(input *types.ContinuousBackupsDescription) *dynamodb.ContinuousBackupsDescription {
+	pitr := input.PointInTimeRecoveryDescription
+
+	return &dynamodb.ContinuousBackupsDescription{
+		ContinuousBackupsStatus: input.ContinuousBackupsStatus,
+		PointInTimeRecoveryDescription: &dynamodb.PointInTimeRecoveryDescription{
+			EarliestRestorableDateTime: pitr.EarliestRestorableDateTime,
+			LatestRestorableDateTime:   pitr.LatestRestorableDateTime,
+			PointInTimeRecoveryStatus:  pitr.PointInTimeRecoveryStatus,
+		},
+	}
+}
+
+func mapExportTableToPointInTimeInputToTypes(input *dynamodb.ExportTableToPointInTimeInput) *types.ExportTableToPointInTimeInput {
+	return &types.ExportTableToPointInTimeInput{
+		TableArn:       input.TableArn,
+		S3Bucket:       input.S3Bucket,
+		S3BucketOwner:  input.S3BucketOwner,
+		S3Prefix:       input.S3Prefix,
+		S3SseAlgorithm: input.S3SseAlgorithm,
+		S3SseKmsKeyID:  input.S3SseKmsKeyId,
+		ExportFormat:   input.ExportFormat,
+		ExportTime:     input.ExportTime,
+		ClientToken:    input.ClientToken,
+	}
+}
+
+func mapExportDescriptionToDynamodb(export *core.Export) *dynamodb.ExportDescription {
+	return &dynamodb.ExportDescription{
+		ExportArn:       aws.String(export.Arn),
+		ExportStatus:    aws.String(export.Status),
+		ExportFormat:    aws.String(export.Format),
+		ExportManifest:  aws.String(export.ManifestKey),
+		ExportTime:      aws.Time(export.ExportTime),
+		StartTime:       aws.Time(export.StartTime),
+		EndTime:         aws.Time(export.EndTime),
+		TableArn:        aws.String(export.TableArn),
+		TableId:         aws.String(export.TableID),
+		S3Bucket:        aws.String(export.S3Bucket),
+		S3BucketOwner:   toString(export.S3BucketOwner),
+		S3Prefix:        toString(export.S3Prefix),
+		S3SseAlgorithm:  aws.String(export.S3SseAlgorithm),
+		S3SseKmsKeyId:   toString(export.S3SseKmsKeyID),
+		ClientToken:     toString(export.ClientToken),
+		ItemCount:       aws.Int64(export.ItemCount),
+		BilledSizeBytes: aws.Int64(export.BilledSizeBytes),
+	}
+}
+
+func mapBackupDetailsToDynamodb(backup *core.Backup) *dynamodb.BackupDetails {
+	return &dynamodb.BackupDetails{
+		BackupArn:              aws.String(backup.Arn),
+		BackupName:             aws.String(backup.Name),
+		BackupStatus:           aws.String(backup.Status),
+		BackupType:             aws.String(backup.Type),
+		BackupCreationDateTime: aws.Time(backup.CreationDateTime),
+		BackupSizeBytes:        aws.Int64(backup.Source.TableSizeBytes),
+	}
+}
+
+func mapBackupSummaryToDynamodb(backup *core.Backup) *dynamodb.BackupSummary {
+	return &dynamodb.BackupSummary{
+		BackupArn:              aws.String(backup.Arn),
+		BackupName:             aws.String(backup.Name),
+		BackupStatus:           aws.String(backup.Status),
+		BackupType:             aws.String(backup.Type),
+		BackupCreationDateTime: aws.Time(backup.CreationDateTime),
+		BackupSizeBytes:        aws.Int64(backup.Source.TableSizeBytes),
+		TableArn:               aws.String(backup.Source.TableArn),
+		TableId:                aws.String(backup.Source.TableID),
+		TableName:              aws.String(backup.Source.TableName),
+	}
+}
+
+func mapBackupDescriptionToDynamodb(backup *core.Backup) *dynamodb.BackupDescription {
+	source := mapTableDescriptionToDynamodb(backup.Source)
+
+	output := &dynamodb.BackupDescription{
+		BackupDetails: mapBackupDetailsToDynamodb(backup),
+		SourceTableDetails: &dynamodb.SourceTableDetails{
+			TableName:             source.TableName,
+			TableArn:              source.TableArn,
+			TableId:               source.TableId,
+			TableCreationDateTime: source.CreationDateTime,
+			TableSizeBytes:        source.TableSizeBytes,
+			ItemCount:             source.ItemCount,
+			KeySchema:             source.KeySchema,
+			BillingMode:           source.BillingModeSummary.BillingMode,
+			ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+				ReadCapacityUnits:  source.ProvisionedThroughput.ReadCapacityUnits,
+				WriteCapacityUnits: source.ProvisionedThroughput.WriteCapacityUnits,
+			},
+		},
+		SourceTableFeatureDetails: &dynamodb.SourceTableFeatureDetails{
+			SSEDescription:    source.SSEDescription,
+			StreamDescription: source.StreamSpecification,
+		},
+	}
+
+	for _, gsi := range source.GlobalSecondaryIndexes {
+		output.SourceTableFeatureDetails.GlobalSecondaryIndexes = append(output.SourceTableFeatureDetails.GlobalSecondaryIndexes, &dynamodb.GlobalSecondaryIndexInfo{
+			IndexName:  gsi.IndexName,
+			KeySchema:  gsi.KeySchema,
+			Projection: gsi.Projection,
+			ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+				ReadCapacityUnits:  gsi.ProvisionedThroughput.ReadCapacityUnits,
+				WriteCapacityUnits: gsi.ProvisionedThroughput.WriteCapacityUnits,
+			},
+		})
+	}
+
+	for _, lsi := range source.LocalSecondaryIndexes {
+		output.SourceTableFeatureDetails.LocalSecondaryIndexes = append(output.SourceTableFeatureDetails.LocalSecondaryIndexes, &dynamodb.LocalSecondaryIndexInfo{
+			IndexName:  lsi.IndexName,
+			KeySchema:  lsi.KeySchema,
+			Projection: lsi.Projection,
+		})
+	}
+
+	return output
+}
+
+func mapAttributeDefinitionToDynamodb(attrs []*types.AttributeDefinition) []*dynamodb.AttributeDefinition {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	attributeDefinitions := make([]*dynamodb.AttributeDefinition, len(attrs))
+
+	for i, attr := range attrs {
+		attributeDefinitions[i] = &dynamodb.AttributeDefinition{
+			AttributeName: attr.AttributeName,
+			AttributeType: attr.AttributeType,
+		}
+	}
+
+	return attributeDefinitions
+}
+
+func mapProvisionedThroughputDescriptionToDynamodb(pt *types.ProvisionedThroughputDescription) *dynamodb.ProvisionedThroughputDescription {
+	if pt == nil {
+		return nil
+	}
+
+	return &dynamodb.ProvisionedThroughputDescription{
+		ReadCapacityUnits:      aws.Int64(pt.ReadCapacityUnits),
+		WriteCapacityUnits:     aws.Int64(pt.WriteCapacityUnits),
+		NumberOfDecreasesToday: aws.Int64(pt.NumberOfDecreasesToday),
+		LastIncreaseDateTime:   pt.LastIncreaseDateTime,
+		LastDecreaseDateTime:   pt.LastDecreaseDateTime,
+	}
+}
+
 func mapAttributeValueDefinitionToDynamodb(attrs []*dynamodb.AttributeDefinition) []*types.AttributeDefinition {
 	if attrs == nil {
 		return nil
@@ -184,6 +472,35 @@ func mapAttributeValueDefinitionToDynamodb(attrs []*dynamodb.AttributeDefinition
 	return attributeDefinitions
 }
 
+func mapUpdateTableInputToTypes(input *dynamodb.UpdateTableInput) *types.UpdateTableInput {
+	output := &types.UpdateTableInput{
+		AttributeDefinitions:  mapAttributeValueDefinitionToDynamodb(input.AttributeDefinitions),
+		BillingMode:           input.BillingMode,
+		ProvisionedThroughput: mapProvisionedThroughputToTypes(input.ProvisionedThroughput),
+	}
+
+	for _, change := range input.GlobalSecondaryIndexUpdates {
+		output.GlobalSecondaryIndexUpdates = append(output.GlobalSecondaryIndexUpdates, mapGlobalSecondaryIndexUpdateToTypes(change))
+	}
+
+	if input.StreamSpecification != nil {
+		output.StreamSpecification = &types.StreamSpecification{
+			StreamEnabled:  input.StreamSpecification.StreamEnabled,
+			StreamViewType: input.StreamSpecification.StreamViewType,
+		}
+	}
+
+	if input.SSESpecification != nil {
+		output.SSESpecification = &types.SSESpecification{
+			Enabled:        input.SSESpecification.Enabled,
+			KMSMasterKeyID: input.SSESpecification.KMSMasterKeyId,
+			SSEType:        input.SSESpecification.SSEType,
+		}
+	}
+
+	return output
+}
+
 func mapGlobalSecondaryIndexUpdateToTypes(gsiUpdate *dynamodb.GlobalSecondaryIndexUpdate) *types.GlobalSecondaryIndexUpdate {
 	if gsiUpdate == nil {
 		return nil
@@ -225,6 +542,7 @@ func mapDeleteItemInputToTypes(input *dynamodb.DeleteItemInput) *types.DeleteIte
 		TableName:                   input.TableName,
 		ConditionExpression:         input.ConditionExpression,
 		ConditionalOperator:         input.ConditionalOperator,
+		Expected:                    mapExpectedAttributeValueToTypes(input.Expected),
 		ReturnValues:                input.ReturnValues,
 		ExpressionAttributeNames:    input.ExpressionAttributeNames,
 		ReturnConsumedCapacity:      input.ReturnConsumedCapacity,
@@ -245,6 +563,7 @@ func mapPutItemInputToTypes(input *dynamodb.PutItemInput) *types.PutItemInput {
 		TableName:                   input.TableName,
 		ConditionExpression:         input.ConditionExpression,
 		ConditionalOperator:         input.ConditionalOperator,
+		Expected:                    mapExpectedAttributeValueToTypes(input.Expected),
 		ReturnValues:                input.ReturnValues,
 		ExpressionAttributeNames:    aws.StringValueMap(input.ExpressionAttributeNames),
 		ReturnConsumedCapacity:      input.ReturnConsumedCapacity,
@@ -259,8 +578,10 @@ func mapPutItemInputToTypes(input *dynamodb.PutItemInput) *types.PutItemInput {
 func mapUpdateItemInputToTypes(input *dynamodb.UpdateItemInput) *types.UpdateItemInput {
 	updateInput := &types.UpdateItemInput{
 		TableName:                   input.TableName,
+		AttributeUpdates:            mapAttributeValueUpdateToTypes(input.AttributeUpdates),
 		ConditionExpression:         input.ConditionExpression,
 		ConditionalOperator:         input.ConditionalOperator,
+		Expected:                    mapExpectedAttributeValueToTypes(input.Expected),
 		ReturnValues:                input.ReturnValues,
 		ExpressionAttributeNames:    aws.StringValueMap(input.ExpressionAttributeNames),
 		ReturnConsumedCapacity:      input.ReturnConsumedCapacity,
@@ -273,6 +594,79 @@ func mapUpdateItemInputToTypes(input *dynamodb.UpdateItemInput) *types.UpdateIte
 	return updateInput
 }
 
+func mapExpectedAttributeValueToTypes(expected map[string]*dynamodb.ExpectedAttributeValue) map[string]*types.ExpectedAttributeValue {
+	if len(expected) == 0 {
+		return nil
+	}
+
+	output := make(map[string]*types.ExpectedAttributeValue, len(expected))
+
+	for key, val := range expected {
+		if val == nil {
+			continue
+		}
+
+		output[key] = &types.ExpectedAttributeValue{
+			AttributeValueList: mapAttributeValueListToTypes(val.AttributeValueList),
+			ComparisonOperator: val.ComparisonOperator,
+			Exists:             val.Exists,
+			Value:              mapAttributeValueItemToTypes(val.Value),
+		}
+	}
+
+	return output
+}
+
+func mapConditionToTypes(conditions map[string]*dynamodb.Condition) map[string]*types.Condition {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	output := make(map[string]*types.Condition, len(conditions))
+
+	for key, cond := range conditions {
+		if cond == nil {
+			continue
+		}
+
+		output[key] = &types.Condition{
+			AttributeValueList: mapAttributeValueListToTypes(cond.AttributeValueList),
+			ComparisonOperator: cond.ComparisonOperator,
+		}
+	}
+
+	return output
+}
+
+func mapAttributeValueUpdateToTypes(updates map[string]*dynamodb.AttributeValueUpdate) map[string]*types.AttributeValueUpdate {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	output := make(map[string]*types.AttributeValueUpdate, len(updates))
+
+	for key, update := range updates {
+		if update == nil {
+			continue
+		}
+
+		output[key] = &types.AttributeValueUpdate{
+			Action: update.Action,
+			Value:  mapAttributeValueItemToTypes(update.Value),
+		}
+	}
+
+	return output
+}
+
+func mapAttributeValueItemToTypes(attr *dynamodb.AttributeValue) *types.Item {
+	if attr == nil {
+		return nil
+	}
+
+	return mapAttributeValueListToTypes([]*dynamodb.AttributeValue{attr})[0]
+}
+
 func mapAttributeValueToTypes(attrs map[string]*dynamodb.AttributeValue) map[string]*types.Item {
 	if attrs == nil {
 		return nil
@@ -286,16 +680,16 @@ func mapAttributeValueToTypes(attrs map[string]*dynamodb.AttributeValue) map[str
 		}
 
 		mapItems[key] = &types.Item{
-			B:    attr.B,
-			BOOL: attr.BOOL,
-			BS:   attr.BS,
+			B:    types.CopyBytes(attr.B),
+			BOOL: types.CopyBool(attr.BOOL),
+			BS:   types.CopyBytesSlice(attr.BS),
 			L:    mapAttributeValueListToTypes(attr.L),
 			M:    mapAttributeValueToTypes(attr.M),
-			N:    attr.N,
-			NS:   attr.NS,
-			NULL: attr.NULL,
-			S:    attr.S,
-			SS:   attr.SS,
+			N:    types.CopyString(attr.N),
+			NS:   types.CopyStringSlice(attr.NS),
+			NULL: types.CopyBool(attr.NULL),
+			S:    types.CopyString(attr.S),
+			SS:   types.CopyStringSlice(attr.SS),
 		}
 	}
 
@@ -315,16 +709,16 @@ func mapAttributeValueListToTypes(attrs []*dynamodb.AttributeValue) []*types.Ite
 		}
 
 		mapItems[i] = &types.Item{
-			B:    attr.B,
-			BOOL: attr.BOOL,
-			BS:   attr.BS,
+			B:    types.CopyBytes(attr.B),
+			BOOL: types.CopyBool(attr.BOOL),
+			BS:   types.CopyBytesSlice(attr.BS),
 			L:    mapAttributeValueListToTypes(attr.L),
 			M:    mapAttributeValueToTypes(attr.M),
-			N:    attr.N,
-			NS:   attr.NS,
-			NULL: attr.NULL,
-			S:    attr.S,
-			SS:   attr.SS,
+			N:    types.CopyString(attr.N),
+			NS:   types.CopyStringSlice(attr.NS),
+			NULL: types.CopyBool(attr.NULL),
+			S:    types.CopyString(attr.S),
+			SS:   types.CopyStringSlice(attr.SS),
 		}
 	}
 
@@ -390,3 +784,11 @@ func mapAttributeValueListToDynamodb(attrs []*types.Item) []*dynamodb.AttributeV
 
 	return mapItems
 }
+
+func toString(str string) *string {
+	if str == "" {
+		return nil
+	}
+
+	return aws.String(str)
+}
@@ -2,8 +2,12 @@ package client
 
 import (
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
 )
 
 func TestMapProjectionToTypesNil(t *testing.T) {
@@ -23,3 +27,24 @@ func TestMapPutItemInputToTypesNil(t *testing.T) {
 
 	c.Nil(mapPutItemInputToTypes(nil))
 }
+
+func TestMapProvisionedThroughputDescriptionToDynamodb(t *testing.T) {
+	c := require.New(t)
+
+	c.Nil(mapProvisionedThroughputDescriptionToDynamodb(nil))
+
+	increased := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	output := mapProvisionedThroughputDescriptionToDynamodb(&types.ProvisionedThroughputDescription{
+		ReadCapacityUnits:      5,
+		WriteCapacityUnits:     10,
+		NumberOfDecreasesToday: 1,
+		LastIncreaseDateTime:   &increased,
+	})
+	c.Equal(&dynamodb.ProvisionedThroughputDescription{
+		ReadCapacityUnits:      aws.Int64(5),
+		WriteCapacityUnits:     aws.Int64(10),
+		NumberOfDecreasesToday: aws.Int64(1),
+		LastIncreaseDateTime:   &increased,
+	}, output)
+}
@@ -135,8 +135,8 @@ func ClearTable(client dynamodbiface.DynamoDBAPI, tableName string) error {
 		return err
 	}
 
-	fakeClient.mu.Lock()
-	defer fakeClient.mu.Unlock()
+	table.Lock()
+	defer table.Unlock()
 
 	table.Clear()
 
@@ -189,12 +189,3 @@ func generateAddTableInput(tableName, hashKey, rangeKey string) *dynamodb.Create
 
 	return input
 }
-
-func copyItem(item map[string]*dynamodb.AttributeValue) map[string]*dynamodb.AttributeValue {
-	copy := map[string]*dynamodb.AttributeValue{}
-	for key, val := range item {
-		copy[key] = val
-	}
-
-	return copy
-}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/truora/minidyn/core"
+)
+
+// UpdateContinuousBackups enables or disables the point in time recovery of the table, the table keeps
+// the history of its writes stamped with the client clock while the recovery is enabled
+func (fd *Client) UpdateContinuousBackups(input *dynamodb.UpdateContinuousBackupsInput) (*dynamodb.UpdateContinuousBackupsOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	tableName := aws.StringValue(input.TableName)
+
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	table, ok := fd.tables[tableName]
+	if !ok {
+		return nil, awserr.New(dynamodb.ErrCodeTableNotFoundException, "Table not found: "+tableName, nil)
+	}
+
+	table.Lock()
+	defer table.Unlock()
+
+	if err := table.ValidateAvailable(); err != nil {
+		return nil, awserr.New(dynamodb.ErrCodeTableNotFoundException, "Table not found: "+tableName, nil)
+	}
+
+	if err := table.UpdateContinuousBackups(input.PointInTimeRecoverySpecification.PointInTimeRecoveryEnabled); err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.UpdateContinuousBackupsOutput{
+		ContinuousBackupsDescription: mapContinuousBackupsDescriptionToDynamodb(table.ContinuousBackupsDescription()),
+	}, nil
+}
+
+// UpdateContinuousBackupsWithContext enables or disables the point in time recovery of the table
+func (fd *Client) UpdateContinuousBackupsWithContext(ctx aws.Context, input *dynamodb.UpdateContinuousBackupsInput, opts ...request.Option) (*dynamodb.UpdateContinuousBackupsOutput, error) {
+	return fd.UpdateContinuousBackups(input)
+}
+
+// DescribeContinuousBackups returns the point in time recovery status of the table and its restorable window
+func (fd *Client) DescribeContinuousBackups(input *dynamodb.DescribeContinuousBackupsInput) (*dynamodb.DescribeContinuousBackupsOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	tableName := aws.StringValue(input.TableName)
+
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	table, ok := fd.tables[tableName]
+	if !ok {
+		return nil, awserr.New(dynamodb.ErrCodeTableNotFoundException, "Table not found: "+tableName, nil)
+	}
+
+	table.RLock()
+	defer table.RUnlock()
+
+	return &dynamodb.DescribeContinuousBackupsOutput{
+		ContinuousBackupsDescription: mapContinuousBackupsDescriptionToDynamodb(table.ContinuousBackupsDescription()),
+	}, nil
+}
+
+// DescribeContinuousBackupsWithContext returns the point in time recovery status of the table
+func (fd *Client) DescribeContinuousBackupsWithContext(ctx aws.Context, input *dynamodb.DescribeContinuousBackupsInput, opts ...request.Option) (*dynamodb.DescribeContinuousBackupsOutput, error) {
+	return fd.DescribeContinuousBackups(input)
+}
+
+// RestoreTableToPointInTime creates a new table with the items of the source table at the restore time,
+// the restored table has the current definition, settings and indexes of the source table and the
+// overrides of the input replace its billing mode, throughput, encryption and indexes
+func (fd *Client) RestoreTableToPointInTime(input *dynamodb.RestoreTableToPointInTimeInput) (*dynamodb.RestoreTableToPointInTimeOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	sourceName := aws.StringValue(input.SourceTableName)
+	if sourceName == "" {
+		sourceName = core.TableNameFromArn(aws.StringValue(input.SourceTableArn))
+	}
+
+	tableName := aws.StringValue(input.TargetTableName)
+
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	source, ok := fd.tables[sourceName]
+	if !ok {
+		return nil, awserr.New(dynamodb.ErrCodeTableNotFoundException, "Table not found: "+sourceName, nil)
+	}
+
+	if _, ok := fd.tables[tableName]; ok {
+		return nil, awserr.New(dynamodb.ErrCodeTableAlreadyExistsException, "Table already exists: "+tableName, nil)
+	}
+
+	source.RLock()
+	defer source.RUnlock()
+
+	table, err := source.RestoreToPointInTime(mapRestoreTableToPointInTimeInputToTypes(input))
+	if err != nil {
+		return nil, err
+	}
+
+	fd.setupTable(table)
+	fd.tables[tableName] = table
+
+	return &dynamodb.RestoreTableToPointInTimeOutput{
+		TableDescription: mapTableDescriptionToDynamodb(table.Description(tableName)),
+	}, nil
+}
+
+// RestoreTableToPointInTimeWithContext creates a new table with the items of the source table at the restore time
+func (fd *Client) RestoreTableToPointInTimeWithContext(ctx aws.Context, input *dynamodb.RestoreTableToPointInTimeInput, opts ...request.Option) (*dynamodb.RestoreTableToPointInTimeOutput, error) {
+	return fd.RestoreTableToPointInTime(input)
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/truora/minidyn/core"
+)
+
+// TagResource adds the tags to the table of the ARN, the tags with an existing key replace its value
+func (fd *Client) TagResource(input *dynamodb.TagResourceInput) (*dynamodb.TagResourceOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	table, err := fd.getTaggedTable(aws.StringValue(input.ResourceArn))
+	if err != nil {
+		return nil, err
+	}
+
+	table.Lock()
+	defer table.Unlock()
+
+	if err := table.TagResource(mapTagsToTypes(input.Tags)); err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.TagResourceOutput{}, nil
+}
+
+// TagResourceWithContext adds the tags to the table of the ARN
+func (fd *Client) TagResourceWithContext(ctx aws.Context, input *dynamodb.TagResourceInput, opts ...request.Option) (*dynamodb.TagResourceOutput, error) {
+	return fd.TagResource(input)
+}
+
+// UntagResource removes the tags with the keys from the table of the ARN
+func (fd *Client) UntagResource(input *dynamodb.UntagResourceInput) (*dynamodb.UntagResourceOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	table, err := fd.getTaggedTable(aws.StringValue(input.ResourceArn))
+	if err != nil {
+		return nil, err
+	}
+
+	table.Lock()
+	defer table.Unlock()
+
+	if err := table.UntagResource(input.TagKeys); err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.UntagResourceOutput{}, nil
+}
+
+// UntagResourceWithContext removes the tags with the keys from the table of the ARN
+func (fd *Client) UntagResourceWithContext(ctx aws.Context, input *dynamodb.UntagResourceInput, opts ...request.Option) (*dynamodb.UntagResourceOutput, error) {
+	return fd.UntagResource(input)
+}
+
+// ListTagsOfResource returns a page of the tags of the table of the ARN sorted by key, the NextToken
+// of the output is set when there are more tags
+func (fd *Client) ListTagsOfResource(input *dynamodb.ListTagsOfResourceInput) (*dynamodb.ListTagsOfResourceOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	table, err := fd.getTaggedTable(aws.StringValue(input.ResourceArn))
+	if err != nil {
+		return nil, err
+	}
+
+	table.RLock()
+	defer table.RUnlock()
+
+	tags, last := table.ListTags(aws.StringValue(input.NextToken))
+
+	output := &dynamodb.ListTagsOfResourceOutput{
+		Tags: mapTagsToDynamodb(tags),
+	}
+
+	if last != "" {
+		output.NextToken = aws.String(last)
+	}
+
+	return output, nil
+}
+
+// ListTagsOfResourceWithContext returns a page of the tags of the table of the ARN
+func (fd *Client) ListTagsOfResourceWithContext(ctx aws.Context, input *dynamodb.ListTagsOfResourceInput, opts ...request.Option) (*dynamodb.ListTagsOfResourceOutput, error) {
+	return fd.ListTagsOfResource(input)
+}
+
+// getTaggedTable looks up the table of the ARN, the tags are stored by the tables
+func (fd *Client) getTaggedTable(arn string) (*core.Table, error) {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	table, ok := fd.tables[core.TableNameFromArn(arn)]
+	if !ok || table.Arn() != arn {
+		return nil, awserr.New(dynamodb.ErrCodeResourceNotFoundException, "Requested resource not found: ResourcArn: "+arn+" not found", nil)
+	}
+
+	return table, nil
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/truora/minidyn/core"
+)
+
+// CreateBackup creates an on-demand backup with a copy of the items, indexes and settings of the table
+func (fd *Client) CreateBackup(ctx context.Context, input *dynamodb.CreateBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.CreateBackupOutput, error) {
+	tableName := aws.ToString(input.TableName)
+
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	table, ok := fd.tables[tableName]
+	if !ok {
+		return nil, &types.TableNotFoundException{Message: aws.String("Table not found: " + tableName)}
+	}
+
+	table.RLock()
+	defer table.RUnlock()
+
+	if err := table.ValidateAvailable(); err != nil {
+		return nil, &types.TableNotFoundException{Message: aws.String("Table not found: " + tableName)}
+	}
+
+	backup, err := core.NewBackup(table, aws.ToString(input.BackupName))
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	fd.backups[backup.Arn] = backup
+
+	return &dynamodb.CreateBackupOutput{
+		BackupDetails: mapCoreToDynamoBackupDetails(backup),
+	}, nil
+}
+
+// DescribeBackup returns the details of the backup and of the table when the backup was created
+func (fd *Client) DescribeBackup(ctx context.Context, input *dynamodb.DescribeBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeBackupOutput, error) {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	backup, err := fd.getBackupLocked(aws.ToString(input.BackupArn))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.DescribeBackupOutput{
+		BackupDescription: mapCoreToDynamoBackupDescription(backup),
+	}, nil
+}
+
+// DeleteBackup deletes the backup, the returned description reports the DELETED status
+func (fd *Client) DeleteBackup(ctx context.Context, input *dynamodb.DeleteBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.DeleteBackupOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	backup, err := fd.getBackupLocked(aws.ToString(input.BackupArn))
+	if err != nil {
+		return nil, err
+	}
+
+	delete(fd.backups, backup.Arn)
+
+	backup.Status = core.BackupStatusDeleted
+
+	return &dynamodb.DeleteBackupOutput{
+		BackupDescription: mapCoreToDynamoBackupDescription(backup),
+	}, nil
+}
+
+// ListBackups returns the backups sorted by creation time, the backups are filtered by table name,
+// creation time and type and the pages are limited by the Limit and start after the ExclusiveStartBackupArn
+func (fd *Client) ListBackups(ctx context.Context, input *dynamodb.ListBackupsInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListBackupsOutput, error) {
+	if input == nil {
+		input = &dynamodb.ListBackupsInput{}
+	}
+
+	filter := core.BackupFilter{
+		TableName: aws.ToString(input.TableName),
+		Type:      string(input.BackupType),
+	}
+
+	if input.TimeRangeLowerBound != nil {
+		filter.From = *input.TimeRangeLowerBound
+	}
+
+	if input.TimeRangeUpperBound != nil {
+		filter.To = *input.TimeRangeUpperBound
+	}
+
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	backups, last, err := core.ListBackups(fd.backups, filter, aws.ToString(input.ExclusiveStartBackupArn), int64(aws.ToInt32(input.Limit)))
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	output := &dynamodb.ListBackupsOutput{
+		BackupSummaries: make([]types.BackupSummary, 0, len(backups)),
+	}
+
+	for _, backup := range backups {
+		output.BackupSummaries = append(output.BackupSummaries, mapCoreToDynamoBackupSummary(backup))
+	}
+
+	if last != "" {
+		output.LastEvaluatedBackupArn = aws.String(last)
+	}
+
+	return output, nil
+}
+
+// RestoreTableFromBackup creates a new table with the items, indexes and settings of the backup,
+// the overrides of the input replace the billing mode, throughput, encryption and indexes of the backup
+func (fd *Client) RestoreTableFromBackup(ctx context.Context, input *dynamodb.RestoreTableFromBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.RestoreTableFromBackupOutput, error) {
+	tableName := aws.ToString(input.TargetTableName)
+
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	backup, err := fd.getBackupLocked(aws.ToString(input.BackupArn))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := fd.tables[tableName]; ok {
+		return nil, &types.TableAlreadyExistsException{Message: aws.String("Table already exists: " + tableName)}
+	}
+
+	table, err := backup.Restore(mapDynamoToTypesRestoreTableFromBackupInput(input))
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	fd.setupTable(table)
+	fd.tables[tableName] = table
+
+	return &dynamodb.RestoreTableFromBackupOutput{
+		TableDescription: mapTypesToDynamoTableDescription(table.Description(tableName)),
+	}, nil
+}
+
+// getBackupLocked looks up the backup, the caller must hold the client lock
+func (fd *Client) getBackupLocked(arn string) (*core.Backup, error) {
+	backup, ok := fd.backups[arn]
+	if !ok {
+		return nil, &types.BackupNotFoundException{Message: aws.String("Backup not found: " + arn)}
+	}
+
+	return backup, nil
+}
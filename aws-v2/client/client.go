@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -22,6 +26,10 @@ const (
 	unusedExpressionAttributeValuesMsg = "Value provided in ExpressionAttributeValues unused in expressions"
 	invalidExpressionAttributeName     = "ExpressionAttributeNames contains invalid key"
 	invalidExpressionAttributeValue    = "ExpressionAttributeValues contains invalid key"
+	// revive:disable-next-line
+	undefinedExpressionAttributeNameMsg = "An expression attribute name used in the document path is not defined; attribute name"
+	// revive:disable-next-line
+	undefinedExpressionAttributeValueMsg = "An expression attribute value used in expression is not defined; attribute value"
 )
 
 var (
@@ -46,38 +54,118 @@ type FakeClient interface {
 	Query(ctx context.Context, input *dynamodb.QueryInput, opt ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
 	Scan(ctx context.Context, input *dynamodb.ScanInput, opt ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
 	BatchWriteItem(ctx context.Context, input *dynamodb.BatchWriteItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	ListTables(ctx context.Context, input *dynamodb.ListTablesInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error)
 	TransactWriteItems(ctx context.Context, input *dynamodb.TransactWriteItemsInput, opts ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+	CreateBackup(ctx context.Context, input *dynamodb.CreateBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.CreateBackupOutput, error)
+	DescribeBackup(ctx context.Context, input *dynamodb.DescribeBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeBackupOutput, error)
+	DeleteBackup(ctx context.Context, input *dynamodb.DeleteBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.DeleteBackupOutput, error)
+	ListBackups(ctx context.Context, input *dynamodb.ListBackupsInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListBackupsOutput, error)
+	RestoreTableFromBackup(ctx context.Context, input *dynamodb.RestoreTableFromBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.RestoreTableFromBackupOutput, error)
+	UpdateContinuousBackups(ctx context.Context, input *dynamodb.UpdateContinuousBackupsInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateContinuousBackupsOutput, error)
+	DescribeContinuousBackups(ctx context.Context, input *dynamodb.DescribeContinuousBackupsInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeContinuousBackupsOutput, error)
+	RestoreTableToPointInTime(ctx context.Context, input *dynamodb.RestoreTableToPointInTimeInput, opts ...func(*dynamodb.Options)) (*dynamodb.RestoreTableToPointInTimeOutput, error)
+	ExportTableToPointInTime(ctx context.Context, input *dynamodb.ExportTableToPointInTimeInput, opts ...func(*dynamodb.Options)) (*dynamodb.ExportTableToPointInTimeOutput, error)
+	DescribeExport(ctx context.Context, input *dynamodb.DescribeExportInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeExportOutput, error)
+	ImportTable(ctx context.Context, input *dynamodb.ImportTableInput, opts ...func(*dynamodb.Options)) (*dynamodb.ImportTableOutput, error)
+	DescribeImport(ctx context.Context, input *dynamodb.DescribeImportInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeImportOutput, error)
+	ListImports(ctx context.Context, input *dynamodb.ListImportsInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListImportsOutput, error)
+	TagResource(ctx context.Context, input *dynamodb.TagResourceInput, opts ...func(*dynamodb.Options)) (*dynamodb.TagResourceOutput, error)
+	UntagResource(ctx context.Context, input *dynamodb.UntagResourceInput, opts ...func(*dynamodb.Options)) (*dynamodb.UntagResourceOutput, error)
+	ListTagsOfResource(ctx context.Context, input *dynamodb.ListTagsOfResourceInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListTagsOfResourceOutput, error)
 }
 
 // Client define a mock struct to be used
 type Client struct {
 	tables                map[string]*core.Table
-	mu                    sync.Mutex
+	backups               map[string]*core.Backup
+	exports               map[string]*core.Export
+	imports               map[string]*core.Import
+	importParameters      map[string]*types.TableCreationParameters
+	mu                    sync.RWMutex
 	itemCollectionMetrics map[string][]types.ItemCollectionMetrics
 	langInterpreter       *interpreter.Language
 	nativeInterpreter     *interpreter.Native
 	useNativeInterpreter  bool
+	mismatchReporter      interpreter.MismatchReporter
+	explainConditions     bool
 	forceFailureErr       error
+	limits                core.Limits
+	mutationDetection     bool
+	tableLifecycle        bool
+	transitionDescribes   int
+	clock                 func() time.Time
+	exportDirectory       string
+	importDirectory       string
+	decompressors         map[string]core.Decompressor
 }
 
 // NewClient initializes dynamodb client with a mock
 func NewClient() *Client {
 	fake := Client{
 		tables:            map[string]*core.Table{},
-		mu:                sync.Mutex{},
+		backups:           map[string]*core.Backup{},
+		exports:           map[string]*core.Export{},
+		imports:           map[string]*core.Import{},
+		importParameters:  map[string]*types.TableCreationParameters{},
+		decompressors:     map[string]core.Decompressor{},
 		nativeInterpreter: interpreter.NewNativeInterpreter(),
 		langInterpreter:   &interpreter.Language{},
+		limits:            core.DefaultLimits,
+		clock:             time.Now,
 	}
 
 	return &fake
 }
 
-// ActivateDebug it activates the debug mode
+// ActivateDebug it activates the debug mode, the evaluated expressions are printed in the standard output
 func (fd *Client) ActivateDebug() {
+	fd.SetTracer(interpreter.NewLogTracer(log.New(os.Stdout, "", 0)))
+}
+
+// ActivateMutationDetection it makes the table operations fail with core.ErrItemMutated when a stored item is mutated outside of them
+func (fd *Client) ActivateMutationDetection() {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.mutationDetection = true
+
+	for _, table := range fd.tables {
+		table.Lock()
+		table.ActivateMutationDetection()
+		table.Unlock()
+	}
+}
+
+// SetTracer sets the tracer that receives the expressions evaluated by the interpreters,
+// a nil tracer disables the tracing
+func (fd *Client) SetTracer(tracer interpreter.Tracer) {
 	fd.mu.Lock()
 	defer fd.mu.Unlock()
 
-	fd.langInterpreter.Debug = true
+	fd.langInterpreter.Tracer = tracer
+	fd.nativeInterpreter.Tracer = tracer
+
+	for _, table := range fd.tables {
+		table.Lock()
+		table.LangInterpreter.Tracer = tracer
+		table.NativeInterpreter.Tracer = tracer
+		table.Unlock()
+	}
+}
+
+// ActivateConditionExplanations it adds the evaluation of the failed condition expressions
+// to the message of the ConditionalCheckFailedException errors
+func (fd *Client) ActivateConditionExplanations() {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.explainConditions = true
+
+	for _, table := range fd.tables {
+		table.Lock()
+		table.ExplainConditions = true
+		table.Unlock()
+	}
 }
 
 // ActivateNativeInterpreter it activates the debug mode
@@ -88,10 +176,53 @@ func (fd *Client) ActivateNativeInterpreter() {
 	fd.useNativeInterpreter = true
 
 	for _, table := range fd.tables {
+		table.Lock()
+		table.UseNativeInterpreter = true
+		table.Unlock()
+	}
+}
+
+// ActivateDifferentialInterpreter it activates the native interpreter and evaluates the expressions
+// with a native override with the language interpreter too, the differences are sent to the reporter
+func (fd *Client) ActivateDifferentialInterpreter(reporter interpreter.MismatchReporter) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.useNativeInterpreter = true
+	fd.mismatchReporter = reporter
+
+	for _, table := range fd.tables {
+		table.Lock()
 		table.UseNativeInterpreter = true
+		table.MismatchReporter = reporter
+		table.Unlock()
 	}
 }
 
+// ActivateTableLifecycle it makes the tables go through the CREATING, UPDATING and DELETING statuses,
+// each status is reported by the given number of DescribeTable calls before the table becomes ACTIVE or is removed
+func (fd *Client) ActivateTableLifecycle(describes int) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.tableLifecycle = true
+	fd.transitionDescribes = describes
+}
+
+func (fd *Client) forcedFailure() error {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	return fd.forceFailureErr
+}
+
+func (fd *Client) getItemCollectionMetrics() map[string][]types.ItemCollectionMetrics {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	return fd.itemCollectionMetrics
+}
+
 func (fd *Client) setFailureCondition(condition FailureCondition) {
 	fd.mu.Lock()
 	defer fd.mu.Unlock()
@@ -106,31 +237,108 @@ func (fd *Client) SetInterpreter(i interpreter.Interpreter) {
 		panic("invalid interpreter type")
 	}
 
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
 	fd.nativeInterpreter = native
 
 	for _, table := range fd.tables {
+		table.Lock()
 		table.NativeInterpreter = *native
+		table.Unlock()
+	}
+}
+
+// SetClock sets the clock of the client and its tables, it stamps the creation of the tables and backups
+// and the history of writes used by the point in time recovery
+func (fd *Client) SetClock(clock func() time.Time) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.clock = clock
+
+	for _, table := range fd.tables {
+		table.Lock()
+		table.Clock = clock
+		table.Unlock()
+	}
+}
+
+// SetExportDirectory sets the local directory that replaces S3 in the exports, the buckets are its subdirectories
+func (fd *Client) SetExportDirectory(dir string) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.exportDirectory = dir
+}
+
+// SetImportDirectory sets the local directory that replaces S3 in the imports, the buckets are its subdirectories
+func (fd *Client) SetImportDirectory(dir string) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.importDirectory = dir
+}
+
+// SetDecompressor sets the decompressor of the imports with the compression type, GZIP is built in
+// and ZSTD requires a decompressor
+func (fd *Client) SetDecompressor(compressionType types.InputCompressionType, decompressor core.Decompressor) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.decompressors[string(compressionType)] = decompressor
+}
+
+// SetLimits overrides the service limits enforced by the client and its tables
+func (fd *Client) SetLimits(limits core.Limits) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.limits = limits
+
+	for _, table := range fd.tables {
+		table.Lock()
+		table.Limits = limits
+		table.Unlock()
 	}
 }
 
 // GetNativeInterpreter returns native interpreter
 func (fd *Client) GetNativeInterpreter() *interpreter.Native {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
 	return fd.nativeInterpreter
 }
 
 // CreateTable creates a new table
 func (fd *Client) CreateTable(ctx context.Context, input *dynamodb.CreateTableInput, opt ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
 	tableName := aws.ToString(input.TableName)
 	if _, ok := fd.tables[tableName]; ok {
 		return nil, &types.ResourceInUseException{Message: aws.String("Cannot create preexisting table")}
 	}
 
-	newTable := core.NewTable(tableName)
+	newTable, err := fd.newTableLocked(input)
+	if err != nil {
+		return nil, err
+	}
+
+	fd.tables[tableName] = newTable
+
+	return &dynamodb.CreateTableOutput{
+		TableDescription: mapTypesToDynamoTableDescription(newTable.Description(tableName)),
+	}, nil
+}
+
+// newTableLocked builds the table of the input without storing it, the caller must hold the client lock
+func (fd *Client) newTableLocked(input *dynamodb.CreateTableInput) (*core.Table, error) {
+	newTable := core.NewTable(aws.ToString(input.TableName))
 	newTable.SetAttributeDefinition(mapDynamoToTypesAttributeDefinitionSlice(input.AttributeDefinitions))
 	newTable.BillingMode = aws.String(string(input.BillingMode))
-	newTable.NativeInterpreter = *fd.nativeInterpreter
-	newTable.UseNativeInterpreter = fd.useNativeInterpreter
-	newTable.LangInterpreter = *fd.langInterpreter
+	fd.setupTable(newTable)
 
 	if err := newTable.CreatePrimaryIndex(mapDynamoToTypesCreateTableInput(input)); err != nil {
 		return nil, mapKnownError(err)
@@ -144,25 +352,39 @@ func (fd *Client) CreateTable(ctx context.Context, input *dynamodb.CreateTableIn
 		return nil, mapKnownError(err)
 	}
 
-	fd.tables[tableName] = newTable
-
-	return &dynamodb.CreateTableOutput{
-		TableDescription: mapTypesToDynamoTableDescription(newTable.Description(tableName)),
-	}, nil
+	return newTable, nil
 }
 
 // DeleteTable deletes a table
 func (fd *Client) DeleteTable(ctx context.Context, input *dynamodb.DeleteTableInput, opt ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error) {
 	tableName := aws.ToString(input.TableName)
 
-	table, err := fd.getTable(tableName)
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	table, err := fd.getTableLocked(tableName)
 	if err != nil {
 		return nil, mapKnownError(err)
 	}
 
-	desc := mapTypesToDynamoTableDescription(table.Description(tableName))
+	table.Lock()
+	defer table.Unlock()
 
-	delete(fd.tables, tableName)
+	if err := table.ValidateNotInUse(); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if err := table.ValidateDeletable(); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if fd.tableLifecycle {
+		table.StartTransition(core.TableStatusDeleting, fd.transitionDescribes)
+	} else {
+		delete(fd.tables, tableName)
+	}
+
+	desc := mapTypesToDynamoTableDescription(table.Description(tableName))
 
 	return &dynamodb.DeleteTableOutput{
 		TableDescription: desc,
@@ -173,21 +395,27 @@ func (fd *Client) DeleteTable(ctx context.Context, input *dynamodb.DeleteTableIn
 func (fd *Client) UpdateTable(ctx context.Context, input *dynamodb.UpdateTableInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error) {
 	tableName := aws.ToString(input.TableName)
 
-	table, ok := fd.tables[tableName]
-	if !ok {
-		return nil, &types.ResourceNotFoundException{Message: aws.String("Cannot do operations on a non-existent table")}
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	table, err := fd.getTableLocked(tableName)
+	if err != nil {
+		return nil, mapKnownError(err)
 	}
 
-	if input.AttributeDefinitions != nil {
-		table.SetAttributeDefinition(mapDynamoToTypesAttributeDefinitionSlice(input.AttributeDefinitions))
+	table.Lock()
+	defer table.Unlock()
+
+	if err := table.ValidateNotInUse(); err != nil {
+		return nil, mapKnownError(err)
 	}
 
-	for _, change := range input.GlobalSecondaryIndexUpdates {
-		if err := table.ApplyIndexChange(mapDynamoTotypesGlobalSecondaryIndexUpdate(change)); err != nil {
-			return &dynamodb.UpdateTableOutput{
-				TableDescription: mapTypesToDynamoTableDescription(table.Description(tableName)),
-			}, mapKnownError(err)
-		}
+	if err := table.ApplyTableUpdate(mapDynamoToTypesUpdateTableInput(input)); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if fd.tableLifecycle {
+		table.StartTransition(core.TableStatusUpdating, fd.transitionDescribes)
 	}
 
 	return &dynamodb.UpdateTableOutput{
@@ -195,15 +423,28 @@ func (fd *Client) UpdateTable(ctx context.Context, input *dynamodb.UpdateTableIn
 	}, nil
 }
 
-// DescribeTable returns information about the table
+// DescribeTable returns information about the table,
+// it completes the table transitions when the table lifecycle is active
 func (fd *Client) DescribeTable(ctx context.Context, input *dynamodb.DescribeTableInput, ops ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
 	tableName := aws.ToString(input.TableName)
 
-	table, err := fd.getTable(tableName)
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	table, err := fd.getTableLocked(tableName)
 	if err != nil {
 		return nil, mapKnownError(err)
 	}
 
+	table.Lock()
+	defer table.Unlock()
+
+	if table.DescribeStatus() {
+		delete(fd.tables, tableName)
+
+		return nil, &types.ResourceNotFoundException{Message: aws.String("Requested resource not found: Table: " + tableName + " not found")}
+	}
+
 	output := &dynamodb.DescribeTableOutput{
 		Table: mapTypesToDynamoTableDescription(table.Description(tableName)),
 	}
@@ -211,25 +452,66 @@ func (fd *Client) DescribeTable(ctx context.Context, input *dynamodb.DescribeTab
 	return output, nil
 }
 
+// ListTables returns the names of the tables sorted alphabetically,
+// the pages are limited by the Limit and start after the ExclusiveStartTableName
+func (fd *Client) ListTables(ctx context.Context, input *dynamodb.ListTablesInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error) {
+	if input == nil {
+		input = &dynamodb.ListTablesInput{}
+	}
+
+	var limit *int64
+	if input.Limit != nil {
+		limit = aws.Int64(int64(*input.Limit))
+	}
+
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	names, last, err := core.ListTableNames(fd.tables, aws.ToString(input.ExclusiveStartTableName), limit)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	output := &dynamodb.ListTablesOutput{
+		TableNames: names,
+	}
+
+	if last != "" {
+		output.LastEvaluatedTableName = aws.String(last)
+	}
+
+	return output, nil
+}
+
 // PutItem mock response for dynamodb
 func (fd *Client) PutItem(ctx context.Context, input *dynamodb.PutItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
-	fd.mu.Lock()
-	defer fd.mu.Unlock()
+	if err := fd.forcedFailure(); err != nil {
+		return nil, err
+	}
 
-	if fd.forceFailureErr != nil {
-		return nil, fd.forceFailureErr
+	err := core.ValidateLegacyParameters(map[string]bool{
+		"Expected": len(input.Expected) > 0,
+	}, map[string]bool{
+		"ConditionExpression":       input.ConditionExpression != nil,
+		"ExpressionAttributeNames":  len(input.ExpressionAttributeNames) > 0,
+		"ExpressionAttributeValues": len(input.ExpressionAttributeValues) > 0,
+	})
+	if err != nil {
+		return nil, mapKnownError(err)
 	}
 
-	err := validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues, aws.ToString(input.ConditionExpression))
+	err = validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues, expression{interpreter.ExpressionTypeConditional, aws.ToString(input.ConditionExpression)})
 	if err != nil {
 		return nil, mapKnownError(err)
 	}
 
-	table, err := fd.getTable(aws.ToString(input.TableName))
+	table, unlock, err := fd.lockAvailableTable(aws.ToString(input.TableName), true)
 	if err != nil {
 		return nil, mapKnownError(err)
 	}
 
+	defer unlock()
+
 	item, err := table.Put(mapDynamoToTypesPutItemInput(input))
 
 	return &dynamodb.PutItemOutput{
@@ -239,35 +521,37 @@ func (fd *Client) PutItem(ctx context.Context, input *dynamodb.PutItemInput, opt
 
 // DeleteItem mock response for dynamodb
 func (fd *Client) DeleteItem(ctx context.Context, input *dynamodb.DeleteItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
-	fd.mu.Lock()
-	defer fd.mu.Unlock()
-
-	if fd.forceFailureErr != nil {
-		return nil, fd.forceFailureErr
+	if err := fd.forcedFailure(); err != nil {
+		return nil, err
 	}
 
-	err := validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues, aws.ToString(input.ConditionExpression))
+	err := validateDeleteItemExpressions(input)
 	if err != nil {
 		return nil, mapKnownError(err)
 	}
 
-	table, err := fd.getTable(aws.ToString(input.TableName))
+	table, unlock, err := fd.lockAvailableTable(aws.ToString(input.TableName), true)
 	if err != nil {
 		return nil, mapKnownError(err)
 	}
 
+	defer unlock()
+
 	// support conditional writes
-	if input.ConditionExpression != nil {
-		items, _ := table.SearchData(core.QueryInput{
-			Index:                     core.PrimaryIndexName,
-			ExpressionAttributeValues: mapDynamoToTypesMapItem(input.ExpressionAttributeValues),
-			Aliases:                   input.ExpressionAttributeNames,
-			Limit:                     aws.ToInt64(aws.Int64(1)),
-			ConditionExpression:       input.ConditionExpression,
-		})
-		if len(items) == 0 {
-			return &dynamodb.DeleteItemOutput{}, &types.ConditionalCheckFailedException{Message: aws.String(core.ErrConditionalRequestFailed.Error())}
-		}
+	matched, err := matchDeleteCondition(table, input)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if !matched {
+		message := table.ConditionFailedMessage(
+			aws.ToString(input.ConditionExpression),
+			input.ExpressionAttributeNames,
+			mapDynamoToTypesMapItem(input.ExpressionAttributeValues),
+			mapDynamoToTypesMapItem(input.Key),
+		)
+
+		return &dynamodb.DeleteItemOutput{}, &types.ConditionalCheckFailedException{Message: aws.String(message)}
 	}
 
 	item, err := table.Delete(mapDynamoToTypesDeleteItemInput(input))
@@ -284,31 +568,78 @@ func (fd *Client) DeleteItem(ctx context.Context, input *dynamodb.DeleteItemInpu
 	return &dynamodb.DeleteItemOutput{}, nil
 }
 
+// validateDeleteItemExpressions rejects the legacy parameters mixed with expressions and the unused or undefined placeholders
+func validateDeleteItemExpressions(input *dynamodb.DeleteItemInput) error {
+	err := core.ValidateLegacyParameters(map[string]bool{
+		"Expected": len(input.Expected) > 0,
+	}, map[string]bool{
+		"ConditionExpression":       input.ConditionExpression != nil,
+		"ExpressionAttributeNames":  len(input.ExpressionAttributeNames) > 0,
+		"ExpressionAttributeValues": len(input.ExpressionAttributeValues) > 0,
+	})
+	if err != nil {
+		return err
+	}
+
+	return validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues, expression{interpreter.ExpressionTypeConditional, aws.ToString(input.ConditionExpression)})
+}
+
+// matchDeleteCondition reports whether the ConditionExpression of the delete matches, it is true without a condition
+func matchDeleteCondition(table *core.Table, input *dynamodb.DeleteItemInput) (bool, error) {
+	if input.ConditionExpression == nil {
+		return true, nil
+	}
+
+	items, _, err := table.SearchData(core.QueryInput{
+		Index:                     core.PrimaryIndexName,
+		ExpressionAttributeValues: mapDynamoToTypesMapItem(input.ExpressionAttributeValues),
+		Aliases:                   input.ExpressionAttributeNames,
+		Limit:                     aws.ToInt64(aws.Int64(1)),
+		ConditionExpression:       input.ConditionExpression,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(items) > 0, nil
+}
+
 // UpdateItem mock response for dynamodb
 func (fd *Client) UpdateItem(ctx context.Context, input *dynamodb.UpdateItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
-	fd.mu.Lock()
-	defer fd.mu.Unlock()
-
-	if fd.forceFailureErr != nil {
-		return nil, fd.forceFailureErr
+	if err := fd.forcedFailure(); err != nil {
+		return nil, err
+	}
+
+	err := core.ValidateLegacyParameters(map[string]bool{
+		"AttributeUpdates": len(input.AttributeUpdates) > 0,
+		"Expected":         len(input.Expected) > 0,
+	}, map[string]bool{
+		"UpdateExpression":          input.UpdateExpression != nil,
+		"ConditionExpression":       input.ConditionExpression != nil,
+		"ExpressionAttributeNames":  len(input.ExpressionAttributeNames) > 0,
+		"ExpressionAttributeValues": len(input.ExpressionAttributeValues) > 0,
+	})
+	if err != nil {
+		return nil, mapKnownError(err)
 	}
 
-	err := validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues, aws.ToString(input.UpdateExpression), aws.ToString(input.ConditionExpression))
+	err = validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues,
+		expression{interpreter.ExpressionTypeUpdate, aws.ToString(input.UpdateExpression)},
+		expression{interpreter.ExpressionTypeConditional, aws.ToString(input.ConditionExpression)},
+	)
 	if err != nil {
 		return nil, mapKnownError(err)
 	}
 
-	table, err := fd.getTable(aws.ToString(input.TableName))
+	table, unlock, err := fd.lockAvailableTable(aws.ToString(input.TableName), true)
 	if err != nil {
 		return nil, mapKnownError(err)
 	}
 
+	defer unlock()
+
 	item, err := table.Update(mapDynamoToTypesUpdateItemInput(input))
 	if err != nil {
-		if errors.Is(err, interpreter.ErrSyntaxError) {
-			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: err.Error()}
-		}
-
 		return nil, mapKnownError(err)
 	}
 
@@ -321,32 +652,39 @@ func (fd *Client) UpdateItem(ctx context.Context, input *dynamodb.UpdateItemInpu
 
 // GetItem mock response for dynamodb
 func (fd *Client) GetItem(ctx context.Context, input *dynamodb.GetItemInput, opt ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
-	fd.mu.Lock()
-	defer fd.mu.Unlock()
-
-	if fd.forceFailureErr != nil {
-		return nil, fd.forceFailureErr
+	if err := fd.forcedFailure(); err != nil {
+		return nil, err
 	}
 
-	err := validateExpressionAttributes(input.ExpressionAttributeNames, nil, aws.ToString(input.ProjectionExpression))
+	err := core.ValidateLegacyParameters(map[string]bool{
+		"AttributesToGet": len(input.AttributesToGet) > 0,
+	}, map[string]bool{
+		"ProjectionExpression":     input.ProjectionExpression != nil,
+		"ExpressionAttributeNames": len(input.ExpressionAttributeNames) > 0,
+	})
 	if err != nil {
 		return nil, mapKnownError(err)
 	}
 
-	table, err := fd.getTable(aws.ToString(input.TableName))
+	err = validateExpressionAttributes(input.ExpressionAttributeNames, nil, expression{interpreter.ExpressionTypeProjection, aws.ToString(input.ProjectionExpression)})
 	if err != nil {
 		return nil, mapKnownError(err)
 	}
 
-	key, err := table.KeySchema.GetKey(table.AttributesDef, mapDynamoToTypesMapItem(input.Key))
+	table, unlock, err := fd.lockAvailableTable(aws.ToString(input.TableName), false)
 	if err != nil {
-		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: err.Error()}
+		return nil, mapKnownError(err)
 	}
 
-	item := copyItem(mapTypesToDynamoMapItem(table.Data[key]))
+	defer unlock()
+
+	item, err := table.GetItem(mapDynamoToTypesMapItem(input.Key))
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
 
 	output := &dynamodb.GetItemOutput{
-		Item: item,
+		Item: mapTypesToDynamoMapItem(core.ProjectAttributes(item, input.AttributesToGet)),
 	}
 
 	return output, nil
@@ -354,30 +692,58 @@ func (fd *Client) GetItem(ctx context.Context, input *dynamodb.GetItemInput, opt
 
 // Query mock response for dynamodb
 func (fd *Client) Query(ctx context.Context, input *dynamodb.QueryInput, opt ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
-	fd.mu.Lock()
-	defer fd.mu.Unlock()
-
-	if fd.forceFailureErr != nil {
-		return nil, fd.forceFailureErr
+	if err := fd.forcedFailure(); err != nil {
+		return nil, err
+	}
+
+	err := core.ValidateLegacyParameters(map[string]bool{
+		"AttributesToGet": len(input.AttributesToGet) > 0,
+		"KeyConditions":   len(input.KeyConditions) > 0,
+		"QueryFilter":     len(input.QueryFilter) > 0,
+	}, map[string]bool{
+		"KeyConditionExpression":    input.KeyConditionExpression != nil,
+		"FilterExpression":          input.FilterExpression != nil,
+		"ProjectionExpression":      input.ProjectionExpression != nil,
+		"ExpressionAttributeNames":  len(input.ExpressionAttributeNames) > 0,
+		"ExpressionAttributeValues": len(input.ExpressionAttributeValues) > 0,
+	})
+	if err != nil {
+		return nil, mapKnownError(err)
 	}
 
-	err := validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues, aws.ToString(input.KeyConditionExpression), aws.ToString(input.FilterExpression), aws.ToString(input.ProjectionExpression))
+	err = validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues,
+		expression{interpreter.ExpressionTypeKey, aws.ToString(input.KeyConditionExpression)},
+		expression{interpreter.ExpressionTypeFilter, aws.ToString(input.FilterExpression)},
+		expression{interpreter.ExpressionTypeProjection, aws.ToString(input.ProjectionExpression)},
+	)
 	if err != nil {
 		return nil, mapKnownError(err)
 	}
 
-	table, err := fd.getTable(aws.ToString(input.TableName))
+	table, unlock, err := fd.lockAvailableTable(aws.ToString(input.TableName), false)
 	if err != nil {
 		return nil, mapKnownError(err)
 	}
 
+	defer unlock()
+
 	indexName := aws.ToString(input.IndexName)
 
 	if input.ScanIndexForward == nil {
 		input.ScanIndexForward = aws.Bool(true)
 	}
 
-	items, lastKey := table.SearchData(mapDynamoToTypesQueryInput(input, indexName))
+	query := mapDynamoToTypesQueryInput(input, indexName)
+
+	err = table.ValidateQuery(query)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	items, lastKey, err := table.SearchData(query)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
 
 	count := int64(len(items))
 
@@ -392,26 +758,41 @@ func (fd *Client) Query(ctx context.Context, input *dynamodb.QueryInput, opt ...
 
 // Scan mock scan operation
 func (fd *Client) Scan(ctx context.Context, input *dynamodb.ScanInput, opt ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
-	fd.mu.Lock()
-	defer fd.mu.Unlock()
-
-	if fd.forceFailureErr != nil {
-		return nil, fd.forceFailureErr
+	if err := fd.forcedFailure(); err != nil {
+		return nil, err
+	}
+
+	err := core.ValidateLegacyParameters(map[string]bool{
+		"AttributesToGet": len(input.AttributesToGet) > 0,
+		"ScanFilter":      len(input.ScanFilter) > 0,
+	}, map[string]bool{
+		"FilterExpression":          input.FilterExpression != nil,
+		"ProjectionExpression":      input.ProjectionExpression != nil,
+		"ExpressionAttributeNames":  len(input.ExpressionAttributeNames) > 0,
+		"ExpressionAttributeValues": len(input.ExpressionAttributeValues) > 0,
+	})
+	if err != nil {
+		return nil, mapKnownError(err)
 	}
 
-	err := validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues, aws.ToString(input.ProjectionExpression), aws.ToString(input.FilterExpression))
+	err = validateExpressionAttributes(input.ExpressionAttributeNames, input.ExpressionAttributeValues,
+		expression{interpreter.ExpressionTypeProjection, aws.ToString(input.ProjectionExpression)},
+		expression{interpreter.ExpressionTypeFilter, aws.ToString(input.FilterExpression)},
+	)
 	if err != nil {
 		return nil, mapKnownError(err)
 	}
 
-	table, err := fd.getTable(aws.ToString(input.TableName))
+	table, unlock, err := fd.lockAvailableTable(aws.ToString(input.TableName), false)
 	if err != nil {
 		return nil, mapKnownError(err)
 	}
 
+	defer unlock()
+
 	indexName := aws.ToString(input.IndexName)
 
-	items, lastKey := table.SearchData(core.QueryInput{
+	query := core.QueryInput{
 		Index:                     indexName,
 		ExpressionAttributeValues: mapDynamoToTypesMapItem(input.ExpressionAttributeValues),
 		Aliases:                   input.ExpressionAttributeNames,
@@ -420,7 +801,20 @@ func (fd *Client) Scan(ctx context.Context, input *dynamodb.ScanInput, opt ...fu
 		FilterExpression:          aws.ToString(input.FilterExpression),
 		ScanIndexForward:          true,
 		Scan:                      true,
-	})
+		QueryFilter:               mapDynamoToTypesConditionMap(input.ScanFilter),
+		ConditionalOperator:       string(input.ConditionalOperator),
+		AttributesToGet:           input.AttributesToGet,
+	}
+
+	err = table.ValidateQuery(query)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	items, lastKey, err := table.SearchData(query)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
 
 	count := int64(len(items))
 
@@ -435,6 +829,9 @@ func (fd *Client) Scan(ctx context.Context, input *dynamodb.ScanInput, opt ...fu
 
 // SetItemCollectionMetrics set the value of the property itemCollectionMetrics
 func (fd *Client) setItemCollectionMetrics(itemCollectionMetrics map[string][]types.ItemCollectionMetrics) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
 	fd.itemCollectionMetrics = itemCollectionMetrics
 }
 
@@ -469,7 +866,7 @@ func (fd *Client) BatchWriteItem(ctx context.Context, input *dynamodb.BatchWrite
 
 	return &dynamodb.BatchWriteItemOutput{
 		UnprocessedItems:      unprocessed,
-		ItemCollectionMetrics: fd.itemCollectionMetrics,
+		ItemCollectionMetrics: fd.getItemCollectionMetrics(),
 	}, nil
 }
 
@@ -556,16 +953,83 @@ func handleBatchWriteRequestError(table string, req types.WriteRequest, unproces
 
 // TransactWriteItems mock response for dynamodb
 func (fd *Client) TransactWriteItems(ctx context.Context, input *dynamodb.TransactWriteItemsInput, opts ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
-	if fd.forceFailureErr != nil {
+	fd.mu.RLock()
+	forceFailureErr, limits := fd.forceFailureErr, fd.limits
+	fd.mu.RUnlock()
+
+	if forceFailureErr != nil {
 		return nil, ErrForcedFailure
 	}
 
+	if err := core.ValidateTransactWriteItems(limits, len(input.TransactItems)); err != nil {
+		return nil, mapKnownError(err)
+	}
+
 	//TODO: Implement transact write
 
 	return &dynamodb.TransactWriteItemsOutput{}, nil
 }
 
+// lockAvailableTable looks up a table that serves items and locks it, the tables being created or deleted are not found.
+// The client lock is held until the table is locked so a concurrent DeleteTable can not remove the table in between,
+// the returned function releases the table lock
+func (fd *Client) lockAvailableTable(tableName string, exclusive bool) (*core.Table, func(), error) {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	table, err := fd.getTableLocked(tableName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unlock := table.RUnlock
+
+	if exclusive {
+		table.Lock()
+
+		unlock = table.Unlock
+	} else {
+		table.RLock()
+	}
+
+	if err := table.ValidateAvailable(); err != nil {
+		unlock()
+
+		return nil, nil, err
+	}
+
+	return table, unlock, nil
+}
+
 func (fd *Client) getTable(tableName string) (*core.Table, error) {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	return fd.getTableLocked(tableName)
+}
+
+// setupTable applies the client settings to a new table, the caller must hold the client lock
+func (fd *Client) setupTable(table *core.Table) {
+	table.NativeInterpreter = *fd.nativeInterpreter
+	table.Limits = fd.limits
+	table.UseNativeInterpreter = fd.useNativeInterpreter
+	table.MismatchReporter = fd.mismatchReporter
+	table.ExplainConditions = fd.explainConditions
+	table.LangInterpreter = *fd.langInterpreter
+	table.Clock = fd.clock
+	table.CreationDateTime = fd.clock()
+
+	if fd.mutationDetection {
+		table.ActivateMutationDetection()
+	}
+
+	if fd.tableLifecycle {
+		table.StartTransition(core.TableStatusCreating, fd.transitionDescribes)
+	}
+}
+
+// getTableLocked looks up the table, the caller must hold the client lock
+func (fd *Client) getTableLocked(tableName string) (*core.Table, error) {
 	table, ok := fd.tables[tableName]
 	if !ok {
 		return nil, &types.ResourceNotFoundException{Message: aws.String("Cannot do operations on a non-existent table")}
@@ -574,38 +1038,105 @@ func (fd *Client) getTable(tableName string) (*core.Table, error) {
 	return table, nil
 }
 
-func validateExpressionAttributes(exprNames map[string]string, exprValues map[string]types.AttributeValue, genericExpressions ...string) error {
-	genericExpression := strings.Join(genericExpressions, " ")
-	genericExpression = strings.TrimSpace(genericExpression)
+type expression struct {
+	typ   interpreter.ExpressionType
+	value string
+}
 
-	if genericExpression == "" && len(exprNames) == 0 && len(exprValues) == 0 {
+func validateExpressionAttributes(exprNames map[string]string, exprValues map[string]types.AttributeValue, expressions ...expression) error {
+	if isEmptyExpressions(expressions) && len(exprNames) == 0 && len(exprValues) == 0 {
 		return nil
 	}
 
 	flattenNames := getKeysFromExpressionNames(exprNames)
 	flattenValues := getKeysFromExpressionValues(exprValues)
 
-	missingNames := getMissingSubstrs(genericExpression, flattenNames)
-	missingValues := getMissingSubstrs(genericExpression, flattenValues)
+	err := validateSyntaxExpression(expressionAttributeNamesRegex, flattenNames, invalidExpressionAttributeName)
+	if err != nil {
+		return err
+	}
 
-	if len(missingNames) > 0 {
-		return &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("%s: keys: {%s}", unusedExpressionAttributeNamesMsg, strings.Join(missingNames, ", "))}
+	err = validateSyntaxExpression(expressionAttributeValuesRegex, flattenValues, invalidExpressionAttributeValue)
+	if err != nil {
+		return err
 	}
 
-	err := validateSyntaxExpression(expressionAttributeNamesRegex, flattenNames, invalidExpressionAttributeName)
+	usedNames, usedValues, err := collectUsedPlaceholders(exprNames, exprValues, expressions)
 	if err != nil {
 		return err
 	}
 
-	if len(missingValues) > 0 {
-		return &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("%s: keys: {%s}", unusedExpressionAttributeValuesMsg, strings.Join(missingValues, ", "))}
+	err = validateUnusedAttributes(flattenNames, usedNames, unusedExpressionAttributeNamesMsg)
+	if err != nil {
+		return err
 	}
 
-	err = validateSyntaxExpression(expressionAttributeValuesRegex, flattenValues, invalidExpressionAttributeValue)
+	return validateUnusedAttributes(flattenValues, usedValues, unusedExpressionAttributeValuesMsg)
+}
+
+// collectUsedPlaceholders returns the names and values used by the expressions,
+// it fails when a parsed expression uses a placeholder that is not defined
+func collectUsedPlaceholders(exprNames map[string]string, exprValues map[string]types.AttributeValue, expressions []expression) (map[string]bool, map[string]bool, error) {
+	usedNames := map[string]bool{}
+	usedValues := map[string]bool{}
+
+	for _, expr := range expressions {
+		if expr.value == "" {
+			continue
+		}
+
+		placeholders, parsed := interpreter.Placeholders(expr.value, expr.typ)
+		if parsed {
+			err := validateDefinedPlaceholders(expr.typ, placeholders.Names, placeholders.Values, exprNames, exprValues)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		mergeKeys(usedNames, placeholders.Names)
+		mergeKeys(usedValues, placeholders.Values)
+	}
+
+	return usedNames, usedValues, nil
+}
+
+func validateDefinedPlaceholders(typ interpreter.ExpressionType, names, values map[string]bool, exprNames map[string]string, exprValues map[string]types.AttributeValue) error {
+	err := validateUndefinedPlaceholders(typ, names, exprNames, undefinedExpressionAttributeNameMsg)
 	if err != nil {
 		return err
 	}
 
+	return validateUndefinedPlaceholders(typ, values, exprValues, undefinedExpressionAttributeValueMsg)
+}
+
+func validateUnusedAttributes(keys []string, used map[string]bool, msg string) error {
+	missing := getMissingKeys(keys, used)
+	if len(missing) > 0 {
+		return &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("%s: keys: {%s}", msg, strings.Join(missing, ", "))}
+	}
+
+	return nil
+}
+
+func isEmptyExpressions(expressions []expression) bool {
+	for _, expr := range expressions {
+		if strings.TrimSpace(expr.value) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func validateUndefinedPlaceholders[V any](typ interpreter.ExpressionType, used map[string]bool, defined map[string]V, errorMsg string) error {
+	for _, placeholder := range getSortedKeys(used) {
+		if _, ok := defined[placeholder]; ok {
+			continue
+		}
+
+		return &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("Invalid %s: %s: %s", typ.ParameterName(), errorMsg, placeholder)}
+	}
+
 	return nil
 }
 
@@ -621,35 +1152,41 @@ func validateSyntaxExpression(regex *regexp.Regexp, expressions []string, errorM
 }
 
 func getKeysFromExpressionNames(m map[string]string) []string {
-	keys := make([]string, 0, len(m))
-
-	for k := range m {
-		keys = append(keys, k)
-	}
-
-	return keys
+	return getSortedKeys(m)
 }
 
 func getKeysFromExpressionValues(m map[string]types.AttributeValue) []string {
+	return getSortedKeys(m)
+}
+
+func getSortedKeys[V any](m map[string]V) []string {
 	keys := make([]string, 0, len(m))
 
 	for k := range m {
 		keys = append(keys, k)
 	}
 
+	sort.Strings(keys)
+
 	return keys
 }
 
-func getMissingSubstrs(s string, substrs []string) []string {
-	missingSubstrs := make([]string, 0, len(substrs))
+func mergeKeys(dst, src map[string]bool) {
+	for k := range src {
+		dst[k] = true
+	}
+}
+
+func getMissingKeys(keys []string, used map[string]bool) []string {
+	missing := make([]string, 0, len(keys))
 
-	for _, substr := range substrs {
-		if strings.Contains(s, substr) {
+	for _, key := range keys {
+		if used[key] {
 			continue
 		}
 
-		missingSubstrs = append(missingSubstrs, substr)
+		missing = append(missing, key)
 	}
 
-	return missingSubstrs
+	return missing
 }
@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -17,6 +21,7 @@ import (
 	"github.com/aws/smithy-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
 	"github.com/truora/minidyn/interpreter"
 	"github.com/truora/minidyn/types"
 )
@@ -161,12 +166,15 @@ func setupDynamoDBLocal(endpoint string) FakeClient {
 	return dynamodb.NewFromConfig(cfg)
 }
 
-func setupNativeInterpreter(native *interpreter.Native, table string) {
-	native.AddUpdater(table, "SET second_type = :ntype", func(item map[string]*types.Item, updates map[string]*types.Item) {
+func setupNativeInterpreter(native *interpreter.Native, table string) error {
+	err := native.AddUpdater(table, "SET second_type = :ntype", func(item map[string]*types.Item, updates map[string]*types.Item) {
 		item["second_type"] = updates[":ntype"]
 	})
+	if err != nil {
+		return err
+	}
 
-	native.AddUpdater(table, "SET #type = :ntype", func(item map[string]*types.Item, updates map[string]*types.Item) {
+	return native.AddUpdater(table, "SET #type = :ntype", func(item map[string]*types.Item, updates map[string]*types.Item) {
 		item["type"] = updates[":ntype"]
 	})
 }
@@ -223,23 +231,757 @@ func TestSetInterpreter(t *testing.T) {
 		c.Error(err)
 	})
 
-	err := AddTable(context.Background(), client, "tests", "hk", "rk")
+	err := AddTable(context.Background(), client, "tests", "hk", "rk")
+	c.NoError(err)
+
+	native := interpreter.NewNativeInterpreter()
+	client.SetInterpreter(native)
+
+	_, err = client.getTable("tests")
+	c.NoError(err)
+}
+
+func TestActivateDebug(t *testing.T) {
+	c := require.New(t)
+	fake := NewClient()
+
+	fake.ActivateDebug()
+
+	c.NotNil(fake.langInterpreter.Tracer)
+	c.NotNil(fake.nativeInterpreter.Tracer)
+	c.False(fake.mutationDetection)
+}
+
+func TestActivateMutationDetection(t *testing.T) {
+	c := require.New(t)
+	fake := NewClient()
+
+	fake.ActivateMutationDetection()
+
+	c.True(fake.mutationDetection)
+	c.Nil(fake.langInterpreter.Tracer)
+}
+
+func TestActivateDifferentialInterpreter(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	err = createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	c.NoError(err)
+
+	mismatches := []interpreter.Mismatch{}
+	client.ActivateDifferentialInterpreter(func(m interpreter.Mismatch) {
+		mismatches = append(mismatches, m)
+	})
+
+	err = client.GetNativeInterpreter().AddUpdater(tableName, "SET second_type = :ntype", func(item map[string]*types.Item, updates map[string]*types.Item) {
+		item["second_type"] = &types.Item{S: types.ToString("fire")}
+	})
+	c.NoError(err)
+
+	_, err = client.UpdateItem(context.Background(), &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]dynamodbtypes.AttributeValue{
+			"id": &dynamodbtypes.AttributeValueMemberS{Value: "001"},
+		},
+		UpdateExpression: aws.String("SET second_type = :ntype"),
+		ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{
+			":ntype": &dynamodbtypes.AttributeValueMemberS{Value: "poison"},
+		},
+	})
+	c.NoError(err)
+
+	item, err := getPokemon(client, "001")
+	c.NoError(err)
+	c.Equal("fire", item["second_type"].(*dynamodbtypes.AttributeValueMemberS).Value)
+
+	c.Len(mismatches, 1)
+	c.Equal("SET second_type = :ntype", mismatches[0].Expression)
+	c.Contains(mismatches[0].Language, `"second_type": {S: "poison"}`)
+}
+
+func TestSetTracer(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	err = createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	c.NoError(err)
+
+	traces := []interpreter.Trace{}
+	client.SetTracer(interpreter.TracerFunc(func(tr interpreter.Trace) {
+		traces = append(traces, tr)
+	}))
+
+	_, err = client.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item: map[string]dynamodbtypes.AttributeValue{
+			"id": &dynamodbtypes.AttributeValueMemberS{Value: "001"},
+		},
+		ConditionExpression: aws.String("attribute_exists(id) AND #type = :type"),
+		ExpressionAttributeNames: map[string]string{
+			"#type": "type",
+		},
+		ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{
+			":type": &dynamodbtypes.AttributeValueMemberS{Value: "fire"},
+		},
+	})
+	c.Error(err)
+
+	c.Len(traces, 1)
+	c.Equal(`language conditional expression "attribute_exists(id) AND #type = :type" in table "pokemons" with key {"id": {S: "001"}}: not matched at (#type = :type)`, traces[0].String())
+
+	client.SetTracer(nil)
+
+	_, err = getPokemon(client, "001")
+	c.NoError(err)
+	c.Len(traces, 1)
+}
+
+func TestActivateConditionExplanations(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	err = createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	c.NoError(err)
+
+	client.ActivateConditionExplanations()
+
+	_, err = client.DeleteItem(context.Background(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]dynamodbtypes.AttributeValue{
+			"id": &dynamodbtypes.AttributeValueMemberS{Value: "001"},
+		},
+		ConditionExpression: aws.String("#type = :type"),
+		ExpressionAttributeNames: map[string]string{
+			"#type": "type",
+		},
+		ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{
+			":type": &dynamodbtypes.AttributeValueMemberS{Value: "fire"},
+		},
+	})
+
+	var checkErr *dynamodbtypes.ConditionalCheckFailedException
+	c.True(errors.As(err, &checkErr))
+	c.Equal("conditional request failed\n#type = :type → false\n  #type → \"grass\"\n  :type → \"fire\"", checkErr.ErrorMessage())
+
+	_, err = client.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item: map[string]dynamodbtypes.AttributeValue{
+			"id": &dynamodbtypes.AttributeValueMemberS{Value: "001"},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	})
+	c.True(errors.As(err, &checkErr))
+	c.Equal("conditional request failed\nattribute_not_exists(id) → false\n  id → \"001\"", checkErr.ErrorMessage())
+
+	c.NotPanics(func() {
+		_, err = client.PutItem(context.Background(), &dynamodb.PutItemInput{
+			TableName: aws.String(tableName),
+			Item: map[string]dynamodbtypes.AttributeValue{
+				"id": &dynamodbtypes.AttributeValueMemberS{Value: "001"},
+			},
+			ConditionExpression: aws.String(""),
+		})
+	})
+	c.Error(err)
+}
+
+func TestListTables(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	for _, name := range []string{"pokemons", "moves", "abilities"} {
+		err := AddTable(context.Background(), client, name, "id", "")
+		c.NoError(err)
+	}
+
+	output, err := client.ListTables(context.Background(), &dynamodb.ListTablesInput{})
+	c.NoError(err)
+	c.Equal([]string{"abilities", "moves", "pokemons"}, output.TableNames)
+	c.Nil(output.LastEvaluatedTableName)
+
+	output, err = client.ListTables(context.Background(), &dynamodb.ListTablesInput{Limit: aws.Int32(2)})
+	c.NoError(err)
+	c.Equal([]string{"abilities", "moves"}, output.TableNames)
+	c.Equal("moves", aws.ToString(output.LastEvaluatedTableName))
+
+	output, err = client.ListTables(context.Background(), &dynamodb.ListTablesInput{
+		Limit:                   aws.Int32(2),
+		ExclusiveStartTableName: output.LastEvaluatedTableName,
+	})
+	c.NoError(err)
+	c.Equal([]string{"pokemons"}, output.TableNames)
+	c.Nil(output.LastEvaluatedTableName)
+
+	_, err = client.ListTables(context.Background(), &dynamodb.ListTablesInput{Limit: aws.Int32(101)})
+
+	var apiErr smithy.APIError
+	c.True(errors.As(err, &apiErr))
+	c.Equal("ValidationException", apiErr.ErrorCode())
+
+	_, err = client.ListTables(context.Background(), &dynamodb.ListTablesInput{Limit: aws.Int32(0)})
+	c.Error(err)
+	c.Contains(err.Error(), "Value '0' at 'limit' failed to satisfy constraint")
+}
+
+func TestDeleteTableDuringWrite(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	table, err := client.getTable(tableName)
+	c.NoError(err)
+
+	// the write waits for the table while the deletion waits behind it
+	table.Lock()
+
+	putErr := make(chan error, 1)
+
+	go func() {
+		putErr <- createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	deleteErr := make(chan error, 1)
+
+	var out *dynamodb.DeleteTableOutput
+
+	go func() {
+		var err error
+
+		out, err = client.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{TableName: aws.String(tableName)})
+		deleteErr <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	table.Unlock()
+
+	c.NoError(<-putErr)
+	c.NoError(<-deleteErr)
+	c.Equal(int64(1), aws.ToInt64(out.TableDescription.ItemCount), "the item was written after the table was deleted")
+}
+
+func TestTableLifecycle(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+	client.ActivateTableLifecycle(1)
+
+	input := generateAddTableInput(tableName, "id", "")
+
+	output, err := client.CreateTable(context.Background(), input)
+	c.NoError(err)
+	c.Equal(dynamodbtypes.TableStatusCreating, output.TableDescription.TableStatus)
+
+	err = createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+
+	var notFoundErr *dynamodbtypes.ResourceNotFoundException
+	c.True(errors.As(err, &notFoundErr))
+
+	_, err = client.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{TableName: aws.String(tableName)})
+
+	var inUseErr *dynamodbtypes.ResourceInUseException
+	c.True(errors.As(err, &inUseErr))
+	c.Equal("Attempt to change a resource which is still in use: Table is being created: pokemons", inUseErr.ErrorMessage())
+
+	describeInput := &dynamodb.DescribeTableInput{TableName: aws.String(tableName)}
+
+	waiter := dynamodb.NewTableExistsWaiter(client, func(o *dynamodb.TableExistsWaiterOptions) {
+		o.MinDelay = time.Millisecond
+		o.MaxDelay = time.Millisecond
+	})
+	err = waiter.Wait(context.Background(), describeInput, time.Second)
+	c.NoError(err)
+
+	err = createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	c.NoError(err)
+
+	err = AddIndex(context.Background(), client, tableName, "by-type", "type", "id")
+	c.NoError(err)
+
+	described, err := client.DescribeTable(context.Background(), describeInput)
+	c.NoError(err)
+	c.Equal(dynamodbtypes.TableStatusUpdating, described.Table.TableStatus)
+
+	err = createPokemon(client, pokemon{ID: "004", Type: "fire", Name: "Charmander"})
+	c.NoError(err)
+
+	described, err = client.DescribeTable(context.Background(), describeInput)
+	c.NoError(err)
+	c.Equal(dynamodbtypes.TableStatusActive, described.Table.TableStatus)
+
+	deleted, err := client.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.TableStatusDeleting, deleted.TableDescription.TableStatus)
+
+	_, err = client.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]dynamodbtypes.AttributeValue{
+			"id": &dynamodbtypes.AttributeValueMemberS{Value: "001"},
+		},
+	})
+	c.True(errors.As(err, &notFoundErr))
+
+	notExistsWaiter := dynamodb.NewTableNotExistsWaiter(client, func(o *dynamodb.TableNotExistsWaiterOptions) {
+		o.MinDelay = time.Millisecond
+		o.MaxDelay = time.Millisecond
+	})
+	err = notExistsWaiter.Wait(context.Background(), describeInput, time.Second)
+	c.NoError(err)
+
+	tables, err := client.ListTables(context.Background(), &dynamodb.ListTablesInput{})
+	c.NoError(err)
+	c.Empty(tables.TableNames)
+}
+
+func TestDeletionProtection(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	input := generateAddTableInput(tableName, "id", "")
+	input.DeletionProtectionEnabled = aws.Bool(true)
+
+	_, err := client.CreateTable(context.Background(), input)
+	c.NoError(err)
+
+	described, err := client.DescribeTable(context.Background(), &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.True(aws.ToBool(described.Table.DeletionProtectionEnabled))
+
+	_, err = client.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{TableName: aws.String(tableName)})
+
+	var oe smithy.APIError
+	c.True(errors.As(err, &oe))
+	c.Equal("ValidationException", oe.ErrorCode())
+	c.Equal("Resource cannot be deleted as it is currently protected against deletion. Disable deletion protection first.", oe.ErrorMessage())
+
+	_, err = client.UpdateTable(context.Background(), &dynamodb.UpdateTableInput{
+		TableName:                 aws.String(tableName),
+		DeletionProtectionEnabled: aws.Bool(false),
+	})
+	c.NoError(err)
+
+	_, err = client.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+}
+
+func TestBackups(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(ensurePokemonTypeIndex(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"}))
+	c.NoError(createPokemon(client, pokemon{ID: "004", Type: "fire", Name: "Charmander"}))
+
+	_, err := client.CreateBackup(context.Background(), &dynamodb.CreateBackupInput{
+		TableName:  aws.String("trainers"),
+		BackupName: aws.String("trainers-backup"),
+	})
+
+	var tableNotFound *dynamodbtypes.TableNotFoundException
+	c.True(errors.As(err, &tableNotFound))
+
+	created, err := client.CreateBackup(context.Background(), &dynamodb.CreateBackupInput{
+		TableName:  aws.String(tableName),
+		BackupName: aws.String("pokemons-backup"),
+	})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.BackupStatusAvailable, created.BackupDetails.BackupStatus)
+	c.Equal(dynamodbtypes.BackupTypeUser, created.BackupDetails.BackupType)
+
+	backupArn := created.BackupDetails.BackupArn
+
+	c.NoError(createPokemon(client, pokemon{ID: "007", Type: "water", Name: "Squirtle"}))
+
+	described, err := client.DescribeBackup(context.Background(), &dynamodb.DescribeBackupInput{BackupArn: backupArn})
+	c.NoError(err)
+	c.Equal(tableName, aws.ToString(described.BackupDescription.SourceTableDetails.TableName))
+	c.Equal(int64(2), aws.ToInt64(described.BackupDescription.SourceTableDetails.ItemCount))
+	c.Len(described.BackupDescription.SourceTableFeatureDetails.GlobalSecondaryIndexes, 1)
+
+	listed, err := client.ListBackups(context.Background(), &dynamodb.ListBackupsInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Len(listed.BackupSummaries, 1)
+	c.Equal(backupArn, listed.BackupSummaries[0].BackupArn)
+
+	_, err = client.RestoreTableFromBackup(context.Background(), &dynamodb.RestoreTableFromBackupInput{
+		BackupArn:       backupArn,
+		TargetTableName: aws.String(tableName),
+	})
+
+	var alreadyExists *dynamodbtypes.TableAlreadyExistsException
+	c.True(errors.As(err, &alreadyExists))
+
+	restored, err := client.RestoreTableFromBackup(context.Background(), &dynamodb.RestoreTableFromBackupInput{
+		BackupArn:           backupArn,
+		TargetTableName:     aws.String("pokemons-restored"),
+		BillingModeOverride: dynamodbtypes.BillingModeProvisioned,
+		ProvisionedThroughputOverride: &dynamodbtypes.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(5),
+		},
+		GlobalSecondaryIndexOverride: []dynamodbtypes.GlobalSecondaryIndex{},
+	})
+	c.NoError(err)
+	c.Equal(int64(2), aws.ToInt64(restored.TableDescription.ItemCount))
+	c.Equal(dynamodbtypes.BillingModeProvisioned, restored.TableDescription.BillingModeSummary.BillingMode)
+	c.Empty(restored.TableDescription.GlobalSecondaryIndexes)
+	c.Equal(backupArn, restored.TableDescription.RestoreSummary.SourceBackupArn)
+
+	item, err := client.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName: aws.String("pokemons-restored"),
+		Key:       map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "004"}},
+	})
+	c.NoError(err)
+	c.Equal(&dynamodbtypes.AttributeValueMemberS{Value: "Charmander"}, item.Item["name"])
+
+	deleted, err := client.DeleteBackup(context.Background(), &dynamodb.DeleteBackupInput{BackupArn: backupArn})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.BackupStatusDeleted, deleted.BackupDescription.BackupDetails.BackupStatus)
+
+	_, err = client.DescribeBackup(context.Background(), &dynamodb.DescribeBackupInput{BackupArn: backupArn})
+
+	var backupNotFound *dynamodbtypes.BackupNotFoundException
+	c.True(errors.As(err, &backupNotFound))
+}
+
+func TestPointInTimeRecovery(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.SetClock(func() time.Time { return now })
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"}))
+
+	_, err := client.RestoreTableToPointInTime(context.Background(), &dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableName:         aws.String(tableName),
+		TargetTableName:         aws.String("pokemons-restored"),
+		UseLatestRestorableTime: aws.Bool(true),
+	})
+
+	var unavailable *dynamodbtypes.PointInTimeRecoveryUnavailableException
+	c.True(errors.As(err, &unavailable))
+
+	updated, err := client.UpdateContinuousBackups(context.Background(), &dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String(tableName),
+		PointInTimeRecoverySpecification: &dynamodbtypes.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.PointInTimeRecoveryStatusEnabled, updated.ContinuousBackupsDescription.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus)
+
+	now = now.Add(time.Hour)
+	healthy := now
+
+	now = now.Add(time.Hour)
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "MissingNo"}))
+
+	described, err := client.DescribeContinuousBackups(context.Background(), &dynamodb.DescribeContinuousBackupsInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Equal(now, aws.ToTime(described.ContinuousBackupsDescription.PointInTimeRecoveryDescription.LatestRestorableDateTime))
+
+	tooEarly := healthy.Add(-2 * time.Hour)
+
+	_, err = client.RestoreTableToPointInTime(context.Background(), &dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableName: aws.String(tableName),
+		TargetTableName: aws.String("pokemons-restored"),
+		RestoreDateTime: &tooEarly,
+	})
+
+	var invalidTime *dynamodbtypes.InvalidRestoreTimeException
+	c.True(errors.As(err, &invalidTime))
+
+	source, err := client.DescribeTable(context.Background(), &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+
+	restored, err := client.RestoreTableToPointInTime(context.Background(), &dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableArn:  source.Table.TableArn,
+		TargetTableName: aws.String("pokemons-restored"),
+		RestoreDateTime: &healthy,
+	})
+	c.NoError(err)
+	c.Equal(healthy, aws.ToTime(restored.TableDescription.RestoreSummary.RestoreDateTime))
+	c.Equal(now, aws.ToTime(restored.TableDescription.CreationDateTime))
+
+	item, err := client.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName: aws.String("pokemons-restored"),
+		Key:       map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "001"}},
+	})
+	c.NoError(err)
+	c.Equal(&dynamodbtypes.AttributeValueMemberS{Value: "Bulbasaur"}, item.Item["name"])
+
+	_, err = client.DescribeContinuousBackups(context.Background(), &dynamodb.DescribeContinuousBackupsInput{TableName: aws.String("trainers")})
+
+	var tableNotFound *dynamodbtypes.TableNotFoundException
+	c.True(errors.As(err, &tableNotFound))
+}
+
+func TestExportTableToPointInTime(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"}))
+
+	source, err := client.DescribeTable(context.Background(), &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+
+	input := &dynamodb.ExportTableToPointInTimeInput{
+		TableArn:     source.Table.TableArn,
+		S3Bucket:     aws.String("analytics"),
+		S3Prefix:     aws.String("pokemons"),
+		ExportFormat: dynamodbtypes.ExportFormatIon,
+	}
+
+	_, err = client.ExportTableToPointInTime(context.Background(), input)
+	c.Error(err)
+	c.Contains(err.Error(), "The export directory is not set")
+
+	dir := t.TempDir()
+	client.SetExportDirectory(dir)
+
+	_, err = client.ExportTableToPointInTime(context.Background(), input)
+
+	var unavailable *dynamodbtypes.PointInTimeRecoveryUnavailableException
+	c.True(errors.As(err, &unavailable))
+
+	_, err = client.UpdateContinuousBackups(context.Background(), &dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String(tableName),
+		PointInTimeRecoverySpecification: &dynamodbtypes.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+
+	exported, err := client.ExportTableToPointInTime(context.Background(), input)
+	c.NoError(err)
+	c.Equal(dynamodbtypes.ExportStatusCompleted, exported.ExportDescription.ExportStatus)
+	c.Equal(int64(1), aws.ToInt64(exported.ExportDescription.ItemCount))
+
+	_, err = os.Stat(dir + "/analytics/" + aws.ToString(exported.ExportDescription.ExportManifest))
+	c.NoError(err)
+
+	described, err := client.DescribeExport(context.Background(), &dynamodb.DescribeExportInput{ExportArn: exported.ExportDescription.ExportArn})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.ExportFormatIon, described.ExportDescription.ExportFormat)
+
+	_, err = client.DescribeExport(context.Background(), &dynamodb.DescribeExportInput{ExportArn: aws.String(aws.ToString(source.Table.TableArn) + "/export/unknown")})
+
+	var exportNotFound *dynamodbtypes.ExportNotFoundException
+	c.True(errors.As(err, &exportNotFound))
+}
+
+func TestImportTable(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+	dir := t.TempDir()
+
+	client.SetExportDirectory(dir)
+	client.SetImportDirectory(dir)
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"}))
+	c.NoError(createPokemon(client, pokemon{ID: "004", Type: "fire", Name: "Charmander"}))
+
+	source, err := client.DescribeTable(context.Background(), &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+
+	_, err = client.UpdateContinuousBackups(context.Background(), &dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String(tableName),
+		PointInTimeRecoverySpecification: &dynamodbtypes.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+
+	exported, err := client.ExportTableToPointInTime(context.Background(), &dynamodb.ExportTableToPointInTimeInput{
+		TableArn: source.Table.TableArn,
+		S3Bucket: aws.String("fixtures"),
+		S3Prefix: aws.String("pokemons"),
+	})
+	c.NoError(err)
+
+	manifest := aws.ToString(exported.ExportDescription.ExportManifest)
+
+	input := &dynamodb.ImportTableInput{
+		InputFormat:          dynamodbtypes.InputFormatDynamodbJson,
+		InputCompressionType: dynamodbtypes.InputCompressionTypeGzip,
+		S3BucketSource: &dynamodbtypes.S3BucketSource{
+			S3Bucket:    aws.String("fixtures"),
+			S3KeyPrefix: aws.String(manifest[:strings.LastIndex(manifest, "/")] + "/data/"),
+		},
+		TableCreationParameters: &dynamodbtypes.TableCreationParameters{
+			TableName:            aws.String(tableName),
+			AttributeDefinitions: []dynamodbtypes.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: dynamodbtypes.ScalarAttributeTypeS}},
+			KeySchema:            []dynamodbtypes.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: dynamodbtypes.KeyTypeHash}},
+			BillingMode:          dynamodbtypes.BillingModePayPerRequest,
+		},
+	}
+
+	_, err = client.ImportTable(context.Background(), input)
+
+	var inUse *dynamodbtypes.ResourceInUseException
+	c.True(errors.As(err, &inUse))
+
+	input.TableCreationParameters.TableName = aws.String("pokemons-copy")
+
+	imported, err := client.ImportTable(context.Background(), input)
+	c.NoError(err)
+	c.Equal(dynamodbtypes.ImportStatusCompleted, imported.ImportTableDescription.ImportStatus)
+	c.Equal(int64(2), imported.ImportTableDescription.ImportedItemCount)
+	c.Equal(int64(0), imported.ImportTableDescription.ErrorCount)
+
+	item, err := client.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName: aws.String("pokemons-copy"),
+		Key:       map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "004"}},
+	})
+	c.NoError(err)
+	c.Equal(&dynamodbtypes.AttributeValueMemberS{Value: "Charmander"}, item.Item["name"])
+
+	input.S3BucketSource.S3KeyPrefix = aws.String("missing/")
+	input.TableCreationParameters.TableName = aws.String("pokemons-missing")
+
+	failed, err := client.ImportTable(context.Background(), input)
+	c.NoError(err)
+	c.Equal(dynamodbtypes.ImportStatusFailed, failed.ImportTableDescription.ImportStatus)
+	c.Equal("S3ReadError", aws.ToString(failed.ImportTableDescription.FailureCode))
+
+	_, err = client.DescribeTable(context.Background(), &dynamodb.DescribeTableInput{TableName: aws.String("pokemons-missing")})
+	c.Error(err)
+
+	described, err := client.DescribeImport(context.Background(), &dynamodb.DescribeImportInput{ImportArn: imported.ImportTableDescription.ImportArn})
+	c.NoError(err)
+	c.Equal("pokemons-copy", aws.ToString(described.ImportTableDescription.TableCreationParameters.TableName))
+
+	_, err = client.DescribeImport(context.Background(), &dynamodb.DescribeImportInput{ImportArn: aws.String(aws.ToString(source.Table.TableArn) + "/import/unknown")})
+
+	var importNotFound *dynamodbtypes.ImportNotFoundException
+	c.True(errors.As(err, &importNotFound))
+
+	listed, err := client.ListImports(context.Background(), &dynamodb.ListImportsInput{PageSize: aws.Int32(1)})
+	c.NoError(err)
+	c.Len(listed.ImportSummaryList, 1)
+	c.NotNil(listed.NextToken)
+
+	listed, err = client.ListImports(context.Background(), &dynamodb.ListImportsInput{TableArn: imported.ImportTableDescription.TableArn})
+	c.NoError(err)
+	c.Len(listed.ImportSummaryList, 1)
+	c.Equal(imported.ImportTableDescription.ImportArn, listed.ImportSummaryList[0].ImportArn)
+	c.Nil(listed.NextToken)
+}
+
+func TestImportTableWithDecompressor(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+	dir := t.TempDir()
+
+	client.SetImportDirectory(dir)
+
+	c.NoError(os.MkdirAll(filepath.Join(dir, "fixtures", "csv"), 0o755))
+	c.NoError(os.WriteFile(filepath.Join(dir, "fixtures", "csv", "part-1.csv.zst"), []byte("001|grass\n004|fire\n"), 0o600))
+
+	decompressed := 0
+
+	client.SetDecompressor(dynamodbtypes.InputCompressionTypeZstd, func(r io.Reader) (io.Reader, error) {
+		decompressed++
+
+		return r, nil
+	})
+
+	imported, err := client.ImportTable(context.Background(), &dynamodb.ImportTableInput{
+		InputFormat:          dynamodbtypes.InputFormatCsv,
+		InputCompressionType: dynamodbtypes.InputCompressionTypeZstd,
+		InputFormatOptions: &dynamodbtypes.InputFormatOptions{
+			Csv: &dynamodbtypes.CsvOptions{Delimiter: aws.String("|"), HeaderList: []string{"id", "type"}},
+		},
+		S3BucketSource: &dynamodbtypes.S3BucketSource{S3Bucket: aws.String("fixtures"), S3KeyPrefix: aws.String("csv/")},
+		TableCreationParameters: &dynamodbtypes.TableCreationParameters{
+			TableName:            aws.String(tableName),
+			AttributeDefinitions: []dynamodbtypes.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: dynamodbtypes.ScalarAttributeTypeS}},
+			KeySchema:            []dynamodbtypes.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: dynamodbtypes.KeyTypeHash}},
+			BillingMode:          dynamodbtypes.BillingModePayPerRequest,
+		},
+	})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.ImportStatusCompleted, imported.ImportTableDescription.ImportStatus)
+	c.Equal(int64(2), imported.ImportTableDescription.ImportedItemCount)
+	c.Equal(1, decompressed)
+
+	item, err := client.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key:       map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "004"}},
+	})
+	c.NoError(err)
+	c.Equal(&dynamodbtypes.AttributeValueMemberS{Value: "fire"}, item.Item["type"])
+}
+
+func TestTagResource(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	input := generateAddTableInput(tableName, "id", "")
+	input.Tags = []dynamodbtypes.Tag{{Key: aws.String("team"), Value: aws.String("pokedex")}}
+
+	created, err := client.CreateTable(context.Background(), input)
+	c.NoError(err)
+
+	arn := created.TableDescription.TableArn
+
+	tags := make([]dynamodbtypes.Tag, 0, 11)
+	for n := 0; n < 11; n++ {
+		tags = append(tags, dynamodbtypes.Tag{Key: aws.String(fmt.Sprintf("key-%02d", n)), Value: aws.String("value")})
+	}
+
+	_, err = client.TagResource(context.Background(), &dynamodb.TagResourceInput{ResourceArn: arn, Tags: tags})
+	c.NoError(err)
+
+	listed, err := client.ListTagsOfResource(context.Background(), &dynamodb.ListTagsOfResourceInput{ResourceArn: arn})
+	c.NoError(err)
+	c.Len(listed.Tags, 10)
+	c.Equal("key-00", aws.ToString(listed.Tags[0].Key))
+	c.NotNil(listed.NextToken)
+
+	listed, err = client.ListTagsOfResource(context.Background(), &dynamodb.ListTagsOfResourceInput{ResourceArn: arn, NextToken: listed.NextToken})
+	c.NoError(err)
+	c.Len(listed.Tags, 2)
+	c.Equal("team", aws.ToString(listed.Tags[1].Key))
+	c.Nil(listed.NextToken)
+
+	_, err = client.UntagResource(context.Background(), &dynamodb.UntagResourceInput{ResourceArn: arn, TagKeys: []string{"team"}})
 	c.NoError(err)
 
-	native := interpreter.NewNativeInterpreter()
-	client.SetInterpreter(native)
+	limits := core.DefaultLimits
+	limits.TagsPerResource = 11
+	client.SetLimits(limits)
 
-	_, err = client.getTable("tests")
-	c.NoError(err)
-}
+	_, err = client.TagResource(context.Background(), &dynamodb.TagResourceInput{
+		ResourceArn: arn,
+		Tags:        []dynamodbtypes.Tag{{Key: aws.String("owner"), Value: aws.String("oak")}},
+	})
 
-func TestActivateDebug(t *testing.T) {
-	c := require.New(t)
-	fake := NewClient()
+	var limitExceeded *dynamodbtypes.LimitExceededException
+	c.True(errors.As(err, &limitExceeded))
 
-	fake.ActivateDebug()
+	_, err = client.TagResource(context.Background(), &dynamodb.TagResourceInput{
+		ResourceArn: arn,
+		Tags:        []dynamodbtypes.Tag{{Key: aws.String(""), Value: aws.String("empty")}},
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "at 'tags.1.member.key' failed to satisfy constraint")
+
+	_, err = client.ListTagsOfResource(context.Background(), &dynamodb.ListTagsOfResourceInput{ResourceArn: aws.String(aws.ToString(arn) + "-unknown")})
 
-	c.True(fake.langInterpreter.Debug)
+	var notFound *dynamodbtypes.ResourceNotFoundException
+	c.True(errors.As(err, &notFound))
 }
 
 func TestCreateTable(t *testing.T) {
@@ -517,7 +1259,17 @@ func TestUpdateTable(t *testing.T) {
 		TableName: aws.String(tableName),
 	}
 	_, err = client.UpdateTable(context.Background(), input)
+	c.Contains(err.Error(), "ProvisionedThroughput should not be specified for index: newIndex when BillingMode is PAY_PER_REQUEST")
+
+	input.BillingMode = dynamodbtypes.BillingModeProvisioned
+	input.ProvisionedThroughput = &dynamodbtypes.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(5),
+		WriteCapacityUnits: aws.Int64(5),
+	}
+
+	output, err = client.UpdateTable(context.Background(), input)
 	c.NoError(err)
+	c.Equal(int64(1), aws.ToInt64(output.TableDescription.GlobalSecondaryIndexes[0].ProvisionedThroughput.ReadCapacityUnits))
 
 	input = &dynamodb.UpdateTableInput{
 		GlobalSecondaryIndexUpdates: []dynamodbtypes.GlobalSecondaryIndexUpdate{
@@ -536,6 +1288,74 @@ func TestUpdateTable(t *testing.T) {
 	c.Equal("ResourceNotFoundException: Requested resource not found", err.Error())
 }
 
+func TestUpdateTableSettings(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	err = ensurePokemonTypeIndex(client)
+	c.NoError(err)
+
+	_, err = client.UpdateTable(context.Background(), &dynamodb.UpdateTableInput{
+		TableName: aws.String(tableName),
+	})
+	c.Contains(err.Error(), "ValidationException: At least one of ProvisionedThroughput")
+
+	output, err := client.UpdateTable(context.Background(), &dynamodb.UpdateTableInput{
+		TableName: aws.String(tableName),
+		StreamSpecification: &dynamodbtypes.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: dynamodbtypes.StreamViewTypeNewImage,
+		},
+		TableClass:                dynamodbtypes.TableClassStandardInfrequentAccess,
+		DeletionProtectionEnabled: aws.Bool(true),
+	})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.StreamViewTypeNewImage, output.TableDescription.StreamSpecification.StreamViewType)
+	c.NotEmpty(aws.ToString(output.TableDescription.LatestStreamArn))
+	c.Equal(dynamodbtypes.TableClassStandardInfrequentAccess, output.TableDescription.TableClassSummary.TableClass)
+	c.True(aws.ToBool(output.TableDescription.DeletionProtectionEnabled))
+
+	_, err = client.UpdateTable(context.Background(), &dynamodb.UpdateTableInput{
+		TableName: aws.String(tableName),
+		GlobalSecondaryIndexUpdates: []dynamodbtypes.GlobalSecondaryIndexUpdate{
+			{Delete: &dynamodbtypes.DeleteGlobalSecondaryIndexAction{IndexName: aws.String("by-type")}},
+			{Create: &dynamodbtypes.CreateGlobalSecondaryIndexAction{
+				IndexName:  aws.String("by-id"),
+				KeySchema:  []dynamodbtypes.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: dynamodbtypes.KeyTypeHash}},
+				Projection: &dynamodbtypes.Projection{ProjectionType: dynamodbtypes.ProjectionTypeKeysOnly},
+			}},
+		},
+	})
+
+	var limitErr *dynamodbtypes.LimitExceededException
+	c.True(errors.As(err, &limitErr))
+
+	output, err = client.UpdateTable(context.Background(), &dynamodb.UpdateTableInput{
+		TableName:   aws.String(tableName),
+		BillingMode: dynamodbtypes.BillingModeProvisioned,
+		ProvisionedThroughput: &dynamodbtypes.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(5),
+		},
+		GlobalSecondaryIndexUpdates: []dynamodbtypes.GlobalSecondaryIndexUpdate{
+			{Update: &dynamodbtypes.UpdateGlobalSecondaryIndexAction{
+				IndexName: aws.String("by-type"),
+				ProvisionedThroughput: &dynamodbtypes.ProvisionedThroughput{
+					ReadCapacityUnits:  aws.Int64(2),
+					WriteCapacityUnits: aws.Int64(2),
+				},
+			}},
+		},
+	})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.BillingModeProvisioned, output.TableDescription.BillingModeSummary.BillingMode)
+	c.Equal(int64(5), aws.ToInt64(output.TableDescription.ProvisionedThroughput.ReadCapacityUnits))
+	c.Equal(int64(2), aws.ToInt64(output.TableDescription.GlobalSecondaryIndexes[0].ProvisionedThroughput.ReadCapacityUnits))
+}
+
 func TestPutAndGetItem(t *testing.T) {
 	c := require.New(t)
 	client := setupClient(tableName)
@@ -585,6 +1405,50 @@ func TestPutAndGetItem(t *testing.T) {
 	c.Contains(err.Error(), "number of conditions on the keys is invalid")
 }
 
+func TestItemsAreNotSharedWithTheCaller(t *testing.T) {
+	c := require.New(t)
+	client := setupClient(tableName)
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	sprite := []byte("png")
+	item := map[string]dynamodbtypes.AttributeValue{
+		"id":     &dynamodbtypes.AttributeValueMemberS{Value: "001"},
+		"sprite": &dynamodbtypes.AttributeValueMemberB{Value: sprite},
+		"stats": &dynamodbtypes.AttributeValueMemberM{Value: map[string]dynamodbtypes.AttributeValue{
+			"hp": &dynamodbtypes.AttributeValueMemberN{Value: "45"},
+		}},
+	}
+
+	_, err = client.PutItem(context.Background(), &dynamodb.PutItemInput{
+		Item:      item,
+		TableName: aws.String(tableName),
+	})
+	c.NoError(err)
+
+	sprite[0] = 'j'
+
+	getInput := &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]dynamodbtypes.AttributeValue{
+			"id": &dynamodbtypes.AttributeValueMemberS{Value: "001"},
+		},
+	}
+
+	out, err := client.GetItem(context.Background(), getInput)
+	c.NoError(err)
+	c.Equal(&dynamodbtypes.AttributeValueMemberB{Value: []byte("png")}, out.Item["sprite"])
+
+	out.Item["sprite"].(*dynamodbtypes.AttributeValueMemberB).Value[0] = 'g'
+	out.Item["stats"].(*dynamodbtypes.AttributeValueMemberM).Value["hp"] = &dynamodbtypes.AttributeValueMemberN{Value: "2"}
+
+	out, err = client.GetItem(context.Background(), getInput)
+	c.NoError(err)
+	c.Equal(&dynamodbtypes.AttributeValueMemberB{Value: []byte("png")}, out.Item["sprite"])
+	c.Equal(&dynamodbtypes.AttributeValueMemberN{Value: "45"}, out.Item["stats"].(*dynamodbtypes.AttributeValueMemberM).Value["hp"])
+}
+
 func TestPutWithGSI(t *testing.T) {
 	c := require.New(t)
 	client := setupClient(tableName)
@@ -656,6 +1520,47 @@ func TestGetItemWithUnusedAttributes(t *testing.T) {
 	c.Contains(err.Error(), unusedExpressionAttributeNamesMsg)
 }
 
+func TestQueryWithUndefinedAttributes(t *testing.T) {
+	c := require.New(t)
+
+	client := setupClient(tableName)
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	input := &dynamodb.QueryInput{
+		TableName: aws.String(tableName),
+		ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{
+			":id": &dynamodbtypes.AttributeValueMemberS{Value: "001"},
+		},
+		KeyConditionExpression: aws.String("id = :id2"),
+	}
+
+	_, err = client.Query(context.Background(), input)
+	c.NotNil(err)
+	c.Contains(err.Error(), "Invalid KeyConditionExpression: "+undefinedExpressionAttributeValueMsg+": :id2")
+
+	input.KeyConditionExpression = aws.String("#name = :id")
+	input.ExpressionAttributeNames = map[string]string{
+		"#n": "id",
+	}
+
+	_, err = client.Query(context.Background(), input)
+	c.NotNil(err)
+	c.Contains(err.Error(), "Invalid KeyConditionExpression: "+undefinedExpressionAttributeNameMsg+": #name")
+
+	input.KeyConditionExpression = aws.String("id = :id")
+	input.FilterExpression = aws.String("#name = :id")
+	input.ExpressionAttributeNames = map[string]string{
+		"#name": "name",
+		"#n":    "id",
+	}
+
+	_, err = client.Query(context.Background(), input)
+	c.NotNil(err)
+	c.Contains(err.Error(), unusedExpressionAttributeNamesMsg+": keys: {#n}")
+}
+
 func TestGetItemWithInvalidExpressionAttributeNames(t *testing.T) {
 	c := require.New(t)
 
@@ -799,7 +1704,9 @@ func TestUpdateItem(t *testing.T) {
 		return
 	}
 
-	setupNativeInterpreter(minidynClient.GetNativeInterpreter(), tableName)
+	err = setupNativeInterpreter(minidynClient.GetNativeInterpreter(), tableName)
+	c.NoError(err)
+
 	minidynClient.ActivateNativeInterpreter()
 
 	input.Key = map[string]dynamodbtypes.AttributeValue{
@@ -840,7 +1747,7 @@ func TestUpdateItemWithConditionalExpression(t *testing.T) {
 
 	uexpr := "SET second_type = :ntype"
 	expr := map[string]dynamodbtypes.AttributeValue{
-		":ntyp": &dynamodbtypes.AttributeValueMemberS{
+		":ntype": &dynamodbtypes.AttributeValueMemberS{
 			Value: string("poison"),
 		},
 	}
@@ -1234,7 +2141,9 @@ func TestQuery(t *testing.T) {
 		return
 	}
 
-	setupNativeInterpreter(minidynClient.GetNativeInterpreter(), tableName)
+	err = setupNativeInterpreter(minidynClient.GetNativeInterpreter(), tableName)
+	c.NoError(err)
+
 	minidynClient.ActivateNativeInterpreter()
 
 	input = &dynamodb.QueryInput{
@@ -1427,9 +2336,13 @@ func TestQuerySyntaxError(t *testing.T) {
 		TableName:              aws.String(tableName),
 	}
 
-	c.Panics(func() {
-		_, _ = client.Query(context.Background(), input)
-	})
+	_, err = client.Query(context.Background(), input)
+
+	var apiErr *smithy.GenericAPIError
+
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+	c.Equal(`Invalid KeyConditionExpression: Syntax error; token: "!", near: "#partition !="`, apiErr.ErrorMessage())
 }
 
 func TestScan(t *testing.T) {
@@ -1738,6 +2651,68 @@ func TestDescribeTableFail(t *testing.T) {
 	c.Empty(output)
 }
 
+func TestDescribeTableSettings(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	_, err := client.CreateTable(context.Background(), &dynamodb.CreateTableInput{
+		TableName:   aws.String(tableName),
+		BillingMode: dynamodbtypes.BillingModePayPerRequest,
+		AttributeDefinitions: []dynamodbtypes.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: dynamodbtypes.ScalarAttributeTypeS},
+			{AttributeName: aws.String("type"), AttributeType: dynamodbtypes.ScalarAttributeTypeS},
+		},
+		KeySchema: []dynamodbtypes.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: dynamodbtypes.KeyTypeHash},
+		},
+		GlobalSecondaryIndexes: []dynamodbtypes.GlobalSecondaryIndex{
+			{
+				IndexName:  aws.String("by-type"),
+				KeySchema:  []dynamodbtypes.KeySchemaElement{{AttributeName: aws.String("type"), KeyType: dynamodbtypes.KeyTypeHash}},
+				Projection: &dynamodbtypes.Projection{ProjectionType: dynamodbtypes.ProjectionTypeAll},
+			},
+		},
+		StreamSpecification: &dynamodbtypes.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: dynamodbtypes.StreamViewTypeNewAndOldImages,
+		},
+		SSESpecification: &dynamodbtypes.SSESpecification{Enabled: aws.Bool(true)},
+		TableClass:       dynamodbtypes.TableClassStandardInfrequentAccess,
+	})
+	c.NoError(err)
+
+	err = createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	c.NoError(err)
+
+	output, err := client.DescribeTable(context.Background(), &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	c.NoError(err)
+
+	table := output.Table
+	c.Equal("arn:aws:dynamodb:ddblocal:000000000000:table/"+tableName, aws.ToString(table.TableArn))
+	c.NotEmpty(aws.ToString(table.TableId))
+	c.NotNil(table.CreationDateTime)
+	c.Len(table.AttributeDefinitions, 2)
+	c.Equal(dynamodbtypes.BillingModePayPerRequest, table.BillingModeSummary.BillingMode)
+	c.Equal(int64(0), aws.ToInt64(table.ProvisionedThroughput.ReadCapacityUnits))
+	c.Equal(int64(1), aws.ToInt64(table.ItemCount))
+	c.Positive(aws.ToInt64(table.TableSizeBytes))
+	c.Equal(dynamodbtypes.StreamViewTypeNewAndOldImages, table.StreamSpecification.StreamViewType)
+	c.Equal(aws.ToString(table.TableArn)+"/stream/"+aws.ToString(table.LatestStreamLabel), aws.ToString(table.LatestStreamArn))
+	c.Equal(dynamodbtypes.SSEStatusEnabled, table.SSEDescription.Status)
+	c.Equal(dynamodbtypes.SSETypeKms, table.SSEDescription.SSEType)
+	c.Equal(dynamodbtypes.TableClassStandardInfrequentAccess, table.TableClassSummary.TableClass)
+	c.False(aws.ToBool(table.DeletionProtectionEnabled))
+
+	c.Len(table.GlobalSecondaryIndexes, 1)
+	gsi := table.GlobalSecondaryIndexes[0]
+	c.Equal(aws.ToString(table.TableArn)+"/index/by-type", aws.ToString(gsi.IndexArn))
+	c.Equal(dynamodbtypes.IndexStatusActive, gsi.IndexStatus)
+	c.Equal(dynamodbtypes.ProjectionTypeAll, gsi.Projection.ProjectionType)
+	c.Equal(aws.ToInt64(table.TableSizeBytes), aws.ToInt64(gsi.IndexSizeBytes))
+}
+
 func TestBatchWriteItem(t *testing.T) {
 	c := require.New(t)
 	client := setupClient(tableName)
@@ -1943,6 +2918,219 @@ func TestTransactWriteItems(t *testing.T) {
 	c.Equal(ErrForcedFailure, err)
 }
 
+func TestSetLimits(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	limits := core.DefaultLimits
+	limits.ItemSize = 20
+	limits.TransactionItems = 0
+
+	client.SetLimits(limits)
+
+	err = createPokemon(client, pokemon{
+		ID:   "001",
+		Type: "grass",
+		Name: "Bulbasaur",
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "Item size has exceeded the maximum allowed size")
+
+	_, err = client.TransactWriteItems(context.Background(), &dynamodb.TransactWriteItemsInput{
+		TransactItems: []dynamodbtypes.TransactWriteItem{{}},
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "Member must have length less than or equal to 0")
+
+	client.SetLimits(core.DefaultLimits)
+
+	err = createPokemon(client, pokemon{
+		ID:   "001",
+		Type: "grass",
+		Name: "Bulbasaur",
+	})
+	c.NoError(err)
+}
+
+func TestLegacyParameters(t *testing.T) {
+	c := require.New(t)
+
+	client := setupClient(tableName)
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	err = createPokemon(client, pokemon{
+		ID:    "001",
+		Type:  "grass",
+		Name:  "Bulbasaur",
+		Level: 5,
+	})
+	c.NoError(err)
+
+	key := map[string]dynamodbtypes.AttributeValue{
+		"id": &dynamodbtypes.AttributeValueMemberS{Value: "001"},
+	}
+
+	_, err = client.UpdateItem(context.Background(), &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key:       key,
+		AttributeUpdates: map[string]dynamodbtypes.AttributeValueUpdate{
+			"lvl": {
+				Action: dynamodbtypes.AttributeActionAdd,
+				Value:  &dynamodbtypes.AttributeValueMemberN{Value: "1"},
+			},
+			"second_type": {Action: dynamodbtypes.AttributeActionDelete},
+		},
+		Expected: map[string]dynamodbtypes.ExpectedAttributeValue{
+			"type": {Value: &dynamodbtypes.AttributeValueMemberS{Value: "grass"}},
+		},
+	})
+	c.NoError(err)
+
+	out, err := client.Query(context.Background(), &dynamodb.QueryInput{
+		TableName: aws.String(tableName),
+		KeyConditions: map[string]dynamodbtypes.Condition{
+			"id": {
+				ComparisonOperator: dynamodbtypes.ComparisonOperatorEq,
+				AttributeValueList: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberS{Value: "001"}},
+			},
+		},
+		QueryFilter: map[string]dynamodbtypes.Condition{
+			"lvl": {
+				ComparisonOperator: dynamodbtypes.ComparisonOperatorGt,
+				AttributeValueList: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberN{Value: "5"}},
+			},
+		},
+	})
+	c.NoError(err)
+	c.Len(out.Items, 1)
+	c.Equal(&dynamodbtypes.AttributeValueMemberN{Value: "6"}, out.Items[0]["lvl"])
+	c.NotContains(out.Items[0], "second_type")
+
+	scan, err := client.Scan(context.Background(), &dynamodb.ScanInput{
+		TableName: aws.String(tableName),
+		ScanFilter: map[string]dynamodbtypes.Condition{
+			"type": {ComparisonOperator: dynamodbtypes.ComparisonOperatorNull},
+		},
+	})
+	c.NoError(err)
+	c.Empty(scan.Items)
+
+	item, err := client.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName:       aws.String(tableName),
+		Key:             key,
+		AttributesToGet: []string{"lvl", "missing"},
+	})
+	c.NoError(err)
+	c.Equal(map[string]dynamodbtypes.AttributeValue{"lvl": &dynamodbtypes.AttributeValueMemberN{Value: "6"}}, item.Item)
+
+	out, err = client.Query(context.Background(), &dynamodb.QueryInput{
+		TableName: aws.String(tableName),
+		KeyConditions: map[string]dynamodbtypes.Condition{
+			"id": {
+				ComparisonOperator: dynamodbtypes.ComparisonOperatorEq,
+				AttributeValueList: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberS{Value: "001"}},
+			},
+		},
+		AttributesToGet: []string{"id"},
+	})
+	c.NoError(err)
+	c.Equal([]map[string]dynamodbtypes.AttributeValue{{"id": &dynamodbtypes.AttributeValueMemberS{Value: "001"}}}, out.Items)
+
+	scan, err = client.Scan(context.Background(), &dynamodb.ScanInput{
+		TableName:       aws.String(tableName),
+		AttributesToGet: []string{"id"},
+	})
+	c.NoError(err)
+	c.Equal([]map[string]dynamodbtypes.AttributeValue{{"id": &dynamodbtypes.AttributeValueMemberS{Value: "001"}}}, scan.Items)
+
+	_, err = client.DeleteItem(context.Background(), &dynamodb.DeleteItemInput{
+		TableName:           aws.String(tableName),
+		Key:                 key,
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		Expected: map[string]dynamodbtypes.ExpectedAttributeValue{
+			"id": {Exists: aws.Bool(true), Value: &dynamodbtypes.AttributeValueMemberS{Value: "001"}},
+		},
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "Can not use both expression and non-expression parameters in the same request: Non-expression parameters: {Expected} Expression parameters: {ConditionExpression}")
+
+	_, err = client.DeleteItem(context.Background(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableName),
+		Key:       key,
+		Expected: map[string]dynamodbtypes.ExpectedAttributeValue{
+			"id": {Exists: aws.Bool(false)},
+		},
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "ConditionalCheckFailedException")
+}
+
+func TestConcurrentOperations(t *testing.T) {
+	c := require.New(t)
+
+	client := NewClient()
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	err = AddTable(context.Background(), client, "trainers", "id", "")
+	c.NoError(err)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+
+		id := fmt.Sprintf("%03d", i)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, createPokemon(client, pokemon{ID: id, Type: "grass", Name: "Bulbasaur"}))
+
+			_, err := getPokemon(client, id)
+			assert.NoError(t, err)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_, err := client.Scan(context.Background(), &dynamodb.ScanInput{TableName: aws.String(tableName)})
+			assert.NoError(t, err)
+
+			_, err = client.DescribeTable(context.Background(), &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+			assert.NoError(t, err)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_, err := client.PutItem(context.Background(), &dynamodb.PutItemInput{
+				TableName: aws.String("trainers"),
+				Item: map[string]dynamodbtypes.AttributeValue{
+					"id": &dynamodbtypes.AttributeValueMemberS{Value: id},
+				},
+			})
+			assert.NoError(t, err)
+
+			assert.NoError(t, ClearTable(client, "trainers"))
+
+			_, _ = client.CreateTable(context.Background(), generateAddTableInput("gyms"+id, "id", ""))
+			_, _ = client.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{TableName: aws.String("gyms" + id)})
+		}()
+	}
+
+	wg.Wait()
+
+	out, err := client.Scan(context.Background(), &dynamodb.ScanInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Len(out.Items, 10)
+}
+
 func TestCheckTableName(t *testing.T) {
 	c := require.New(t)
 
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/truora/minidyn/core"
+)
+
+// ExportTableToPointInTime writes the items of the table at the export time to the export directory, the S3
+// bucket is a subdirectory of the directory set with SetExportDirectory and the export is completed when it returns
+func (fd *Client) ExportTableToPointInTime(ctx context.Context, input *dynamodb.ExportTableToPointInTimeInput, opts ...func(*dynamodb.Options)) (*dynamodb.ExportTableToPointInTimeOutput, error) {
+	tableName := core.TableNameFromArn(aws.ToString(input.TableArn))
+
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	table, ok := fd.tables[tableName]
+	if !ok {
+		return nil, &types.TableNotFoundException{Message: aws.String("Table not found: " + tableName)}
+	}
+
+	table.RLock()
+	defer table.RUnlock()
+
+	export, err := core.NewExport(table, mapDynamoToTypesExportTableToPointInTimeInput(input), fd.exportDirectory)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	fd.exports[export.Arn] = export
+
+	return &dynamodb.ExportTableToPointInTimeOutput{
+		ExportDescription: mapCoreToDynamoExportDescription(export),
+	}, nil
+}
+
+// DescribeExport returns the description of the export
+func (fd *Client) DescribeExport(ctx context.Context, input *dynamodb.DescribeExportInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeExportOutput, error) {
+	arn := aws.ToString(input.ExportArn)
+
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	export, ok := fd.exports[arn]
+	if !ok {
+		return nil, &types.ExportNotFoundException{Message: aws.String("Export not found: " + arn)}
+	}
+
+	return &dynamodb.DescribeExportOutput{
+		ExportDescription: mapCoreToDynamoExportDescription(export),
+	}, nil
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+// ImportTable creates a table with the creation parameters and puts the items of the files of the import
+// directory, the S3 bucket is a subdirectory of the directory set with SetImportDirectory. The import is
+// completed or failed when it returns and the table is only created when the import is completed
+func (fd *Client) ImportTable(ctx context.Context, input *dynamodb.ImportTableInput, opts ...func(*dynamodb.Options)) (*dynamodb.ImportTableOutput, error) {
+	if input.TableCreationParameters == nil {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "1 validation error detected: Value null at 'tableCreationParameters' failed to satisfy constraint: Member must not be null"}
+	}
+
+	params := *input.TableCreationParameters
+	tableName := aws.ToString(params.TableName)
+
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	if _, ok := fd.tables[tableName]; ok {
+		return nil, &types.ResourceInUseException{Message: aws.String("Table already exists: " + tableName)}
+	}
+
+	table, err := fd.newTableLocked(&dynamodb.CreateTableInput{
+		TableName:              params.TableName,
+		AttributeDefinitions:   params.AttributeDefinitions,
+		KeySchema:              params.KeySchema,
+		BillingMode:            params.BillingMode,
+		GlobalSecondaryIndexes: params.GlobalSecondaryIndexes,
+		ProvisionedThroughput:  params.ProvisionedThroughput,
+		SSESpecification:       params.SSESpecification,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imp, err := core.NewImport(table, mapDynamoToTypesImportTableInput(input), fd.importDirectory, fd.decompressors)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	imp.Run()
+
+	if imp.Status == core.ImportStatusCompleted {
+		fd.tables[tableName] = table
+	}
+
+	fd.imports[imp.Arn] = imp
+	fd.importParameters[imp.Arn] = &params
+
+	return &dynamodb.ImportTableOutput{
+		ImportTableDescription: mapCoreToDynamoImportTableDescription(imp, &params),
+	}, nil
+}
+
+// DescribeImport returns the description of the import
+func (fd *Client) DescribeImport(ctx context.Context, input *dynamodb.DescribeImportInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeImportOutput, error) {
+	arn := aws.ToString(input.ImportArn)
+
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	imp, ok := fd.imports[arn]
+	if !ok {
+		return nil, &types.ImportNotFoundException{Message: aws.String("Import not found: " + arn)}
+	}
+
+	return &dynamodb.DescribeImportOutput{
+		ImportTableDescription: mapCoreToDynamoImportTableDescription(imp, fd.importParameters[arn]),
+	}, nil
+}
+
+// ListImports returns the imports sorted by start time, the imports are filtered by table ARN and
+// the pages are limited by the PageSize and start after the NextToken
+func (fd *Client) ListImports(ctx context.Context, input *dynamodb.ListImportsInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListImportsOutput, error) {
+	if input == nil {
+		input = &dynamodb.ListImportsInput{}
+	}
+
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	imports, last, err := core.ListImports(fd.imports, aws.ToString(input.TableArn), aws.ToString(input.NextToken), int64(aws.ToInt32(input.PageSize)))
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	output := &dynamodb.ListImportsOutput{
+		ImportSummaryList: make([]types.ImportSummary, 0, len(imports)),
+	}
+
+	for _, imp := range imports {
+		output.ImportSummaryList = append(output.ImportSummaryList, mapCoreToDynamoImportSummary(imp))
+	}
+
+	if last != "" {
+		output.NextToken = aws.String(last)
+	}
+
+	return output, nil
+}
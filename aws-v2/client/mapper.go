@@ -7,6 +7,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
 	"github.com/truora/minidyn/core"
 	"github.com/truora/minidyn/types"
 )
@@ -42,8 +43,56 @@ func mapDynamoToTypesCreateTableInput(input *dynamodb.CreateTableInput) *types.C
 	}
 
 	return &types.CreateTableInput{
-		ProvisionedThroughput: mapDynamoToTypesProvisionedThroughput(input.ProvisionedThroughput),
-		KeySchema:             mapDynamoToTypesKeySchemaElements(input.KeySchema),
+		ProvisionedThroughput:     mapDynamoToTypesProvisionedThroughput(input.ProvisionedThroughput),
+		KeySchema:                 mapDynamoToTypesKeySchemaElements(input.KeySchema),
+		StreamSpecification:       mapDynamoToTypesStreamSpecification(input.StreamSpecification),
+		SSESpecification:          mapDynamoToTypesSSESpecification(input.SSESpecification),
+		TableClass:                toString(string(input.TableClass)),
+		DeletionProtectionEnabled: input.DeletionProtectionEnabled,
+		Tags:                      mapDynamoToTypesTags(input.Tags),
+	}
+}
+
+func mapDynamoToTypesTags(tags []dynamodbtypes.Tag) []*types.Tag {
+	output := make([]*types.Tag, 0, len(tags))
+
+	for _, tag := range tags {
+		output = append(output, &types.Tag{Key: tag.Key, Value: tag.Value})
+	}
+
+	return output
+}
+
+func mapTypesToDynamoTags(tags []*types.Tag) []dynamodbtypes.Tag {
+	output := make([]dynamodbtypes.Tag, 0, len(tags))
+
+	for _, tag := range tags {
+		output = append(output, dynamodbtypes.Tag{Key: tag.Key, Value: tag.Value})
+	}
+
+	return output
+}
+
+func mapDynamoToTypesStreamSpecification(input *dynamodbtypes.StreamSpecification) *types.StreamSpecification {
+	if input == nil {
+		return nil
+	}
+
+	return &types.StreamSpecification{
+		StreamEnabled:  input.StreamEnabled,
+		StreamViewType: toString(string(input.StreamViewType)),
+	}
+}
+
+func mapDynamoToTypesSSESpecification(input *dynamodbtypes.SSESpecification) *types.SSESpecification {
+	if input == nil {
+		return nil
+	}
+
+	return &types.SSESpecification{
+		Enabled:        input.Enabled,
+		KMSMasterKeyID: input.KMSMasterKeyId,
+		SSEType:        toString(string(input.SSEType)),
 	}
 }
 
@@ -161,6 +210,36 @@ func mapDynamoToTypesLocalSecondaryIndexes(input []dynamodbtypes.LocalSecondaryI
 	return output
 }
 
+func mapDynamoToTypesUpdateTableInput(input *dynamodb.UpdateTableInput) *types.UpdateTableInput {
+	output := &types.UpdateTableInput{
+		AttributeDefinitions:      mapDynamoToTypesAttributeDefinitionSlice(input.AttributeDefinitions),
+		BillingMode:               toString(string(input.BillingMode)),
+		ProvisionedThroughput:     mapDynamoToTypesProvisionedThroughput(input.ProvisionedThroughput),
+		StreamSpecification:       mapDynamoToTypesStreamSpecification(input.StreamSpecification),
+		SSESpecification:          mapDynamoToTypesSSESpecification(input.SSESpecification),
+		TableClass:                toString(string(input.TableClass)),
+		DeletionProtectionEnabled: input.DeletionProtectionEnabled,
+	}
+
+	for _, change := range input.GlobalSecondaryIndexUpdates {
+		output.GlobalSecondaryIndexUpdates = append(output.GlobalSecondaryIndexUpdates, mapDynamoTotypesGlobalSecondaryIndexUpdate(change))
+	}
+
+	return output
+}
+
+func mapDynamoToTypesRestoreTableFromBackupInput(input *dynamodb.RestoreTableFromBackupInput) *types.RestoreTableFromBackupInput {
+	return &types.RestoreTableFromBackupInput{
+		BackupArn:                     input.BackupArn,
+		TargetTableName:               input.TargetTableName,
+		BillingModeOverride:           toString(string(input.BillingModeOverride)),
+		GlobalSecondaryIndexOverride:  mapDynamoToTypesGlobalSecondaryIndexes(input.GlobalSecondaryIndexOverride),
+		LocalSecondaryIndexOverride:   mapDynamoToTypesLocalSecondaryIndexes(input.LocalSecondaryIndexOverride),
+		ProvisionedThroughputOverride: mapDynamoToTypesProvisionedThroughput(input.ProvisionedThroughputOverride),
+		SSESpecificationOverride:      mapDynamoToTypesSSESpecification(input.SSESpecificationOverride),
+	}
+}
+
 func mapDynamoTotypesGlobalSecondaryIndexUpdate(input dynamodbtypes.GlobalSecondaryIndexUpdate) *types.GlobalSecondaryIndexUpdate {
 	return &types.GlobalSecondaryIndexUpdate{
 		Create: mapDynamoToTypesCreateGlobalSecondaryIndexAction(input.Create),
@@ -211,6 +290,7 @@ func mapDynamoToTypesPutItemInput(input *dynamodb.PutItemInput) *types.PutItemIn
 	return &types.PutItemInput{
 		ConditionExpression:         input.ConditionExpression,
 		ConditionalOperator:         toString(string(input.ConditionalOperator)),
+		Expected:                    mapDynamoToTypesExpectedAttributeValueMap(input.Expected),
 		ExpressionAttributeNames:    input.ExpressionAttributeNames,
 		ExpressionAttributeValues:   mapDynamoToTypesMapItem(input.ExpressionAttributeValues),
 		Item:                        mapDynamoToTypesMapItem(input.Item),
@@ -252,17 +332,17 @@ func mapDynamoToTypesSliceItem(input []dynamodbtypes.AttributeValue) []*types.It
 func mapDynamoToTypesItem(item dynamodbtypes.AttributeValue) *types.Item {
 	itemB, ok := item.(*dynamodbtypes.AttributeValueMemberB)
 	if ok {
-		return &types.Item{B: itemB.Value}
+		return &types.Item{B: types.CopyBytes(itemB.Value)}
 	}
 
 	itemBOOL, ok := item.(*dynamodbtypes.AttributeValueMemberBOOL)
 	if ok {
-		return &types.Item{BOOL: &itemBOOL.Value}
+		return &types.Item{BOOL: types.CopyBool(&itemBOOL.Value)}
 	}
 
 	itemBS, ok := item.(*dynamodbtypes.AttributeValueMemberBS)
 	if ok {
-		return &types.Item{BS: itemBS.Value}
+		return &types.Item{BS: types.CopyBytesSlice(itemBS.Value)}
 	}
 
 	itemS, ok := item.(*dynamodbtypes.AttributeValueMemberS)
@@ -340,8 +420,54 @@ func mapDynamoToTypesExpectedAttributeValue(input dynamodbtypes.ExpectedAttribut
 		AttributeValueList: mapDynamoToTypesSliceItem(input.AttributeValueList),
 		ComparisonOperator: toString(string(input.ComparisonOperator)),
 		Exists:             input.Exists,
-		Value:              mapDynamoToTypesItem(input.Value),
+		Value:              mapDynamoToTypesOptionalItem(input.Value),
+	}
+}
+
+func mapDynamoToTypesOptionalItem(item dynamodbtypes.AttributeValue) *types.Item {
+	if item == nil {
+		return nil
+	}
+
+	return mapDynamoToTypesItem(item)
+}
+
+func mapDynamoToTypesCondition(input dynamodbtypes.Condition) *types.Condition {
+	return &types.Condition{
+		AttributeValueList: mapDynamoToTypesSliceItem(input.AttributeValueList),
+		ComparisonOperator: toString(string(input.ComparisonOperator)),
+	}
+}
+
+func mapDynamoToTypesConditionMap(input map[string]dynamodbtypes.Condition) map[string]*types.Condition {
+	if len(input) == 0 {
+		return nil
+	}
+
+	output := map[string]*types.Condition{}
+
+	for key, item := range input {
+		output[key] = mapDynamoToTypesCondition(item)
+	}
+
+	return output
+}
+
+func mapDynamoToTypesAttributeValueUpdateMap(input map[string]dynamodbtypes.AttributeValueUpdate) map[string]*types.AttributeValueUpdate {
+	if len(input) == 0 {
+		return nil
+	}
+
+	output := map[string]*types.AttributeValueUpdate{}
+
+	for key, item := range input {
+		output[key] = &types.AttributeValueUpdate{
+			Action: toString(string(item.Action)),
+			Value:  mapDynamoToTypesOptionalItem(item.Value),
+		}
 	}
+
+	return output
 }
 
 func mapDynamoToTypesExpectedAttributeValueMap(input map[string]dynamodbtypes.ExpectedAttributeValue) map[string]*types.ExpectedAttributeValue {
@@ -360,6 +486,7 @@ func mapDynamoToTypesExpectedAttributeValueMap(input map[string]dynamodbtypes.Ex
 
 func mapDynamoToTypesUpdateItemInput(input *dynamodb.UpdateItemInput) *types.UpdateItemInput {
 	return &types.UpdateItemInput{
+		AttributeUpdates:                    mapDynamoToTypesAttributeValueUpdateMap(input.AttributeUpdates),
 		ConditionExpression:                 input.ConditionExpression,
 		ConditionalOperator:                 toString(string(input.ConditionalOperator)),
 		Expected:                            mapDynamoToTypesExpectedAttributeValueMap(input.Expected),
@@ -370,7 +497,7 @@ func mapDynamoToTypesUpdateItemInput(input *dynamodb.UpdateItemInput) *types.Upd
 		ReturnItemCollectionMetrics:         toString(string(input.ReturnItemCollectionMetrics)),
 		ReturnValues:                        toString(string(input.ReturnValues)),
 		TableName:                           input.TableName,
-		UpdateExpression:                    *input.UpdateExpression,
+		UpdateExpression:                    aws.ToString(input.UpdateExpression),
 		ReturnValuesOnConditionCheckFailure: toString(string(input.ReturnValuesOnConditionCheckFailure)),
 	}
 }
@@ -381,6 +508,10 @@ func mapDynamoToTypesQueryInput(input *dynamodb.QueryInput, indexName string) co
 		ExpressionAttributeValues: mapDynamoToTypesMapItem(input.ExpressionAttributeValues),
 		Aliases:                   input.ExpressionAttributeNames,
 		ExclusiveStartKey:         mapDynamoToTypesMapItem(input.ExclusiveStartKey),
+		KeyConditions:             mapDynamoToTypesConditionMap(input.KeyConditions),
+		QueryFilter:               mapDynamoToTypesConditionMap(input.QueryFilter),
+		ConditionalOperator:       string(input.ConditionalOperator),
+		AttributesToGet:           input.AttributesToGet,
 	}
 
 	if input.Limit != nil {
@@ -410,14 +541,325 @@ func mapTypesToDynamoTableDescription(input *types.TableDescription) *dynamodbty
 	}
 
 	return &dynamodbtypes.TableDescription{
-		TableName:              toString(input.TableName),
-		ItemCount:              aws.Int64(input.ItemCount),
-		KeySchema:              mapTypesToDynamoKeySchemaElements(input.KeySchema),
-		GlobalSecondaryIndexes: mapTypesToDynamoTypesGlobalSecondaryIndexes(input.GlobalSecondaryIndexes),
-		LocalSecondaryIndexes:  mapTypesToDynamoLocalSecondaryIndexes(input.LocalSecondaryIndexes),
+		TableName:                 toString(input.TableName),
+		TableStatus:               dynamodbtypes.TableStatus(input.TableStatus),
+		TableArn:                  toString(input.TableArn),
+		TableId:                   toString(input.TableID),
+		CreationDateTime:          aws.Time(input.CreationDateTime),
+		AttributeDefinitions:      mapTypesToDynamoAttributeDefinitions(input.AttributeDefinitions),
+		BillingModeSummary:        mapTypesToDynamoBillingModeSummary(input.BillingModeSummary),
+		ProvisionedThroughput:     mapTypesToDynamoProvisionedThroughput(input.ProvisionedThroughput),
+		RestoreSummary:            mapTypesToDynamoRestoreSummary(input.RestoreSummary),
+		ItemCount:                 aws.Int64(input.ItemCount),
+		TableSizeBytes:            aws.Int64(input.TableSizeBytes),
+		KeySchema:                 mapTypesToDynamoKeySchemaElements(input.KeySchema),
+		GlobalSecondaryIndexes:    mapTypesToDynamoTypesGlobalSecondaryIndexes(input.GlobalSecondaryIndexes),
+		LocalSecondaryIndexes:     mapTypesToDynamoLocalSecondaryIndexes(input.LocalSecondaryIndexes),
+		StreamSpecification:       mapTypesToDynamoStreamSpecification(input.StreamSpecification),
+		LatestStreamLabel:         toString(input.LatestStreamLabel),
+		LatestStreamArn:           toString(input.LatestStreamArn),
+		SSEDescription:            mapTypesToDynamoSSEDescription(input.SSEDescription),
+		TableClassSummary:         mapTypesToDynamoTableClassSummary(input.TableClassSummary),
+		DeletionProtectionEnabled: aws.Bool(input.DeletionProtectionEnabled),
 	}
 }
 
+func mapTypesToDynamoAttributeDefinitions(input []*types.AttributeDefinition) []dynamodbtypes.AttributeDefinition {
+	if len(input) == 0 {
+		return nil
+	}
+
+	output := make([]dynamodbtypes.AttributeDefinition, 0, len(input))
+
+	for _, attr := range input {
+		output = append(output, dynamodbtypes.AttributeDefinition{
+			AttributeName: attr.AttributeName,
+			AttributeType: dynamodbtypes.ScalarAttributeType(aws.ToString(attr.AttributeType)),
+		})
+	}
+
+	return output
+}
+
+func mapTypesToDynamoBillingModeSummary(input *types.BillingModeSummary) *dynamodbtypes.BillingModeSummary {
+	if input == nil {
+		return nil
+	}
+
+	return &dynamodbtypes.BillingModeSummary{
+		BillingMode:                       dynamodbtypes.BillingMode(aws.ToString(input.BillingMode)),
+		LastUpdateToPayPerRequestDateTime: input.LastUpdateToPayPerRequestDateTime,
+	}
+}
+
+func mapTypesToDynamoStreamSpecification(input *types.StreamSpecification) *dynamodbtypes.StreamSpecification {
+	if input == nil {
+		return nil
+	}
+
+	return &dynamodbtypes.StreamSpecification{
+		StreamEnabled:  input.StreamEnabled,
+		StreamViewType: dynamodbtypes.StreamViewType(aws.ToString(input.StreamViewType)),
+	}
+}
+
+func mapTypesToDynamoSSEDescription(input *types.SSEDescription) *dynamodbtypes.SSEDescription {
+	if input == nil {
+		return nil
+	}
+
+	return &dynamodbtypes.SSEDescription{
+		KMSMasterKeyArn: input.KMSMasterKeyArn,
+		SSEType:         dynamodbtypes.SSEType(aws.ToString(input.SSEType)),
+		Status:          dynamodbtypes.SSEStatus(aws.ToString(input.Status)),
+	}
+}
+
+func mapTypesToDynamoTableClassSummary(input *types.TableClassSummary) *dynamodbtypes.TableClassSummary {
+	if input == nil {
+		return nil
+	}
+
+	return &dynamodbtypes.TableClassSummary{
+		TableClass:         dynamodbtypes.TableClass(aws.ToString(input.TableClass)),
+		LastUpdateDateTime: input.LastUpdateDateTime,
+	}
+}
+
+func mapTypesToDynamoRestoreSummary(input *types.RestoreSummary) *dynamodbtypes.RestoreSummary {
+	if input == nil {
+		return nil
+	}
+
+	return &dynamodbtypes.RestoreSummary{
+		RestoreDateTime:   aws.Time(input.RestoreDateTime),
+		RestoreInProgress: aws.Bool(input.RestoreInProgress),
+		SourceBackupArn:   input.SourceBackupArn,
+		SourceTableArn:    input.SourceTableArn,
+	}
+}
+
+func mapDynamoToTypesRestoreTableToPointInTimeInput(input *dynamodb.RestoreTableToPointInTimeInput) *types.RestoreTableToPointInTimeInput {
+	return &types.RestoreTableToPointInTimeInput{
+		SourceTableArn:                input.SourceTableArn,
+		SourceTableName:               input.SourceTableName,
+		TargetTableName:               input.TargetTableName,
+		RestoreDateTime:               input.RestoreDateTime,
+		UseLatestRestorableTime:       input.UseLatestRestorableTime,
+		BillingModeOverride:           toString(string(input.BillingModeOverride)),
+		GlobalSecondaryIndexOverride:  mapDynamoToTypesGlobalSecondaryIndexes(input.GlobalSecondaryIndexOverride),
+		LocalSecondaryIndexOverride:   mapDynamoToTypesLocalSecondaryIndexes(input.LocalSecondaryIndexOverride),
+		ProvisionedThroughputOverride: mapDynamoToTypesProvisionedThroughput(input.ProvisionedThroughputOverride),
+		SSESpecificationOverride:      mapDynamoToTypesSSESpecification(input.SSESpecificationOverride),
+	}
+}
+
+func mapTypesToDynamoContinuousBackupsDescription(input *types.ContinuousBackupsDescription) *dynamodbtypes.ContinuousBackupsDescription {
+	pitr := input.PointInTimeRecoveryDescription
+
+	return &dynamodbtypes.ContinuousBackupsDescription{
+		ContinuousBackupsStatus: dynamodbtypes.ContinuousBackupsStatus(aws.ToString(input.ContinuousBackupsStatus)),
+		PointInTimeRecoveryDescription: &dynamodbtypes.PointInTimeRecoveryDescription{
+			EarliestRestorableDateTime: pitr.EarliestRestorableDateTime,
+			LatestRestorableDateTime:   pitr.LatestRestorableDateTime,
+			PointInTimeRecoveryStatus:  dynamodbtypes.PointInTimeRecoveryStatus(aws.ToString(pitr.PointInTimeRecoveryStatus)),
+		},
+	}
+}
+
+func mapDynamoToTypesExportTableToPointInTimeInput(input *dynamodb.ExportTableToPointInTimeInput) *types.ExportTableToPointInTimeInput {
+	return &types.ExportTableToPointInTimeInput{
+		TableArn:       input.TableArn,
+		S3Bucket:       input.S3Bucket,
+		S3BucketOwner:  input.S3BucketOwner,
+		S3Prefix:       input.S3Prefix,
+		S3SseAlgorithm: toString(string(input.S3SseAlgorithm)),
+		S3SseKmsKeyID:  input.S3SseKmsKeyId,
+		ExportFormat:   toString(string(input.ExportFormat)),
+		ExportType:     toString(string(input.ExportType)),
+		ExportTime:     input.ExportTime,
+		ClientToken:    input.ClientToken,
+	}
+}
+
+func mapCoreToDynamoExportDescription(export *core.Export) *dynamodbtypes.ExportDescription {
+	return &dynamodbtypes.ExportDescription{
+		ExportArn:       aws.String(export.Arn),
+		ExportStatus:    dynamodbtypes.ExportStatus(export.Status),
+		ExportFormat:    dynamodbtypes.ExportFormat(export.Format),
+		ExportType:      dynamodbtypes.ExportType(export.Type),
+		ExportManifest:  aws.String(export.ManifestKey),
+		ExportTime:      aws.Time(export.ExportTime),
+		StartTime:       aws.Time(export.StartTime),
+		EndTime:         aws.Time(export.EndTime),
+		TableArn:        aws.String(export.TableArn),
+		TableId:         aws.String(export.TableID),
+		S3Bucket:        aws.String(export.S3Bucket),
+		S3BucketOwner:   toString(export.S3BucketOwner),
+		S3Prefix:        toString(export.S3Prefix),
+		S3SseAlgorithm:  dynamodbtypes.S3SseAlgorithm(export.S3SseAlgorithm),
+		S3SseKmsKeyId:   toString(export.S3SseKmsKeyID),
+		ClientToken:     toString(export.ClientToken),
+		ItemCount:       aws.Int64(export.ItemCount),
+		BilledSizeBytes: aws.Int64(export.BilledSizeBytes),
+	}
+}
+
+func mapDynamoToTypesImportTableInput(input *dynamodb.ImportTableInput) *types.ImportTableInput {
+	output := &types.ImportTableInput{
+		ClientToken:          input.ClientToken,
+		InputCompressionType: toString(string(input.InputCompressionType)),
+		InputFormat:          aws.String(string(input.InputFormat)),
+	}
+
+	if source := input.S3BucketSource; source != nil {
+		output.S3BucketSource = &types.S3BucketSource{
+			S3Bucket:      source.S3Bucket,
+			S3BucketOwner: source.S3BucketOwner,
+			S3KeyPrefix:   source.S3KeyPrefix,
+		}
+	}
+
+	if options := input.InputFormatOptions; options != nil {
+		output.InputFormatOptions = &types.InputFormatOptions{}
+
+		if options.Csv != nil {
+			output.InputFormatOptions.Csv = &types.CsvOptions{
+				Delimiter:  options.Csv.Delimiter,
+				HeaderList: toStringSlice(options.Csv.HeaderList),
+			}
+		}
+	}
+
+	return output
+}
+
+func mapCoreToDynamoImportTableDescription(imp *core.Import, params *dynamodbtypes.TableCreationParameters) *dynamodbtypes.ImportTableDescription {
+	output := &dynamodbtypes.ImportTableDescription{
+		ImportArn:               aws.String(imp.Arn),
+		ImportStatus:            dynamodbtypes.ImportStatus(imp.Status),
+		InputFormat:             dynamodbtypes.InputFormat(imp.Format),
+		InputCompressionType:    dynamodbtypes.InputCompressionType(imp.CompressionType),
+		TableArn:                aws.String(imp.TableArn),
+		TableId:                 aws.String(imp.TableID),
+		TableCreationParameters: params,
+		S3BucketSource:          mapCoreToDynamoS3BucketSource(imp),
+		ClientToken:             toString(imp.ClientToken),
+		StartTime:               aws.Time(imp.StartTime),
+		EndTime:                 aws.Time(imp.EndTime),
+		FailureCode:             toString(imp.FailureCode),
+		FailureMessage:          toString(imp.FailureMessage),
+		ProcessedItemCount:      imp.ProcessedItems,
+		ImportedItemCount:       imp.ImportedItems,
+		ErrorCount:              imp.ErrorCount,
+		ProcessedSizeBytes:      aws.Int64(imp.ProcessedBytes),
+	}
+
+	if imp.CsvOptions != nil {
+		output.InputFormatOptions = &dynamodbtypes.InputFormatOptions{
+			Csv: &dynamodbtypes.CsvOptions{
+				Delimiter:  imp.CsvOptions.Delimiter,
+				HeaderList: toStringValueSlice(imp.CsvOptions.HeaderList),
+			},
+		}
+	}
+
+	return output
+}
+
+func mapCoreToDynamoImportSummary(imp *core.Import) dynamodbtypes.ImportSummary {
+	return dynamodbtypes.ImportSummary{
+		ImportArn:      aws.String(imp.Arn),
+		ImportStatus:   dynamodbtypes.ImportStatus(imp.Status),
+		InputFormat:    dynamodbtypes.InputFormat(imp.Format),
+		TableArn:       aws.String(imp.TableArn),
+		S3BucketSource: mapCoreToDynamoS3BucketSource(imp),
+		StartTime:      aws.Time(imp.StartTime),
+		EndTime:        aws.Time(imp.EndTime),
+	}
+}
+
+func mapCoreToDynamoS3BucketSource(imp *core.Import) *dynamodbtypes.S3BucketSource {
+	return &dynamodbtypes.S3BucketSource{
+		S3Bucket:      aws.String(imp.S3Bucket),
+		S3BucketOwner: toString(imp.S3BucketOwner),
+		S3KeyPrefix:   toString(imp.S3KeyPrefix),
+	}
+}
+
+func mapCoreToDynamoBackupDetails(backup *core.Backup) *dynamodbtypes.BackupDetails {
+	return &dynamodbtypes.BackupDetails{
+		BackupArn:              aws.String(backup.Arn),
+		BackupName:             aws.String(backup.Name),
+		BackupStatus:           dynamodbtypes.BackupStatus(backup.Status),
+		BackupType:             dynamodbtypes.BackupType(backup.Type),
+		BackupCreationDateTime: aws.Time(backup.CreationDateTime),
+		BackupSizeBytes:        aws.Int64(backup.Source.TableSizeBytes),
+	}
+}
+
+func mapCoreToDynamoBackupSummary(backup *core.Backup) dynamodbtypes.BackupSummary {
+	return dynamodbtypes.BackupSummary{
+		BackupArn:              aws.String(backup.Arn),
+		BackupName:             aws.String(backup.Name),
+		BackupStatus:           dynamodbtypes.BackupStatus(backup.Status),
+		BackupType:             dynamodbtypes.BackupType(backup.Type),
+		BackupCreationDateTime: aws.Time(backup.CreationDateTime),
+		BackupSizeBytes:        aws.Int64(backup.Source.TableSizeBytes),
+		TableArn:               aws.String(backup.Source.TableArn),
+		TableId:                aws.String(backup.Source.TableID),
+		TableName:              aws.String(backup.Source.TableName),
+	}
+}
+
+func mapCoreToDynamoBackupDescription(backup *core.Backup) *dynamodbtypes.BackupDescription {
+	source := backup.Source
+
+	output := &dynamodbtypes.BackupDescription{
+		BackupDetails: mapCoreToDynamoBackupDetails(backup),
+		SourceTableDetails: &dynamodbtypes.SourceTableDetails{
+			TableName:             aws.String(source.TableName),
+			TableArn:              aws.String(source.TableArn),
+			TableId:               aws.String(source.TableID),
+			TableCreationDateTime: aws.Time(source.CreationDateTime),
+			TableSizeBytes:        aws.Int64(source.TableSizeBytes),
+			ItemCount:             aws.Int64(source.ItemCount),
+			KeySchema:             mapTypesToDynamoKeySchemaElements(source.KeySchema),
+			BillingMode:           dynamodbtypes.BillingMode(aws.ToString(source.BillingModeSummary.BillingMode)),
+			ProvisionedThroughput: &dynamodbtypes.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(source.ProvisionedThroughput.ReadCapacityUnits),
+				WriteCapacityUnits: aws.Int64(source.ProvisionedThroughput.WriteCapacityUnits),
+			},
+		},
+		SourceTableFeatureDetails: &dynamodbtypes.SourceTableFeatureDetails{
+			SSEDescription:    mapTypesToDynamoSSEDescription(source.SSEDescription),
+			StreamDescription: mapTypesToDynamoStreamSpecification(source.StreamSpecification),
+		},
+	}
+
+	for _, gsi := range source.GlobalSecondaryIndexes {
+		output.SourceTableFeatureDetails.GlobalSecondaryIndexes = append(output.SourceTableFeatureDetails.GlobalSecondaryIndexes, dynamodbtypes.GlobalSecondaryIndexInfo{
+			IndexName:  gsi.IndexName,
+			KeySchema:  mapTypesToDynamoKeySchemaElements(gsi.KeySchema),
+			Projection: mapTypesToDynamoProjection(gsi.Projection),
+			ProvisionedThroughput: &dynamodbtypes.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(gsi.ProvisionedThroughput.ReadCapacityUnits),
+				WriteCapacityUnits: aws.Int64(gsi.ProvisionedThroughput.WriteCapacityUnits),
+			},
+		})
+	}
+
+	for _, lsi := range source.LocalSecondaryIndexes {
+		output.SourceTableFeatureDetails.LocalSecondaryIndexes = append(output.SourceTableFeatureDetails.LocalSecondaryIndexes, dynamodbtypes.LocalSecondaryIndexInfo{
+			IndexName:  lsi.IndexName,
+			KeySchema:  mapTypesToDynamoKeySchemaElements(lsi.KeySchema),
+			Projection: mapTypesToDynamoProjection(lsi.Projection),
+		})
+	}
+
+	return output
+}
+
 func mapTypesToDynamoKeySchemaElements(input []types.KeySchemaElement) []dynamodbtypes.KeySchemaElement {
 	if len(input) == 0 || input == nil {
 		return nil
@@ -459,14 +901,15 @@ func mapTypesToDynamoKeySchemaElementsPointer(input []*types.KeySchemaElement) [
 
 func mapTypesToDynamoGlobalSecondaryIndex(input types.GlobalSecondaryIndexDescription) dynamodbtypes.GlobalSecondaryIndexDescription {
 	return dynamodbtypes.GlobalSecondaryIndexDescription{
-		IndexName:      input.IndexName,
-		KeySchema:      mapTypesToDynamoKeySchemaElements(input.KeySchema),
-		Projection:     mapTypesToDynamoProjection(input.Projection),
-		Backfilling:    input.Backfilling,
-		IndexArn:       input.IndexArn,
-		IndexSizeBytes: input.IndexSizeBytes,
-		IndexStatus:    dynamodbtypes.IndexStatus(aws.ToString(input.IndexStatus)),
-		ItemCount:      aws.Int64(input.ItemCount),
+		IndexName:             input.IndexName,
+		KeySchema:             mapTypesToDynamoKeySchemaElements(input.KeySchema),
+		Projection:            mapTypesToDynamoProjection(input.Projection),
+		Backfilling:           input.Backfilling,
+		IndexArn:              input.IndexArn,
+		IndexSizeBytes:        input.IndexSizeBytes,
+		IndexStatus:           dynamodbtypes.IndexStatus(aws.ToString(input.IndexStatus)),
+		ItemCount:             aws.Int64(input.ItemCount),
+		ProvisionedThroughput: mapTypesToDynamoProvisionedThroughput(input.ProvisionedThroughput),
 	}
 }
 
@@ -477,17 +920,21 @@ func mapTypesToDynamoProjection(input *types.Projection) *dynamodbtypes.Projecti
 
 	return &dynamodbtypes.Projection{
 		NonKeyAttributes: mapTypesToDynamoStringSlice(input.NonKeyAttributes),
+		ProjectionType:   dynamodbtypes.ProjectionType(aws.ToString(input.ProjectionType)),
 	}
 }
 
-func mapTypesToDynamoProvisionedThroughput(input *types.ProvisionedThroughput) *dynamodbtypes.ProvisionedThroughputDescription {
+func mapTypesToDynamoProvisionedThroughput(input *types.ProvisionedThroughputDescription) *dynamodbtypes.ProvisionedThroughputDescription {
 	if input == nil {
 		return nil
 	}
 
 	return &dynamodbtypes.ProvisionedThroughputDescription{
-		ReadCapacityUnits:  aws.Int64(input.ReadCapacityUnits),
-		WriteCapacityUnits: aws.Int64(input.WriteCapacityUnits),
+		ReadCapacityUnits:      aws.Int64(input.ReadCapacityUnits),
+		WriteCapacityUnits:     aws.Int64(input.WriteCapacityUnits),
+		NumberOfDecreasesToday: aws.Int64(input.NumberOfDecreasesToday),
+		LastIncreaseDateTime:   input.LastIncreaseDateTime,
+		LastDecreaseDateTime:   input.LastDecreaseDateTime,
 	}
 }
 
@@ -521,9 +968,12 @@ func mapTypesToDynamoTypesGlobalSecondaryIndexes(input []types.GlobalSecondaryIn
 
 func mapTypesToDynamoLocalSecondaryIndex(input types.LocalSecondaryIndexDescription) dynamodbtypes.LocalSecondaryIndexDescription {
 	return dynamodbtypes.LocalSecondaryIndexDescription{
-		IndexName:  input.IndexName,
-		KeySchema:  mapTypesToDynamoKeySchemaElements(input.KeySchema),
-		Projection: mapTypesToDynamoProjection(input.Projection),
+		IndexName:      input.IndexName,
+		KeySchema:      mapTypesToDynamoKeySchemaElements(input.KeySchema),
+		Projection:     mapTypesToDynamoProjection(input.Projection),
+		IndexArn:       input.IndexArn,
+		IndexSizeBytes: input.IndexSizeBytes,
+		ItemCount:      aws.Int64(input.ItemCount),
 	}
 }
 
@@ -656,6 +1106,28 @@ func toString(str string) *string {
 	return aws.String(str)
 }
 
+// knownErrors builds the SDK errors that only carry the message of the core errors
+var knownErrors = map[string]func(msg *string) error{
+	"ResourceNotFoundException": func(msg *string) error {
+		return &dynamodbtypes.ResourceNotFoundException{Message: msg}
+	},
+	"ResourceInUseException": func(msg *string) error {
+		return &dynamodbtypes.ResourceInUseException{Message: msg}
+	},
+	"LimitExceededException": func(msg *string) error {
+		return &dynamodbtypes.LimitExceededException{Message: msg}
+	},
+	"PointInTimeRecoveryUnavailableException": func(msg *string) error {
+		return &dynamodbtypes.PointInTimeRecoveryUnavailableException{Message: msg}
+	},
+	"InvalidRestoreTimeException": func(msg *string) error {
+		return &dynamodbtypes.InvalidRestoreTimeException{Message: msg}
+	},
+	"InvalidExportTimeException": func(msg *string) error {
+		return &dynamodbtypes.InvalidExportTimeException{Message: msg}
+	},
+}
+
 func mapKnownError(err error) error {
 	var intErr types.Error
 
@@ -675,8 +1147,12 @@ func mapKnownError(err error) error {
 		}
 
 		return checkErr
-	case "ResourceNotFoundException":
-		return &dynamodbtypes.ResourceNotFoundException{Message: aws.String(intErr.Message())}
+	case "ValidationException":
+		return &smithy.GenericAPIError{Code: intErr.Code(), Message: intErr.Message()}
+	}
+
+	if mapErr, ok := knownErrors[intErr.Code()]; ok {
+		return mapErr(aws.String(intErr.Message()))
 	}
 
 	return err
@@ -2,7 +2,9 @@ package client
 
 import (
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/require"
@@ -52,6 +54,47 @@ func TestMapDynamoToTypes(t *testing.T) {
 	c.Equal(expectedMap, item.M)
 }
 
+func TestMapDynamoToTypesImportTableInput(t *testing.T) {
+	c := require.New(t)
+
+	output := mapDynamoToTypesImportTableInput(&dynamodb.ImportTableInput{InputFormat: dynamodbtypes.InputFormatIon})
+	c.Equal("ION", types.StringValue(output.InputFormat))
+	c.Nil(output.InputCompressionType)
+	c.Nil(output.S3BucketSource)
+	c.Nil(output.InputFormatOptions)
+
+	output = mapDynamoToTypesImportTableInput(&dynamodb.ImportTableInput{
+		ClientToken:          aws.String("token"),
+		InputFormat:          dynamodbtypes.InputFormatCsv,
+		InputCompressionType: dynamodbtypes.InputCompressionTypeGzip,
+		InputFormatOptions:   &dynamodbtypes.InputFormatOptions{},
+		S3BucketSource: &dynamodbtypes.S3BucketSource{
+			S3Bucket:      aws.String("fixtures"),
+			S3BucketOwner: aws.String("123456789012"),
+			S3KeyPrefix:   aws.String("csv/"),
+		},
+	})
+	c.Equal("token", types.StringValue(output.ClientToken))
+	c.Equal("GZIP", types.StringValue(output.InputCompressionType))
+	c.Equal(&types.S3BucketSource{
+		S3Bucket:      aws.String("fixtures"),
+		S3BucketOwner: aws.String("123456789012"),
+		S3KeyPrefix:   aws.String("csv/"),
+	}, output.S3BucketSource)
+	c.Nil(output.InputFormatOptions.Csv)
+
+	output = mapDynamoToTypesImportTableInput(&dynamodb.ImportTableInput{
+		InputFormat: dynamodbtypes.InputFormatCsv,
+		InputFormatOptions: &dynamodbtypes.InputFormatOptions{
+			Csv: &dynamodbtypes.CsvOptions{Delimiter: aws.String(";"), HeaderList: []string{"id", "type"}},
+		},
+	})
+	c.Equal(&types.CsvOptions{
+		Delimiter:  aws.String(";"),
+		HeaderList: []*string{aws.String("id"), aws.String("type")},
+	}, output.InputFormatOptions.Csv)
+}
+
 func TestMapTypesToDynamo(t *testing.T) {
 	c := require.New(t)
 
@@ -61,7 +104,7 @@ func TestMapTypesToDynamo(t *testing.T) {
 	provisionedThroughputOutput := mapTypesToDynamoProvisionedThroughput(nil)
 	c.Nil(provisionedThroughputOutput)
 
-	provisionedThroughputOutput = mapTypesToDynamoProvisionedThroughput(&types.ProvisionedThroughput{ReadCapacityUnits: 1})
+	provisionedThroughputOutput = mapTypesToDynamoProvisionedThroughput(&types.ProvisionedThroughputDescription{ReadCapacityUnits: 1})
 	c.Equal(aws.Int64(1), provisionedThroughputOutput.ReadCapacityUnits)
 
 	var expectedAttributteValue dynamodbtypes.AttributeValue = &dynamodbtypes.AttributeValueMemberNULL{Value: true}
@@ -109,6 +152,33 @@ func TestMapTypesToDynamo(t *testing.T) {
 	c.Nil(projection)
 }
 
+func TestMapTypesToDynamoSummaries(t *testing.T) {
+	c := require.New(t)
+
+	c.Nil(mapTypesToDynamoBillingModeSummary(nil))
+	c.Nil(mapTypesToDynamoTableClassSummary(nil))
+
+	updated := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	billingMode := mapTypesToDynamoBillingModeSummary(&types.BillingModeSummary{
+		BillingMode:                       types.ToString("PAY_PER_REQUEST"),
+		LastUpdateToPayPerRequestDateTime: &updated,
+	})
+	c.Equal(&dynamodbtypes.BillingModeSummary{
+		BillingMode:                       dynamodbtypes.BillingModePayPerRequest,
+		LastUpdateToPayPerRequestDateTime: &updated,
+	}, billingMode)
+
+	tableClass := mapTypesToDynamoTableClassSummary(&types.TableClassSummary{
+		TableClass:         types.ToString("STANDARD_INFREQUENT_ACCESS"),
+		LastUpdateDateTime: &updated,
+	})
+	c.Equal(&dynamodbtypes.TableClassSummary{
+		TableClass:         dynamodbtypes.TableClassStandardInfrequentAccess,
+		LastUpdateDateTime: &updated,
+	}, tableClass)
+}
+
 func TestMapDynamoToTypesProjection(t *testing.T) {
 	c := require.New(t)
 
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/truora/minidyn/core"
+)
+
+// UpdateContinuousBackups enables or disables the point in time recovery of the table, the table keeps
+// the history of its writes stamped with the client clock while the recovery is enabled
+func (fd *Client) UpdateContinuousBackups(ctx context.Context, input *dynamodb.UpdateContinuousBackupsInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateContinuousBackupsOutput, error) {
+	tableName := aws.ToString(input.TableName)
+
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	table, ok := fd.tables[tableName]
+	if !ok {
+		return nil, &types.TableNotFoundException{Message: aws.String("Table not found: " + tableName)}
+	}
+
+	table.Lock()
+	defer table.Unlock()
+
+	if err := table.ValidateAvailable(); err != nil {
+		return nil, &types.TableNotFoundException{Message: aws.String("Table not found: " + tableName)}
+	}
+
+	var enabled *bool
+	if input.PointInTimeRecoverySpecification != nil {
+		enabled = input.PointInTimeRecoverySpecification.PointInTimeRecoveryEnabled
+	}
+
+	if err := table.UpdateContinuousBackups(enabled); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	return &dynamodb.UpdateContinuousBackupsOutput{
+		ContinuousBackupsDescription: mapTypesToDynamoContinuousBackupsDescription(table.ContinuousBackupsDescription()),
+	}, nil
+}
+
+// DescribeContinuousBackups returns the point in time recovery status of the table and its restorable window
+func (fd *Client) DescribeContinuousBackups(ctx context.Context, input *dynamodb.DescribeContinuousBackupsInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeContinuousBackupsOutput, error) {
+	tableName := aws.ToString(input.TableName)
+
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	table, ok := fd.tables[tableName]
+	if !ok {
+		return nil, &types.TableNotFoundException{Message: aws.String("Table not found: " + tableName)}
+	}
+
+	table.RLock()
+	defer table.RUnlock()
+
+	return &dynamodb.DescribeContinuousBackupsOutput{
+		ContinuousBackupsDescription: mapTypesToDynamoContinuousBackupsDescription(table.ContinuousBackupsDescription()),
+	}, nil
+}
+
+// RestoreTableToPointInTime creates a new table with the items of the source table at the restore time,
+// the restored table has the current definition, settings and indexes of the source table and the
+// overrides of the input replace its billing mode, throughput, encryption and indexes
+func (fd *Client) RestoreTableToPointInTime(ctx context.Context, input *dynamodb.RestoreTableToPointInTimeInput, opts ...func(*dynamodb.Options)) (*dynamodb.RestoreTableToPointInTimeOutput, error) {
+	sourceName := aws.ToString(input.SourceTableName)
+	if sourceName == "" {
+		sourceName = core.TableNameFromArn(aws.ToString(input.SourceTableArn))
+	}
+
+	tableName := aws.ToString(input.TargetTableName)
+
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	source, ok := fd.tables[sourceName]
+	if !ok {
+		return nil, &types.TableNotFoundException{Message: aws.String("Table not found: " + sourceName)}
+	}
+
+	if _, ok := fd.tables[tableName]; ok {
+		return nil, &types.TableAlreadyExistsException{Message: aws.String("Table already exists: " + tableName)}
+	}
+
+	source.RLock()
+	defer source.RUnlock()
+
+	table, err := source.RestoreToPointInTime(mapDynamoToTypesRestoreTableToPointInTimeInput(input))
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	fd.setupTable(table)
+	fd.tables[tableName] = table
+
+	return &dynamodb.RestoreTableToPointInTimeOutput{
+		TableDescription: mapTypesToDynamoTableDescription(table.Description(tableName)),
+	}, nil
+}
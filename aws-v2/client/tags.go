@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/truora/minidyn/core"
+)
+
+// TagResource adds the tags to the table of the ARN, the tags with an existing key replace its value
+func (fd *Client) TagResource(ctx context.Context, input *dynamodb.TagResourceInput, opts ...func(*dynamodb.Options)) (*dynamodb.TagResourceOutput, error) {
+	table, err := fd.getTaggedTable(aws.ToString(input.ResourceArn))
+	if err != nil {
+		return nil, err
+	}
+
+	table.Lock()
+	defer table.Unlock()
+
+	if err := table.TagResource(mapDynamoToTypesTags(input.Tags)); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	return &dynamodb.TagResourceOutput{}, nil
+}
+
+// UntagResource removes the tags with the keys from the table of the ARN
+func (fd *Client) UntagResource(ctx context.Context, input *dynamodb.UntagResourceInput, opts ...func(*dynamodb.Options)) (*dynamodb.UntagResourceOutput, error) {
+	table, err := fd.getTaggedTable(aws.ToString(input.ResourceArn))
+	if err != nil {
+		return nil, err
+	}
+
+	table.Lock()
+	defer table.Unlock()
+
+	if err := table.UntagResource(toStringSlice(input.TagKeys)); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	return &dynamodb.UntagResourceOutput{}, nil
+}
+
+// ListTagsOfResource returns a page of the tags of the table of the ARN sorted by key, the NextToken
+// of the output is set when there are more tags
+func (fd *Client) ListTagsOfResource(ctx context.Context, input *dynamodb.ListTagsOfResourceInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListTagsOfResourceOutput, error) {
+	table, err := fd.getTaggedTable(aws.ToString(input.ResourceArn))
+	if err != nil {
+		return nil, err
+	}
+
+	table.RLock()
+	defer table.RUnlock()
+
+	tags, last := table.ListTags(aws.ToString(input.NextToken))
+
+	output := &dynamodb.ListTagsOfResourceOutput{
+		Tags: mapTypesToDynamoTags(tags),
+	}
+
+	if last != "" {
+		output.NextToken = aws.String(last)
+	}
+
+	return output, nil
+}
+
+// getTaggedTable looks up the table of the ARN, the tags are stored by the tables
+func (fd *Client) getTaggedTable(arn string) (*core.Table, error) {
+	fd.mu.RLock()
+	defer fd.mu.RUnlock()
+
+	table, ok := fd.tables[core.TableNameFromArn(arn)]
+	if !ok || table.Arn() != arn {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("Requested resource not found: ResourcArn: " + arn + " not found")}
+	}
+
+	return table, nil
+}
@@ -0,0 +1,402 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// BackupStatusAvailable is the status of the backups ready to be restored
+	BackupStatusAvailable = "AVAILABLE"
+	// BackupStatusDeleted is the status reported by the deleted backups
+	BackupStatusDeleted = "DELETED"
+	// BackupTypeUser is the type of the on-demand backups
+	BackupTypeUser = "USER"
+
+	backupTypeAll       = "ALL"
+	backupNameMinLength = 3
+	backupNameMaxLength = 255
+	listBackupsMaxLimit = 100
+
+	// revive:disable-next-line
+	backupNameLengthMsg = "1 validation error detected: Value '%s' at 'backupName' failed to satisfy constraint: Member must have length between 3 and 255"
+	// revive:disable-next-line
+	backupNamePatternMsg = "1 validation error detected: Value '%s' at 'backupName' failed to satisfy constraint: Member must satisfy regular expression pattern: [a-zA-Z0-9_.-]+"
+	// revive:disable-next-line
+	backupTypeEnumMsg = "1 validation error detected: Value '%s' at 'backupType' failed to satisfy constraint: Member must satisfy enum value set: [USER, SYSTEM, AWS_BACKUP, ALL]"
+	// revive:disable-next-line
+	listBackupsLimitMsg = "1 validation error detected: Value '%d' at 'limit' failed to satisfy constraint: Member must have value between 1 and 100"
+	// revive:disable-next-line
+	missingBackupIndexMsg = "One or more parameter values were invalid: The index %s to restore does not exist in the source table"
+)
+
+var (
+	backupNameRegex   = regexp.MustCompile(`^[a-zA-Z0-9_.\-]+$`)
+	backupTypeFilters = map[string]bool{BackupTypeUser: true, "SYSTEM": false, "AWS_BACKUP": false, backupTypeAll: true}
+)
+
+// Backup is an on-demand backup of a table, it keeps a copy of the items, indexes and settings
+// that does not change with the table
+type Backup struct {
+	Arn              string
+	Name             string
+	Status           string
+	Type             string
+	CreationDateTime time.Time
+	// Source is the description of the table when the backup was created
+	Source *types.TableDescription
+	table  *Table
+}
+
+// NewBackup creates a backup of the table, the caller must hold the table read lock
+func NewBackup(t *Table, name string) (*Backup, error) {
+	if err := validateBackupName(name); err != nil {
+		return nil, err
+	}
+
+	now := t.now()
+
+	return &Backup{
+		Arn:              fmt.Sprintf("%s/backup/%014d-%s", t.Arn(), now.UnixMilli(), newTableID()[:8]),
+		Name:             name,
+		Status:           BackupStatusAvailable,
+		Type:             BackupTypeUser,
+		CreationDateTime: now,
+		Source:           t.Description(t.Name),
+		table:            t.snapshot(t.Data),
+	}, nil
+}
+
+func validateBackupName(name string) error {
+	if len(name) < backupNameMinLength || len(name) > backupNameMaxLength {
+		return types.NewError("ValidationException", fmt.Sprintf(backupNameLengthMsg, name), nil)
+	}
+
+	if !backupNameRegex.MatchString(name) {
+		return types.NewError("ValidationException", fmt.Sprintf(backupNamePatternMsg, name), nil)
+	}
+
+	return nil
+}
+
+// snapshot returns a copy of the definition, settings and indexes of the table with the given items
+func (t *Table) snapshot(data map[string]map[string]*types.Item) *Table {
+	s := NewTable(t.Name)
+	s.TableID = t.TableID
+	s.CreationDateTime = t.CreationDateTime
+	s.Clock = t.Clock
+	s.KeySchema = t.KeySchema
+	s.BillingMode = types.ToString(t.billingMode())
+	s.TableClass = t.TableClass
+	s.throughput = t.throughput.clone()
+	s.sse = t.sse
+
+	for name, typ := range t.AttributesDef {
+		s.AttributesDef[name] = typ
+	}
+
+	for name, i := range t.Indexes {
+		s.Indexes[name] = s.copyIndex(i, i.throughput.clone())
+	}
+
+	s.load(data)
+
+	return s
+}
+
+func (t *Table) copyIndex(i *index, tp *throughput) *index {
+	c := newIndex(t, i.typ, i.keySchema)
+	c.projection = i.projection
+	c.throughput = tp
+
+	return c
+}
+
+// load stores a copy of the items and adds them to the indexes
+func (t *Table) load(data map[string]map[string]*types.Item) {
+	for key, item := range data {
+		item = copyItem(item)
+		t.setItem(key, item)
+
+		for _, i := range t.Indexes {
+			// the items were validated when they were stored, the items with invalid index keys
+			// are left out of the index
+			_ = i.putData(key, item)
+		}
+	}
+}
+
+// clone returns the capacity units without the history of changes
+func (tp *throughput) clone() *throughput {
+	if tp == nil {
+		return nil
+	}
+
+	return &throughput{read: tp.read, write: tp.write}
+}
+
+// Restore creates a table with the target name from the backup. The overrides replace the billing mode,
+// the throughput and the encryption of the backup, the index overrides select the indexes kept by the
+// restored table and set their throughput. The streams and the deletion protection are not restored
+func (b *Backup) Restore(input *types.RestoreTableFromBackupInput) (*Table, error) {
+	restored, err := b.table.restore(input)
+	if err != nil {
+		return nil, err
+	}
+
+	restored.restoreSummary = &types.RestoreSummary{
+		RestoreDateTime: b.CreationDateTime,
+		SourceBackupArn: types.ToString(b.Arn),
+		SourceTableArn:  types.ToString(b.Source.TableArn),
+	}
+
+	return restored, nil
+}
+
+// restore creates a table with the target name, the definition, settings, indexes and items
+// of the snapshot and the overrides of the input
+func (t *Table) restore(input *types.RestoreTableFromBackupInput) (*Table, error) {
+	mode := t.billingMode()
+	if input.BillingModeOverride != nil {
+		mode = types.StringValue(input.BillingModeOverride)
+	}
+
+	if mode != BillingModeProvisioned && mode != BillingModePayPerRequest {
+		return nil, types.NewError("ValidationException", fmt.Sprintf(billingModeEnumMsg, mode), nil)
+	}
+
+	tp, err := restoredThroughput(t.throughput, input.ProvisionedThroughputOverride, mode, "")
+	if err != nil {
+		return nil, err
+	}
+
+	restored := NewTable(types.StringValue(input.TargetTableName))
+	restored.KeySchema = t.KeySchema
+	restored.BillingMode = types.ToString(mode)
+	restored.TableClass = t.TableClass
+	restored.Clock = t.Clock
+	restored.throughput = tp
+	restored.sse = t.sse
+
+	if input.SSESpecificationOverride != nil {
+		restored.sse = input.SSESpecificationOverride
+	}
+
+	for name, typ := range t.AttributesDef {
+		restored.AttributesDef[name] = typ
+	}
+
+	if err := t.restoreIndexes(restored, input, mode); err != nil {
+		return nil, err
+	}
+
+	restored.load(t.Data)
+
+	return restored, nil
+}
+
+func (t *Table) restoreIndexes(restored *Table, input *types.RestoreTableFromBackupInput, mode string) error {
+	selected, err := t.selectIndexes(input)
+	if err != nil {
+		return err
+	}
+
+	for name, override := range selected {
+		i := t.Indexes[name]
+
+		var tp *throughput
+
+		if i.typ == indexTypeGlobal {
+			tp, err = restoredThroughput(i.throughput, override, mode, name)
+			if err != nil {
+				return err
+			}
+		}
+
+		restored.Indexes[name] = restored.copyIndex(i, tp)
+	}
+
+	return nil
+}
+
+// selectIndexes returns the indexes kept by the restore with their throughput override,
+// every index of a kind is kept when there is no override for that kind
+func (t *Table) selectIndexes(input *types.RestoreTableFromBackupInput) (map[string]*types.ProvisionedThroughput, error) {
+	selected := t.indexesWithoutOverride(input)
+
+	for _, gsi := range input.GlobalSecondaryIndexOverride {
+		name := types.StringValue(gsi.IndexName)
+		if err := t.validateIndexOverride(name, indexTypeGlobal); err != nil {
+			return nil, err
+		}
+
+		selected[name] = gsi.ProvisionedThroughput
+	}
+
+	for _, lsi := range input.LocalSecondaryIndexOverride {
+		name := types.StringValue(lsi.IndexName)
+		if err := t.validateIndexOverride(name, indexTypeLocal); err != nil {
+			return nil, err
+		}
+
+		selected[name] = nil
+	}
+
+	return selected, nil
+}
+
+// indexesWithoutOverride returns the indexes of the kinds that have no override in the restore request
+func (t *Table) indexesWithoutOverride(input *types.RestoreTableFromBackupInput) map[string]*types.ProvisionedThroughput {
+	selected := map[string]*types.ProvisionedThroughput{}
+
+	for name, i := range t.Indexes {
+		if i.typ == indexTypeGlobal && input.GlobalSecondaryIndexOverride == nil ||
+			i.typ == indexTypeLocal && input.LocalSecondaryIndexOverride == nil {
+			selected[name] = nil
+		}
+	}
+
+	return selected
+}
+
+func (t *Table) validateIndexOverride(name string, typ indexType) error {
+	if i, ok := t.Indexes[name]; ok && i.typ == typ {
+		return nil
+	}
+
+	return types.NewError("ValidationException", fmt.Sprintf(missingBackupIndexMsg, name), nil)
+}
+
+// restoredThroughput returns the throughput of the restored table or global index, the override
+// replaces the throughput of the backup and the on-demand tables have no throughput
+func restoredThroughput(current *throughput, override *types.ProvisionedThroughput, mode, indexName string) (*throughput, error) {
+	if mode == BillingModePayPerRequest {
+		return nil, validatePayPerRequestOverride(override, indexName)
+	}
+
+	if override != nil {
+		if err := validateCapacityUnits(override, "provisionedThroughput"); err != nil {
+			return nil, err
+		}
+
+		return newThroughput(override), nil
+	}
+
+	if current != nil {
+		return current.clone(), nil
+	}
+
+	if indexName == "" {
+		return nil, types.NewError("ValidationException", missingThroughputMsg, nil)
+	}
+
+	return nil, types.NewError("ValidationException", fmt.Sprintf(missingIndexThroughputMsg, indexName), nil)
+}
+
+func validatePayPerRequestOverride(override *types.ProvisionedThroughput, indexName string) error {
+	switch {
+	case override == nil:
+		return nil
+	case indexName == "":
+		return types.NewError("ValidationException", payPerRequestThroughputMsg, nil)
+	default:
+		return types.NewError("ValidationException", fmt.Sprintf(payPerRequestIndexThroughputMsg, indexName), nil)
+	}
+}
+
+// BackupFilter selects the backups returned by ListBackups, the zero values match every backup
+type BackupFilter struct {
+	TableName string
+	// From is the inclusive lower bound of the creation time and To is the exclusive upper bound
+	From time.Time
+	To   time.Time
+	// Type is USER, SYSTEM, AWS_BACKUP or ALL, the fake only creates USER backups
+	Type string
+}
+
+func (f BackupFilter) match(b *Backup) bool {
+	if f.TableName != "" && f.TableName != b.Source.TableName {
+		return false
+	}
+
+	if !f.From.IsZero() && b.CreationDateTime.Before(f.From) {
+		return false
+	}
+
+	if !f.To.IsZero() && !b.CreationDateTime.Before(f.To) {
+		return false
+	}
+
+	return f.Type == "" || backupTypeFilters[f.Type]
+}
+
+// ListBackups returns a page of the backups matching the filter sorted by creation time, the page starts
+// after the backup with the exclusive start ARN and has up to limit backups, 100 when it is 0. The ARN of
+// the last backup of the page is returned when there are more backups
+func ListBackups(backups map[string]*Backup, filter BackupFilter, exclusiveStart string, limit int64) ([]*Backup, string, error) {
+	if err := validateListBackups(filter, limit); err != nil {
+		return nil, "", err
+	}
+
+	if limit == 0 {
+		limit = listBackupsMaxLimit
+	}
+
+	page := backupsAfter(sortedBackups(backups, filter), exclusiveStart)
+
+	if int64(len(page)) <= limit {
+		return page, "", nil
+	}
+
+	page = page[:limit]
+
+	return page, page[limit-1].Arn, nil
+}
+
+func validateListBackups(filter BackupFilter, limit int64) error {
+	if limit < 0 || limit > listBackupsMaxLimit {
+		return types.NewError("ValidationException", fmt.Sprintf(listBackupsLimitMsg, limit), nil)
+	}
+
+	if _, ok := backupTypeFilters[filter.Type]; filter.Type != "" && !ok {
+		return types.NewError("ValidationException", fmt.Sprintf(backupTypeEnumMsg, filter.Type), nil)
+	}
+
+	return nil
+}
+
+// sortedBackups returns the backups matching the filter sorted by creation time
+func sortedBackups(backups map[string]*Backup, filter BackupFilter) []*Backup {
+	page := make([]*Backup, 0, len(backups))
+
+	for _, b := range backups {
+		if filter.match(b) {
+			page = append(page, b)
+		}
+	}
+
+	sort.Slice(page, func(i, j int) bool {
+		if page[i].CreationDateTime.Equal(page[j].CreationDateTime) {
+			return page[i].Arn < page[j].Arn
+		}
+
+		return page[i].CreationDateTime.Before(page[j].CreationDateTime)
+	})
+
+	return page
+}
+
+// backupsAfter returns the backups after the one with the exclusive start ARN, all of them when it is not found
+func backupsAfter(page []*Backup, exclusiveStart string) []*Backup {
+	for i, b := range page {
+		if b.Arn == exclusiveStart {
+			return page[i+1:]
+		}
+	}
+
+	return page
+}
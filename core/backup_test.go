@@ -0,0 +1,277 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func putSettingsItem(c *require.Assertions, table *Table, id, typ string) {
+	_, err := table.Put(&types.PutItemInput{
+		Item: map[string]*types.Item{
+			"id":   {S: types.ToString(id)},
+			"type": {S: types.ToString(typ)},
+		},
+	})
+	c.NoError(err)
+}
+
+func TestBackupRestore(t *testing.T) {
+	c := require.New(t)
+
+	table := createSettingsTable(c, BillingModeProvisioned)
+	putSettingsItem(c, table, "001", "grass")
+	putSettingsItem(c, table, "004", "fire")
+
+	backup, err := NewBackup(table, "pokemons-backup")
+	c.NoError(err)
+	c.True(strings.HasPrefix(backup.Arn, table.Arn()+"/backup/"))
+	c.Equal(BackupStatusAvailable, backup.Status)
+	c.Equal(BackupTypeUser, backup.Type)
+	c.Equal(int64(2), backup.Source.ItemCount)
+
+	// the later changes of the table are not in the backup
+	putSettingsItem(c, table, "007", "water")
+	putSettingsItem(c, table, "001", "poison")
+
+	restored, err := backup.Restore(&types.RestoreTableFromBackupInput{TargetTableName: types.ToString("restored")})
+	c.NoError(err)
+
+	desc := restored.Description("restored")
+	c.Equal("restored", desc.TableName)
+	c.NotEqual(table.TableID, desc.TableID)
+	c.Equal(int64(2), desc.ItemCount)
+	c.Equal(BillingModeProvisioned, types.StringValue(desc.BillingModeSummary.BillingMode))
+	c.Equal(int64(5), desc.ProvisionedThroughput.ReadCapacityUnits)
+	c.Len(desc.GlobalSecondaryIndexes, 1)
+	c.Equal(int64(2), desc.GlobalSecondaryIndexes[0].ItemCount)
+	c.Equal(int64(5), desc.GlobalSecondaryIndexes[0].ProvisionedThroughput.WriteCapacityUnits)
+	c.Equal(backup.Arn, types.StringValue(desc.RestoreSummary.SourceBackupArn))
+	c.Equal(table.Arn(), types.StringValue(desc.RestoreSummary.SourceTableArn))
+	c.False(desc.RestoreSummary.RestoreInProgress)
+
+	item, err := restored.GetItem(map[string]*types.Item{"id": {S: types.ToString("001")}})
+	c.NoError(err)
+	c.Equal("grass", types.StringValue(item["type"].S))
+
+	items, _, err := restored.SearchData(QueryInput{
+		Index:                     "by-type",
+		KeyConditionExpression:    "#type = :type",
+		Aliases:                   map[string]string{"#type": "type"},
+		ExpressionAttributeValues: map[string]*types.Item{":type": {S: types.ToString("fire")}},
+		ScanIndexForward:          true,
+	})
+	c.NoError(err)
+	c.Len(items, 1)
+
+	// the changes of the restored table are not in the backup
+	putSettingsItem(c, restored, "025", "electric")
+
+	again, err := backup.Restore(&types.RestoreTableFromBackupInput{TargetTableName: types.ToString("again")})
+	c.NoError(err)
+	c.Equal(int64(2), again.Description("again").ItemCount)
+}
+
+func TestBackupRestoreOverrides(t *testing.T) {
+	c := require.New(t)
+
+	provisioned := createSettingsTable(c, BillingModeProvisioned)
+	onDemand := createSettingsTable(c, BillingModePayPerRequest)
+	throughput := &types.ProvisionedThroughput{ReadCapacityUnits: 10, WriteCapacityUnits: 20}
+
+	provisionedBackup, err := NewBackup(provisioned, "provisioned")
+	c.NoError(err)
+
+	onDemandBackup, err := NewBackup(onDemand, "on-demand")
+	c.NoError(err)
+
+	tests := []struct {
+		name   string
+		backup *Backup
+		input  *types.RestoreTableFromBackupInput
+		err    string
+		check  func(desc *types.TableDescription)
+	}{
+		{
+			name:   "on-demand override",
+			backup: provisionedBackup,
+			input:  &types.RestoreTableFromBackupInput{BillingModeOverride: types.ToString(BillingModePayPerRequest)},
+			check: func(desc *types.TableDescription) {
+				c.Equal(BillingModePayPerRequest, types.StringValue(desc.BillingModeSummary.BillingMode))
+				c.Zero(desc.ProvisionedThroughput.ReadCapacityUnits)
+				c.Zero(desc.GlobalSecondaryIndexes[0].ProvisionedThroughput.ReadCapacityUnits)
+			},
+		},
+		{
+			name:   "provisioned override",
+			backup: onDemandBackup,
+			input: &types.RestoreTableFromBackupInput{
+				BillingModeOverride:           types.ToString(BillingModeProvisioned),
+				ProvisionedThroughputOverride: throughput,
+				GlobalSecondaryIndexOverride: []*types.GlobalSecondaryIndex{
+					{IndexName: types.ToString("by-type"), ProvisionedThroughput: throughput},
+				},
+			},
+			check: func(desc *types.TableDescription) {
+				c.Equal(int64(10), desc.ProvisionedThroughput.ReadCapacityUnits)
+				c.Equal(int64(20), desc.GlobalSecondaryIndexes[0].ProvisionedThroughput.WriteCapacityUnits)
+			},
+		},
+		{
+			name:   "without indexes",
+			backup: provisionedBackup,
+			input:  &types.RestoreTableFromBackupInput{GlobalSecondaryIndexOverride: []*types.GlobalSecondaryIndex{}},
+			check: func(desc *types.TableDescription) {
+				c.Empty(desc.GlobalSecondaryIndexes)
+			},
+		},
+		{
+			name:   "encryption override",
+			backup: provisionedBackup,
+			input:  &types.RestoreTableFromBackupInput{SSESpecificationOverride: &types.SSESpecification{Enabled: aws.Bool(true)}},
+			check: func(desc *types.TableDescription) {
+				c.Equal("ENABLED", types.StringValue(desc.SSEDescription.Status))
+			},
+		},
+		{
+			name:   "invalid billing mode",
+			backup: provisionedBackup,
+			input:  &types.RestoreTableFromBackupInput{BillingModeOverride: types.ToString("FREE")},
+			err:    "Value 'FREE' at 'billingMode' failed to satisfy constraint",
+		},
+		{
+			name:   "throughput in on-demand table",
+			backup: onDemandBackup,
+			input:  &types.RestoreTableFromBackupInput{ProvisionedThroughputOverride: throughput},
+			err:    "Neither ReadCapacityUnits nor WriteCapacityUnits can be specified when BillingMode is PAY_PER_REQUEST",
+		},
+		{
+			name:   "index throughput in on-demand table",
+			backup: onDemandBackup,
+			input: &types.RestoreTableFromBackupInput{
+				GlobalSecondaryIndexOverride: []*types.GlobalSecondaryIndex{
+					{IndexName: types.ToString("by-type"), ProvisionedThroughput: throughput},
+				},
+			},
+			err: "ProvisionedThroughput should not be specified for index: by-type when BillingMode is PAY_PER_REQUEST",
+		},
+		{
+			name:   "provisioned without throughput",
+			backup: onDemandBackup,
+			input:  &types.RestoreTableFromBackupInput{BillingModeOverride: types.ToString(BillingModeProvisioned)},
+			err:    "ProvisionedThroughput must be specified when BillingMode is PROVISIONED",
+		},
+		{
+			name:   "provisioned without index throughput",
+			backup: onDemandBackup,
+			input: &types.RestoreTableFromBackupInput{
+				BillingModeOverride:           types.ToString(BillingModeProvisioned),
+				ProvisionedThroughputOverride: throughput,
+			},
+			err: "ProvisionedThroughput must be specified for index: by-type",
+		},
+		{
+			name:   "missing index",
+			backup: provisionedBackup,
+			input: &types.RestoreTableFromBackupInput{
+				LocalSecondaryIndexOverride: []*types.LocalSecondaryIndex{{IndexName: types.ToString("by-type")}},
+			},
+			err: "The index by-type to restore does not exist in the source table",
+		},
+	}
+
+	for _, tt := range tests {
+		tt.input.TargetTableName = types.ToString("restored")
+
+		restored, err := tt.backup.Restore(tt.input)
+		if tt.err != "" {
+			c.Error(err, tt.name)
+			c.Contains(err.Error(), tt.err, tt.name)
+
+			continue
+		}
+
+		c.NoError(err, tt.name)
+		tt.check(restored.Description("restored"))
+	}
+}
+
+func TestNewBackupName(t *testing.T) {
+	c := require.New(t)
+
+	table := createSettingsTable(c, BillingModePayPerRequest)
+
+	_, err := NewBackup(table, "ab")
+	c.Error(err)
+	c.Contains(err.Error(), "Value 'ab' at 'backupName' failed to satisfy constraint: Member must have length between 3 and 255")
+
+	_, err = NewBackup(table, "daily backup")
+	c.Error(err)
+	c.Contains(err.Error(), "Member must satisfy regular expression pattern: [a-zA-Z0-9_.-]+")
+
+	_, err = NewBackup(table, "daily_backup-2024.01")
+	c.NoError(err)
+}
+
+func TestListBackups(t *testing.T) {
+	c := require.New(t)
+
+	pokemons := createSettingsTable(c, BillingModePayPerRequest)
+	trainers := createSettingsTable(c, BillingModePayPerRequest)
+	trainers.Name = "trainers"
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	backups := map[string]*Backup{}
+
+	for i, table := range []*Table{pokemons, trainers, pokemons} {
+		backup, err := NewBackup(table, "backup")
+		c.NoError(err)
+
+		backup.CreationDateTime = start.Add(time.Duration(i) * time.Hour)
+		backups[backup.Arn] = backup
+	}
+
+	page, last, err := ListBackups(backups, BackupFilter{}, "", 0)
+	c.NoError(err)
+	c.Empty(last)
+	c.Len(page, 3)
+	c.Equal("trainers", page[1].Source.TableName)
+
+	page, last, err = ListBackups(backups, BackupFilter{}, "", 2)
+	c.NoError(err)
+	c.Len(page, 2)
+	c.Equal(page[1].Arn, last)
+
+	page, last, err = ListBackups(backups, BackupFilter{}, last, 2)
+	c.NoError(err)
+	c.Empty(last)
+	c.Len(page, 1)
+	c.Equal(start.Add(2*time.Hour), page[0].CreationDateTime)
+
+	page, _, err = ListBackups(backups, BackupFilter{TableName: tableName}, "", 0)
+	c.NoError(err)
+	c.Len(page, 2)
+
+	page, _, err = ListBackups(backups, BackupFilter{From: start.Add(time.Hour), To: start.Add(2 * time.Hour)}, "", 0)
+	c.NoError(err)
+	c.Len(page, 1)
+	c.Equal("trainers", page[0].Source.TableName)
+
+	page, _, err = ListBackups(backups, BackupFilter{Type: "SYSTEM"}, "", 0)
+	c.NoError(err)
+	c.Empty(page)
+
+	_, _, err = ListBackups(backups, BackupFilter{Type: "DAILY"}, "", 0)
+	c.Error(err)
+	c.Contains(err.Error(), "Value 'DAILY' at 'backupType' failed to satisfy constraint")
+
+	_, _, err = ListBackups(backups, BackupFilter{}, "", 101)
+	c.EqualError(err, "ValidationException: 1 validation error detected: Value '101' at 'limit' failed to satisfy constraint: Member must have value between 1 and 100")
+
+	_, _, err = ListBackups(backups, BackupFilter{}, "", -1)
+	c.Contains(err.Error(), "Value '-1' at 'limit' failed to satisfy constraint")
+}
@@ -0,0 +1,352 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5" // nolint:gosec
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// ExportFormatDynamoDBJSON is the format of the exports with an item in DynamoDB JSON per line
+	ExportFormatDynamoDBJSON = "DYNAMODB_JSON"
+	// ExportFormatIon is the format of the exports with an item in Amazon Ion text per line
+	ExportFormatIon = "ION"
+	// ExportStatusCompleted is the status of the exports, the fake writes them before returning
+	ExportStatusCompleted = "COMPLETED"
+	// ExportTypeFull is the type of the exports with every item of the table
+	ExportTypeFull = "FULL_EXPORT"
+
+	exportTypeIncremental   = "INCREMENTAL_EXPORT"
+	exportManifestVersion   = "2020-06-30"
+	exportSseAlgorithm      = "AES256"
+	exportDirectoryMode     = 0o750
+	exportFileMode          = 0o600
+	exportTimeLayout        = "2006-01-02T15:04:05.000Z"
+	exportDataDirectory     = "data"
+	exportSummaryManifest   = "manifest-summary"
+	exportFilesManifest     = "manifest-files"
+	exportStartedMarkerFile = "_started"
+
+	// revive:disable-next-line
+	exportDirectoryMsg = "The export directory is not set, the exports are written to the buckets of a local directory"
+	// revive:disable-next-line
+	exportFormatEnumMsg = "1 validation error detected: Value '%s' at 'exportFormat' failed to satisfy constraint: Member must satisfy enum value set: [DYNAMODB_JSON, ION]"
+	// revive:disable-next-line
+	exportTypeEnumMsg = "1 validation error detected: Value '%s' at 'exportType' failed to satisfy constraint: Member must satisfy enum value set: [FULL_EXPORT, INCREMENTAL_EXPORT]"
+	// revive:disable-next-line
+	incrementalExportMsg = "One or more parameter values were invalid: minidyn only supports FULL_EXPORT exports"
+	// revive:disable-next-line
+	invalidExportTimeMsg = "Invalid export time: %s. The export time must be between the earliest restorable time %s and the latest restorable time %s"
+)
+
+// Export is an export of the items of a table, the S3 bucket of the export is a directory of the local filesystem
+type Export struct {
+	Arn             string
+	Status          string
+	Format          string
+	Type            string
+	TableArn        string
+	TableID         string
+	S3Bucket        string
+	S3Prefix        string
+	S3BucketOwner   string
+	S3SseAlgorithm  string
+	S3SseKmsKeyID   string
+	ClientToken     string
+	StartTime       time.Time
+	EndTime         time.Time
+	ExportTime      time.Time
+	ItemCount       int64
+	BilledSizeBytes int64
+	// ManifestKey is the key of the manifest-summary.json file in the bucket
+	ManifestKey string
+}
+
+type exportManifestSummary struct {
+	Version            string  `json:"version"`
+	ExportArn          string  `json:"exportArn"`
+	StartTime          string  `json:"startTime"`
+	EndTime            string  `json:"endTime"`
+	TableArn           string  `json:"tableArn"`
+	TableID            string  `json:"tableId"`
+	ExportTime         string  `json:"exportTime"`
+	S3Bucket           string  `json:"s3Bucket"`
+	S3Prefix           *string `json:"s3Prefix"`
+	S3SseAlgorithm     string  `json:"s3SseAlgorithm"`
+	S3SseKmsKeyID      *string `json:"s3SseKmsKeyId"`
+	ManifestFilesS3Key string  `json:"manifestFilesS3Key"`
+	BilledSizeBytes    int64   `json:"billedSizeBytes"`
+	ItemCount          int64   `json:"itemCount"`
+	OutputFormat       string  `json:"outputFormat"`
+	ExportType         string  `json:"exportType"`
+}
+
+type exportManifestFile struct {
+	ItemCount     int64  `json:"itemCount"`
+	MD5Checksum   string `json:"md5Checksum"`
+	ETag          string `json:"etag"`
+	DataFileS3Key string `json:"dataFileS3Key"`
+}
+
+// NewExport writes the items of the table at the export time with the layout of the DynamoDB exports, the
+// bucket is a subdirectory of the given directory. The export requires the point in time recovery and the
+// caller must hold the table read lock
+func NewExport(t *Table, input *types.ExportTableToPointInTimeInput, dir string) (*Export, error) {
+	if dir == "" {
+		return nil, types.NewError("ValidationException", exportDirectoryMsg, nil)
+	}
+
+	e, err := newExport(t, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.validateRecoveryTime(e.ExportTime, "InvalidExportTimeException", invalidExportTimeMsg); err != nil {
+		return nil, err
+	}
+
+	root := filepath.Join(dir, e.S3Bucket)
+
+	if err := e.write(root, t.pitr.itemsAt(e.ExportTime)); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func newExport(t *Table, input *types.ExportTableToPointInTimeInput) (*Export, error) {
+	e := &Export{
+		Status:         ExportStatusCompleted,
+		Format:         ExportFormatDynamoDBJSON,
+		Type:           ExportTypeFull,
+		TableArn:       t.Arn(),
+		TableID:        t.TableID,
+		S3Bucket:       types.StringValue(input.S3Bucket),
+		S3Prefix:       types.StringValue(input.S3Prefix),
+		S3BucketOwner:  types.StringValue(input.S3BucketOwner),
+		S3SseAlgorithm: exportSseAlgorithm,
+		S3SseKmsKeyID:  types.StringValue(input.S3SseKmsKeyID),
+		ClientToken:    types.StringValue(input.ClientToken),
+		StartTime:      t.now(),
+	}
+
+	if input.ExportFormat != nil {
+		e.Format = *input.ExportFormat
+	}
+
+	if e.Format != ExportFormatDynamoDBJSON && e.Format != ExportFormatIon {
+		return nil, types.NewError("ValidationException", fmt.Sprintf(exportFormatEnumMsg, e.Format), nil)
+	}
+
+	if err := validateExportType(input.ExportType); err != nil {
+		return nil, err
+	}
+
+	if input.S3SseAlgorithm != nil {
+		e.S3SseAlgorithm = *input.S3SseAlgorithm
+	}
+
+	if t.pitr == nil {
+		return nil, types.NewError("PointInTimeRecoveryUnavailableException", fmt.Sprintf(pitrUnavailableMsg, t.Name), nil)
+	}
+
+	e.ExportTime = e.StartTime
+	if input.ExportTime != nil {
+		e.ExportTime = *input.ExportTime
+	}
+
+	id := fmt.Sprintf("%013d-%s", e.StartTime.UnixMilli(), newTableID()[:8])
+	e.Arn = t.Arn() + "/export/" + id
+	e.ManifestKey = e.key(id, exportSummaryManifest+".json")
+
+	return e, nil
+}
+
+func validateExportType(exportType *string) error {
+	switch types.StringValue(exportType) {
+	case "", ExportTypeFull:
+		return nil
+	case exportTypeIncremental:
+		return types.NewError("ValidationException", incrementalExportMsg, nil)
+	default:
+		return types.NewError("ValidationException", fmt.Sprintf(exportTypeEnumMsg, *exportType), nil)
+	}
+}
+
+// key returns the key of a file of the export in the bucket, prefix/AWSDynamoDB/<export id>/<name>
+func (e *Export) key(id, name string) string {
+	return path.Join(strings.Trim(e.S3Prefix, "/"), "AWSDynamoDB", id, name)
+}
+
+// write writes the started marker, the data file and the manifests of the export in the bucket directory
+func (e *Export) write(root string, items map[string]map[string]*types.Item) error {
+	id := path.Base(e.Arn)
+
+	if err := writeExportFile(root, e.key(id, exportStartedMarkerFile), nil); err != nil {
+		return err
+	}
+
+	data, err := e.encode(items)
+	if err != nil {
+		return err
+	}
+
+	dataKey := e.key(id, path.Join(exportDataDirectory, strings.ReplaceAll(newTableID(), "-", "")+e.extension()))
+
+	compressed, err := gzipData(data)
+	if err != nil {
+		return err
+	}
+
+	if err := writeExportFile(root, dataKey, compressed); err != nil {
+		return err
+	}
+
+	sum := md5.Sum(compressed) // nolint:gosec
+	e.ItemCount = int64(len(items))
+	e.BilledSizeBytes = int64(len(data))
+	e.EndTime = e.StartTime
+
+	files, err := json.Marshal(exportManifestFile{
+		ItemCount:     e.ItemCount,
+		MD5Checksum:   base64.StdEncoding.EncodeToString(sum[:]),
+		ETag:          hex.EncodeToString(sum[:]),
+		DataFileS3Key: dataKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writeExportManifest(root, e.key(id, exportFilesManifest), append(files, '\n')); err != nil {
+		return err
+	}
+
+	summary, err := json.Marshal(e.summary(id))
+	if err != nil {
+		return err
+	}
+
+	return writeExportManifest(root, e.key(id, exportSummaryManifest), summary)
+}
+
+func (e *Export) summary(id string) exportManifestSummary {
+	var prefix, kmsKeyID *string
+
+	if e.S3Prefix != "" {
+		prefix = types.ToString(e.S3Prefix)
+	}
+
+	if e.S3SseKmsKeyID != "" {
+		kmsKeyID = types.ToString(e.S3SseKmsKeyID)
+	}
+
+	return exportManifestSummary{
+		Version:            exportManifestVersion,
+		ExportArn:          e.Arn,
+		StartTime:          e.StartTime.UTC().Format(exportTimeLayout),
+		EndTime:            e.EndTime.UTC().Format(exportTimeLayout),
+		TableArn:           e.TableArn,
+		TableID:            e.TableID,
+		ExportTime:         e.ExportTime.UTC().Format(exportTimeLayout),
+		S3Bucket:           e.S3Bucket,
+		S3Prefix:           prefix,
+		S3SseAlgorithm:     e.S3SseAlgorithm,
+		S3SseKmsKeyID:      kmsKeyID,
+		ManifestFilesS3Key: e.key(id, exportFilesManifest+".json"),
+		BilledSizeBytes:    e.BilledSizeBytes,
+		ItemCount:          e.ItemCount,
+		OutputFormat:       e.Format,
+		ExportType:         e.Type,
+	}
+}
+
+func (e *Export) extension() string {
+	if e.Format == ExportFormatIon {
+		return ".ion.gz"
+	}
+
+	return ".json.gz"
+}
+
+// encode returns the items sorted by key with an item per line, the Ion data starts with the version marker
+func (e *Export) encode(items map[string]map[string]*types.Item) ([]byte, error) {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+
+	if e.Format == ExportFormatIon {
+		buf.WriteString("$ion_1_0 ")
+	}
+
+	for _, key := range keys {
+		line := marshalIon(items[key])
+
+		if e.Format == ExportFormatDynamoDBJSON {
+			var err error
+
+			line, err = marshalDynamoDBJSON(items[key])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+func gzipData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeExportManifest writes the manifest in the name.json file and its MD5 checksum in the name.md5 file
+func writeExportManifest(root, name string, data []byte) error {
+	if err := writeExportFile(root, name+".json", data); err != nil {
+		return err
+	}
+
+	sum := md5.Sum(data) // nolint:gosec
+
+	return writeExportFile(root, name+".md5", []byte(base64.StdEncoding.EncodeToString(sum[:])))
+}
+
+func writeExportFile(root, key string, data []byte) error {
+	name := filepath.Join(root, filepath.FromSlash(key))
+
+	if err := os.MkdirAll(filepath.Dir(name), exportDirectoryMode); err != nil {
+		return err
+	}
+
+	return os.WriteFile(name, data, exportFileMode)
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func readExportFile(c *require.Assertions, dir, bucket, key string) []byte {
+	data, err := os.ReadFile(filepath.Join(dir, bucket, filepath.FromSlash(key)))
+	c.NoError(err)
+
+	return data
+}
+
+func readExportData(c *require.Assertions, dir string, export *Export) []string {
+	var summary exportManifestSummary
+
+	c.NoError(json.Unmarshal(readExportFile(c, dir, export.S3Bucket, export.ManifestKey), &summary))
+
+	var file exportManifestFile
+
+	c.NoError(json.Unmarshal(readExportFile(c, dir, export.S3Bucket, summary.ManifestFilesS3Key), &file))
+	c.Equal(export.ItemCount, file.ItemCount)
+
+	f, err := os.Open(filepath.Join(dir, export.S3Bucket, filepath.FromSlash(file.DataFileS3Key)))
+	c.NoError(err)
+
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	c.NoError(err)
+
+	lines := []string{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	c.NoError(scanner.Err())
+
+	return lines
+}
+
+func TestExport(t *testing.T) {
+	c := require.New(t)
+	dir := t.TempDir()
+
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	table := createSettingsTable(c, BillingModePayPerRequest)
+	table.Clock = clock.Now
+
+	input := &types.ExportTableToPointInTimeInput{
+		TableArn: types.ToString(table.Arn()),
+		S3Bucket: types.ToString("analytics"),
+		S3Prefix: types.ToString("exports/pokemons"),
+	}
+
+	_, err := NewExport(table, input, dir)
+	c.Error(err)
+	c.Contains(err.Error(), "PointInTimeRecoveryUnavailableException")
+
+	c.NoError(table.UpdateContinuousBackups(aws.Bool(true)))
+	putSettingsItem(c, table, "001", "grass")
+
+	exportTime := clock.now
+
+	clock.advance(time.Hour)
+	putSettingsItem(c, table, "004", "fire")
+
+	input.ExportTime = &exportTime
+
+	export, err := NewExport(table, input, dir)
+	c.NoError(err)
+	c.Equal(ExportStatusCompleted, export.Status)
+	c.Equal(ExportFormatDynamoDBJSON, export.Format)
+	c.Equal(int64(1), export.ItemCount)
+	c.Regexp(`^exports/pokemons/AWSDynamoDB/\d{13}-[0-9a-f]{8}/manifest-summary.json$`, export.ManifestKey)
+
+	var summary exportManifestSummary
+
+	c.NoError(json.Unmarshal(readExportFile(c, dir, "analytics", export.ManifestKey), &summary))
+	c.Equal(export.Arn, summary.ExportArn)
+	c.Equal("2024-01-01T00:00:00.000Z", summary.ExportTime)
+	c.Equal("2024-01-01T01:00:00.000Z", summary.StartTime)
+	c.Equal("exports/pokemons", types.StringValue(summary.S3Prefix))
+	c.Equal(ExportTypeFull, summary.ExportType)
+
+	c.Equal([]string{`{"Item":{"id":{"S":"001"},"type":{"S":"grass"}}}`}, readExportData(c, dir, export))
+
+	input.ExportTime = nil
+	input.ExportFormat = types.ToString(ExportFormatIon)
+
+	export, err = NewExport(table, input, dir)
+	c.NoError(err)
+	c.Equal(int64(2), export.ItemCount)
+	c.Equal([]string{
+		`$ion_1_0 {Item:{id:"001",type:"grass"}}`,
+		`{Item:{id:"004",type:"fire"}}`,
+	}, readExportData(c, dir, export))
+
+	tooLate := clock.now.Add(time.Minute)
+	input.ExportTime = &tooLate
+
+	_, err = NewExport(table, input, dir)
+	c.Error(err)
+	c.Contains(err.Error(), "InvalidExportTimeException")
+
+	input.ExportTime = nil
+	input.ExportFormat = types.ToString("CSV")
+
+	_, err = NewExport(table, input, dir)
+	c.Error(err)
+	c.Contains(err.Error(), "Value 'CSV' at 'exportFormat' failed to satisfy constraint")
+
+	input.ExportFormat = nil
+	input.ExportType = types.ToString(exportTypeIncremental)
+
+	_, err = NewExport(table, input, dir)
+	c.Error(err)
+	c.Contains(err.Error(), "minidyn only supports FULL_EXPORT exports")
+
+	input.ExportType = types.ToString("DELTA")
+
+	_, err = NewExport(table, input, dir)
+	c.Error(err)
+	c.Contains(err.Error(), "Value 'DELTA' at 'exportType' failed to satisfy constraint")
+}
+
+func TestMarshalItemFormats(t *testing.T) {
+	c := require.New(t)
+
+	item := map[string]*types.Item{
+		"id":         {S: types.ToString("025")},
+		"level":      {N: types.ToString("12")},
+		"weight":     {N: types.ToString("6.0e1")},
+		"shiny":      {BOOL: aws.Bool(false)},
+		"nickname":   {NULL: aws.Bool(true)},
+		"sprite":     {B: []byte("pika")},
+		"moves":      {L: []*types.Item{{S: types.ToString("thunder")}, {N: types.ToString("90")}}},
+		"stats":      {M: map[string]*types.Item{"hp": {N: types.ToString("35")}}},
+		"types":      {SS: []*string{types.ToString("electric")}},
+		"evolutions": {NS: []*string{types.ToString("26")}},
+		"badges":     {BS: [][]byte{[]byte("a")}},
+		"null":       {S: types.ToString("it's")},
+		"home town":  {S: types.ToString("Pallet")},
+	}
+
+	data, err := marshalDynamoDBJSON(item)
+	c.NoError(err)
+	c.JSONEq(`{"Item":{
+		"id":{"S":"025"},"level":{"N":"12"},"weight":{"N":"6.0e1"},"shiny":{"BOOL":false},"nickname":{"NULL":true},
+		"sprite":{"B":"cGlrYQ=="},"moves":{"L":[{"S":"thunder"},{"N":"90"}]},"stats":{"M":{"hp":{"N":"35"}}},
+		"types":{"SS":["electric"]},"evolutions":{"NS":["26"]},"badges":{"BS":["YQ=="]},"null":{"S":"it's"},
+		"home town":{"S":"Pallet"}}}`, string(data))
+
+	c.Equal(`{Item:{badges:$dynamodb_BS::[{{YQ==}}],evolutions:$dynamodb_NS::[26.],'home town':"Pallet",id:"025",`+
+		`level:12.,moves:["thunder",90.],nickname:null,'null':"it's",shiny:false,sprite:{{cGlrYQ==}},`+
+		`stats:{hp:35.},types:$dynamodb_SS::["electric"],weight:6.0d1}}`, string(marshalIon(item)))
+}
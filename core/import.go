@@ -0,0 +1,457 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// ImportStatusCompleted is the status of the imports that created the table, the items with errors are counted
+	ImportStatusCompleted = "COMPLETED"
+	// ImportStatusFailed is the status of the imports that could not read their files, the table is not created
+	ImportStatusFailed = "FAILED"
+	// InputFormatCSV is the format of the imports of CSV files, the attributes other than the keys are strings
+	InputFormatCSV = "CSV"
+	// InputCompressionGzip is the compression of the gzipped files
+	InputCompressionGzip = "GZIP"
+	// InputCompressionZstd is the compression of the zstd files, it requires a registered decompressor
+	InputCompressionZstd = "ZSTD"
+	// InputCompressionNone is the compression of the uncompressed files
+	InputCompressionNone = "NONE"
+
+	importFailureS3     = "S3ReadError"
+	importFailureCodec  = "DecompressionError"
+	listImportsMaxLimit = 25
+
+	// revive:disable-next-line
+	importDirectoryMsg = "The import directory is not set, the imports are read from the buckets of a local directory"
+	// revive:disable-next-line
+	inputFormatEnumMsg = "1 validation error detected: Value '%s' at 'inputFormat' failed to satisfy constraint: Member must satisfy enum value set: [DYNAMODB_JSON, ION, CSV]"
+	// revive:disable-next-line
+	inputCompressionEnumMsg = "1 validation error detected: Value '%s' at 'inputCompressionType' failed to satisfy constraint: Member must satisfy enum value set: [GZIP, ZSTD, NONE]"
+	// revive:disable-next-line
+	csvOptionsFormatMsg = "One or more parameter values were invalid: InputFormatOptions can only be specified for the CSV format"
+	// revive:disable-next-line
+	csvDelimiterMsg = "One or more parameter values were invalid: The CSV delimiter %q must be one of comma, tab, colon, semicolon, pipe or space"
+	// revive:disable-next-line
+	missingDecompressorMsg = "One or more parameter values were invalid: There is no decompressor for the %s compression"
+	missingBucketSourceMsg = "1 validation error detected: Value null at 's3BucketSource' failed to satisfy constraint: Member must not be null"
+	// revive:disable-next-line
+	listImportsPageSizeMsg = "1 validation error detected: Value '%d' at 'pageSize' failed to satisfy constraint: Member must have value between 1 and 25"
+)
+
+var csvDelimiters = map[string]bool{",": true, "\t": true, ":": true, ";": true, "|": true, " ": true}
+
+// Decompressor returns a reader of the uncompressed data of a file
+type Decompressor func(r io.Reader) (io.Reader, error)
+
+// Import is an import of the items of a table from the files of a S3 bucket, the bucket is a directory
+// of the local filesystem
+type Import struct {
+	Arn             string
+	Status          string
+	Format          string
+	CompressionType string
+	CsvOptions      *types.CsvOptions
+	TableArn        string
+	TableID         string
+	S3Bucket        string
+	S3BucketOwner   string
+	S3KeyPrefix     string
+	ClientToken     string
+	StartTime       time.Time
+	EndTime         time.Time
+	FailureCode     string
+	FailureMessage  string
+	ProcessedItems  int64
+	ImportedItems   int64
+	ErrorCount      int64
+	ProcessedBytes  int64
+	decompressors   map[string]Decompressor
+	table           *Table
+	dir             string
+}
+
+// NewImport validates the import of the items of the table from the buckets of the given directory, the table
+// must be new and empty. The gzip decompression is built in and the zstd decompression requires a decompressor
+func NewImport(t *Table, input *types.ImportTableInput, dir string, decompressors map[string]Decompressor) (*Import, error) {
+	if dir == "" {
+		return nil, types.NewError("ValidationException", importDirectoryMsg, nil)
+	}
+
+	i := &Import{
+		Status:          ImportStatusCompleted,
+		Format:          types.StringValue(input.InputFormat),
+		CompressionType: InputCompressionNone,
+		TableArn:        t.Arn(),
+		TableID:         t.TableID,
+		ClientToken:     types.StringValue(input.ClientToken),
+		StartTime:       t.now(),
+		decompressors:   map[string]Decompressor{InputCompressionGzip: gunzip},
+		table:           t,
+		dir:             dir,
+	}
+
+	for compression, d := range decompressors {
+		i.decompressors[compression] = d
+	}
+
+	if input.InputCompressionType != nil {
+		i.CompressionType = *input.InputCompressionType
+	}
+
+	if input.InputFormatOptions != nil {
+		i.CsvOptions = input.InputFormatOptions.Csv
+	}
+
+	if input.S3BucketSource == nil {
+		return nil, types.NewError("ValidationException", missingBucketSourceMsg, nil)
+	}
+
+	i.S3Bucket = types.StringValue(input.S3BucketSource.S3Bucket)
+	i.S3BucketOwner = types.StringValue(input.S3BucketSource.S3BucketOwner)
+	i.S3KeyPrefix = types.StringValue(input.S3BucketSource.S3KeyPrefix)
+
+	if err := i.validate(); err != nil {
+		return nil, err
+	}
+
+	i.Arn = fmt.Sprintf("%s/import/%013d-%s", t.Arn(), i.StartTime.UnixMilli(), newTableID()[:8])
+
+	return i, nil
+}
+
+func (i *Import) validate() error {
+	switch i.Format {
+	case ExportFormatDynamoDBJSON, ExportFormatIon, InputFormatCSV:
+	default:
+		return types.NewError("ValidationException", fmt.Sprintf(inputFormatEnumMsg, i.Format), nil)
+	}
+
+	switch i.CompressionType {
+	case InputCompressionGzip, InputCompressionZstd, InputCompressionNone:
+	default:
+		return types.NewError("ValidationException", fmt.Sprintf(inputCompressionEnumMsg, i.CompressionType), nil)
+	}
+
+	if _, ok := i.decompressors[i.CompressionType]; !ok && i.CompressionType != InputCompressionNone {
+		return types.NewError("ValidationException", fmt.Sprintf(missingDecompressorMsg, i.CompressionType), nil)
+	}
+
+	return i.validateCsvOptions()
+}
+
+func (i *Import) validateCsvOptions() error {
+	if i.CsvOptions == nil {
+		return nil
+	}
+
+	if i.Format != InputFormatCSV {
+		return types.NewError("ValidationException", csvOptionsFormatMsg, nil)
+	}
+
+	if d := i.CsvOptions.Delimiter; d != nil && !csvDelimiters[*d] {
+		return types.NewError("ValidationException", fmt.Sprintf(csvDelimiterMsg, *d), nil)
+	}
+
+	return nil
+}
+
+// Run reads the files of the bucket under the key prefix and puts their items in the table, the items with
+// errors are counted and skipped. The import fails when the files can not be read or decompressed
+func (i *Import) Run() {
+	defer func() {
+		i.EndTime = i.table.now()
+	}()
+
+	keys, err := i.keys(filepath.Join(i.dir, i.S3Bucket))
+	if err != nil {
+		i.fail(importFailureS3, err)
+
+		return
+	}
+
+	for _, name := range keys {
+		if err := i.readFile(name); err != nil {
+			return
+		}
+	}
+}
+
+func (i *Import) fail(code string, err error) {
+	i.Status = ImportStatusFailed
+	i.FailureCode = code
+	i.FailureMessage = err.Error()
+}
+
+// keys returns the files of the bucket with a key that starts with the key prefix, sorted by key
+func (i *Import) keys(root string) ([]string, error) {
+	var files []string
+
+	err := filepath.WalkDir(root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		key, err := filepath.Rel(root, name)
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && strings.HasPrefix(filepath.ToSlash(key), i.S3KeyPrefix) {
+			files = append(files, name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("there are no files in the bucket %s with the key prefix %q", i.S3Bucket, i.S3KeyPrefix)
+	}
+
+	return files, nil
+}
+
+func (i *Import) readFile(name string) error {
+	data, err := os.ReadFile(name) // nolint:gosec
+	if err != nil {
+		i.fail(importFailureS3, err)
+
+		return err
+	}
+
+	i.ProcessedBytes += int64(len(data))
+
+	data, err = i.decompress(data)
+	if err != nil {
+		i.fail(importFailureCodec, fmt.Errorf("the file %s could not be decompressed: %w", filepath.Base(name), err))
+
+		return err
+	}
+
+	switch i.Format {
+	case InputFormatCSV:
+		i.readCSV(data)
+	case ExportFormatIon:
+		i.readIon(data)
+	default:
+		i.readDynamoDBJSON(data)
+	}
+
+	return nil
+}
+
+func (i *Import) decompress(data []byte) ([]byte, error) {
+	d, ok := i.decompressors[i.CompressionType]
+	if !ok {
+		return data, nil
+	}
+
+	r, err := d(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(r)
+}
+
+func gunzip(r io.Reader) (io.Reader, error) {
+	return gzip.NewReader(r)
+}
+
+// put stores the item of a record, the records with errors are counted
+func (i *Import) put(item map[string]*types.Item, err error) {
+	i.ProcessedItems++
+
+	if err == nil {
+		_, err = i.table.Put(&types.PutItemInput{Item: item})
+	}
+
+	if err != nil {
+		i.ErrorCount++
+
+		return
+	}
+
+	i.ImportedItems++
+}
+
+func (i *Import) readDynamoDBJSON(data []byte) {
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		i.put(unmarshalDynamoDBJSON(line))
+	}
+}
+
+func (i *Import) readIon(data []byte) {
+	r := &ionReader{data: data}
+
+	for {
+		item, ok, err := r.next()
+		if !ok {
+			return
+		}
+
+		i.put(item, err)
+	}
+}
+
+// readCSV reads the records of the CSV data, the header is the first record unless the header list is given
+func (i *Import) readCSV(data []byte) {
+	r, header := i.csvReader(data)
+
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+
+		if err != nil {
+			i.put(nil, err)
+
+			continue
+		}
+
+		if header == nil {
+			header = record
+
+			continue
+		}
+
+		i.put(i.csvItem(header, record))
+	}
+}
+
+// csvReader returns the reader of the CSV data with the delimiter of the options and the given header list
+func (i *Import) csvReader(data []byte) (*csv.Reader, []string) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+
+	if i.CsvOptions == nil {
+		return r, nil
+	}
+
+	if d := types.StringValue(i.CsvOptions.Delimiter); d != "" {
+		r.Comma = rune(d[0])
+	}
+
+	var header []string
+
+	for _, h := range i.CsvOptions.HeaderList {
+		header = append(header, types.StringValue(h))
+	}
+
+	return r, header
+}
+
+// csvItem returns the item of a CSV record, the keys have the type of their attribute definition
+// and the other attributes are strings, the empty values are left out
+func (i *Import) csvItem(header, record []string) (map[string]*types.Item, error) {
+	if len(record) > len(header) {
+		return nil, fmt.Errorf("the record has %d values and the header has %d", len(record), len(header))
+	}
+
+	item := make(map[string]*types.Item, len(record))
+
+	for n, value := range record {
+		if value == "" {
+			continue
+		}
+
+		attr, err := csvAttribute(i.table.AttributesDef[header[n]], value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute %s: %w", header[n], err)
+		}
+
+		item[header[n]] = attr
+	}
+
+	return item, nil
+}
+
+func csvAttribute(typ, value string) (*types.Item, error) {
+	switch typ {
+	case "N":
+		return &types.Item{N: types.ToString(value)}, nil
+	case "B":
+		b, err := base64.StdEncoding.DecodeString(value)
+
+		return &types.Item{B: b}, err
+	}
+
+	return &types.Item{S: types.ToString(value)}, nil
+}
+
+// ListImports returns a page of the imports of the table, or of every table when the table ARN is empty, sorted
+// by start time. The page starts after the import with the next token ARN and has up to pageSize imports, 25
+// when it is 0. The ARN of the last import of the page is returned when there are more imports
+func ListImports(imports map[string]*Import, tableArn, nextToken string, pageSize int64) ([]*Import, string, error) {
+	if pageSize < 0 || pageSize > listImportsMaxLimit {
+		return nil, "", types.NewError("ValidationException", fmt.Sprintf(listImportsPageSizeMsg, pageSize), nil)
+	}
+
+	if pageSize == 0 {
+		pageSize = listImportsMaxLimit
+	}
+
+	page := importsAfter(sortedImports(imports, tableArn), nextToken)
+
+	if int64(len(page)) <= pageSize {
+		return page, "", nil
+	}
+
+	page = page[:pageSize]
+
+	return page, page[pageSize-1].Arn, nil
+}
+
+// sortedImports returns the imports of the table, or every import when the table ARN is empty, sorted by start time
+func sortedImports(imports map[string]*Import, tableArn string) []*Import {
+	page := make([]*Import, 0, len(imports))
+
+	for _, i := range imports {
+		if tableArn == "" || i.TableArn == tableArn {
+			page = append(page, i)
+		}
+	}
+
+	sort.Slice(page, func(i, j int) bool {
+		if page[i].StartTime.Equal(page[j].StartTime) {
+			return page[i].Arn < page[j].Arn
+		}
+
+		return page[i].StartTime.Before(page[j].StartTime)
+	})
+
+	return page
+}
+
+// importsAfter returns the imports after the one with the next token ARN, all of them when it is not found
+func importsAfter(page []*Import, nextToken string) []*Import {
+	for n, i := range page {
+		if i.Arn == nextToken {
+			return page[n+1:]
+		}
+	}
+
+	return page
+}
@@ -0,0 +1,350 @@
+package core
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func writeImportFile(c *require.Assertions, dir, key string, data []byte) {
+	c.NoError(writeExportFile(filepath.Join(dir, "fixtures"), key, data))
+}
+
+func newImportInput(format, prefix string) *types.ImportTableInput {
+	return &types.ImportTableInput{
+		InputFormat: types.ToString(format),
+		S3BucketSource: &types.S3BucketSource{
+			S3Bucket:    types.ToString("fixtures"),
+			S3KeyPrefix: types.ToString(prefix),
+		},
+	}
+}
+
+func runImport(c *require.Assertions, input *types.ImportTableInput, dir string, decompressors map[string]Decompressor) (*Import, *Table) {
+	table := createSettingsTable(c, BillingModePayPerRequest)
+
+	imp, err := NewImport(table, input, dir, decompressors)
+	c.NoError(err)
+
+	imp.Run()
+
+	return imp, table
+}
+
+func TestImportCSV(t *testing.T) {
+	c := require.New(t)
+	dir := t.TempDir()
+
+	writeImportFile(c, dir, "csv/header/part-1.csv", []byte("id,type,name\n001,grass,Bulbasaur\n004,fire,\n007,water,Squirtle,extra\n"))
+	writeImportFile(c, dir, "csv/list/part-1.csv", []byte("025;electric\n133;normal\n"))
+
+	imp, table := runImport(c, newImportInput(InputFormatCSV, "csv/header/"), dir, nil)
+	c.Equal(ImportStatusCompleted, imp.Status)
+	c.Equal(int64(3), imp.ProcessedItems)
+	c.Equal(int64(2), imp.ImportedItems)
+	c.Equal(int64(1), imp.ErrorCount)
+	c.Equal("grass", getSettingsType(c, table, "001"))
+
+	item, err := table.GetItem(map[string]*types.Item{"id": {S: types.ToString("004")}})
+	c.NoError(err)
+	c.NotContains(item, "name")
+
+	input := newImportInput(InputFormatCSV, "csv/list/")
+	input.InputFormatOptions = &types.InputFormatOptions{
+		Csv: &types.CsvOptions{
+			Delimiter:  types.ToString(";"),
+			HeaderList: []*string{types.ToString("id"), types.ToString("type")},
+		},
+	}
+
+	imp, table = runImport(c, input, dir, nil)
+	c.Equal(int64(2), imp.ImportedItems)
+	c.Equal("electric", getSettingsType(c, table, "025"))
+	c.Equal("normal", getSettingsType(c, table, "133"))
+}
+
+func TestImportCSVItem(t *testing.T) {
+	c := require.New(t)
+
+	table := createSettingsTable(c, BillingModePayPerRequest)
+	table.AttributesDef["level"] = "N"
+	table.AttributesDef["sprite"] = "B"
+
+	imp := &Import{table: table}
+	header := []string{"id", "level", "sprite", "type", "nickname"}
+
+	item, err := imp.csvItem(header, []string{"001", "5", "cGlrYQ==", "grass", "5"})
+	c.NoError(err)
+	c.Equal(map[string]*types.Item{
+		"id":       {S: types.ToString("001")},
+		"level":    {N: types.ToString("5")},
+		"sprite":   {B: []byte("pika")},
+		"type":     {S: types.ToString("grass")},
+		"nickname": {S: types.ToString("5")},
+	}, item)
+
+	item, err = imp.csvItem(header, []string{"004", "", ""})
+	c.NoError(err)
+	c.Equal(map[string]*types.Item{"id": {S: types.ToString("004")}}, item)
+
+	_, err = imp.csvItem(header, []string{"007", "7", "!!"})
+	c.Error(err)
+	c.Contains(err.Error(), "invalid attribute sprite")
+
+	_, err = imp.csvItem(header[:1], []string{"007", "7"})
+	c.EqualError(err, "the record has 2 values and the header has 1")
+}
+
+func TestImportDynamoDBJSON(t *testing.T) {
+	c := require.New(t)
+	dir := t.TempDir()
+
+	data, err := gzipData([]byte(strings.Join([]string{
+		`{"Item":{"id":{"S":"001"},"type":{"S":"grass"}}}`,
+		`{"Item":{"id":{"S":"004"},"type":{"S":"fire","N":"4"}}}`,
+		`{"Item":{"type":{"S":"water"}}}`,
+		`not json`,
+		``,
+		`{"Item":{"id":{"S":"025"},"type":{"S":"electric"},"moves":{"L":[{"S":"thunder"}]}}}`,
+	}, "\n")))
+	c.NoError(err)
+
+	writeImportFile(c, dir, "json/data/part-1.json.gz", data)
+
+	input := newImportInput(ExportFormatDynamoDBJSON, "json/")
+	input.InputCompressionType = types.ToString(InputCompressionGzip)
+
+	imp, table := runImport(c, input, dir, nil)
+	c.Equal(ImportStatusCompleted, imp.Status)
+	c.Equal(int64(5), imp.ProcessedItems)
+	c.Equal(int64(2), imp.ImportedItems)
+	c.Equal(int64(3), imp.ErrorCount)
+	c.Equal(int64(len(data)), imp.ProcessedBytes)
+	c.Equal("electric", getSettingsType(c, table, "025"))
+}
+
+func TestImportIon(t *testing.T) {
+	c := require.New(t)
+	dir := t.TempDir()
+
+	item := map[string]*types.Item{
+		"id":         {S: types.ToString("025")},
+		"type":       {S: types.ToString("electric")},
+		"level":      {N: types.ToString("12")},
+		"weight":     {N: types.ToString("6.0e1")},
+		"shiny":      {BOOL: aws.Bool(false)},
+		"nickname":   {NULL: aws.Bool(true)},
+		"sprite":     {B: []byte("pika")},
+		"moves":      {L: []*types.Item{{S: types.ToString("thunder")}, {N: types.ToString("90")}}},
+		"stats":      {M: map[string]*types.Item{"hp": {N: types.ToString("35")}}},
+		"evolutions": {NS: []*string{types.ToString("26")}},
+		"badges":     {BS: [][]byte{[]byte("a")}},
+		"home town":  {S: types.ToString("it's")},
+	}
+
+	lines := []string{
+		"$ion_1_0 " + string(marshalIon(item)),
+		"// the fields can be symbols, the sets can be annotated lists",
+		`{Item:{id:'001',type:grass,'types':$dynamodb_SS::["grass","poison"],level:0x10,hp:45e0,}}`,
+		`{Item:{id:"004",type:"fire",created:2024-01-01T}}`,
+		`{Record:{id:"007"}}`,
+		`{Item:{id:"133",type:'''nor''' '''mal'''}}`,
+	}
+
+	writeImportFile(c, dir, "ion/part-1.ion", []byte(strings.Join(lines, "\n")))
+
+	imp, table := runImport(c, newImportInput(ExportFormatIon, "ion/"), dir, nil)
+	c.Equal(ImportStatusCompleted, imp.Status)
+	c.Equal(int64(5), imp.ProcessedItems)
+	c.Equal(int64(3), imp.ImportedItems)
+	c.Equal(int64(2), imp.ErrorCount)
+
+	got, err := table.GetItem(map[string]*types.Item{"id": {S: types.ToString("025")}})
+	c.NoError(err)
+	c.Equal(item, got)
+
+	got, err = table.GetItem(map[string]*types.Item{"id": {S: types.ToString("001")}})
+	c.NoError(err)
+	c.Equal("grass", types.StringValue(got["type"].S))
+	c.Equal([]*string{types.ToString("grass"), types.ToString("poison")}, got["types"].SS)
+	c.Equal("16", types.StringValue(got["level"].N))
+	c.Equal("45e0", types.StringValue(got["hp"].N))
+
+	c.Equal("normal", getSettingsType(c, table, "133"))
+}
+
+func TestIonReader(t *testing.T) {
+	c := require.New(t)
+
+	tests := []struct {
+		input string
+		item  map[string]*types.Item
+		err   string
+	}{
+		{
+			input: `{Item:{id:"a\x41é\0\?\/\"\t"}}`,
+			item:  map[string]*types.Item{"id": {S: types.ToString("aAé\x00?/\"\t")}},
+		},
+		{
+			input: "{Item:{id:'''first \\\nline'''}}",
+			item:  map[string]*types.Item{"id": {S: types.ToString("first line")}},
+		},
+		{
+			input: "/* block\ncomment */\r\n\f{\"Item\":{'''id''':\"1\",sprite:{{ aGVs\n bG8= }},shiny:true,nickname:null.string}}",
+			item: map[string]*types.Item{
+				"id":       {S: types.ToString("1")},
+				"sprite":   {B: []byte("hello")},
+				"shiny":    {BOOL: aws.Bool(true)},
+				"nickname": {NULL: aws.Bool(true)},
+			},
+		},
+		{input: `{Item:{id:"\q"}}`, err: `invalid escape sequence in "\\q"`},
+		{input: `{Item:{id:"1",text:{{"clob"}}}}`, err: "the clobs have no DynamoDB type"},
+		{input: `{Item:{id:"1",sprite:{{!!}}}}`, err: "invalid blob"},
+		{input: `{Item:{id:"1",sprite:{{aGVs`, err: errIonEOF.Error()},
+		{input: `{Item:{id:"1`, err: errIonEOF.Error()},
+		{input: `{Item:{id:'''1`, err: errIonEOF.Error()},
+		{input: `{Item:{id`, err: errIonEOF.Error()},
+		{input: `{Item:{1:"a"}}`, err: "invalid field name at offset 7"},
+		{input: `{Item:{id "1"}}`, err: `expected ':' at offset 10, found '"'`},
+		{input: `{Item:{id:"1" type:"a"}}`, err: `expected ',' or '}' at offset 14, found 't'`},
+		{input: `{Item:{id:"1",hp:nan}}`, err: "the number nan has no DynamoDB type"},
+		{input: `{Item:{id:"1",hp:-inf}}`, err: "the number -inf has no DynamoDB type"},
+		{input: `{Item:{id:#}}`, err: `invalid Ion value "#" at offset 10`},
+		{input: `{Item:{id:"1",types:$dynamodb_SS::"a"}}`, err: "the $dynamodb_SS annotation requires a list"},
+		{input: `{Item:{id:"1",types:$dynamodb_NS::["a"]}}`, err: "invalid element of the $dynamodb_NS set"},
+		{input: `{Item:"1"}`, err: "the record has no Item"},
+	}
+
+	for _, tt := range tests {
+		r := &ionReader{data: []byte(tt.input)}
+
+		item, ok, err := r.next()
+		c.True(ok, tt.input)
+
+		if tt.err != "" {
+			c.Error(err, tt.input)
+			c.Contains(err.Error(), tt.err, tt.input)
+
+			continue
+		}
+
+		c.NoError(err, tt.input)
+		c.Equal(tt.item, item, tt.input)
+	}
+
+	r := &ionReader{data: []byte("$ion_1_0 // the end\n/* unterminated")}
+
+	_, ok, err := r.next()
+	c.NoError(err)
+	c.False(ok)
+}
+
+func TestImportCompression(t *testing.T) {
+	c := require.New(t)
+	dir := t.TempDir()
+
+	writeImportFile(c, dir, "zstd/part-1.csv.zst", []byte("id,type\n001,grass\n"))
+	writeImportFile(c, dir, "gzip/part-1.csv.gz", []byte("id,type\n001,grass\n"))
+
+	input := newImportInput(InputFormatCSV, "zstd/")
+	input.InputCompressionType = types.ToString(InputCompressionZstd)
+
+	_, err := NewImport(createSettingsTable(c, BillingModePayPerRequest), input, dir, nil)
+	c.Error(err)
+	c.Contains(err.Error(), "There is no decompressor for the ZSTD compression")
+
+	identity := func(r io.Reader) (io.Reader, error) { return r, nil }
+
+	imp, table := runImport(c, input, dir, map[string]Decompressor{InputCompressionZstd: identity})
+	c.Equal(ImportStatusCompleted, imp.Status)
+	c.Equal("grass", getSettingsType(c, table, "001"))
+
+	input = newImportInput(InputFormatCSV, "gzip/")
+	input.InputCompressionType = types.ToString(InputCompressionGzip)
+
+	imp, _ = runImport(c, input, dir, nil)
+	c.Equal(ImportStatusFailed, imp.Status)
+	c.Equal(importFailureCodec, imp.FailureCode)
+	c.Contains(imp.FailureMessage, "part-1.csv.gz")
+}
+
+func TestImportValidation(t *testing.T) {
+	c := require.New(t)
+	dir := t.TempDir()
+
+	table := createSettingsTable(c, BillingModePayPerRequest)
+
+	_, err := NewImport(table, newImportInput(InputFormatCSV, ""), "", nil)
+	c.Error(err)
+	c.Contains(err.Error(), importDirectoryMsg)
+
+	_, err = NewImport(table, newImportInput("XML", ""), dir, nil)
+	c.Error(err)
+	c.Contains(err.Error(), "Value 'XML' at 'inputFormat' failed to satisfy constraint")
+
+	input := newImportInput(ExportFormatDynamoDBJSON, "")
+	input.InputFormatOptions = &types.InputFormatOptions{Csv: &types.CsvOptions{}}
+
+	_, err = NewImport(table, input, dir, nil)
+	c.Error(err)
+	c.Contains(err.Error(), csvOptionsFormatMsg)
+
+	input = newImportInput(InputFormatCSV, "")
+	input.InputFormatOptions = &types.InputFormatOptions{Csv: &types.CsvOptions{Delimiter: types.ToString("#")}}
+
+	_, err = NewImport(table, input, dir, nil)
+	c.Error(err)
+	c.Contains(err.Error(), "The CSV delimiter \"#\" must be one of")
+
+	input.S3BucketSource = nil
+
+	_, err = NewImport(table, input, dir, nil)
+	c.Error(err)
+	c.Contains(err.Error(), "Value null at 's3BucketSource'")
+
+	imp, _ := runImport(c, newImportInput(InputFormatCSV, "missing/"), dir, nil)
+	c.Equal(ImportStatusFailed, imp.Status)
+	c.Equal(importFailureS3, imp.FailureCode)
+}
+
+func TestListImports(t *testing.T) {
+	c := require.New(t)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	imports := map[string]*Import{}
+
+	for n, arn := range []string{"table/a/import/3", "table/b/import/1", "table/a/import/2", "table/a/import/1"} {
+		imports[arn] = &Import{
+			Arn:       arn,
+			TableArn:  arn[:len("table/a")],
+			StartTime: start.Add(-time.Duration(n) * time.Minute),
+		}
+	}
+
+	page, last, err := ListImports(imports, "table/a", "", 2)
+	c.NoError(err)
+	c.Equal("table/a/import/1", page[0].Arn)
+	c.Equal("table/a/import/2", page[1].Arn)
+	c.Equal("table/a/import/2", last)
+
+	page, last, err = ListImports(imports, "table/a", last, 2)
+	c.NoError(err)
+	c.Len(page, 1)
+	c.Equal("table/a/import/3", page[0].Arn)
+	c.Empty(last)
+
+	page, _, err = ListImports(imports, "", "", 0)
+	c.NoError(err)
+	c.Len(page, 4)
+
+	_, _, err = ListImports(imports, "", "", 26)
+	c.Error(err)
+	c.Contains(err.Error(), "Member must have value between 1 and 25")
+}
@@ -1,8 +1,6 @@
 package core
 
 import (
-	"sort"
-
 	"github.com/truora/minidyn/types"
 )
 
@@ -15,138 +13,94 @@ const (
 
 type index struct {
 	keySchema  keySchema
-	sortedKeys []string
-	sortedRefs [][2]string // used for searching
+	keys       *keyList
 	typ        indexType
 	projection *types.Projection // TODO use projection in queries
 	Table      *Table
 	refs       map[string]string
+	throughput *throughput
 }
 
 func newIndex(t *Table, typ indexType, ks keySchema) *index {
 	ks.Secondary = true
 
 	return &index{
-		keySchema:  ks,
-		sortedKeys: []string{},
-		typ:        typ,
-		Table:      t,
-		refs:       map[string]string{},
+		keySchema: ks,
+		keys:      newKeyList(),
+		typ:       typ,
+		Table:     t,
+		refs:      map[string]string{},
 	}
 }
 
 func (i *index) Clear() {
-	i.sortedKeys = []string{}
+	i.keys = newKeyList()
 	i.refs = map[string]string{}
 }
 
+// entryKey returns the key used to sort the entries of the index,
+// items sharing the index key are sorted by their primary key
+func entryKey(indexKey, key string) string {
+	return indexKey + "\x00" + key
+}
+
 func (i *index) putData(key string, item map[string]*types.Item) error {
 	indexKey, err := i.keySchema.GetKey(i.Table.AttributesDef, item)
 	if err != nil || indexKey == "" {
 		return err
 	}
 
-	_, exists := i.refs[key]
-
-	i.refs[key] = indexKey
-
-	if !exists {
-		i.sortedKeys = append(i.sortedKeys, indexKey)
-		sort.Strings(i.sortedKeys)
-	}
+	i.setRef(key, indexKey)
 
 	return nil
 }
 
 func (i *index) updateData(key string, item, oldItem map[string]*types.Item) error {
 	indexKey, err := i.keySchema.GetKey(i.Table.AttributesDef, item)
-	if err != nil || indexKey == "" {
+	if err != nil {
 		return err
 	}
 
-	old := i.refs[key]
-	i.refs[key] = indexKey
+	if indexKey == "" {
+		i.removeRef(key)
 
-	if old != indexKey {
-		pos := sort.SearchStrings(i.sortedKeys, old)
-		if pos >= len(i.sortedKeys) {
-			i.sortedKeys = append(i.sortedKeys, indexKey)
-		} else {
-			i.sortedKeys[pos] = indexKey
-		}
-
-		sort.Strings(i.sortedKeys)
+		return nil
 	}
 
+	i.setRef(key, indexKey)
+
 	return nil
 }
 
 func (i *index) delete(key string, item map[string]*types.Item) error {
-	delete(i.refs, key)
-
-	indexKey, err := i.keySchema.GetKey(i.Table.AttributesDef, item)
-	if err != nil || indexKey == "" {
-		return err
-	}
-
-	pos := sort.SearchStrings(i.sortedKeys, indexKey)
-	if pos == len(i.sortedKeys) {
-		return err
-	}
-
-	copy(i.sortedKeys[pos:], i.sortedKeys[pos+1:])
-	i.sortedKeys[len(i.sortedKeys)-1] = ""
-	i.sortedKeys = i.sortedKeys[:len(i.sortedKeys)-1]
+	i.removeRef(key)
 
 	return nil
 }
 
-func (i *index) lessKey(x, y int) bool {
-	if i.sortedRefs[x][1] < i.sortedRefs[y][1] {
-		return true
-	}
-
-	if i.sortedRefs[x][1] > i.sortedRefs[y][1] {
-		return false
-	}
-
-	return i.sortedRefs[x][0] < i.sortedRefs[y][0]
-}
-
-func (i *index) startSearch(scanForward bool) {
-	i.sortedRefs = make([][2]string, len(i.refs))
-	pos := 0
+func (i *index) setRef(key, indexKey string) {
+	if old, exists := i.refs[key]; exists {
+		if old == indexKey {
+			return
+		}
 
-	for k, v := range i.refs {
-		i.sortedRefs[pos] = [2]string{k, v}
-		pos++
+		i.keys.delete(entryKey(old, key))
 	}
 
-	sort.Slice(i.sortedRefs, func(x, y int) bool {
-		less := i.lessKey(x, y)
-		if scanForward {
-			return less
-		}
-
-		return !less
-	})
+	i.refs[key] = indexKey
+	i.keys.put(entryKey(indexKey, key), key)
 }
 
-func (i *index) getPrimaryKey(indexKey string) (string, bool) {
-	if len(i.sortedRefs) > 0 {
-		key := i.sortedRefs[0]
-		pk, ik := key[0], key[1]
-
-		i.sortedRefs = i.sortedRefs[1:len(i.sortedRefs)]
-
-		if indexKey == ik {
-			return pk, true
-		}
+func (i *index) removeRef(key string) {
+	old, exists := i.refs[key]
+	if !exists {
+		return
 	}
 
-	return "", false
+	delete(i.refs, key)
+	i.keys.delete(entryKey(old, key))
 }
 
 func (i *index) count() int64 {
-	return int64(len(i.sortedKeys))
+	return int64(i.keys.size())
 }
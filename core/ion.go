@@ -0,0 +1,546 @@
+package core
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/truora/minidyn/types"
+)
+
+const ionVersionMarker = "$ion_1_0"
+
+var (
+	errIonEOF = errors.New("unexpected end of the Ion data")
+
+	ionSetAnnotations = map[string]bool{"$dynamodb_SS": true, "$dynamodb_NS": true, "$dynamodb_BS": true}
+)
+
+// ionReader reads the Amazon Ion text values of the imports, the structs, lists, strings, symbols, numbers,
+// blobs, booleans and nulls. The clobs, s-expressions and timestamps have no DynamoDB type
+type ionReader struct {
+	data []byte
+	pos  int
+}
+
+// next returns the item of the next {Item:{...}} value, it returns false when there are no more values. The
+// reader skips the rest of the line of a value with errors
+func (r *ionReader) next() (map[string]*types.Item, bool, error) {
+	r.skipVersionMarkers()
+
+	if r.pos >= len(r.data) {
+		return nil, false, nil
+	}
+
+	item, err := r.record()
+	if err != nil {
+		r.skipLine()
+
+		return nil, true, err
+	}
+
+	return item, true, nil
+}
+
+func (r *ionReader) record() (map[string]*types.Item, error) {
+	value, err := r.value()
+	if err != nil {
+		return nil, err
+	}
+
+	record, ok := value.M["Item"]
+	if !ok || record.M == nil {
+		return nil, errors.New("the record has no Item")
+	}
+
+	return record.M, validateAttributeValues(record.M)
+}
+
+func (r *ionReader) skipVersionMarkers() {
+	for {
+		r.skipSpace()
+
+		if !bytes.HasPrefix(r.data[r.pos:], []byte(ionVersionMarker)) {
+			return
+		}
+
+		r.pos += len(ionVersionMarker)
+	}
+}
+
+func (r *ionReader) skipLine() {
+	if i := bytes.IndexByte(r.data[r.pos:], '\n'); i >= 0 {
+		r.pos += i + 1
+
+		return
+	}
+
+	r.pos = len(r.data)
+}
+
+// skipSpace skips the white spaces and the comments
+func (r *ionReader) skipSpace() {
+	for r.pos < len(r.data) {
+		rest := r.data[r.pos:]
+
+		switch {
+		case bytes.HasPrefix(rest, []byte("//")):
+			r.skipLine()
+		case bytes.HasPrefix(rest, []byte("/*")):
+			end := bytes.Index(rest[2:], []byte("*/"))
+			if end < 0 {
+				r.pos = len(r.data)
+
+				return
+			}
+
+			r.pos += end + 4
+		case strings.IndexByte(" \t\n\r\f\v", rest[0]) >= 0:
+			r.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (r *ionReader) peek() (byte, error) {
+	r.skipSpace()
+
+	if r.pos >= len(r.data) {
+		return 0, errIonEOF
+	}
+
+	return r.data[r.pos], nil
+}
+
+func (r *ionReader) expect(c byte) error {
+	next, err := r.peek()
+	if err != nil {
+		return err
+	}
+
+	if next != c {
+		return fmt.Errorf("expected %q at offset %d, found %q", c, r.pos, next)
+	}
+
+	r.pos++
+
+	return nil
+}
+
+// value returns the next value, the set annotations turn the lists into DynamoDB sets
+func (r *ionReader) value() (*types.Item, error) {
+	annotations, err := r.annotations()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := r.bareValue()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, annotation := range annotations {
+		if ionSetAnnotations[annotation] {
+			return ionSet(annotation, value)
+		}
+	}
+
+	return value, nil
+}
+
+func (r *ionReader) annotations() ([]string, error) {
+	var annotations []string
+
+	for {
+		start := r.pos
+
+		symbol, ok, err := r.symbol()
+		if err != nil {
+			return nil, err
+		}
+
+		r.skipSpace()
+
+		if !ok || !bytes.HasPrefix(r.data[r.pos:], []byte("::")) {
+			r.pos = start
+
+			return annotations, nil
+		}
+
+		r.pos += 2
+		annotations = append(annotations, symbol)
+	}
+}
+
+func (r *ionReader) bareValue() (*types.Item, error) {
+	c, err := r.peek()
+	if err != nil {
+		return nil, err
+	}
+
+	rest := r.data[r.pos:]
+
+	switch {
+	case bytes.HasPrefix(rest, []byte("{{")):
+		return r.blob()
+	case c == '{':
+		return r.structValue()
+	case c == '[':
+		return r.list()
+	case c == '"' || bytes.HasPrefix(rest, []byte("'''")):
+		s, err := r.stringValue()
+
+		return &types.Item{S: types.ToString(s)}, err
+	case c == '\'':
+		s, _, err := r.symbol()
+
+		return &types.Item{S: types.ToString(s)}, err
+	}
+
+	return r.atom()
+}
+
+func (r *ionReader) structValue() (*types.Item, error) {
+	r.pos++
+
+	m := map[string]*types.Item{}
+
+	for {
+		if c, err := r.peek(); err != nil || c == '}' {
+			r.pos++
+
+			return &types.Item{M: m}, err
+		}
+
+		if err := r.field(m); err != nil {
+			return nil, err
+		}
+
+		if err := r.separator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// field reads a name: value pair of a struct into the map
+func (r *ionReader) field(m map[string]*types.Item) error {
+	name, err := r.fieldName()
+	if err != nil {
+		return err
+	}
+
+	if err := r.expect(':'); err != nil {
+		return err
+	}
+
+	m[name], err = r.value()
+
+	return err
+}
+
+func (r *ionReader) list() (*types.Item, error) {
+	r.pos++
+
+	l := []*types.Item{}
+
+	for {
+		if c, err := r.peek(); err != nil || c == ']' {
+			r.pos++
+
+			return &types.Item{L: l}, err
+		}
+
+		value, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+
+		l = append(l, value)
+
+		if err := r.separator(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// separator consumes the comma after a field or an element, the comma is optional before the closing character
+func (r *ionReader) separator(closing byte) error {
+	c, err := r.peek()
+	if err != nil {
+		return err
+	}
+
+	if c == ',' {
+		r.pos++
+
+		return nil
+	}
+
+	if c != closing {
+		return fmt.Errorf("expected ',' or %q at offset %d, found %q", closing, r.pos, c)
+	}
+
+	return nil
+}
+
+func (r *ionReader) fieldName() (string, error) {
+	c, err := r.peek()
+	if err != nil {
+		return "", err
+	}
+
+	if c == '"' || bytes.HasPrefix(r.data[r.pos:], []byte("'''")) {
+		return r.stringValue()
+	}
+
+	symbol, ok, err := r.symbol()
+	if err == nil && !ok {
+		err = fmt.Errorf("invalid field name at offset %d", r.pos)
+	}
+
+	return symbol, err
+}
+
+// symbol reads an identifier or a quoted symbol, it returns false when the next value is not a symbol
+func (r *ionReader) symbol() (string, bool, error) {
+	r.skipSpace()
+
+	rest := r.data[r.pos:]
+
+	if len(rest) > 0 && rest[0] == '\'' && !bytes.HasPrefix(rest, []byte("'''")) {
+		s, err := r.quoted('\'')
+
+		return s, err == nil, err
+	}
+
+	end := 0
+	for end < len(rest) && isIonIdentifierByte(rest[end], end == 0) {
+		end++
+	}
+
+	if end == 0 || ionSymbolKeyword[string(rest[:end])] {
+		return "", false, nil
+	}
+
+	r.pos += end
+
+	return string(rest[:end]), true, nil
+}
+
+func isIonIdentifierByte(c byte, first bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c == '$':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+
+	return false
+}
+
+// stringValue reads a string or a sequence of long strings, the adjacent long strings are concatenated
+func (r *ionReader) stringValue() (string, error) {
+	if r.data[r.pos] == '"' {
+		return r.quoted('"')
+	}
+
+	var sb strings.Builder
+
+	for bytes.HasPrefix(r.data[r.pos:], []byte("'''")) {
+		r.pos += 3
+
+		end := bytes.Index(r.data[r.pos:], []byte("'''"))
+		if end < 0 {
+			return "", errIonEOF
+		}
+
+		s, err := unescapeIon(string(r.data[r.pos:r.pos+end]), '\'')
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteString(s)
+		r.pos += end + 3
+		r.skipSpace()
+	}
+
+	return sb.String(), nil
+}
+
+// quoted reads a string or a symbol delimited by the quote
+func (r *ionReader) quoted(quote byte) (string, error) {
+	for end := r.pos + 1; end < len(r.data); end++ {
+		switch r.data[end] {
+		case '\\':
+			end++
+		case quote:
+			s, err := unescapeIon(string(r.data[r.pos+1:end]), quote)
+			r.pos = end + 1
+
+			return s, err
+		}
+	}
+
+	return "", errIonEOF
+}
+
+func unescapeIon(s string, quote byte) (string, error) {
+	var sb strings.Builder
+
+	for len(s) > 0 {
+		if s[0] != '\\' || len(s) < 2 {
+			c, size := utf8.DecodeRuneInString(s)
+			sb.WriteRune(c)
+			s = s[size:]
+
+			continue
+		}
+
+		switch s[1] {
+		case '0':
+			sb.WriteByte(0)
+			s = s[2:]
+		case '?', '/':
+			sb.WriteByte(s[1])
+			s = s[2:]
+		case '\n':
+			s = s[2:]
+		default:
+			c, _, tail, err := strconv.UnquoteChar(s, quote)
+			if err != nil {
+				return "", fmt.Errorf("invalid escape sequence in %q", s)
+			}
+
+			sb.WriteRune(c)
+			s = tail
+		}
+	}
+
+	return sb.String(), nil
+}
+
+func (r *ionReader) blob() (*types.Item, error) {
+	end := bytes.Index(r.data[r.pos:], []byte("}}"))
+	if end < 0 {
+		return nil, errIonEOF
+	}
+
+	content := strings.Join(strings.Fields(string(r.data[r.pos+2:r.pos+end])), "")
+	r.pos += end + 2
+
+	if strings.HasPrefix(content, `"`) || strings.HasPrefix(content, "'") {
+		return nil, errors.New("the clobs have no DynamoDB type")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blob: %w", err)
+	}
+
+	return &types.Item{B: b}, nil
+}
+
+// atom reads the nulls, booleans, numbers and the symbols used as values, the symbols are strings
+func (r *ionReader) atom() (*types.Item, error) {
+	token := r.atomToken()
+
+	if item, ok := ionKeyword(token); ok {
+		return item, nil
+	}
+
+	switch {
+	case token == "nan" || strings.HasSuffix(token, "inf"):
+		return nil, fmt.Errorf("the number %s has no DynamoDB type", token)
+	case token != "" && strings.IndexByte("+-0123456789", token[0]) >= 0:
+		n, err := ionNumber(token)
+
+		return &types.Item{N: types.ToString(n)}, err
+	case ionSymbolRegex.MatchString(token):
+		return &types.Item{S: types.ToString(token)}, nil
+	}
+
+	return nil, fmt.Errorf("invalid Ion value %q at offset %d", token, r.pos-len(token))
+}
+
+// atomToken reads the characters of the value until the next delimiter
+func (r *ionReader) atomToken() string {
+	rest := r.data[r.pos:]
+
+	end := 0
+	for end < len(rest) && strings.IndexByte(" \t\n\r\f\v,:]}/", rest[end]) < 0 {
+		end++
+	}
+
+	r.pos += end
+
+	return string(rest[:end])
+}
+
+// ionKeyword returns the value of the typed nulls and the booleans
+func ionKeyword(token string) (*types.Item, bool) {
+	switch {
+	case token == "null" || strings.HasPrefix(token, "null."):
+		return &types.Item{NULL: types.ToBool(true)}, true
+	case token == "true" || token == "false":
+		return &types.Item{BOOL: types.ToBool(token == "true")}, true
+	}
+
+	return nil, false
+}
+
+// ionNumber returns the Ion int, decimal or float as a DynamoDB number
+func ionNumber(token string) (string, error) {
+	if !strings.ContainsAny(token, ".dDeE") || strings.HasPrefix(strings.TrimPrefix(token, "-"), "0x") {
+		i, ok := new(big.Int).SetString(token, 0)
+		if !ok {
+			return "", fmt.Errorf("invalid Ion int %s", token)
+		}
+
+		return i.String(), nil
+	}
+
+	n := strings.ReplaceAll(token, "_", "")
+	mantissa, exponent := n, ""
+
+	if i := strings.IndexAny(n, "dDeE"); i >= 0 {
+		mantissa, exponent = n[:i], "e"+n[i+1:]
+	}
+
+	n = strings.TrimSuffix(mantissa, ".") + exponent
+
+	if _, ok := new(big.Float).SetString(n); !ok {
+		return "", fmt.Errorf("invalid Ion number %s", token)
+	}
+
+	return n, nil
+}
+
+// ionSet returns the DynamoDB set of an annotated list
+func ionSet(annotation string, value *types.Item) (*types.Item, error) {
+	if value.L == nil {
+		return nil, fmt.Errorf("the %s annotation requires a list", annotation)
+	}
+
+	set := &types.Item{}
+
+	for _, elem := range value.L {
+		switch {
+		case annotation == "$dynamodb_SS" && elem.S != nil:
+			set.SS = append(set.SS, elem.S)
+		case annotation == "$dynamodb_NS" && elem.N != nil:
+			set.NS = append(set.NS, elem.N)
+		case annotation == "$dynamodb_BS" && elem.B != nil:
+			set.BS = append(set.BS, elem.B)
+		default:
+			return nil, fmt.Errorf("invalid element of the %s set", annotation)
+		}
+	}
+
+	return set, nil
+}
@@ -0,0 +1,256 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/truora/minidyn/types"
+)
+
+var (
+	ionSymbolRegex   = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	ionSymbolKeyword = map[string]bool{"null": true, "true": true, "false": true, "nan": true}
+)
+
+// marshalDynamoDBJSON returns the item in the DynamoDB JSON format of the exports, {"Item":{"id":{"S":"001"}}}
+func marshalDynamoDBJSON(item map[string]*types.Item) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"Item": dynamoDBJSONMap(item)})
+}
+
+func dynamoDBJSONMap(item map[string]*types.Item) map[string]interface{} {
+	m := make(map[string]interface{}, len(item))
+
+	for name, value := range item {
+		m[name] = dynamoDBJSONValue(value)
+	}
+
+	return m
+}
+
+func dynamoDBJSONValue(value *types.Item) map[string]interface{} {
+	switch {
+	case value.S != nil:
+		return map[string]interface{}{"S": *value.S}
+	case value.N != nil:
+		return map[string]interface{}{"N": *value.N}
+	case value.B != nil:
+		return map[string]interface{}{"B": value.B}
+	case value.BOOL != nil:
+		return map[string]interface{}{"BOOL": *value.BOOL}
+	case value.NULL != nil:
+		return map[string]interface{}{"NULL": *value.NULL}
+	}
+
+	return dynamoDBJSONCollection(value)
+}
+
+func dynamoDBJSONCollection(value *types.Item) map[string]interface{} {
+	switch {
+	case value.M != nil:
+		return map[string]interface{}{"M": dynamoDBJSONMap(value.M)}
+	case value.L != nil:
+		l := make([]interface{}, 0, len(value.L))
+		for _, v := range value.L {
+			l = append(l, dynamoDBJSONValue(v))
+		}
+
+		return map[string]interface{}{"L": l}
+	case value.SS != nil:
+		return map[string]interface{}{"SS": value.SS}
+	case value.NS != nil:
+		return map[string]interface{}{"NS": value.NS}
+	case value.BS != nil:
+		return map[string]interface{}{"BS": value.BS}
+	}
+
+	return map[string]interface{}{"NULL": true}
+}
+
+// unmarshalDynamoDBJSON returns the item of a line in the DynamoDB JSON format of the exports
+func unmarshalDynamoDBJSON(line []byte) (map[string]*types.Item, error) {
+	var record struct {
+		Item map[string]*types.Item
+	}
+
+	// the attribute values share the field names of the DynamoDB JSON types, S, N, B, BOOL, NULL, M, L, SS, NS and BS
+	if err := json.Unmarshal(line, &record); err != nil {
+		return nil, err
+	}
+
+	if len(record.Item) == 0 {
+		return nil, errors.New("the record has no Item")
+	}
+
+	return record.Item, validateAttributeValues(record.Item)
+}
+
+func validateAttributeValues(item map[string]*types.Item) error {
+	for name, value := range item {
+		if err := validateAttributeValue(value); err != nil {
+			return fmt.Errorf("invalid attribute %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateAttributeValue returns an error when the value does not have exactly one type
+func validateAttributeValue(value *types.Item) error {
+	if value == nil {
+		return errors.New("the value is null")
+	}
+
+	set := 0
+
+	for _, ok := range []bool{
+		value.S != nil, value.N != nil, value.B != nil, value.BOOL != nil, value.NULL != nil,
+		value.M != nil, value.L != nil, value.SS != nil, value.NS != nil, value.BS != nil,
+	} {
+		if ok {
+			set++
+		}
+	}
+
+	if set != 1 {
+		return errors.New("the value must have exactly one type")
+	}
+
+	for _, v := range value.L {
+		if err := validateAttributeValue(v); err != nil {
+			return err
+		}
+	}
+
+	return validateAttributeValues(value.M)
+}
+
+// marshalIon returns the item in the Amazon Ion text format of the exports, {Item:{id:"001"}}. The numbers
+// are decimals and the sets are lists annotated with $dynamodb_SS, $dynamodb_NS and $dynamodb_BS
+func marshalIon(item map[string]*types.Item) []byte {
+	var sb strings.Builder
+
+	sb.WriteString("{Item:")
+	writeIonStruct(&sb, item)
+	sb.WriteString("}")
+
+	return []byte(sb.String())
+}
+
+func writeIonStruct(sb *strings.Builder, item map[string]*types.Item) {
+	names := make([]string, 0, len(item))
+	for name := range item {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	sb.WriteString("{")
+
+	for i, name := range names {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+
+		sb.WriteString(ionSymbol(name))
+		sb.WriteString(":")
+		writeIonValue(sb, item[name])
+	}
+
+	sb.WriteString("}")
+}
+
+func writeIonValue(sb *strings.Builder, value *types.Item) {
+	switch {
+	case value.S != nil:
+		sb.WriteString(strconv.Quote(*value.S))
+	case value.N != nil:
+		sb.WriteString(ionDecimal(*value.N))
+	case value.B != nil:
+		sb.WriteString(ionBlob(value.B))
+	case value.BOOL != nil:
+		sb.WriteString(strconv.FormatBool(*value.BOOL))
+	case value.M != nil:
+		writeIonStruct(sb, value.M)
+	default:
+		writeIonCollection(sb, value)
+	}
+}
+
+func writeIonCollection(sb *strings.Builder, value *types.Item) {
+	var elems []string
+
+	switch {
+	case value.L != nil:
+		elems = ionElems(value.L, func(v *types.Item) string {
+			var elem strings.Builder
+
+			writeIonValue(&elem, v)
+
+			return elem.String()
+		})
+	case value.SS != nil:
+		sb.WriteString("$dynamodb_SS::")
+
+		elems = ionElems(value.SS, func(s *string) string { return strconv.Quote(types.StringValue(s)) })
+	case value.NS != nil:
+		sb.WriteString("$dynamodb_NS::")
+
+		elems = ionElems(value.NS, func(n *string) string { return ionDecimal(types.StringValue(n)) })
+	case value.BS != nil:
+		sb.WriteString("$dynamodb_BS::")
+
+		elems = ionElems(value.BS, ionBlob)
+	default:
+		sb.WriteString("null")
+
+		return
+	}
+
+	sb.WriteString("[" + strings.Join(elems, ",") + "]")
+}
+
+func ionElems[T any](values []T, encode func(T) string) []string {
+	elems := make([]string, 0, len(values))
+
+	for _, v := range values {
+		elems = append(elems, encode(v))
+	}
+
+	return elems
+}
+
+func ionBlob(b []byte) string {
+	return "{{" + base64.StdEncoding.EncodeToString(b) + "}}"
+}
+
+// ionSymbol returns the field name as an identifier or as a quoted symbol when it is not an identifier
+func ionSymbol(name string) string {
+	if ionSymbolRegex.MatchString(name) && !ionSymbolKeyword[name] {
+		return name
+	}
+
+	quoted := strconv.Quote(name)
+
+	return "'" + strings.ReplaceAll(quoted[1:len(quoted)-1], "'", `\'`) + "'"
+}
+
+// ionDecimal returns the number as an Ion decimal, the decimals have a dot or a d exponent
+func ionDecimal(n string) string {
+	mantissa, exponent := strings.TrimPrefix(n, "+"), ""
+
+	if i := strings.IndexAny(mantissa, "eE"); i >= 0 {
+		mantissa, exponent = mantissa[:i], "d"+mantissa[i+1:]
+	}
+
+	if exponent == "" && !strings.Contains(mantissa, ".") {
+		mantissa += "."
+	}
+
+	return mantissa + exponent
+}
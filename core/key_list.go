@@ -0,0 +1,186 @@
+package core
+
+import (
+	"math/rand"
+)
+
+const (
+	keyListMaxLevel = 32
+	// keyListBranching is the inverse of the probability of promoting a node to the next level
+	keyListBranching = 4
+)
+
+// keyList is a skiplist of encoded keys sorted in byte order, the tables and the indexes
+// use it to keep their keys ordered with O(log n) inserts, deletes and seeks
+type keyList struct {
+	head   *keyNode
+	tail   *keyNode
+	level  int
+	length int
+	rnd    *rand.Rand
+}
+
+type keyNode struct {
+	key   string
+	value string
+	prev  *keyNode
+	next  []*keyNode
+}
+
+func newKeyList() *keyList {
+	return &keyList{
+		head:  &keyNode{next: make([]*keyNode, keyListMaxLevel)},
+		level: 1,
+		// a fixed seed keeps the structure of the list reproducible between runs
+		rnd: rand.New(rand.NewSource(1)), // nolint:gosec
+	}
+}
+
+func (l *keyList) randomLevel() int {
+	level := 1
+
+	for level < keyListMaxLevel && l.rnd.Intn(keyListBranching) == 0 {
+		level++
+	}
+
+	return level
+}
+
+// findPrevious returns the last node with a key lower than the given key,
+// update is filled with the last node visited on every level when it is not nil
+func (l *keyList) findPrevious(key string, update []*keyNode) *keyNode {
+	x := l.head
+
+	for i := l.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key < key {
+			x = x.next[i]
+		}
+
+		if update != nil {
+			update[i] = x
+		}
+	}
+
+	return x
+}
+
+// put inserts the key or replaces its value, it returns true when the key is new
+func (l *keyList) put(key, value string) bool {
+	update := make([]*keyNode, keyListMaxLevel)
+
+	x := l.findPrevious(key, update)
+	if n := x.next[0]; n != nil && n.key == key {
+		n.value = value
+
+		return false
+	}
+
+	level := l.randomLevel()
+	for i := l.level; i < level; i++ {
+		update[i] = l.head
+	}
+
+	if level > l.level {
+		l.level = level
+	}
+
+	node := &keyNode{key: key, value: value, next: make([]*keyNode, level)}
+
+	for i := 0; i < level; i++ {
+		node.next[i] = update[i].next[i]
+		update[i].next[i] = node
+	}
+
+	if update[0] != l.head {
+		node.prev = update[0]
+	}
+
+	if node.next[0] != nil {
+		node.next[0].prev = node
+	} else {
+		l.tail = node
+	}
+
+	l.length++
+
+	return true
+}
+
+// delete removes the key, it returns false when the key does not exist
+func (l *keyList) delete(key string) bool {
+	update := make([]*keyNode, keyListMaxLevel)
+
+	node := l.findPrevious(key, update).next[0]
+	if node == nil || node.key != key {
+		return false
+	}
+
+	for i := range node.next {
+		update[i].next[i] = node.next[i]
+	}
+
+	if node.next[0] != nil {
+		node.next[0].prev = node.prev
+	} else {
+		l.tail = node.prev
+	}
+
+	for l.level > 1 && l.head.next[l.level-1] == nil {
+		l.level--
+	}
+
+	l.length--
+
+	return true
+}
+
+// get returns the node of the key
+func (l *keyList) get(key string) (*keyNode, bool) {
+	node := l.seek(key)
+	if node == nil || node.key != key {
+		return nil, false
+	}
+
+	return node, true
+}
+
+// seek returns the first node with a key greater than or equal to the given key
+func (l *keyList) seek(key string) *keyNode {
+	return l.findPrevious(key, nil).next[0]
+}
+
+// seekReverse returns the last node with a key lower than or equal to the given key
+func (l *keyList) seekReverse(key string) *keyNode {
+	node := l.seek(key)
+
+	switch {
+	case node == nil:
+		return l.tail
+	case node.key == key:
+		return node
+	}
+
+	return node.prev
+}
+
+// edge returns the first node when walking forward or the last one when walking backward
+func (l *keyList) edge(forward bool) *keyNode {
+	if forward {
+		return l.head.next[0]
+	}
+
+	return l.tail
+}
+
+func (l *keyList) size() int {
+	return l.length
+}
+
+// step returns the following node in the given direction
+func (n *keyNode) step(forward bool) *keyNode {
+	if forward {
+		return n.next[0]
+	}
+
+	return n.prev
+}
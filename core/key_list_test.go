@@ -0,0 +1,115 @@
+package core
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func keyListKeys(l *keyList, forward bool) []string {
+	keys := []string{}
+
+	for node := l.edge(forward); node != nil; node = node.step(forward) {
+		keys = append(keys, node.key)
+	}
+
+	return keys
+}
+
+func TestKeyListPutAndDelete(t *testing.T) {
+	c := require.New(t)
+
+	l := newKeyList()
+	c.Nil(l.edge(true))
+	c.Nil(l.edge(false))
+
+	c.True(l.put("b", "1"))
+	c.True(l.put("a", "2"))
+	c.True(l.put("c", "3"))
+	c.False(l.put("b", "4"))
+	c.Equal(3, l.size())
+
+	node, ok := l.get("b")
+	c.True(ok)
+	c.Equal("4", node.value)
+
+	c.Equal([]string{"a", "b", "c"}, keyListKeys(l, true))
+	c.Equal([]string{"c", "b", "a"}, keyListKeys(l, false))
+
+	c.True(l.delete("c"))
+	c.False(l.delete("c"))
+	c.Equal(2, l.size())
+	c.Equal("b", l.edge(false).key)
+
+	c.True(l.delete("a"))
+	c.Nil(l.edge(false).prev)
+	c.Equal([]string{"b"}, keyListKeys(l, true))
+
+	_, ok = l.get("a")
+	c.False(ok)
+}
+
+func TestKeyListSeek(t *testing.T) {
+	c := require.New(t)
+
+	l := newKeyList()
+	for _, k := range []string{"002", "004", "006"} {
+		l.put(k, k)
+	}
+
+	c.Equal("002", l.seek("001").key)
+	c.Equal("004", l.seek("004").key)
+	c.Equal("006", l.seek("005").key)
+	c.Nil(l.seek("007"))
+
+	c.Nil(l.seekReverse("001"))
+	c.Equal("004", l.seekReverse("004").key)
+	c.Equal("004", l.seekReverse("005").key)
+	c.Equal("006", l.seekReverse("007").key)
+}
+
+func TestKeyListKeepsOrder(t *testing.T) {
+	c := require.New(t)
+
+	l := newKeyList()
+	expected := map[string]bool{}
+	rnd := rand.New(rand.NewSource(42)) // nolint:gosec
+
+	for i := 0; i < 2000; i++ {
+		k := fmt.Sprintf("%04d", rnd.Intn(1000))
+		if rnd.Intn(3) == 0 {
+			c.Equal(expected[k], l.delete(k))
+			delete(expected, k)
+
+			continue
+		}
+
+		c.Equal(!expected[k], l.put(k, k))
+		expected[k] = true
+	}
+
+	keys := make([]string, 0, len(expected))
+	for k := range expected {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	c.Equal(len(keys), l.size())
+	c.Equal(keys, keyListKeys(l, true))
+
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	c.Equal(keys, keyListKeys(l, false))
+}
+
+func BenchmarkKeyListPut(b *testing.B) {
+	l := newKeyList()
+
+	for i := 0; i < b.N; i++ {
+		k := fmt.Sprintf("%09d", (i*7919)%1000000007)
+		l.put(k, k)
+	}
+}
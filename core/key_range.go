@@ -0,0 +1,261 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/truora/minidyn/interpreter"
+	"github.com/truora/minidyn/interpreter/language"
+	"github.com/truora/minidyn/types"
+)
+
+// keyRange is the span of encoded keys that may satisfy a key condition,
+// lower is inclusive and upper is exclusive, an empty upper means the range is unbounded
+type keyRange struct {
+	lower string
+	upper string
+}
+
+func (r keyRange) contains(key string) bool {
+	return key >= r.lower && (r.upper == "" || key < r.upper)
+}
+
+// start returns the first node of the range in the given direction
+func (r keyRange) start(keys *keyList, forward bool) *keyNode {
+	if forward {
+		return keys.seek(r.lower)
+	}
+
+	if r.upper == "" {
+		return keys.edge(false)
+	}
+
+	node := keys.seek(r.upper)
+	if node == nil {
+		return keys.edge(false)
+	}
+
+	return node.prev
+}
+
+// prefixEnd returns the lowest key greater than every key starting with the prefix
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+
+			return string(end[:i+1])
+		}
+	}
+
+	return ""
+}
+
+// keyBounds is the part of a key condition that can be used to seek the keys
+type keyBounds struct {
+	names      map[string]string
+	values     map[string]*types.Item
+	hash       *types.Item
+	comparison language.Expression
+}
+
+// queryKeyRange extracts the partition key equality and the sort key comparison from the key condition,
+// the range covers every key when the condition can not be used to narrow the search
+func (t *Table) queryKeyRange(input QueryInput, ks keySchema) keyRange {
+	bounds, ok := t.parseKeyBounds(input, ks)
+	if !ok {
+		return keyRange{}
+	}
+
+	hash, ok := t.encodeKeyValue(ks.HashKey, bounds.hash, "S", "B")
+	if !ok {
+		return keyRange{}
+	}
+
+	if ks.RangeKey == "" {
+		return keyRange{lower: hash, upper: hash + "\x01"}
+	}
+
+	partition := hash + "."
+	full := keyRange{lower: partition, upper: prefixEnd(partition)}
+
+	if bounds.comparison == nil || t.AttributesDef[ks.RangeKey] != "S" {
+		return full
+	}
+
+	r, ok := bounds.sortKeyRange(partition)
+	if !ok {
+		return full
+	}
+
+	return r
+}
+
+func (t *Table) parseKeyBounds(input QueryInput, ks keySchema) (keyBounds, bool) {
+	bounds := keyBounds{names: input.Aliases, values: input.ExpressionAttributeValues}
+
+	var conditional *language.ConditionalExpression
+
+	switch {
+	case input.Scan:
+		return bounds, false
+	case input.legacy != nil:
+		conditional = input.legacy.key
+		bounds.names = input.legacy.translator.Names
+		bounds.values = input.legacy.translator.Values
+	case input.KeyConditionExpression != "":
+		var err error
+
+		conditional, err = interpreter.ParseCondition(input.KeyConditionExpression)
+		if err != nil {
+			return bounds, false
+		}
+	}
+
+	if conditional == nil || conditional.Expression == nil {
+		return bounds, false
+	}
+
+	for _, exp := range flattenAnd(conditional.Expression, nil) {
+		bounds.add(exp, ks)
+	}
+
+	return bounds, bounds.hash != nil
+}
+
+// flattenAnd returns the operands of the AND expressions
+func flattenAnd(exp language.Expression, operands []language.Expression) []language.Expression {
+	infix, ok := exp.(*language.InfixExpression)
+	if !ok || infix.Operator != language.AND {
+		return append(operands, exp)
+	}
+
+	operands = flattenAnd(infix.Left, operands)
+
+	return flattenAnd(infix.Right, operands)
+}
+
+func (b *keyBounds) add(exp language.Expression, ks keySchema) {
+	if e, ok := exp.(*language.InfixExpression); ok && e.Operator == language.EQ && b.name(e.Left) == ks.HashKey {
+		b.hash = b.value(e.Right)
+
+		return
+	}
+
+	if ks.RangeKey != "" && b.name(comparedOperand(exp)) == ks.RangeKey {
+		b.comparison = exp
+	}
+}
+
+// comparedOperand returns the attribute compared by a key condition, it is nil for the unsupported conditions
+func comparedOperand(exp language.Expression) language.Expression {
+	switch e := exp.(type) {
+	case *language.InfixExpression:
+		return e.Left
+	case *language.BetweenExpression:
+		return e.Left
+	case *language.CallExpression:
+		if len(e.Arguments) != 2 || e.Function.String() != "begins_with" {
+			return nil
+		}
+
+		return e.Arguments[0]
+	}
+
+	return nil
+}
+
+func (b *keyBounds) name(exp language.Expression) string {
+	ident, ok := exp.(*language.Identifier)
+	if !ok {
+		return ""
+	}
+
+	if name, ok := b.names[ident.Value]; ok {
+		return name
+	}
+
+	return ident.Value
+}
+
+func (b *keyBounds) value(exp language.Expression) *types.Item {
+	ident, ok := exp.(*language.Identifier)
+	if !ok || !strings.HasPrefix(ident.Value, ":") {
+		return nil
+	}
+
+	return b.values[ident.Value]
+}
+
+func (b *keyBounds) stringValue(exp language.Expression) (string, bool) {
+	val := b.value(exp)
+	if val == nil || val.S == nil {
+		return "", false
+	}
+
+	return *val.S, true
+}
+
+// sortKeyRange narrows the partition with the sort key comparison,
+// the encoded keys of a partition share its prefix so they are sorted by the string sort key
+func (b *keyBounds) sortKeyRange(partition string) (keyRange, bool) {
+	switch e := b.comparison.(type) {
+	case *language.InfixExpression:
+		val, ok := b.stringValue(e.Right)
+		if !ok {
+			return keyRange{}, false
+		}
+
+		return comparisonRange(partition, e.Operator, val)
+	case *language.BetweenExpression:
+		from, ok := b.stringValue(e.Range[0])
+		if !ok {
+			return keyRange{}, false
+		}
+
+		to, ok := b.stringValue(e.Range[1])
+
+		return keyRange{lower: partition + from, upper: partition + to + "\x01"}, ok
+	case *language.CallExpression:
+		prefix, ok := b.stringValue(e.Arguments[1])
+
+		return keyRange{lower: partition + prefix, upper: prefixEnd(partition + prefix)}, ok
+	}
+
+	return keyRange{}, false
+}
+
+func comparisonRange(partition, operator, val string) (keyRange, bool) {
+	switch operator {
+	case language.EQ:
+		return keyRange{lower: partition + val, upper: partition + val + "\x01"}, true
+	case language.LT, language.LTE:
+		return keyRange{lower: partition, upper: partition + val + "\x01"}, true
+	case language.GT, language.GTE:
+		return keyRange{lower: partition + val, upper: prefixEnd(partition)}, true
+	}
+
+	return keyRange{}, false
+}
+
+// encodeKeyValue encodes the value as the keys do, only the types with a single representation are encoded
+func (t *Table) encodeKeyValue(attr string, val *types.Item, allowed ...string) (string, bool) {
+	typ := t.AttributesDef[attr]
+
+	for _, a := range allowed {
+		if a != typ {
+			continue
+		}
+
+		goVal, err := getItemValue(map[string]*types.Item{attr: val}, attr, typ)
+		if err != nil {
+			return "", false
+		}
+
+		return fmt.Sprintf("%v", goVal), true
+	}
+
+	return "", false
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestQueryKeyRange(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	values := map[string]*types.Item{
+		":id":   {S: types.ToString("001")},
+		":from": {S: types.ToString("B")},
+		":to":   {S: types.ToString("D")},
+		":n":    {N: types.ToString("1")},
+	}
+
+	tests := map[string]keyRange{
+		"id = :id":                                 {lower: "001.", upper: "001/"},
+		"#id = :id":                                {lower: "001.", upper: "001/"},
+		"id = :id AND #name = :from":               {lower: "001.B", upper: "001.B\x01"},
+		"id = :id AND #name < :from":               {lower: "001.", upper: "001.B\x01"},
+		"id = :id AND #name >= :from":              {lower: "001.B", upper: "001/"},
+		"id = :id AND #name BETWEEN :from AND :to": {lower: "001.B", upper: "001.D\x01"},
+		"id = :id AND begins_with(#name, :from)":   {lower: "001.B", upper: "001.C"},
+		"id = :id AND #name > :from":               {lower: "001.B", upper: "001/"},
+		"id = :id AND #name <= :to":                {lower: "001.", upper: "001.D\x01"},
+		"id = :id AND begins_with(#name, :id)":     {lower: "001.001", upper: "001.002"},
+		"id = :id AND begins_with(#name, #id)":     {lower: "001.", upper: "001/"},
+		"id = :id AND begins_with(#name, :a, :b)":  {lower: "001.", upper: "001/"},
+		"id = :id AND contains(#name, :from)":      {lower: "001.", upper: "001/"},
+		"#name = :from AND id = :id":               {lower: "001.B", upper: "001.B\x01"},
+		"id = :id AND #name = :n":                  {lower: "001.", upper: "001/"},
+		"id = :n":                                  {},
+		"id <> :id":                                {},
+		"#name = :from":                            {},
+		"id = #name":                               {},
+	}
+
+	for expression, expected := range tests {
+		r := newTable.queryKeyRange(QueryInput{
+			KeyConditionExpression:    expression,
+			ExpressionAttributeValues: values,
+			Aliases:                   map[string]string{"#id": "id", "#name": "name"},
+		}, newTable.KeySchema)
+		c.Equal(expected, r, expression)
+	}
+
+	r := newTable.queryKeyRange(QueryInput{Scan: true, KeyConditionExpression: "id = :id", ExpressionAttributeValues: values}, newTable.KeySchema)
+	c.Equal(keyRange{}, r)
+
+	legacy, err := translateLegacyQuery(QueryInput{
+		KeyConditions: map[string]*types.Condition{
+			"id": {ComparisonOperator: types.ToString("EQ"), AttributeValueList: []*types.Item{values[":id"]}},
+		},
+	})
+	c.NoError(err)
+
+	r = newTable.queryKeyRange(QueryInput{legacy: legacy}, newTable.KeySchema)
+	c.Equal(keyRange{lower: "001.", upper: "001/"}, r)
+}
+
+func TestSearchDataSeeksThePartition(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	for i := 0; i < 30; i++ {
+		_, err = newTable.Put(&types.PutItemInput{
+			Item: createPokemon(pokemon{
+				ID:   fmt.Sprintf("%03d", i%3),
+				Type: "grass",
+				Name: fmt.Sprintf("pokemon-%02d", i),
+			}),
+		})
+		c.NoError(err)
+	}
+
+	input := QueryInput{
+		KeyConditionExpression: "id = :id AND begins_with(#name, :prefix)",
+		ExpressionAttributeValues: map[string]*types.Item{
+			":id":     {S: types.ToString("001")},
+			":prefix": {S: types.ToString("pokemon-1")},
+		},
+		Aliases:          map[string]string{"#name": "name"},
+		ScanIndexForward: true,
+		Limit:            2,
+	}
+
+	items, last, err := newTable.SearchData(input)
+	c.NoError(err)
+	c.Len(items, 2)
+	c.Equal("pokemon-10", types.StringValue(items[0]["name"].S))
+	c.Equal("pokemon-13", types.StringValue(items[1]["name"].S))
+
+	input.ExclusiveStartKey = last
+	items, last, err = newTable.SearchData(input)
+	c.NoError(err)
+	c.Len(items, 2)
+	c.Equal("pokemon-16", types.StringValue(items[0]["name"].S))
+	c.Equal("pokemon-19", types.StringValue(items[1]["name"].S))
+
+	input.ExclusiveStartKey = last
+	items, last, err = newTable.SearchData(input)
+	c.NoError(err)
+	c.Empty(items)
+	c.Empty(last)
+
+	input.ScanIndexForward = false
+	input.ExclusiveStartKey = nil
+	input.Limit = 0
+
+	items, _, err = newTable.SearchData(input)
+	c.NoError(err)
+	c.Len(items, 4)
+	c.Equal("pokemon-19", types.StringValue(items[0]["name"].S))
+	c.Equal("pokemon-10", types.StringValue(items[3]["name"].S))
+
+	input.Index = "invert"
+	items, _, err = newTable.SearchData(input)
+	c.NoError(err)
+	c.Len(items, 4)
+	c.Equal("pokemon-19", types.StringValue(items[0]["name"].S))
+}
+
+func TestSearchStartKey(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	newTable.AttributesDef["type"] = "S"
+
+	item := createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	startKey := map[string]*types.Item{"id": item["id"], "name": item["name"]}
+
+	key, ok := newTable.searchStartKey(startKey, nil)
+	c.True(ok)
+	c.Equal("001.Bulbasaur", key)
+
+	key, ok = newTable.searchStartKey(nil, newTable.Indexes["invert"])
+	c.True(ok)
+	c.Empty(key)
+
+	byType := newIndex(newTable, indexTypeGlobal, keySchema{HashKey: "type"})
+
+	// the start key may belong to an item that is no longer stored
+	key, ok = newTable.searchStartKey(item, byType)
+	c.True(ok)
+	c.Equal(entryKey("grass", "001.Bulbasaur"), key)
+
+	_, ok = newTable.searchStartKey(startKey, byType)
+	c.False(ok)
+
+	byType.refs["001.Bulbasaur"] = "poison"
+
+	key, ok = newTable.searchStartKey(startKey, byType)
+	c.True(ok)
+	c.Equal(entryKey("poison", "001.Bulbasaur"), key)
+}
+
+func TestSearchDataExclusiveStartKeyOutsideTheRange(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	for _, name := range []string{"A", "B", "C", "D"} {
+		_, err = newTable.Put(&types.PutItemInput{Item: createPokemon(pokemon{ID: "001", Type: "grass", Name: name})})
+		c.NoError(err)
+	}
+
+	input := QueryInput{
+		KeyConditionExpression: "id = :id AND #name BETWEEN :from AND :to",
+		ExpressionAttributeValues: map[string]*types.Item{
+			":id":   {S: types.ToString("001")},
+			":from": {S: types.ToString("B")},
+			":to":   {S: types.ToString("C")},
+		},
+		Aliases:           map[string]string{"#name": "name"},
+		ScanIndexForward:  true,
+		ExclusiveStartKey: map[string]*types.Item{"id": {S: types.ToString("001")}, "name": {S: types.ToString("A")}},
+	}
+
+	items, _, err := newTable.SearchData(input)
+	c.NoError(err)
+	c.Len(items, 2)
+	c.Equal("B", types.StringValue(items[0]["name"].S))
+
+	input.ScanIndexForward = false
+	input.ExclusiveStartKey["name"] = &types.Item{S: types.ToString("D")}
+
+	items, _, err = newTable.SearchData(input)
+	c.NoError(err)
+	c.Len(items, 2)
+	c.Equal("C", types.StringValue(items[0]["name"].S))
+
+	input.ExclusiveStartKey["name"] = &types.Item{S: types.ToString("B")}
+
+	items, _, err = newTable.SearchData(input)
+	c.NoError(err)
+	c.Empty(items)
+}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/truora/minidyn/interpreter"
+	"github.com/truora/minidyn/interpreter/language"
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// revive:disable-next-line
+	mixedParametersMsg = "Can not use both expression and non-expression parameters in the same request: Non-expression parameters: {%s} Expression parameters: {%s}"
+	// revive:disable-next-line
+	expressionOnlyParameterMsg = "%s can only be specified when using expressions"
+)
+
+var expressionAttributesParameters = []string{"ExpressionAttributeNames", "ExpressionAttributeValues"}
+
+// legacyQuery holds the KeyConditions and QueryFilter (or ScanFilter) parameters translated into expressions
+type legacyQuery struct {
+	translator *language.Legacy
+	key        *language.ConditionalExpression
+	filter     *language.ConditionalExpression
+}
+
+// ValidateLegacyParameters rejects the requests mixing legacy and expression parameters,
+// the maps hold the parameter names and whether they were set in the request
+func ValidateLegacyParameters(nonExpression, expression map[string]bool) error {
+	legacy := setParameters(nonExpression)
+	if len(legacy) == 0 {
+		return nil
+	}
+
+	exprs := setParameters(expression, expressionAttributesParameters...)
+	if len(exprs) > 0 {
+		return types.NewError("ValidationException", fmt.Sprintf(mixedParametersMsg, strings.Join(legacy, ", "), strings.Join(exprs, ", ")), nil)
+	}
+
+	for _, param := range expressionAttributesParameters {
+		if expression[param] {
+			return types.NewError("ValidationException", fmt.Sprintf(expressionOnlyParameterMsg, param), nil)
+		}
+	}
+
+	return nil
+}
+
+func setParameters(params map[string]bool, exclude ...string) []string {
+	names := []string{}
+
+	for name, set := range params {
+		if set {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range exclude {
+		names = removeString(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func removeString(names []string, name string) []string {
+	for i, n := range names {
+		if n == name {
+			return append(names[:i], names[i+1:]...)
+		}
+	}
+
+	return names
+}
+
+// ProjectAttributes returns the attributes of the item listed in the AttributesToGet parameter,
+// the item is returned as it is when the parameter is empty
+func ProjectAttributes(item map[string]*types.Item, attributes []string) map[string]*types.Item {
+	if len(attributes) == 0 || item == nil {
+		return item
+	}
+
+	projected := make(map[string]*types.Item, len(attributes))
+
+	for _, attr := range attributes {
+		if val, ok := item[attr]; ok {
+			projected[attr] = val
+		}
+	}
+
+	return projected
+}
+
+func projectItems(items []map[string]*types.Item, attributes []string) []map[string]*types.Item {
+	if len(attributes) == 0 {
+		return items
+	}
+
+	for i, item := range items {
+		items[i] = ProjectAttributes(item, attributes)
+	}
+
+	return items
+}
+
+func (t *Table) matchLegacy(lg *language.Legacy, conditional *language.ConditionalExpression, typ interpreter.ExpressionType, item map[string]*types.Item) (bool, error) {
+	if conditional == nil {
+		return true, nil
+	}
+
+	// the legacy parameters do not have a native representation, they are always evaluated by the language interpreter
+	matched, err := t.LangInterpreter.MatchConditional(conditional, interpreter.MatchInput{
+		TableName:      t.Name,
+		ExpressionType: typ,
+		Item:           item,
+		Aliases:        lg.Names,
+		Attributes:     lg.Values,
+		Key:            t.traceKey(item),
+	})
+	if err != nil {
+		return false, expressionError(typ, err)
+	}
+
+	return matched, nil
+}
+
+func (t *Table) matchExpected(expected map[string]*types.ExpectedAttributeValue, conditionalOperator *string, item map[string]*types.Item) (bool, error) {
+	lg := language.NewLegacy()
+
+	conditional, err := lg.Expected(expected, types.StringValue(conditionalOperator))
+	if err != nil {
+		return false, types.NewError("ValidationException", err.Error(), nil)
+	}
+
+	return t.matchLegacy(lg, conditional, interpreter.ExpressionTypeConditional, item)
+}
+
+func (t *Table) applyAttributeUpdates(updates map[string]*types.AttributeValueUpdate, item map[string]*types.Item) error {
+	lg := language.NewLegacy()
+
+	update, err := lg.AttributeUpdates(updates)
+	if err != nil {
+		return types.NewError("ValidationException", err.Error(), nil)
+	}
+
+	return t.LangInterpreter.UpdateStatement(update, interpreter.UpdateInput{
+		TableName:  t.Name,
+		Item:       item,
+		Attributes: lg.Values,
+		Aliases:    lg.Names,
+		Key:        t.traceKey(item),
+	})
+}
+
+func translateLegacyQuery(input QueryInput) (*legacyQuery, error) {
+	if len(input.KeyConditions) == 0 && len(input.QueryFilter) == 0 {
+		return nil, nil
+	}
+
+	lg := language.NewLegacy()
+
+	key, err := lg.Conditions(input.KeyConditions, "")
+	if err != nil {
+		return nil, types.NewError("ValidationException", err.Error(), nil)
+	}
+
+	filter, err := lg.Conditions(input.QueryFilter, input.ConditionalOperator)
+	if err != nil {
+		return nil, types.NewError("ValidationException", err.Error(), nil)
+	}
+
+	return &legacyQuery{translator: lg, key: key, filter: filter}, nil
+}
+
+func (t *Table) matchLegacyQuery(input QueryInput, item map[string]*types.Item) (interpreter.ExpressionType, bool, error) {
+	var (
+		lastMatchExpressionType interpreter.ExpressionType
+		err                     error
+	)
+
+	matched := input.Scan
+	lg := input.legacy
+
+	if lg.key != nil {
+		lastMatchExpressionType = interpreter.ExpressionTypeKey
+
+		matched, err = t.matchLegacy(lg.translator, lg.key, lastMatchExpressionType, item)
+		if err != nil {
+			return lastMatchExpressionType, false, err
+		}
+	}
+
+	if lg.filter != nil {
+		lastMatchExpressionType = interpreter.ExpressionTypeFilter
+
+		if matched {
+			matched, err = t.matchLegacy(lg.translator, lg.filter, lastMatchExpressionType, item)
+		}
+	}
+
+	return lastMatchExpressionType, matched, err
+}
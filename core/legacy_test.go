@@ -0,0 +1,275 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestValidateLegacyParameters(t *testing.T) {
+	c := require.New(t)
+
+	err := ValidateLegacyParameters(map[string]bool{"Expected": false}, map[string]bool{"ConditionExpression": true})
+	c.NoError(err)
+
+	err = ValidateLegacyParameters(map[string]bool{"Expected": true}, map[string]bool{"ExpressionAttributeNames": false})
+	c.NoError(err)
+
+	err = ValidateLegacyParameters(map[string]bool{"Expected": true, "AttributeUpdates": true}, map[string]bool{
+		"ConditionExpression":      true,
+		"UpdateExpression":         true,
+		"ExpressionAttributeNames": true,
+	})
+	c.EqualError(err, "ValidationException: Can not use both expression and non-expression parameters in the same request: Non-expression parameters: {AttributeUpdates, Expected} Expression parameters: {ConditionExpression, UpdateExpression}")
+
+	err = ValidateLegacyParameters(map[string]bool{"Expected": true}, map[string]bool{"ExpressionAttributeValues": true})
+	c.EqualError(err, "ValidationException: ExpressionAttributeValues can only be specified when using expressions")
+}
+
+func TestPutItemWithExpected(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	item := createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+
+	input := &types.PutItemInput{
+		Item:      item,
+		TableName: &newTable.Name,
+		Expected: map[string]*types.ExpectedAttributeValue{
+			"id": {Exists: aws.Bool(false)},
+		},
+	}
+
+	_, err = newTable.Put(input)
+	c.NoError(err)
+
+	_, err = newTable.Put(input)
+	c.EqualError(err, "ConditionalCheckFailedException: "+ErrConditionalRequestFailed.Error())
+
+	input.Expected = map[string]*types.ExpectedAttributeValue{
+		"type": {Value: &types.Item{S: types.ToString("grass")}},
+	}
+
+	_, err = newTable.Put(input)
+	c.NoError(err)
+
+	input.Expected = map[string]*types.ExpectedAttributeValue{
+		"type": {Exists: aws.Bool(true)},
+	}
+
+	_, err = newTable.Put(input)
+	c.EqualError(err, "ValidationException: One or more parameter values were invalid: Value must be provided when Exists is true for Attribute: type")
+}
+
+func TestUpdateWithAttributeUpdates(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	item := createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	item["lvl"] = &types.Item{N: types.ToString("5")}
+
+	_, err = newTable.Put(&types.PutItemInput{Item: item, TableName: &newTable.Name})
+	c.NoError(err)
+
+	key := map[string]*types.Item{"id": item["id"], "name": item["name"]}
+
+	updateInput := &types.UpdateItemInput{
+		Key: key,
+		AttributeUpdates: map[string]*types.AttributeValueUpdate{
+			"lvl":  {Action: types.ToString("ADD"), Value: &types.Item{N: types.ToString("1")}},
+			"type": {Action: types.ToString("DELETE")},
+			"moves": {
+				Action: types.ToString("PUT"),
+				Value:  &types.Item{SS: []*string{types.ToString("tackle")}},
+			},
+		},
+		Expected: map[string]*types.ExpectedAttributeValue{
+			"lvl": {ComparisonOperator: types.ToString("LT"), AttributeValueList: []*types.Item{{N: types.ToString("10")}}},
+		},
+	}
+
+	result, err := newTable.Update(updateInput)
+	c.NoError(err)
+	c.Equal("6", types.StringValue(result["lvl"].N))
+	c.NotContains(result, "type")
+	c.Len(result["moves"].SS, 1)
+
+	updateInput.Expected["lvl"].AttributeValueList[0].N = types.ToString("6")
+
+	_, err = newTable.Update(updateInput)
+
+	var checkErr *types.ConditionalCheckFailedException
+	c.True(errors.As(err, &checkErr))
+}
+
+func TestDeleteWithExpected(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	item := createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+
+	_, err = newTable.Put(&types.PutItemInput{Item: item, TableName: &newTable.Name})
+	c.NoError(err)
+
+	deleteInput := &types.DeleteItemInput{
+		Key: map[string]*types.Item{"id": item["id"], "name": item["name"]},
+		Expected: map[string]*types.ExpectedAttributeValue{
+			"type": {ComparisonOperator: types.ToString("EQ"), AttributeValueList: []*types.Item{{S: types.ToString("fire")}}},
+		},
+	}
+
+	_, err = newTable.Delete(deleteInput)
+	c.EqualError(err, "ConditionalCheckFailedException: "+ErrConditionalRequestFailed.Error())
+
+	deleteInput.Expected["type"].ComparisonOperator = types.ToString("MATCHES")
+
+	_, err = newTable.Delete(deleteInput)
+	c.Error(err)
+	c.Contains(err.Error(), "ValidationException")
+
+	deleteInput.Expected["type"].ComparisonOperator = types.ToString("EQ")
+	deleteInput.Expected["type"].AttributeValueList[0].S = types.ToString("grass")
+
+	_, err = newTable.Delete(deleteInput)
+	c.NoError(err)
+	c.Empty(newTable.Data)
+
+	deleteInput.Expected = map[string]*types.ExpectedAttributeValue{"type": {Exists: types.ToBool(false)}}
+
+	_, err = newTable.Delete(deleteInput)
+	c.NoError(err)
+}
+
+func TestSearchDataWithLegacyConditions(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	for _, p := range []pokemon{
+		{ID: "001", Type: "grass", Name: "Bulbasaur"},
+		{ID: "001", Type: "poison", Name: "Ivysaur"},
+		{ID: "004", Type: "fire", Name: "Charmander"},
+	} {
+		_, err = newTable.Put(&types.PutItemInput{Item: createPokemon(p), TableName: &newTable.Name})
+		c.NoError(err)
+	}
+
+	input := QueryInput{
+		KeyConditions: map[string]*types.Condition{
+			"id": {ComparisonOperator: types.ToString("EQ"), AttributeValueList: []*types.Item{{S: types.ToString("001")}}},
+		},
+		QueryFilter: map[string]*types.Condition{
+			"type": {ComparisonOperator: types.ToString("BEGINS_WITH"), AttributeValueList: []*types.Item{{S: types.ToString("gr")}}},
+		},
+		ScanIndexForward: true,
+	}
+
+	c.NoError(newTable.ValidateQuery(input))
+
+	result, _, err := newTable.SearchData(input)
+	c.NoError(err)
+	c.Len(result, 1)
+	c.Equal("Bulbasaur", types.StringValue(result[0]["name"].S))
+
+	input = QueryInput{
+		QueryFilter: map[string]*types.Condition{
+			"type": {ComparisonOperator: types.ToString("IN"), AttributeValueList: []*types.Item{{S: types.ToString("fire")}, {S: types.ToString("poison")}}},
+		},
+		Scan:             true,
+		ScanIndexForward: true,
+	}
+
+	result, _, err = newTable.SearchData(input)
+	c.NoError(err)
+	c.Len(result, 2)
+
+	input.ConditionalOperator = "XOR"
+	c.EqualError(newTable.ValidateQuery(input), "ValidationException: One or more parameter values were invalid: Unsupported ConditionalOperator: XOR")
+}
+
+func TestLegacyTranslationErrors(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	item := createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+
+	_, err = newTable.Put(&types.PutItemInput{Item: item, TableName: &newTable.Name})
+	c.NoError(err)
+
+	matched, err := newTable.matchExpected(map[string]*types.ExpectedAttributeValue{}, nil, item)
+	c.NoError(err)
+	c.True(matched)
+
+	_, err = newTable.Update(&types.UpdateItemInput{
+		Key: map[string]*types.Item{"id": item["id"], "name": item["name"]},
+		AttributeUpdates: map[string]*types.AttributeValueUpdate{
+			"type": {Action: types.ToString("REPLACE"), Value: &types.Item{S: types.ToString("poison")}},
+		},
+	})
+	c.EqualError(err, "ValidationException: One or more parameter values were invalid: Unsupported action on AttributeUpdates: REPLACE")
+
+	_, _, err = newTable.SearchData(QueryInput{
+		KeyConditions: map[string]*types.Condition{
+			"id": {ComparisonOperator: types.ToString("LIKE"), AttributeValueList: []*types.Item{{S: types.ToString("001")}}},
+		},
+	})
+	c.EqualError(err, "ValidationException: One or more parameter values were invalid: Unsupported operator on ComparisonOperator: LIKE")
+
+	_, _, err = newTable.SearchData(QueryInput{
+		QueryFilter: map[string]*types.Condition{
+			"type": {ComparisonOperator: types.ToString("IN")},
+		},
+		Scan: true,
+	})
+	c.EqualError(err, "ValidationException: One or more parameter values were invalid: Invalid number of argument(s) for the IN ComparisonOperator")
+
+	_, _, err = newTable.SearchData(QueryInput{
+		QueryFilter: map[string]*types.Condition{
+			"type": {ComparisonOperator: types.ToString("BETWEEN"), AttributeValueList: []*types.Item{{S: types.ToString("z")}, {S: types.ToString("a")}}},
+		},
+		Scan: true,
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "The BETWEEN operator requires upper bound to be greater than or equal to lower bound")
+}
+
+func TestProjectAttributes(t *testing.T) {
+	c := require.New(t)
+
+	item := createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+
+	c.Equal(item, ProjectAttributes(item, nil))
+	c.Nil(ProjectAttributes(nil, []string{"name"}))
+
+	projected := ProjectAttributes(item, []string{"name", "missing"})
+	c.Len(projected, 1)
+	c.Equal("Bulbasaur", types.StringValue(projected["name"].S))
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	_, err = newTable.Put(&types.PutItemInput{Item: item, TableName: &newTable.Name})
+	c.NoError(err)
+
+	result, _, err := newTable.SearchData(QueryInput{Scan: true, ScanIndexForward: true, AttributesToGet: []string{"id", "type"}})
+	c.NoError(err)
+	c.Len(result, 1)
+	c.Len(result[0], 2)
+	c.Equal("grass", types.StringValue(result[0]["type"].S))
+
+	key, err := newTable.KeySchema.GetKey(newTable.AttributesDef, item)
+	c.NoError(err)
+	c.Contains(newTable.Data[key], "name")
+}
@@ -0,0 +1,325 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/truora/minidyn/interpreter"
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// revive:disable-next-line
+	itemSizeExceededMsg = "Item size has exceeded the maximum allowed size"
+	// revive:disable-next-line
+	updateItemSizeExceededMsg = "Item size to update has exceeded the maximum allowed size"
+	// revive:disable-next-line
+	hashKeySizeExceededMsg = "One or more parameter values were invalid: Size of hashkey has exceeded the maximum size limit of%d bytes"
+	// revive:disable-next-line
+	rangeKeySizeExceededMsg = "One or more parameter values were invalid: Aggregated size of all range keys has exceeded the size limit of %d bytes"
+	// revive:disable-next-line
+	expressionSizeExceededMsg = "Invalid %s: Expression size has exceeded the maximum allowed size; expression size: %d"
+	// revive:disable-next-line
+	expressionAttributeSizeExceededMsg = "%s contains invalid key: Exceeds the maximum allowed size of %d bytes; key: %q"
+	// revive:disable-next-line
+	nestingLevelsExceededMsg = "Nesting Levels have exceeded supported limits"
+	// revive:disable-next-line
+	transactionItemsExceededMsg = "1 validation error detected: Value at 'transactItems' failed to satisfy constraint: Member must have length less than or equal to %d"
+	// revive:disable-next-line
+	transactionItemsEmptyMsg = "1 validation error detected: Value at 'transactItems' failed to satisfy constraint: Member must have length greater than or equal to 1"
+)
+
+// Limits holds the DynamoDB service limits enforced by the tables
+type Limits struct {
+	// ItemSize is the maximum size in bytes of an item
+	ItemSize int
+	// PartitionKeySize is the maximum size in bytes of the partition key value
+	PartitionKeySize int
+	// SortKeySize is the maximum size in bytes of the sort key value
+	SortKeySize int
+	// ExpressionSize is the maximum length in bytes of an expression
+	ExpressionSize int
+	// ExpressionAttributeSize is the maximum size in bytes of an expression attribute name or value placeholder
+	ExpressionAttributeSize int
+	// NestingDepth is the maximum levels of nested lists and maps in an item
+	NestingDepth int
+	// TransactionItems is the maximum number of actions in a transaction
+	TransactionItems int
+	// TagsPerResource is the maximum number of tags of a table
+	TagsPerResource int
+	// TagsPerPage is the maximum number of tags in a page of ListTagsOfResource
+	TagsPerPage int
+}
+
+// DefaultLimits are the limits assigned to the new tables,
+// override them in tests to exercise the limit errors with smaller payloads
+var DefaultLimits = Limits{
+	ItemSize:                400 * 1024,
+	PartitionKeySize:        2048,
+	SortKeySize:             1024,
+	ExpressionSize:          4 * 1024,
+	ExpressionAttributeSize: 255,
+	NestingDepth:            32,
+	TransactionItems:        100,
+	TagsPerResource:         50,
+	TagsPerPage:             10,
+}
+
+func (l Limits) validateTransactionItems(count int) error {
+	if count < 1 {
+		return types.NewError("ValidationException", transactionItemsEmptyMsg, nil)
+	}
+
+	if count > l.TransactionItems {
+		return types.NewError("ValidationException", fmt.Sprintf(transactionItemsExceededMsg, l.TransactionItems), nil)
+	}
+
+	return nil
+}
+
+// ValidateTransactWriteItems checks the number of actions of a TransactWriteItems request against the limits of the client
+func ValidateTransactWriteItems(limits Limits, actions int) error {
+	return limits.validateTransactionItems(actions)
+}
+
+func (l Limits) validateItem(item map[string]*types.Item, sizeMsg string) error {
+	for _, val := range item {
+		if attributeDepth(val) > l.NestingDepth {
+			return types.NewError("ValidationException", nestingLevelsExceededMsg, nil)
+		}
+	}
+
+	if itemSize(item) > l.ItemSize {
+		return types.NewError("ValidationException", sizeMsg, nil)
+	}
+
+	return nil
+}
+
+func (l Limits) validateKey(ks keySchema, item map[string]*types.Item) error {
+	if val, ok := item[ks.HashKey]; ok && attributeSize(val) > l.PartitionKeySize {
+		return types.NewError("ValidationException", fmt.Sprintf(hashKeySizeExceededMsg, l.PartitionKeySize), nil)
+	}
+
+	if ks.RangeKey == "" {
+		return nil
+	}
+
+	if val, ok := item[ks.RangeKey]; ok && attributeSize(val) > l.SortKeySize {
+		return types.NewError("ValidationException", fmt.Sprintf(rangeKeySizeExceededMsg, l.SortKeySize), nil)
+	}
+
+	return nil
+}
+
+func (l Limits) validateExpression(typ interpreter.ExpressionType, expression string) error {
+	if len(expression) > l.ExpressionSize {
+		return types.NewError("ValidationException", fmt.Sprintf(expressionSizeExceededMsg, typ.ParameterName(), len(expression)), nil)
+	}
+
+	return nil
+}
+
+func validateExpressionAttributeKeys[V any](l Limits, parameter string, attributes map[string]V) error {
+	keys := make([]string, 0, len(attributes))
+
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if len(key) > l.ExpressionAttributeSize {
+			return types.NewError("ValidationException", fmt.Sprintf(expressionAttributeSizeExceededMsg, parameter, l.ExpressionAttributeSize, key), nil)
+		}
+	}
+
+	return nil
+}
+
+func validateExpressionAttributes[N any](l Limits, names map[string]N, values map[string]*types.Item) error {
+	err := validateExpressionAttributeKeys(l, "ExpressionAttributeNames", names)
+	if err != nil {
+		return err
+	}
+
+	return validateExpressionAttributeKeys(l, "ExpressionAttributeValues", values)
+}
+
+func itemSize(item map[string]*types.Item) int {
+	size := 0
+
+	for name, val := range item {
+		size += len(name) + attributeSize(val)
+	}
+
+	return size
+}
+
+// attributeSize approximates the size DynamoDB assigns to an attribute value
+func attributeSize(val *types.Item) int {
+	if val == nil {
+		return 0
+	}
+
+	switch {
+	case val.S != nil:
+		return len(*val.S)
+	case val.N != nil:
+		return numberSize(*val.N)
+	case val.B != nil:
+		return len(val.B)
+	case val.BOOL != nil, val.NULL != nil:
+		return 1
+	}
+
+	return complexAttributeSize(val)
+}
+
+func complexAttributeSize(val *types.Item) int {
+	// lists and maps have an overhead of 3 bytes plus 1 byte per element
+	size := 3
+
+	switch {
+	case val.L != nil:
+		for _, elem := range val.L {
+			size += 1 + attributeSize(elem)
+		}
+	case val.M != nil:
+		size += itemSize(val.M) + len(val.M)
+	default:
+		size = setSize(val)
+	}
+
+	return size
+}
+
+func setSize(val *types.Item) int {
+	size := 0
+
+	for _, s := range val.SS {
+		size += len(types.StringValue(s))
+	}
+
+	for _, n := range val.NS {
+		size += numberSize(types.StringValue(n))
+	}
+
+	for _, b := range val.BS {
+		size += len(b)
+	}
+
+	return size
+}
+
+// numberSize returns the size of a number, it is 1 byte per 2 significant digits plus 1 byte
+func numberSize(n string) int {
+	digits := strings.Trim(strings.TrimLeft(n, "+-"), "0.")
+	digits = strings.Replace(digits, ".", "", 1)
+
+	return (len(digits)+1)/2 + 1
+}
+
+func attributeDepth(val *types.Item) int {
+	if val == nil {
+		return 0
+	}
+
+	depth := 0
+
+	for _, elem := range val.L {
+		depth = maxInt(depth, attributeDepth(elem))
+	}
+
+	for _, elem := range val.M {
+		depth = maxInt(depth, attributeDepth(elem))
+	}
+
+	if val.L != nil || val.M != nil {
+		depth++
+	}
+
+	return depth
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+// ValidateQuery checks the query input against the table limits and the legacy conditions
+func (t *Table) ValidateQuery(input QueryInput) error {
+	_, err := translateLegacyQuery(input)
+	if err != nil {
+		return err
+	}
+
+	err = validateExpressionAttributes(t.Limits, input.Aliases, input.ExpressionAttributeValues)
+	if err != nil {
+		return err
+	}
+
+	err = t.Limits.validateExpression(interpreter.ExpressionTypeKey, input.KeyConditionExpression)
+	if err != nil {
+		return err
+	}
+
+	return t.Limits.validateExpression(interpreter.ExpressionTypeFilter, input.FilterExpression)
+}
+
+func (t *Table) validatePutInput(input *types.PutItemInput) error {
+	err := validateExpressionAttributes(t.Limits, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	if err != nil {
+		return err
+	}
+
+	err = t.Limits.validateExpression(interpreter.ExpressionTypeConditional, types.StringValue(input.ConditionExpression))
+	if err != nil {
+		return err
+	}
+
+	err = t.Limits.validateKey(t.KeySchema, input.Item)
+	if err != nil {
+		return err
+	}
+
+	return t.Limits.validateItem(input.Item, itemSizeExceededMsg)
+}
+
+func (t *Table) validateUpdateInput(input *types.UpdateItemInput) error {
+	err := validateExpressionAttributes(t.Limits, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	if err != nil {
+		return err
+	}
+
+	err = t.Limits.validateExpression(interpreter.ExpressionTypeUpdate, input.UpdateExpression)
+	if err != nil {
+		return err
+	}
+
+	err = t.Limits.validateExpression(interpreter.ExpressionTypeConditional, types.StringValue(input.ConditionExpression))
+	if err != nil {
+		return err
+	}
+
+	return t.Limits.validateKey(t.KeySchema, input.Key)
+}
+
+func (t *Table) validateDeleteInput(input *types.DeleteItemInput) error {
+	err := validateExpressionAttributes(t.Limits, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	if err != nil {
+		return err
+	}
+
+	err = t.Limits.validateExpression(interpreter.ExpressionTypeConditional, types.StringValue(input.ConditionExpression))
+	if err != nil {
+		return err
+	}
+
+	return t.Limits.validateKey(t.KeySchema, input.Key)
+}
@@ -0,0 +1,272 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestPutItemLimits(t *testing.T) {
+	c := require.New(t)
+
+	table, err := createPokemonTable()
+	c.NoError(err)
+
+	table.Limits.ItemSize = 100
+
+	item := createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	item["description"] = &types.Item{S: types.ToString(strings.Repeat("a", 100))}
+
+	_, err = table.Put(&types.PutItemInput{Item: item, TableName: &tableName})
+	c.EqualError(err, "ValidationException: "+itemSizeExceededMsg)
+
+	item = createPokemon(pokemon{ID: strings.Repeat("1", 2049), Type: "grass", Name: "Bulbasaur"})
+
+	table.Limits = DefaultLimits
+
+	_, err = table.Put(&types.PutItemInput{Item: item, TableName: &tableName})
+	c.EqualError(err, "ValidationException: One or more parameter values were invalid: Size of hashkey has exceeded the maximum size limit of2048 bytes")
+
+	item = createPokemon(pokemon{ID: "001", Type: "grass", Name: strings.Repeat("b", 1025)})
+
+	_, err = table.Put(&types.PutItemInput{Item: item, TableName: &tableName})
+	c.EqualError(err, "ValidationException: One or more parameter values were invalid: Aggregated size of all range keys has exceeded the size limit of 1024 bytes")
+
+	item = createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	item["nested"] = nestedAttribute(33)
+
+	_, err = table.Put(&types.PutItemInput{Item: item, TableName: &tableName})
+	c.EqualError(err, "ValidationException: "+nestingLevelsExceededMsg)
+
+	item["nested"] = nestedAttribute(32)
+
+	_, err = table.Put(&types.PutItemInput{Item: item, TableName: &tableName})
+	c.NoError(err)
+}
+
+func TestPutItemExpressionLimits(t *testing.T) {
+	c := require.New(t)
+
+	table, err := createPokemonTable()
+	c.NoError(err)
+
+	item := createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	expr := "attribute_not_exists(#id)" + strings.Repeat(" ", 4096)
+
+	_, err = table.Put(&types.PutItemInput{
+		Item:                     item,
+		TableName:                &tableName,
+		ConditionExpression:      &expr,
+		ExpressionAttributeNames: map[string]string{"#id": "id"},
+	})
+	c.EqualError(err, "ValidationException: Invalid ConditionExpression: Expression size has exceeded the maximum allowed size; expression size: 4121")
+
+	name := "#" + strings.Repeat("a", 255)
+	expr = "attribute_not_exists(" + name + ")"
+
+	_, err = table.Put(&types.PutItemInput{
+		Item:                     item,
+		TableName:                &tableName,
+		ConditionExpression:      &expr,
+		ExpressionAttributeNames: map[string]string{name: "id"},
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "ExpressionAttributeNames contains invalid key: Exceeds the maximum allowed size of 255 bytes")
+}
+
+func TestUpdateItemLimits(t *testing.T) {
+	c := require.New(t)
+
+	table, err := createPokemonTable()
+	c.NoError(err)
+
+	item := createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+
+	_, err = table.Put(&types.PutItemInput{Item: item, TableName: &tableName})
+	c.NoError(err)
+
+	table.Limits.ItemSize = 100
+
+	_, err = table.Update(&types.UpdateItemInput{
+		Key: map[string]*types.Item{
+			"id":   {S: types.ToString("001")},
+			"name": {S: types.ToString("Bulbasaur")},
+		},
+		TableName:        &tableName,
+		UpdateExpression: "SET description = :d",
+		ExpressionAttributeValues: map[string]*types.Item{
+			":d": {S: types.ToString(strings.Repeat("a", 100))},
+		},
+	})
+	c.EqualError(err, "ValidationException: "+updateItemSizeExceededMsg)
+
+	key, err := table.KeySchema.GetKey(table.AttributesDef, item)
+	c.NoError(err)
+	c.NotContains(table.Data[key], "description")
+}
+
+func TestUpdateItemExpressionLimits(t *testing.T) {
+	c := require.New(t)
+
+	table, err := createPokemonTable()
+	c.NoError(err)
+
+	table.Limits.ExpressionSize = 20
+
+	key := map[string]*types.Item{
+		"id":   {S: types.ToString("001")},
+		"name": {S: types.ToString("Bulbasaur")},
+	}
+
+	_, err = table.Update(&types.UpdateItemInput{
+		Key:              key,
+		TableName:        &tableName,
+		UpdateExpression: "SET description = :description",
+		ExpressionAttributeValues: map[string]*types.Item{
+			":description": {S: types.ToString("a")},
+		},
+	})
+	c.EqualError(err, "ValidationException: Invalid UpdateExpression: Expression size has exceeded the maximum allowed size; expression size: 30")
+
+	_, err = table.Update(&types.UpdateItemInput{
+		Key:                 key,
+		TableName:           &tableName,
+		UpdateExpression:    "SET lvl = :lvl",
+		ConditionExpression: types.ToString("attribute_exists(lvl)"),
+		ExpressionAttributeValues: map[string]*types.Item{
+			":lvl": {N: types.ToString("1")},
+		},
+	})
+	c.EqualError(err, "ValidationException: Invalid ConditionExpression: Expression size has exceeded the maximum allowed size; expression size: 21")
+
+	table.Limits = DefaultLimits
+
+	value := ":" + strings.Repeat("v", 255)
+
+	_, err = table.Update(&types.UpdateItemInput{
+		Key:              key,
+		TableName:        &tableName,
+		UpdateExpression: "SET lvl = " + value,
+		ExpressionAttributeValues: map[string]*types.Item{
+			value: {N: types.ToString("1")},
+		},
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "ExpressionAttributeValues contains invalid key: Exceeds the maximum allowed size of 255 bytes")
+
+	_, err = table.Update(&types.UpdateItemInput{
+		Key: map[string]*types.Item{
+			"id":   {S: types.ToString(strings.Repeat("1", 2049))},
+			"name": {S: types.ToString("Bulbasaur")},
+		},
+		TableName:        &tableName,
+		UpdateExpression: "SET lvl = :lvl",
+		ExpressionAttributeValues: map[string]*types.Item{
+			":lvl": {N: types.ToString("1")},
+		},
+	})
+	c.EqualError(err, "ValidationException: One or more parameter values were invalid: Size of hashkey has exceeded the maximum size limit of2048 bytes")
+}
+
+func TestDeleteItemLimits(t *testing.T) {
+	c := require.New(t)
+
+	table, err := createPokemonTable()
+	c.NoError(err)
+
+	table.Limits.ExpressionSize = 20
+
+	key := map[string]*types.Item{
+		"id":   {S: types.ToString("001")},
+		"name": {S: types.ToString("Bulbasaur")},
+	}
+
+	_, err = table.Delete(&types.DeleteItemInput{
+		Key:                 key,
+		TableName:           &tableName,
+		ConditionExpression: types.ToString("attribute_exists(lvl)"),
+	})
+	c.EqualError(err, "ValidationException: Invalid ConditionExpression: Expression size has exceeded the maximum allowed size; expression size: 21")
+
+	table.Limits = DefaultLimits
+
+	name := "#" + strings.Repeat("a", 255)
+
+	_, err = table.Delete(&types.DeleteItemInput{
+		Key:                      key,
+		TableName:                &tableName,
+		ConditionExpression:      types.ToString("attribute_exists(" + name + ")"),
+		ExpressionAttributeNames: map[string]*string{name: types.ToString("lvl")},
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "ExpressionAttributeNames contains invalid key: Exceeds the maximum allowed size of 255 bytes")
+
+	_, err = table.Delete(&types.DeleteItemInput{
+		Key: map[string]*types.Item{
+			"id":   {S: types.ToString("001")},
+			"name": {S: types.ToString(strings.Repeat("b", 1025))},
+		},
+		TableName: &tableName,
+	})
+	c.EqualError(err, "ValidationException: One or more parameter values were invalid: Aggregated size of all range keys has exceeded the size limit of 1024 bytes")
+}
+
+func TestValidateQuery(t *testing.T) {
+	c := require.New(t)
+
+	table, err := createPokemonTable()
+	c.NoError(err)
+
+	table.Limits.ExpressionSize = 10
+
+	err = table.ValidateQuery(QueryInput{KeyConditionExpression: "id = :id"})
+	c.NoError(err)
+
+	err = table.ValidateQuery(QueryInput{KeyConditionExpression: "id = :id", FilterExpression: "#type = :type"})
+	c.EqualError(err, "ValidationException: Invalid FilterExpression: Expression size has exceeded the maximum allowed size; expression size: 13")
+}
+
+func TestLimitsRequestItems(t *testing.T) {
+	c := require.New(t)
+
+	c.NoError(ValidateTransactWriteItems(DefaultLimits, 100))
+	c.EqualError(ValidateTransactWriteItems(DefaultLimits, 101), "ValidationException: 1 validation error detected: Value at 'transactItems' failed to satisfy constraint: Member must have length less than or equal to 100")
+	c.EqualError(ValidateTransactWriteItems(DefaultLimits, 0), "ValidationException: "+transactionItemsEmptyMsg)
+}
+
+func TestItemSize(t *testing.T) {
+	c := require.New(t)
+
+	item := map[string]*types.Item{
+		"name":  {S: types.ToString("Bulbasaur")},
+		"lvl":   {N: types.ToString("123.45")},
+		"alive": {BOOL: new(bool)},
+		"moves": {L: []*types.Item{{S: types.ToString("tackle")}}},
+		"stats": {M: map[string]*types.Item{"hp": {N: types.ToString("45")}}},
+		"tags":  {SS: []*string{types.ToString("a"), types.ToString("bc")}},
+	}
+
+	// name: 4+9, lvl: 3+4, alive: 5+1, moves: 5+3+1+6, stats: 5+3+1+2+2, tags: 4+3
+	c.Equal(61, itemSize(item))
+}
+
+func TestAttributeDepth(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal(0, attributeDepth(nil))
+	c.Equal(0, attributeDepth(&types.Item{S: types.ToString("leaf")}))
+	c.Equal(3, attributeDepth(&types.Item{L: []*types.Item{nestedAttribute(2), {S: types.ToString("leaf")}}}))
+	c.Equal(2, attributeDepth(&types.Item{M: map[string]*types.Item{"a": nestedAttribute(1)}}))
+}
+
+func nestedAttribute(depth int) *types.Item {
+	attr := &types.Item{S: types.ToString("leaf")}
+
+	for i := 0; i < depth; i++ {
+		attr = &types.Item{L: []*types.Item{attr}}
+	}
+
+	return attr
+}
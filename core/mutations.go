@@ -0,0 +1,43 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/truora/minidyn/types"
+)
+
+// ActivateMutationDetection records a fingerprint of every stored item, the operations fail with ErrItemMutated
+// when they read an item that was modified outside of the table since it was stored
+func (t *Table) ActivateMutationDetection() {
+	t.fingerprints = make(map[string]string, len(t.Data))
+
+	for key, item := range t.Data {
+		t.fingerprints[key] = fingerprint(item)
+	}
+}
+
+func (t *Table) recordFingerprint(key string, item map[string]*types.Item) {
+	if t.fingerprints == nil {
+		return
+	}
+
+	t.fingerprints[key] = fingerprint(item)
+}
+
+func (t *Table) verifyFingerprint(key string, item map[string]*types.Item) error {
+	expected, ok := t.fingerprints[key]
+	if !ok || fingerprint(item) == expected {
+		return nil
+	}
+
+	return fmt.Errorf("%w: the item %q of the table %q was mutated outside of the table", ErrItemMutated, key, t.Name)
+}
+
+func fingerprint(item map[string]*types.Item) string {
+	// the json encoding sorts the map keys and dereferences the pointers, so equal items share the same fingerprint.
+	// The stored items are deep copies made of strings, booleans and byte slices, so the encoding can not fail
+	data, _ := json.Marshal(item)
+
+	return string(data)
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestStoredItemsAreNotShared(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	item := createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	item["stats"] = &types.Item{M: map[string]*types.Item{"hp": {N: types.ToString("45")}}}
+
+	output, err := newTable.Put(&types.PutItemInput{Item: item, TableName: &newTable.Name})
+	c.NoError(err)
+
+	item["stats"].M["hp"].N = types.ToString("1")
+	output["stats"].M["hp"] = nil
+
+	stored, err := newTable.GetItem(map[string]*types.Item{"id": item["id"], "name": item["name"]})
+	c.NoError(err)
+	c.Equal("45", types.StringValue(stored["stats"].M["hp"].N))
+
+	stored["stats"].M["hp"].N = types.ToString("2")
+
+	results, _, err := newTable.SearchData(QueryInput{Scan: true, ScanIndexForward: true})
+	c.NoError(err)
+	c.Len(results, 1)
+	c.Equal("45", types.StringValue(results[0]["stats"].M["hp"].N))
+}
+
+func TestMutationDetection(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	item := createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	key := map[string]*types.Item{"id": item["id"], "name": item["name"]}
+
+	_, err = newTable.Put(&types.PutItemInput{Item: item, TableName: &newTable.Name})
+	c.NoError(err)
+
+	newTable.ActivateMutationDetection()
+
+	_, err = newTable.GetItem(key)
+	c.NoError(err)
+
+	for _, stored := range newTable.Data {
+		stored["type"].S = types.ToString("fire")
+	}
+
+	expected := `minidyn: stored item mutated: the item "001.Bulbasaur" of the table "pokemons" was mutated outside of the table`
+
+	_, err = newTable.GetItem(key)
+	c.ErrorIs(err, ErrItemMutated)
+	c.EqualError(err, expected)
+
+	_, err = newTable.Put(&types.PutItemInput{Item: item, TableName: &newTable.Name})
+	c.ErrorIs(err, ErrItemMutated)
+
+	_, err = newTable.Update(&types.UpdateItemInput{Key: key, TableName: &newTable.Name, UpdateExpression: "SET lvl = :one", ExpressionAttributeValues: map[string]*types.Item{":one": {N: types.ToString("1")}}})
+	c.ErrorIs(err, ErrItemMutated)
+
+	_, err = newTable.Delete(&types.DeleteItemInput{Key: key, TableName: &newTable.Name})
+	c.ErrorIs(err, ErrItemMutated)
+
+	_, _, err = newTable.SearchData(QueryInput{Scan: true, ScanIndexForward: true})
+	c.ErrorIs(err, ErrItemMutated)
+
+	c.Len(newTable.Data, 1)
+
+	newTable.Clear()
+
+	_, err = newTable.Put(&types.PutItemInput{Item: item, TableName: &newTable.Name})
+	c.NoError(err)
+
+	_, err = newTable.Delete(&types.DeleteItemInput{Key: key})
+	c.NoError(err)
+	c.Empty(newTable.fingerprints)
+
+	_, err = newTable.GetItem(map[string]*types.Item{"id": item["id"]})
+	c.Error(err)
+	c.NotErrorIs(err, ErrItemMutated)
+}
@@ -0,0 +1,224 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// PointInTimeRecoveryRetention is the period DynamoDB keeps the history of the writes of a table
+	PointInTimeRecoveryRetention = 35 * 24 * time.Hour
+
+	pitrStatusEnabled       = "ENABLED"
+	pitrStatusDisabled      = "DISABLED"
+	continuousBackupsStatus = "ENABLED"
+
+	// revive:disable-next-line
+	pitrSpecificationMsg = "1 validation error detected: Value null at 'pointInTimeRecoverySpecification.pointInTimeRecoveryEnabled' failed to satisfy constraint: Member must not be null"
+	pitrUnavailableMsg   = "Point in time recovery is not enabled for table '%s'"
+	// revive:disable-next-line
+	restoreTimeParamsMsg = "One or more parameter values were invalid: Exactly one of RestoreDateTime or UseLatestRestorableTime must be specified"
+	// revive:disable-next-line
+	invalidRestoreTimeMsg = "Invalid restore time: %s. The restore time must be between the earliest restorable time %s and the latest restorable time %s"
+)
+
+// revision is a write of the table history, the item is nil for the deletes
+type revision struct {
+	at   time.Time
+	key  string
+	item map[string]*types.Item
+}
+
+// history keeps the writes of a table since the point in time recovery was enabled, the writes
+// older than the retention period are folded into the base items
+type history struct {
+	since     time.Time
+	base      map[string]map[string]*types.Item
+	revisions []revision
+}
+
+func newHistory(data map[string]map[string]*types.Item, now time.Time) *history {
+	base := make(map[string]map[string]*types.Item, len(data))
+
+	for key, item := range data {
+		base[key] = copyItem(item)
+	}
+
+	return &history{since: now, base: base}
+}
+
+func (h *history) record(key string, item map[string]*types.Item, now time.Time) {
+	r := revision{at: now, key: key}
+	if item != nil {
+		r.item = copyItem(item)
+	}
+
+	h.revisions = append(h.revisions, r)
+	h.prune(now.Add(-PointInTimeRecoveryRetention))
+}
+
+// prune folds the writes before the cutoff into the base items
+func (h *history) prune(cutoff time.Time) {
+	n := 0
+
+	for ; n < len(h.revisions) && h.revisions[n].at.Before(cutoff); n++ {
+		applyRevision(h.base, h.revisions[n])
+	}
+
+	h.revisions = h.revisions[n:]
+}
+
+// window returns the earliest and the latest restorable times, the fake has no delay
+// between the writes and the latest restorable time
+func (h *history) window(now time.Time) (time.Time, time.Time) {
+	earliest := now.Add(-PointInTimeRecoveryRetention)
+	if h.since.After(earliest) {
+		earliest = h.since
+	}
+
+	return earliest, now
+}
+
+// itemsAt returns the items of the table at the given time
+func (h *history) itemsAt(at time.Time) map[string]map[string]*types.Item {
+	data := make(map[string]map[string]*types.Item, len(h.base))
+
+	for key, item := range h.base {
+		data[key] = item
+	}
+
+	for _, r := range h.revisions {
+		if r.at.After(at) {
+			break
+		}
+
+		applyRevision(data, r)
+	}
+
+	return data
+}
+
+func applyRevision(data map[string]map[string]*types.Item, r revision) {
+	if r.item == nil {
+		delete(data, r.key)
+
+		return
+	}
+
+	data[r.key] = r.item
+}
+
+func (t *Table) recordRevision(key string, item map[string]*types.Item) {
+	if t.pitr == nil {
+		return
+	}
+
+	t.pitr.record(key, item, t.now())
+}
+
+// UpdateContinuousBackups enables or disables the point in time recovery, the table keeps the history
+// of its writes while the recovery is enabled and drops it when the recovery is disabled
+func (t *Table) UpdateContinuousBackups(enabled *bool) error {
+	if enabled == nil {
+		return types.NewError("ValidationException", pitrSpecificationMsg, nil)
+	}
+
+	switch {
+	case !*enabled:
+		t.pitr = nil
+	case t.pitr == nil:
+		t.pitr = newHistory(t.Data, t.now())
+	}
+
+	return nil
+}
+
+// ContinuousBackupsDescription returns the point in time recovery status and its restorable window
+func (t *Table) ContinuousBackupsDescription() *types.ContinuousBackupsDescription {
+	desc := &types.PointInTimeRecoveryDescription{
+		PointInTimeRecoveryStatus: types.ToString(pitrStatusDisabled),
+	}
+
+	if t.pitr != nil {
+		earliest, latest := t.pitr.window(t.now())
+
+		desc.PointInTimeRecoveryStatus = types.ToString(pitrStatusEnabled)
+		desc.EarliestRestorableDateTime = &earliest
+		desc.LatestRestorableDateTime = &latest
+	}
+
+	return &types.ContinuousBackupsDescription{
+		ContinuousBackupsStatus:        types.ToString(continuousBackupsStatus),
+		PointInTimeRecoveryDescription: desc,
+	}
+}
+
+// RestoreToPointInTime creates a table with the target name and the items of the table at the restore time,
+// the restore time must be in the restorable window. The restored table has the current definition, settings
+// and indexes of the table, the overrides replace them like in the restores from backups
+func (t *Table) RestoreToPointInTime(input *types.RestoreTableToPointInTimeInput) (*Table, error) {
+	if t.pitr == nil {
+		return nil, types.NewError("PointInTimeRecoveryUnavailableException", fmt.Sprintf(pitrUnavailableMsg, t.Name), nil)
+	}
+
+	at, err := t.restoreTime(input)
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := t.snapshot(t.pitr.itemsAt(at)).restore(&types.RestoreTableFromBackupInput{
+		TargetTableName:               input.TargetTableName,
+		BillingModeOverride:           input.BillingModeOverride,
+		GlobalSecondaryIndexOverride:  input.GlobalSecondaryIndexOverride,
+		LocalSecondaryIndexOverride:   input.LocalSecondaryIndexOverride,
+		ProvisionedThroughputOverride: input.ProvisionedThroughputOverride,
+		SSESpecificationOverride:      input.SSESpecificationOverride,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	restored.restoreSummary = &types.RestoreSummary{
+		RestoreDateTime: at,
+		SourceTableArn:  types.ToString(t.Arn()),
+	}
+
+	return restored, nil
+}
+
+func (t *Table) restoreTime(input *types.RestoreTableToPointInTimeInput) (time.Time, error) {
+	useLatest := input.UseLatestRestorableTime != nil && *input.UseLatestRestorableTime
+	if useLatest == (input.RestoreDateTime != nil) {
+		return time.Time{}, types.NewError("ValidationException", restoreTimeParamsMsg, nil)
+	}
+
+	if useLatest {
+		_, latest := t.pitr.window(t.now())
+
+		return latest, nil
+	}
+
+	at := *input.RestoreDateTime
+
+	return at, t.validateRecoveryTime(at, "InvalidRestoreTimeException", invalidRestoreTimeMsg)
+}
+
+// validateRecoveryTime returns an error with the given code when the time is out of the restorable window
+func (t *Table) validateRecoveryTime(at time.Time, code, msg string) error {
+	earliest, latest := t.pitr.window(t.now())
+	if at.Before(earliest) || at.After(latest) {
+		layout := time.RFC3339Nano
+
+		return types.NewError(code, fmt.Sprintf(msg, at.UTC().Format(layout), earliest.UTC().Format(layout), latest.UTC().Format(layout)), nil)
+	}
+
+	return nil
+}
+
+// TableNameFromArn returns the name of the table of an ARN built by the fake
+func TableNameFromArn(arn string) string {
+	return strings.TrimPrefix(arn, tableArnPrefix)
+}
@@ -0,0 +1,176 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time {
+	return fc.now
+}
+
+func (fc *fakeClock) advance(d time.Duration) {
+	fc.now = fc.now.Add(d)
+}
+
+func getSettingsType(c *require.Assertions, table *Table, id string) string {
+	item, err := table.GetItem(map[string]*types.Item{"id": {S: types.ToString(id)}})
+	c.NoError(err)
+
+	return types.StringValue(item["type"].S)
+}
+
+func TestRestoreToPointInTime(t *testing.T) {
+	c := require.New(t)
+
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	table := createSettingsTable(c, BillingModePayPerRequest)
+	table.Clock = clock.Now
+
+	putSettingsItem(c, table, "001", "grass")
+
+	_, err := table.RestoreToPointInTime(&types.RestoreTableToPointInTimeInput{
+		TargetTableName:         types.ToString("restored"),
+		UseLatestRestorableTime: aws.Bool(true),
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "Point in time recovery is not enabled for table 'pokemons'")
+
+	c.NoError(table.UpdateContinuousBackups(aws.Bool(true)))
+
+	enabledAt := clock.now
+
+	clock.advance(time.Hour)
+	putSettingsItem(c, table, "004", "fire")
+
+	healthy := clock.now
+
+	clock.advance(time.Hour)
+	putSettingsItem(c, table, "001", "corrupted")
+	_, err = table.Delete(&types.DeleteItemInput{Key: map[string]*types.Item{"id": {S: types.ToString("004")}}})
+	c.NoError(err)
+
+	desc := table.ContinuousBackupsDescription().PointInTimeRecoveryDescription
+	c.Equal("ENABLED", types.StringValue(desc.PointInTimeRecoveryStatus))
+	c.Equal(enabledAt, *desc.EarliestRestorableDateTime)
+	c.Equal(clock.now, *desc.LatestRestorableDateTime)
+
+	restored, err := table.RestoreToPointInTime(&types.RestoreTableToPointInTimeInput{
+		TargetTableName: types.ToString("restored"),
+		RestoreDateTime: &healthy,
+	})
+	c.NoError(err)
+	c.Equal("grass", getSettingsType(c, restored, "001"))
+	c.Equal("fire", getSettingsType(c, restored, "004"))
+
+	summary := restored.Description("restored").RestoreSummary
+	c.Equal(healthy, summary.RestoreDateTime)
+	c.Equal(table.Arn(), types.StringValue(summary.SourceTableArn))
+	c.Nil(summary.SourceBackupArn)
+
+	latest, err := table.RestoreToPointInTime(&types.RestoreTableToPointInTimeInput{
+		TargetTableName:         types.ToString("latest"),
+		UseLatestRestorableTime: aws.Bool(true),
+	})
+	c.NoError(err)
+	c.Equal("corrupted", getSettingsType(c, latest, "001"))
+	c.Equal(int64(1), latest.Description("latest").ItemCount)
+
+	c.NoError(table.UpdateContinuousBackups(aws.Bool(false)))
+	c.Equal("DISABLED", types.StringValue(table.ContinuousBackupsDescription().PointInTimeRecoveryDescription.PointInTimeRecoveryStatus))
+}
+
+func TestRestoreToPointInTimeWindow(t *testing.T) {
+	c := require.New(t)
+
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	table := createSettingsTable(c, BillingModePayPerRequest)
+	table.Clock = clock.Now
+
+	putSettingsItem(c, table, "001", "grass")
+	c.NoError(table.UpdateContinuousBackups(aws.Bool(true)))
+
+	enabledAt := clock.now
+	before := enabledAt.Add(-time.Minute)
+	after := enabledAt.Add(time.Minute)
+
+	tests := []struct {
+		name  string
+		input *types.RestoreTableToPointInTimeInput
+		err   string
+	}{
+		{
+			name:  "without restore time",
+			input: &types.RestoreTableToPointInTimeInput{},
+			err:   "Exactly one of RestoreDateTime or UseLatestRestorableTime must be specified",
+		},
+		{
+			name:  "both restore times",
+			input: &types.RestoreTableToPointInTimeInput{RestoreDateTime: &enabledAt, UseLatestRestorableTime: aws.Bool(true)},
+			err:   "Exactly one of RestoreDateTime or UseLatestRestorableTime must be specified",
+		},
+		{
+			name:  "before the earliest restorable time",
+			input: &types.RestoreTableToPointInTimeInput{RestoreDateTime: &before},
+			err:   "Invalid restore time: 2023-12-31T23:59:00Z",
+		},
+		{
+			name:  "after the latest restorable time",
+			input: &types.RestoreTableToPointInTimeInput{RestoreDateTime: &after},
+			err:   "Invalid restore time: 2024-01-01T00:01:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		tt.input.TargetTableName = types.ToString("restored")
+
+		_, err := table.RestoreToPointInTime(tt.input)
+		c.Error(err, tt.name)
+		c.Contains(err.Error(), tt.err, tt.name)
+	}
+
+	c.Error(table.UpdateContinuousBackups(nil))
+
+	// the writes older than the retention period are folded into the first restorable state
+	clock.advance(time.Hour)
+	putSettingsItem(c, table, "001", "poison")
+
+	clock.advance(PointInTimeRecoveryRetention + time.Minute)
+	putSettingsItem(c, table, "001", "fire")
+	c.Len(table.pitr.revisions, 1)
+
+	earliest := *table.ContinuousBackupsDescription().PointInTimeRecoveryDescription.EarliestRestorableDateTime
+	c.Equal(clock.now.Add(-PointInTimeRecoveryRetention), earliest)
+
+	restored, err := table.RestoreToPointInTime(&types.RestoreTableToPointInTimeInput{
+		TargetTableName: types.ToString("restored"),
+		RestoreDateTime: &earliest,
+	})
+	c.NoError(err)
+	c.Equal("poison", getSettingsType(c, restored, "001"))
+
+	_, err = table.RestoreToPointInTime(&types.RestoreTableToPointInTimeInput{
+		TargetTableName: types.ToString("restored"),
+		RestoreDateTime: &enabledAt,
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "InvalidRestoreTimeException")
+}
+
+func TestTableNameFromArn(t *testing.T) {
+	c := require.New(t)
+
+	table := NewTable(tableName)
+
+	c.Equal(tableName, TableNameFromArn(table.Arn()))
+	c.Equal("arn:aws:dynamodb:us-east-1:123456789012:table/pokemons", TableNameFromArn("arn:aws:dynamodb:us-east-1:123456789012:table/pokemons"))
+	c.Empty(TableNameFromArn(""))
+}
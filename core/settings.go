@@ -0,0 +1,271 @@
+package core
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// BillingModeProvisioned is the billing mode of the tables with provisioned capacity
+	BillingModeProvisioned = "PROVISIONED"
+	// BillingModePayPerRequest is the billing mode of the on-demand tables
+	BillingModePayPerRequest = "PAY_PER_REQUEST"
+	// TableClassStandard is the default table class
+	TableClassStandard = "STANDARD"
+	// TableClassStandardInfrequentAccess is the table class for the tables with infrequent access
+	TableClassStandardInfrequentAccess = "STANDARD_INFREQUENT_ACCESS"
+
+	indexStatusActive = "ACTIVE"
+	sseStatusEnabled  = "ENABLED"
+	sseTypeKMS        = "KMS"
+
+	tableArnPrefix    = "arn:aws:dynamodb:ddblocal:000000000000:table/"
+	kmsArnPrefix      = "arn:aws:kms:ddblocal:000000000000:"
+	defaultKMSKey     = "alias/aws/dynamodb"
+	streamLabelLayout = "2006-01-02T15:04:05.000"
+)
+
+// throughput is the provisioned capacity of a table or a global index
+type throughput struct {
+	read           int64
+	write          int64
+	lastIncrease   time.Time
+	lastDecrease   time.Time
+	decreasesToday int64
+}
+
+func newThroughput(pt *types.ProvisionedThroughput) *throughput {
+	if pt == nil {
+		return nil
+	}
+
+	return &throughput{read: pt.ReadCapacityUnits, write: pt.WriteCapacityUnits}
+}
+
+// describe returns the throughput description, the on-demand tables and indexes report zero units
+func (tp *throughput) describe() *types.ProvisionedThroughputDescription {
+	desc := &types.ProvisionedThroughputDescription{}
+
+	if tp == nil {
+		return desc
+	}
+
+	desc.ReadCapacityUnits = tp.read
+	desc.WriteCapacityUnits = tp.write
+	desc.NumberOfDecreasesToday = tp.decreasesToday
+	desc.LastIncreaseDateTime = timePointer(tp.lastIncrease)
+	desc.LastDecreaseDateTime = timePointer(tp.lastDecrease)
+
+	return desc
+}
+
+func timePointer(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+// newTableID returns a random UUID like the ones DynamoDB assigns to the tables
+func newTableID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func (t *Table) now() time.Time {
+	if t.Clock == nil {
+		return time.Now()
+	}
+
+	return t.Clock()
+}
+
+// Arn returns the Amazon Resource Name of the table
+func (t *Table) Arn() string {
+	return tableArnPrefix + t.Name
+}
+
+func (t *Table) isPayPerRequest() bool {
+	return types.StringValue(t.BillingMode) == BillingModePayPerRequest
+}
+
+// configure stores the settings of the table that do not change the stored items
+func (t *Table) configure(input *types.CreateTableInput) {
+	if !t.isPayPerRequest() {
+		t.throughput = newThroughput(input.ProvisionedThroughput)
+	}
+
+	t.TableClass = TableClassStandard
+	if class := types.StringValue(input.TableClass); class != "" {
+		t.TableClass = class
+	}
+
+	t.setStream(input.StreamSpecification)
+	t.sse = input.SSESpecification
+	t.DeletionProtectionEnabled = input.DeletionProtectionEnabled != nil && *input.DeletionProtectionEnabled
+}
+
+func (t *Table) setStream(spec *types.StreamSpecification) {
+	if spec == nil || spec.StreamEnabled == nil || !*spec.StreamEnabled {
+		t.stream = nil
+
+		return
+	}
+
+	t.stream = &types.StreamSpecification{
+		StreamEnabled:  spec.StreamEnabled,
+		StreamViewType: spec.StreamViewType,
+	}
+	t.streamLabel = t.now().UTC().Format(streamLabelLayout)
+}
+
+func (t *Table) attributeDefinitions() []*types.AttributeDefinition {
+	names := make([]string, 0, len(t.AttributesDef))
+	for name := range t.AttributesDef {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	attrs := make([]*types.AttributeDefinition, 0, len(names))
+	for _, name := range names {
+		attrs = append(attrs, &types.AttributeDefinition{
+			AttributeName: types.ToString(name),
+			AttributeType: types.ToString(t.AttributesDef[name]),
+		})
+	}
+
+	return attrs
+}
+
+func (t *Table) billingModeSummary() *types.BillingModeSummary {
+	if !t.isPayPerRequest() {
+		return &types.BillingModeSummary{BillingMode: types.ToString(BillingModeProvisioned)}
+	}
+
+	updated := t.CreationDateTime
+	if !t.billingModeUpdated.IsZero() {
+		updated = t.billingModeUpdated
+	}
+
+	return &types.BillingModeSummary{
+		BillingMode:                       types.ToString(BillingModePayPerRequest),
+		LastUpdateToPayPerRequestDateTime: &updated,
+	}
+}
+
+func (t *Table) tableClassSummary() *types.TableClassSummary {
+	return &types.TableClassSummary{
+		TableClass:         types.ToString(t.TableClass),
+		LastUpdateDateTime: timePointer(t.tableClassUpdated),
+	}
+}
+
+// sseDescription returns nil for the tables encrypted with the AWS owned key
+func (t *Table) sseDescription() *types.SSEDescription {
+	if t.sse == nil || t.sse.Enabled == nil || !*t.sse.Enabled {
+		return nil
+	}
+
+	key := types.StringValue(t.sse.KMSMasterKeyID)
+
+	switch {
+	case key == "":
+		key = kmsArnPrefix + defaultKMSKey
+	case strings.HasPrefix(key, "alias/"):
+		key = kmsArnPrefix + key
+	case !strings.HasPrefix(key, "arn:"):
+		key = kmsArnPrefix + "key/" + key
+	}
+
+	return &types.SSEDescription{
+		KMSMasterKeyArn: types.ToString(key),
+		SSEType:         types.ToString(sseTypeKMS),
+		Status:          types.ToString(sseStatusEnabled),
+	}
+}
+
+// restoreSummaryDescription returns nil for the tables that were not restored from a backup,
+// the restore is in progress while the restored table is being created
+func (t *Table) restoreSummaryDescription() *types.RestoreSummary {
+	if t.restoreSummary == nil {
+		return nil
+	}
+
+	summary := *t.restoreSummary
+	summary.RestoreInProgress = t.Status == TableStatusCreating
+
+	return &summary
+}
+
+func (t *Table) streamArn() string {
+	if t.streamLabel == "" {
+		return ""
+	}
+
+	return t.Arn() + "/stream/" + t.streamLabel
+}
+
+// sizeBytes returns the size of the stored items, DynamoDB updates it every six hours
+// while the fake reports the current size
+func (t *Table) sizeBytes() int64 {
+	size := 0
+
+	for _, item := range t.Data {
+		size += itemSize(item)
+	}
+
+	return int64(size)
+}
+
+// sizeBytes returns the size of the projected attributes of the indexed items
+func (i *index) sizeBytes() int64 {
+	size := 0
+
+	for key := range i.refs {
+		size += itemSize(i.project(i.Table.Data[key]))
+	}
+
+	return int64(size)
+}
+
+// project returns the attributes of the item copied into the index
+func (i *index) project(item map[string]*types.Item) map[string]*types.Item {
+	if i.projection == nil || types.StringValue(i.projection.ProjectionType) == "ALL" {
+		return item
+	}
+
+	names := []string{
+		i.Table.KeySchema.HashKey,
+		i.Table.KeySchema.RangeKey,
+		i.keySchema.HashKey,
+		i.keySchema.RangeKey,
+	}
+
+	if types.StringValue(i.projection.ProjectionType) == "INCLUDE" {
+		for _, name := range i.projection.NonKeyAttributes {
+			names = append(names, types.StringValue(name))
+		}
+	}
+
+	projected := map[string]*types.Item{}
+
+	for _, name := range names {
+		if val, ok := item[name]; ok {
+			projected[name] = val
+		}
+	}
+
+	return projected
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// TableStatusCreating is the status of the tables that are being created
+	TableStatusCreating = "CREATING"
+	// TableStatusActive is the status of the tables ready to use
+	TableStatusActive = "ACTIVE"
+	// TableStatusUpdating is the status of the tables that are being updated
+	TableStatusUpdating = "UPDATING"
+	// TableStatusDeleting is the status of the tables that are being deleted
+	TableStatusDeleting = "DELETING"
+
+	// revive:disable-next-line
+	deletionProtectedMsg = "Resource cannot be deleted as it is currently protected against deletion. Disable deletion protection first."
+
+	listTablesMaxLimit = 100
+	// revive:disable-next-line
+	listTablesLimitMsg = "1 validation error detected: Value '%d' at 'limit' failed to satisfy constraint: Member must have value between 1 and 100"
+)
+
+var inUseMessages = map[string]string{
+	TableStatusCreating: "Table is being created",
+	TableStatusUpdating: "Table is being updated",
+	TableStatusDeleting: "Table is being deleted",
+}
+
+// StartTransition puts the table in a transitional status, the status is reported
+// by the given number of DescribeTable calls before the transition completes
+func (t *Table) StartTransition(status string, describes int) {
+	t.Status = status
+	t.pendingDescribes = describes
+}
+
+// DescribeStatus counts a DescribeTable call and completes the transition when its calls are exhausted,
+// it returns true when the table finished its deletion and must be removed
+func (t *Table) DescribeStatus() bool {
+	if t.Status == TableStatusActive {
+		return false
+	}
+
+	if t.pendingDescribes > 0 {
+		t.pendingDescribes--
+
+		return false
+	}
+
+	if t.Status == TableStatusDeleting {
+		return true
+	}
+
+	t.Status = TableStatusActive
+
+	return false
+}
+
+// ValidateAvailable returns a ResourceNotFoundException while the table is being created or deleted,
+// DynamoDB only serves the items of the ACTIVE and UPDATING tables
+func (t *Table) ValidateAvailable() error {
+	if t.Status == TableStatusCreating || t.Status == TableStatusDeleting {
+		return types.NewError("ResourceNotFoundException", "Cannot do operations on a non-existent table", nil)
+	}
+
+	return nil
+}
+
+// ValidateNotInUse returns a ResourceInUseException while the table is in a transition
+func (t *Table) ValidateNotInUse() error {
+	msg, ok := inUseMessages[t.Status]
+	if !ok {
+		return nil
+	}
+
+	return types.NewError("ResourceInUseException", "Attempt to change a resource which is still in use: "+msg+": "+t.Name, nil)
+}
+
+// ValidateDeletable returns a ValidationException while the deletion protection is enabled
+func (t *Table) ValidateDeletable() error {
+	if !t.DeletionProtectionEnabled {
+		return nil
+	}
+
+	return types.NewError("ValidationException", deletionProtectedMsg, nil)
+}
+
+// ListTableNames returns a page of the table names sorted alphabetically, the page starts after
+// the exclusive start name and has up to limit names, 100 when it is nil. The last name of the page
+// is returned when there are more tables
+func ListTableNames(tables map[string]*Table, exclusiveStart string, pageLimit *int64) ([]string, string, error) {
+	limit := int64(listTablesMaxLimit)
+
+	if pageLimit != nil {
+		limit = *pageLimit
+	}
+
+	if limit < 1 || limit > listTablesMaxLimit {
+		return nil, "", types.NewError("ValidationException", fmt.Sprintf(listTablesLimitMsg, limit), nil)
+	}
+
+	names := make([]string, 0, len(tables))
+
+	for name := range tables {
+		if name > exclusiveStart {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	if int64(len(names)) <= limit {
+		return names, "", nil
+	}
+
+	names = names[:limit]
+
+	return names, names[limit-1], nil
+}
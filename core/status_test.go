@@ -0,0 +1,87 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestTableTransitions(t *testing.T) {
+	c := require.New(t)
+
+	table := NewTable("pokemons")
+	c.Equal(TableStatusActive, table.Status)
+	c.NoError(table.ValidateAvailable())
+	c.NoError(table.ValidateNotInUse())
+
+	table.StartTransition(TableStatusCreating, 1)
+	c.Error(table.ValidateAvailable())
+	c.EqualError(table.ValidateNotInUse(), "ResourceInUseException: Attempt to change a resource which is still in use: Table is being created: pokemons")
+
+	c.False(table.DescribeStatus())
+	c.Equal(TableStatusCreating, table.Status)
+	c.False(table.DescribeStatus())
+	c.Equal(TableStatusActive, table.Status)
+
+	table.StartTransition(TableStatusUpdating, 0)
+	c.NoError(table.ValidateAvailable())
+	c.Error(table.ValidateNotInUse())
+	c.False(table.DescribeStatus())
+	c.Equal(TableStatusActive, table.Status)
+
+	table.StartTransition(TableStatusDeleting, 0)
+	c.Error(table.ValidateAvailable())
+	c.True(table.DescribeStatus())
+}
+
+func TestValidateDeletable(t *testing.T) {
+	c := require.New(t)
+
+	table := NewTable("pokemons")
+	table.AttributesDef = map[string]string{"id": "S"}
+	table.BillingMode = types.ToString(BillingModePayPerRequest)
+
+	err := table.CreatePrimaryIndex(&types.CreateTableInput{
+		KeySchema:                 []*types.KeySchemaElement{{AttributeName: "id", KeyType: "HASH"}},
+		DeletionProtectionEnabled: aws.Bool(true),
+	})
+	c.NoError(err)
+	c.True(table.Description("pokemons").DeletionProtectionEnabled)
+	c.EqualError(table.ValidateDeletable(), "ValidationException: "+deletionProtectedMsg)
+
+	err = table.ApplyTableUpdate(&types.UpdateTableInput{DeletionProtectionEnabled: aws.Bool(false)})
+	c.NoError(err)
+	c.NoError(table.ValidateDeletable())
+}
+
+func TestListTableNames(t *testing.T) {
+	c := require.New(t)
+
+	tables := map[string]*Table{}
+	for _, name := range []string{"c", "a", "b"} {
+		tables[name] = NewTable(name)
+	}
+
+	names, last, err := ListTableNames(tables, "", nil)
+	c.NoError(err)
+	c.Equal([]string{"a", "b", "c"}, names)
+	c.Empty(last)
+
+	names, last, err = ListTableNames(tables, "a", aws.Int64(1))
+	c.NoError(err)
+	c.Equal([]string{"b"}, names)
+	c.Equal("b", last)
+
+	names, last, err = ListTableNames(tables, "b", aws.Int64(1))
+	c.NoError(err)
+	c.Equal([]string{"c"}, names)
+	c.Empty(last)
+
+	_, _, err = ListTableNames(tables, "", aws.Int64(101))
+	c.Error(err)
+
+	_, _, err = ListTableNames(tables, "", aws.Int64(0))
+	c.EqualError(err, "ValidationException: 1 validation error detected: Value '0' at 'limit' failed to satisfy constraint: Member must have value between 1 and 100")
+}
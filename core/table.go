@@ -1,10 +1,16 @@
 package core
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"sort"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/truora/minidyn/interpreter"
+	"github.com/truora/minidyn/interpreter/language"
 	"github.com/truora/minidyn/types"
 )
 
@@ -20,21 +26,61 @@ type QueryInput struct {
 	Aliases                   map[string]string
 	ScanIndexForward          bool
 	Scan                      bool
-	started                   bool
+	KeyConditions             map[string]*types.Condition
+	QueryFilter               map[string]*types.Condition
+	ConditionalOperator       string
+	AttributesToGet           []string
+	legacy                    *legacyQuery
 }
 
-// Table struct to mock a dynamodb table
+// Table struct to mock a dynamodb table, the embedded lock must be held by the callers:
+// the write lock for the operations that modify the table and the read lock for the rest
 type Table struct {
+	sync.RWMutex
 	Name                 string
 	Indexes              map[string]*index
 	AttributesDef        map[string]string
-	SortedKeys           []string
+	keys                 *keyList
 	Data                 map[string]map[string]*types.Item
 	KeySchema            keySchema
 	BillingMode          *string
 	UseNativeInterpreter bool
 	NativeInterpreter    interpreter.Native
 	LangInterpreter      interpreter.Language
+	Limits               Limits
+	fingerprints         map[string]string
+
+	// ExplainConditions adds the evaluation of the failed condition expressions
+	// to the message of the ConditionalCheckFailedException
+	ExplainConditions bool
+
+	// MismatchReporter enables the differential mode when the native interpreter is used,
+	// the expressions with a native override are evaluated by both interpreters and the differences are reported
+	MismatchReporter interpreter.MismatchReporter
+
+	// Status is the table status, the tables are ACTIVE unless the client emulates the table lifecycle
+	Status           string
+	pendingDescribes int
+
+	// CreationDateTime and TableID are assigned when the table is created
+	CreationDateTime time.Time
+	TableID          string
+	// TableClass is STANDARD unless the table is created with another class
+	TableClass string
+	// DeletionProtectionEnabled makes DeleteTable fail until the protection is disabled
+	DeletionProtectionEnabled bool
+	// Clock returns the current time, it stamps the settings changes and the history of the writes
+	Clock func() time.Time
+
+	throughput         *throughput
+	stream             *types.StreamSpecification
+	streamLabel        string
+	sse                *types.SSESpecification
+	billingModeUpdated time.Time
+	tableClassUpdated  time.Time
+	restoreSummary     *types.RestoreSummary
+	pitr               *history
+	tags               map[string]string
 }
 
 // NewTable creates a new Table
@@ -43,8 +89,16 @@ func NewTable(name string) *Table {
 		Name:          name,
 		Indexes:       map[string]*index{},
 		AttributesDef: map[string]string{},
-		SortedKeys:    []string{},
+		keys:          newKeyList(),
+		tags:          map[string]string{},
 		Data:          map[string]map[string]*types.Item{},
+		Limits:        DefaultLimits,
+		Status:        TableStatusActive,
+
+		CreationDateTime: time.Now(),
+		TableID:          newTableID(),
+		TableClass:       TableClassStandard,
+		Clock:            time.Now,
 	}
 }
 
@@ -94,17 +148,35 @@ func (t *Table) CreatePrimaryIndex(input *types.CreateTableInput) error {
 		}
 	}
 
+	if err := validateStreamSpecification(input.StreamSpecification); err != nil {
+		return err
+	}
+
+	if err := validateTableClass(input.TableClass); err != nil {
+		return err
+	}
+
+	if err := t.TagResource(input.Tags); err != nil {
+		return err
+	}
+
 	t.KeySchema = ks
+	t.configure(input)
 
 	return nil
 }
 
 func (t *Table) validateAttributeDefinition(ks keySchema, message string) error {
-	if _, ok := t.AttributesDef[ks.HashKey]; !ok {
+	return validateKeyAttributes(t.AttributesDef, ks, message)
+}
+
+// validateKeyAttributes checks that the attributes of the key schema are defined
+func validateKeyAttributes(defs map[string]string, ks keySchema, message string) error {
+	if _, ok := defs[ks.HashKey]; !ok {
 		return types.NewError("ValidationException", fmt.Sprintf("%sHash Key not specified in Attribute Definitions.", message), nil)
 	}
 
-	if _, ok := t.AttributesDef[ks.RangeKey]; ks.RangeKey != "" && !ok {
+	if _, ok := defs[ks.RangeKey]; ks.RangeKey != "" && !ok {
 		return types.NewError("ValidationException", fmt.Sprintf("%sRange Key not specified in Attribute Definitions.", message), nil)
 	}
 
@@ -132,6 +204,10 @@ func buildGSI(t *Table, gsiInput *types.GlobalSecondaryIndex) (*index, error) {
 	i := newIndex(t, indexTypeGlobal, ks)
 	i.projection = gsiInput.Projection
 
+	if !t.isPayPerRequest() {
+		i.throughput = newThroughput(gsiInput.ProvisionedThroughput)
+	}
+
 	return i, nil
 }
 
@@ -195,7 +271,13 @@ func (t *Table) deleteIndex(indexName string) error {
 }
 
 func (t *Table) updateIndex(indexName string, provisionedThroughput *types.ProvisionedThroughput) error {
-	// we do not have support for provisionedThroughput values in the index
+	i, ok := t.Indexes[indexName]
+	if !ok {
+		return types.NewError("ResourceNotFoundException", "Requested resource not found", nil)
+	}
+
+	i.throughput = i.throughput.change(provisionedThroughput, t.now())
+
 	return nil
 }
 
@@ -243,55 +325,107 @@ func (t *Table) parseStartKey(schema keySchema, startkeyAttr map[string]*types.I
 	return startKey
 }
 
-func getPrimaryKey(index *index, k string) (string, bool) {
-	pk, ok := k, true
+// searchKeys returns the keys walked by the search, the index that owns them and the range matching the key condition
+func (t *Table) searchKeys(input QueryInput) (*keyList, *index, keyRange) {
+	if input.Index != "" {
+		i := t.Indexes[input.Index]
 
-	if index != nil {
-		pk, ok = index.getPrimaryKey(k)
+		return i.keys, i, t.queryKeyRange(input, i.keySchema)
 	}
 
-	return pk, ok
+	return t.keys, nil, t.queryKeyRange(input, t.KeySchema)
 }
 
-func (t *Table) fetchQueryData(input QueryInput) (*index, []string) {
-	if input.Index != "" {
-		i := t.Indexes[input.Index]
-		i.startSearch(input.ScanIndexForward)
+// startNode returns the node where the search starts, right after the exclusive start key when it is given
+// and never before the beginning of the key range
+func (t *Table) startNode(input QueryInput, keys *keyList, index *index, r keyRange) *keyNode {
+	forward := input.ScanIndexForward
+	first := r.start(keys, forward)
+
+	startKey, ok := t.searchStartKey(input.ExclusiveStartKey, index)
+	if !ok || first == nil {
+		return nil
+	}
+
+	if startKey == "" {
+		return first
+	}
+
+	node := seekAfter(keys, startKey, forward)
+	if node == nil {
+		return nil
+	}
 
-		return i, i.sortedKeys
+	if precedes(node.key, first.key, forward) {
+		return first
 	}
 
-	return nil, t.SortedKeys
+	return node
 }
 
-func prepareSearch(input *QueryInput, index *index, k, startKey string) (string, bool) {
-	pk, ok := getPrimaryKey(index, k)
-	if !ok {
-		return pk, ok
+// seekAfter returns the first node after the key in the search direction
+func seekAfter(keys *keyList, key string, forward bool) *keyNode {
+	var node *keyNode
+
+	if forward {
+		node = keys.seek(key)
+	} else {
+		node = keys.seekReverse(key)
 	}
 
-	if input.started {
-		return pk, true
+	if node != nil && node.key == key {
+		node = node.step(forward)
 	}
 
-	if pk == startKey {
-		input.started = true
+	return node
+}
+
+// precedes reports whether the key a comes before the key b in the search direction
+func precedes(a, b string, forward bool) bool {
+	if forward {
+		return a < b
 	}
 
-	return "", false
+	return a > b
 }
 
-func (t *Table) getMatchedItemAndCount(input *QueryInput, pk, startKey string) (map[string]*types.Item, interpreter.ExpressionType, bool) {
-	storedItem, ok := t.Data[pk]
+// searchStartKey encodes the exclusive start key as a key of the searched list,
+// it returns false when the start key can not be located in an index
+func (t *Table) searchStartKey(exclusiveStartKey map[string]*types.Item, index *index) (string, bool) {
+	startKey := t.parseStartKey(t.KeySchema, exclusiveStartKey)
+	if index == nil || startKey == "" {
+		return startKey, true
+	}
 
-	lastMatchExpressionType, matched := t.matchKey(*input, storedItem)
+	if indexKey, ok := index.refs[startKey]; ok {
+		return entryKey(indexKey, startKey), true
+	}
+
+	indexKey := t.parseStartKey(index.keySchema, exclusiveStartKey)
+	if indexKey == "" {
+		return "", false
+	}
+
+	return entryKey(indexKey, startKey), true
+}
+
+func (t *Table) getMatchedItemAndCount(input *QueryInput, pk string) (map[string]*types.Item, interpreter.ExpressionType, bool, error) {
+	storedItem, ok, err := t.lookupItem(pk)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	lastMatchExpressionType, matched, err := t.matchKey(*input, storedItem)
+	if err != nil {
+		return nil, lastMatchExpressionType, false, err
+	}
 
-	if ok && !(input.started && matched) {
-		return copyItem(storedItem), lastMatchExpressionType, false
+	if ok && !matched {
+		return copyItem(storedItem), lastMatchExpressionType, false, nil
 	}
 
 	// TODO: use project info to create the copy
-	return copyItem(storedItem), lastMatchExpressionType, true
+	return copyItem(storedItem), lastMatchExpressionType, true, nil
 }
 
 func shouldReturnNextKey(item map[string]*types.Item, count, scanned, limit, keysSize int64) bool {
@@ -310,27 +444,25 @@ func shouldBreakPage(count, limit int64) bool {
 	return limit != 0 && limit == count
 }
 
-// GetKeyAt returns the key value in a given position
-func GetKeyAt(sortedKeys []string, size int64, pos int64, forward bool) string {
-	if !forward {
-		return sortedKeys[size-1-int64(pos)]
+// SearchData quiery the table based on the input, it fails when the expressions can not be parsed or evaluated
+func (t *Table) SearchData(input QueryInput) ([]map[string]*types.Item, map[string]*types.Item, error) {
+	legacy, err := translateLegacyQuery(input)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return sortedKeys[pos]
+	input.legacy = legacy
+
+	return t.searchPage(input)
 }
 
-// SearchData quiery the table based on the input
-func (t *Table) SearchData(input QueryInput) ([]map[string]*types.Item, map[string]*types.Item) {
+// searchPage visits the keys in the range of the query until the page is full,
+// the legacy conditions of the input must be already translated
+func (t *Table) searchPage(input QueryInput) ([]map[string]*types.Item, map[string]*types.Item, error) {
 	items := []map[string]*types.Item{}
 	limit := input.Limit
-	exclusiveStartKey := input.ExclusiveStartKey
-	index, sortedKeys := t.fetchQueryData(input)
-
-	startKey := t.parseStartKey(t.KeySchema, exclusiveStartKey)
-	input.started = startKey == ""
+	keys, index, r := t.searchKeys(input)
 	last := map[string]*types.Item{}
-	sortedKeysSize := int64(len(sortedKeys))
-
 	forward := input.ScanIndexForward
 
 	var (
@@ -338,17 +470,12 @@ func (t *Table) SearchData(input QueryInput) ([]map[string]*types.Item, map[stri
 		scanned int64
 	)
 
-	for pos := range sortedKeys {
-		k := GetKeyAt(sortedKeys, sortedKeysSize, int64(pos), forward)
-
-		pk, ok := prepareSearch(&input, index, k, startKey)
-		if !ok {
-			scanned++
-			continue
+	for node := t.startNode(input, keys, index, r); node != nil && r.contains(node.key); node = node.step(forward) {
+		item, expressionType, matched, err := t.getMatchedItemAndCount(&input, node.value)
+		if err != nil {
+			return nil, nil, err
 		}
 
-		item, expressionType, matched := t.getMatchedItemAndCount(&input, pk, startKey)
-
 		if matched {
 			items = append(items, item)
 		}
@@ -366,7 +493,7 @@ func (t *Table) SearchData(input QueryInput) ([]map[string]*types.Item, map[stri
 		}
 	}
 
-	return items, t.getLastKey(last, limit, count, scanned, sortedKeysSize, index)
+	return projectItems(items, input.AttributesToGet), t.getLastKey(last, limit, count, scanned, int64(keys.size()), index), nil
 }
 
 func (t *Table) getLastKey(item map[string]*types.Item, limit, count, scanned, keysSize int64, index *index) map[string]*types.Item {
@@ -386,148 +513,295 @@ func (t *Table) getLastKey(item map[string]*types.Item, limit, count, scanned, k
 	return key
 }
 
-func (t *Table) interpreterMatch(input interpreter.MatchInput) bool {
+// traceKey returns the primary key of the item when the interpreters trace the evaluations
+func (t *Table) traceKey(item map[string]*types.Item) map[string]*types.Item {
+	if t.LangInterpreter.Tracer == nil && t.NativeInterpreter.Tracer == nil {
+		return nil
+	}
+
+	return t.KeySchema.getKeyItem(item)
+}
+
+func (t *Table) interpreterMatch(input interpreter.MatchInput) (bool, error) {
 	if t.UseNativeInterpreter {
 		matched, err := t.NativeInterpreter.Match(input)
 		if err == nil {
-			return matched
+			if t.MismatchReporter != nil {
+				t.compareMatch(input, matched)
+			}
+
+			return matched, nil
 		}
 	}
 
 	matched, err := t.LangInterpreter.Match(input)
 	if err != nil {
-		panic(err)
+		return false, expressionError(input.ExpressionType, err)
 	}
 
-	return matched
+	return matched, nil
 }
 
-func (t *Table) matchKey(input QueryInput, item map[string]*types.Item) (interpreter.ExpressionType, bool) {
-	var lastMatchExpressionType interpreter.ExpressionType
-	matched := input.Scan
+// compareMatch reports when the language interpreter does not agree with the native matcher
+func (t *Table) compareMatch(input interpreter.MatchInput, matched bool) {
+	langMatched, err := t.LangInterpreter.Match(input)
+	if err == nil && langMatched == matched {
+		return
+	}
 
-	if input.KeyConditionExpression != "" {
-		matched = t.interpreterMatch(interpreter.MatchInput{
-			TableName:      t.Name,
-			Expression:     input.KeyConditionExpression,
-			ExpressionType: interpreter.ExpressionTypeKey,
-			Item:           item,
-			Aliases:        input.Aliases,
-			Attributes:     input.ExpressionAttributeValues,
-		})
-		lastMatchExpressionType = interpreter.ExpressionTypeKey
+	outcome := strconv.FormatBool(langMatched)
+	if err != nil {
+		outcome = "error: " + err.Error()
 	}
 
-	if input.FilterExpression != "" {
-		matched = matched && t.interpreterMatch(interpreter.MatchInput{
-			TableName:      t.Name,
-			Expression:     input.FilterExpression,
-			ExpressionType: interpreter.ExpressionTypeFilter,
-			Item:           item,
-			Aliases:        input.Aliases,
-			Attributes:     input.ExpressionAttributeValues,
-		})
-		lastMatchExpressionType = interpreter.ExpressionTypeFilter
+	t.MismatchReporter(interpreter.Mismatch{
+		TableName:      input.TableName,
+		ExpressionType: input.ExpressionType,
+		Expression:     input.Expression,
+		Item:           input.Item,
+		Native:         strconv.FormatBool(matched),
+		Language:       outcome,
+	})
+}
+
+// expressionError maps the syntax and evaluation errors of the interpreter to validation errors,
+// the unsupported features are reported as they are
+func expressionError(typ interpreter.ExpressionType, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var exprErr *interpreter.ExpressionError
+	if !errors.As(err, &exprErr) || errors.Is(err, interpreter.ErrUnsupportedFeature) {
+		return err
+	}
+
+	return types.NewError("ValidationException", fmt.Sprintf("Invalid %s: %s", typ.ParameterName(), exprErr.Message), nil)
+}
+
+func (t *Table) matchKey(input QueryInput, item map[string]*types.Item) (interpreter.ExpressionType, bool, error) {
+	if input.legacy != nil {
+		return t.matchLegacyQuery(input, item)
+	}
+
+	var lastMatchExpressionType interpreter.ExpressionType
+
+	matched := input.Scan
+	expressions := []struct {
+		typ        interpreter.ExpressionType
+		expression string
+	}{
+		{interpreter.ExpressionTypeKey, input.KeyConditionExpression},
+		{interpreter.ExpressionTypeFilter, input.FilterExpression},
+		{interpreter.ExpressionTypeConditional, types.StringValue(input.ConditionExpression)},
 	}
 
-	if input.ConditionExpression != nil && *input.ConditionExpression != "" {
-		matched = t.interpreterMatch(interpreter.MatchInput{
+	for _, exp := range expressions {
+		if exp.expression == "" {
+			continue
+		}
+
+		lastMatchExpressionType = exp.typ
+
+		// the filter is only evaluated on the items matching the key condition
+		if exp.typ == interpreter.ExpressionTypeFilter && !matched {
+			continue
+		}
+
+		var err error
+
+		matched, err = t.interpreterMatch(interpreter.MatchInput{
 			TableName:      t.Name,
-			Expression:     *input.ConditionExpression,
-			ExpressionType: interpreter.ExpressionTypeConditional,
+			Expression:     exp.expression,
+			ExpressionType: exp.typ,
 			Item:           item,
 			Aliases:        input.Aliases,
 			Attributes:     input.ExpressionAttributeValues,
+			Key:            t.traceKey(item),
 		})
-		lastMatchExpressionType = interpreter.ExpressionTypeConditional
+		if err != nil {
+			return exp.typ, false, err
+		}
 	}
 
-	return lastMatchExpressionType, matched
+	return lastMatchExpressionType, matched, nil
 }
 
 func (t *Table) setItem(key string, item map[string]*types.Item) {
-	_, exists := t.Data[key]
 	t.Data[key] = item
+	t.recordFingerprint(key, item)
+	t.keys.put(key, key)
+	t.recordRevision(key, item)
+}
 
+func (t *Table) getItem(key string) (map[string]*types.Item, error) {
+	item, exists, err := t.lookupItem(key)
 	if !exists {
-		t.SortedKeys = append(t.SortedKeys, key)
-		sort.Strings(t.SortedKeys)
+		return map[string]*types.Item{}, err
 	}
+
+	return item, err
 }
 
-func (t *Table) getItem(key string) map[string]*types.Item {
+func (t *Table) lookupItem(key string) (map[string]*types.Item, bool, error) {
 	item, exists := t.Data[key]
 	if !exists {
-		return map[string]*types.Item{}
+		return item, false, nil
 	}
 
-	return item
+	return item, true, t.verifyFingerprint(key, item)
+}
+
+// GetItem returns a copy of the item stored with the given key, the copy is empty when the item does not exist
+func (t *Table) GetItem(key map[string]*types.Item) (map[string]*types.Item, error) {
+	pk, err := t.KeySchema.GetKey(t.AttributesDef, key)
+	if err != nil {
+		return nil, types.NewError("ValidationException", err.Error(), nil)
+	}
+
+	item, err := t.getItem(pk)
+	if err != nil {
+		return nil, err
+	}
+
+	return copyItem(item), nil
 }
 
 // Clear removes data and sorted keys from a table
 func (t *Table) Clear() {
-	t.SortedKeys = []string{}
+	for key := range t.Data {
+		t.recordRevision(key, nil)
+	}
+
+	t.keys = newKeyList()
 	t.Data = map[string]map[string]*types.Item{}
+
+	if t.fingerprints != nil {
+		t.fingerprints = map[string]string{}
+	}
 }
 
 // Put puts items into table
 func (t *Table) Put(input *types.PutItemInput) (map[string]*types.Item, error) {
 	item := copyItem(input.Item)
 
+	err := t.validatePutInput(input)
+	if err != nil {
+		return item, err
+	}
+
 	key, err := t.KeySchema.GetKey(t.AttributesDef, input.Item)
 	if err != nil {
 		return item, types.NewError("ValidationException", err.Error(), nil)
 	}
 
+	stored, err := t.getItem(key)
+	if err != nil {
+		return item, err
+	}
+
+	err = t.checkPutCondition(input, stored)
+	if err != nil {
+		return item, err
+	}
+
+	t.setItem(key, item)
+
+	for _, index := range t.Indexes {
+		err := index.putData(key, item)
+		if err != nil {
+			return nil, types.NewError("ValidationException", err.Error(), nil)
+		}
+	}
+
+	return copyItem(item), nil
+}
+
+func (t *Table) checkPutCondition(input *types.PutItemInput, stored map[string]*types.Item) error {
 	// support conditional writes
 	if input.ConditionExpression != nil {
-		_, matched := t.matchKey(QueryInput{
+		_, matched, err := t.matchKey(QueryInput{
 			Index:                     PrimaryIndexName,
 			ExpressionAttributeValues: input.ExpressionAttributeValues,
 			Aliases:                   input.ExpressionAttributeNames,
 			Limit:                     1,
 			ConditionExpression:       input.ConditionExpression,
-		}, t.getItem(key))
+		}, stored)
+		if err != nil {
+			return err
+		}
 
 		if !matched {
-			return item, types.NewError("ConditionalCheckFailedException", ErrConditionalRequestFailed.Error(), nil)
+			message := t.conditionFailedMessage(*input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues, stored)
+
+			return types.NewError("ConditionalCheckFailedException", message, nil)
 		}
 	}
 
-	t.setItem(key, item)
-
-	for _, index := range t.Indexes {
-		err := index.putData(key, item)
+	if len(input.Expected) > 0 {
+		matched, err := t.matchExpected(input.Expected, input.ConditionalOperator, stored)
 		if err != nil {
-			return nil, types.NewError("ValidationException", err.Error(), nil)
+			return err
+		}
+
+		if !matched {
+			return types.NewError("ConditionalCheckFailedException", ErrConditionalRequestFailed.Error(), nil)
 		}
 	}
 
-	return item, nil
+	return nil
 }
 
 func (t *Table) interpreterUpdate(input interpreter.UpdateInput) error {
 	if t.UseNativeInterpreter {
+		if t.MismatchReporter != nil {
+			return t.compareUpdate(input)
+		}
+
 		return t.NativeInterpreter.Update(input)
 	}
 
-	return t.LangInterpreter.Update(input)
+	return expressionError(interpreter.ExpressionTypeUpdate, t.LangInterpreter.Update(input))
 }
 
-// Update updates an item in the table based on the input
-func (t *Table) Update(input *types.UpdateItemInput) (map[string]*types.Item, error) {
-	// update primary index
-	key, err := t.KeySchema.GetKey(t.AttributesDef, input.Key)
+// compareUpdate applies the native updater and reports when the language interpreter
+// produces a different item, the language interpreter updates a copy of the item
+func (t *Table) compareUpdate(input interpreter.UpdateInput) error {
+	original := copyItem(input.Item)
+
+	langInput := input
+	langInput.Item = copyItem(input.Item)
+	langErr := t.LangInterpreter.Update(langInput)
+
+	err := t.NativeInterpreter.Update(input)
 	if err != nil {
-		return nil, types.NewError("ValidationException", err.Error(), nil)
+		return err
 	}
 
-	item, ok := t.Data[key]
-	if !ok {
-		// it allow the use of attribute_exists to check if the item exists
-		item = map[string]*types.Item{}
+	if langErr == nil && reflect.DeepEqual(input.Item, langInput.Item) {
+		return nil
 	}
 
+	outcome := interpreter.FormatItem(langInput.Item)
+	if langErr != nil {
+		outcome = "error: " + langErr.Error()
+	}
+
+	t.MismatchReporter(interpreter.Mismatch{
+		TableName:      input.TableName,
+		ExpressionType: interpreter.ExpressionTypeUpdate,
+		Expression:     input.Expression,
+		Item:           original,
+		Native:         interpreter.FormatItem(input.Item),
+		Language:       outcome,
+	})
+
+	return nil
+}
+
+func (t *Table) checkUpdateCondition(input *types.UpdateItemInput, item map[string]*types.Item) error {
+	matched := true
+
 	// support conditional writes
 	if input.ConditionExpression != nil {
 		query := QueryInput{
@@ -538,36 +812,115 @@ func (t *Table) Update(input *types.UpdateItemInput) (map[string]*types.Item, er
 			Aliases:                   input.ExpressionAttributeNames,
 		}
 
-		_, matched := t.matchKey(query, item)
-		if !matched {
-			checkErr := &types.ConditionalCheckFailedException{
-				MessageText: ErrConditionalRequestFailed.Error(),
-			}
+		var err error
 
-			handleConditionalCheckError(input, checkErr, item)
+		_, matched, err = t.matchKey(query, item)
+		if err != nil {
+			return err
+		}
+	}
 
-			return nil, checkErr
+	if len(input.Expected) > 0 {
+		var err error
+
+		matched, err = t.matchExpected(input.Expected, input.ConditionalOperator, item)
+		if err != nil {
+			return err
 		}
 	}
 
-	if !ok {
-		// types creates a new item when the item does not exists
-		item = copyItem(input.Key)
+	if !matched {
+		checkErr := &types.ConditionalCheckFailedException{
+			MessageText: ErrConditionalRequestFailed.Error(),
+		}
+
+		if input.ConditionExpression != nil {
+			checkErr.MessageText = t.conditionFailedMessage(*input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues, item)
+		}
+
+		handleConditionalCheckError(input, checkErr, item)
+
+		return checkErr
 	}
 
-	oldItem := copyItem(item)
+	return nil
+}
 
-	err = t.interpreterUpdate(interpreter.UpdateInput{
+func (t *Table) applyUpdate(input *types.UpdateItemInput, item map[string]*types.Item) error {
+	if len(input.AttributeUpdates) > 0 {
+		return t.applyAttributeUpdates(input.AttributeUpdates, item)
+	}
+
+	return t.interpreterUpdate(interpreter.UpdateInput{
 		TableName:  t.Name,
 		Expression: input.UpdateExpression,
 		Item:       item,
 		Attributes: input.ExpressionAttributeValues,
 		Aliases:    input.ExpressionAttributeNames,
+		Key:        t.traceKey(item),
 	})
+}
+
+// updateTarget returns the stored item to update once the update conditions are met
+func (t *Table) updateTarget(input *types.UpdateItemInput, key string) (map[string]*types.Item, error) {
+	item, ok, err := t.lookupItem(key)
 	if err != nil {
 		return nil, err
 	}
 
+	if !ok {
+		// it allow the use of attribute_exists to check if the item exists
+		item = map[string]*types.Item{}
+	}
+
+	err = t.checkUpdateCondition(input, item)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		// types creates a new item when the item does not exists
+		item = copyItem(input.Key)
+	}
+
+	return item, nil
+}
+
+// Update updates an item in the table based on the input
+func (t *Table) Update(input *types.UpdateItemInput) (map[string]*types.Item, error) {
+	err := t.validateUpdateInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// update primary index
+	key, err := t.KeySchema.GetKey(t.AttributesDef, input.Key)
+	if err != nil {
+		return nil, types.NewError("ValidationException", err.Error(), nil)
+	}
+
+	item, err := t.updateTarget(input, key)
+	if err != nil {
+		return nil, err
+	}
+
+	oldItem := copyItem(item)
+	// the update is applied to a copy so the stored item is kept when the result exceeds the limits
+	item = copyItem(item)
+
+	err = t.applyUpdate(input, item)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.Limits.validateItem(item, updateItemSizeExceededMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	// the updated values may come from the expression attribute values, they must not be shared with the request
+	item = copyItem(item)
+
 	t.setItem(key, item)
 
 	// update secondary Indexes
@@ -583,6 +936,11 @@ func (t *Table) Update(input *types.UpdateItemInput) (map[string]*types.Item, er
 
 // Delete deletes an item in the table based on the input
 func (t *Table) Delete(input *types.DeleteItemInput) (map[string]*types.Item, error) {
+	err := t.validateDeleteInput(input)
+	if err != nil {
+		return nil, err
+	}
+
 	key, err := t.KeySchema.GetKey(t.AttributesDef, input.Key)
 	if err != nil {
 		return nil, types.NewError("ValidationException", err.Error(), nil)
@@ -591,7 +949,18 @@ func (t *Table) Delete(input *types.DeleteItemInput) (map[string]*types.Item, er
 	// delete is an idempotent operation,
 	// running it multiple times on the same item or attribute does not result in an error response,
 	// therefore we do not need to check if the item exists.
-	item, ok := t.Data[key]
+	item, ok, err := t.lookupItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(input.Expected) > 0 {
+		err = t.checkDeleteExpected(input, item)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if !ok {
 		return item, nil
 	}
@@ -599,15 +968,10 @@ func (t *Table) Delete(input *types.DeleteItemInput) (map[string]*types.Item, er
 	item = copyItem(item)
 
 	delete(t.Data, key)
+	delete(t.fingerprints, key)
 
-	pos := sort.SearchStrings(t.SortedKeys, key)
-	if pos == len(t.SortedKeys) {
-		return item, nil
-	}
-
-	copy(t.SortedKeys[pos:], t.SortedKeys[pos+1:])
-	t.SortedKeys[len(t.SortedKeys)-1] = ""
-	t.SortedKeys = t.SortedKeys[:len(t.SortedKeys)-1]
+	t.keys.delete(key)
+	t.recordRevision(key, nil)
 
 	for _, index := range t.Indexes {
 		err := index.delete(key, item)
@@ -619,46 +983,94 @@ func (t *Table) Delete(input *types.DeleteItemInput) (map[string]*types.Item, er
 	return item, nil
 }
 
+func (t *Table) checkDeleteExpected(input *types.DeleteItemInput, item map[string]*types.Item) error {
+	if item == nil {
+		item = map[string]*types.Item{}
+	}
+
+	matched, err := t.matchExpected(input.Expected, input.ConditionalOperator, item)
+	if err != nil {
+		return err
+	}
+
+	if !matched {
+		return types.NewError("ConditionalCheckFailedException", ErrConditionalRequestFailed.Error(), nil)
+	}
+
+	return nil
+}
+
 // Description returns the description of a table
 func (t *Table) Description(name string) *types.TableDescription {
-	// TODO: implement other fields for TableDescription
 	gsi, lsi := t.IndexesDescription()
 
 	return &types.TableDescription{
-		TableName:              name,
-		ItemCount:              int64(len(t.SortedKeys)),
-		KeySchema:              t.KeySchema.describe(),
-		GlobalSecondaryIndexes: gsi,
-		LocalSecondaryIndexes:  lsi,
+		TableName:                 name,
+		TableStatus:               t.Status,
+		TableArn:                  t.Arn(),
+		TableID:                   t.TableID,
+		CreationDateTime:          t.CreationDateTime,
+		AttributeDefinitions:      t.attributeDefinitions(),
+		BillingModeSummary:        t.billingModeSummary(),
+		ProvisionedThroughput:     t.throughput.describe(),
+		RestoreSummary:            t.restoreSummaryDescription(),
+		ItemCount:                 int64(t.keys.size()),
+		TableSizeBytes:            t.sizeBytes(),
+		KeySchema:                 t.KeySchema.describe(),
+		GlobalSecondaryIndexes:    gsi,
+		LocalSecondaryIndexes:     lsi,
+		StreamSpecification:       t.stream,
+		LatestStreamLabel:         t.streamLabel,
+		LatestStreamArn:           t.streamArn(),
+		SSEDescription:            t.sseDescription(),
+		TableClassSummary:         t.tableClassSummary(),
+		DeletionProtectionEnabled: t.DeletionProtectionEnabled,
 	}
 }
 
-// IndexesDescription returns the description of the table indexes
+// IndexesDescription returns the description of the table indexes sorted by name
 func (t *Table) IndexesDescription() ([]types.GlobalSecondaryIndexDescription, []types.LocalSecondaryIndexDescription) {
 	gsi := []types.GlobalSecondaryIndexDescription{}
 	lsi := []types.LocalSecondaryIndexDescription{}
 
-	for indexName, index := range t.Indexes {
+	names := make([]string, 0, len(t.Indexes))
+	for indexName := range t.Indexes {
+		names = append(names, indexName)
+	}
+
+	sort.Strings(names)
+
+	for _, indexName := range names {
+		index := t.Indexes[indexName]
+		name := indexName
 		schema := index.keySchema.describe()
 		count := index.count()
+		arn := t.Arn() + "/index/" + indexName
+		size := index.sizeBytes()
 
 		switch index.typ {
 		case indexTypeGlobal:
 			{
 				gsi = append(gsi, types.GlobalSecondaryIndexDescription{
-					IndexName:  &indexName,
-					ItemCount:  count,
-					KeySchema:  schema,
-					Projection: index.projection,
+					IndexName:             &name,
+					IndexArn:              &arn,
+					IndexSizeBytes:        &size,
+					IndexStatus:           types.ToString(indexStatusActive),
+					ItemCount:             count,
+					KeySchema:             schema,
+					Projection:            index.projection,
+					ProvisionedThroughput: index.throughput.describe(),
 				})
 			}
 		case indexTypeLocal:
 			{
 				lsi = append(lsi, types.LocalSecondaryIndexDescription{
-					IndexName:  &indexName,
-					ItemCount:  count,
-					KeySchema:  schema,
-					Projection: index.projection,
+					IndexName:      &name,
+					IndexArn:       &arn,
+					IndexSizeBytes: &size,
+					ItemCount:      count,
+					KeySchema:      schema,
+					Projection:     index.projection,
 				})
 			}
 		}
@@ -667,8 +1079,36 @@ func (t *Table) IndexesDescription() ([]types.GlobalSecondaryIndexDescription, [
 	return gsi, lsi
 }
 
+// ConditionFailedMessage returns the message of the ConditionalCheckFailedException of the
+// condition expression evaluated against the item with the given key
+func (t *Table) ConditionFailedMessage(expression string, aliases map[string]string, values, key map[string]*types.Item) string {
+	if !t.ExplainConditions {
+		return ErrConditionalRequestFailed.Error()
+	}
+
+	item, err := t.GetItem(key)
+	if err != nil {
+		return ErrConditionalRequestFailed.Error()
+	}
+
+	return t.conditionFailedMessage(expression, aliases, values, item)
+}
+
+func (t *Table) conditionFailedMessage(expression string, aliases map[string]string, values, item map[string]*types.Item) string {
+	if !t.ExplainConditions {
+		return ErrConditionalRequestFailed.Error()
+	}
+
+	explanation, err := language.Explain(expression, item, values, aliases)
+	if err != nil {
+		return ErrConditionalRequestFailed.Error()
+	}
+
+	return ErrConditionalRequestFailed.Error() + "\n" + explanation.String()
+}
+
 func handleConditionalCheckError(input *types.UpdateItemInput, checkErr *types.ConditionalCheckFailedException, item map[string]*types.Item) {
 	if input.ReturnValuesOnConditionCheckFailure != nil && *input.ReturnValuesOnConditionCheckFailure == "ALL_OLD" {
-		checkErr.Item = item
+		checkErr.Item = copyItem(item)
 	}
 }
@@ -181,7 +181,7 @@ func TestApplyIndexChange(t *testing.T) {
 	}
 
 	err = newTable.ApplyIndexChange(globalSecondaryIndex)
-	c.NoError(err)
+	c.Contains(err.Error(), "Requested resource not found")
 
 	globalSecondaryIndex.Update = nil
 
@@ -304,6 +304,88 @@ func TestDescriptionTable(t *testing.T) {
 	c.Equal(d.TableName, d.TableName)
 }
 
+func TestDescriptionSettings(t *testing.T) {
+	c := require.New(t)
+
+	newTable := NewTable(tableName)
+	newTable.AttributesDef = map[string]string{"name": "S", "id": "S"}
+
+	err := newTable.CreatePrimaryIndex(&types.CreateTableInput{
+		KeySchema: []*types.KeySchemaElement{
+			{AttributeName: "id", KeyType: "HASH"},
+		},
+		ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: 5, WriteCapacityUnits: 3},
+		StreamSpecification: &types.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: types.ToString("NEW_IMAGE"),
+		},
+		SSESpecification: &types.SSESpecification{Enabled: aws.Bool(true), KMSMasterKeyID: types.ToString("my-key")},
+	})
+	c.NoError(err)
+
+	err = newTable.AddGlobalIndexes([]*types.GlobalSecondaryIndex{
+		{
+			IndexName:             types.ToString("by_name"),
+			KeySchema:             []*types.KeySchemaElement{{AttributeName: "name", KeyType: "HASH"}},
+			Projection:            &types.Projection{ProjectionType: types.ToString("KEYS_ONLY")},
+			ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: 2, WriteCapacityUnits: 1},
+		},
+	})
+	c.NoError(err)
+
+	_, err = newTable.Put(&types.PutItemInput{
+		Item:      createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"}),
+		TableName: &newTable.Name,
+	})
+	c.NoError(err)
+
+	d := newTable.Description(tableName)
+	c.Equal("arn:aws:dynamodb:ddblocal:000000000000:table/"+tableName, d.TableArn)
+	c.Len(d.TableID, 36)
+	c.False(d.CreationDateTime.IsZero())
+	c.Equal([]*types.AttributeDefinition{
+		{AttributeName: types.ToString("id"), AttributeType: types.ToString("S")},
+		{AttributeName: types.ToString("name"), AttributeType: types.ToString("S")},
+	}, d.AttributeDefinitions)
+	c.Equal(BillingModeProvisioned, types.StringValue(d.BillingModeSummary.BillingMode))
+	c.Equal(int64(5), d.ProvisionedThroughput.ReadCapacityUnits)
+	c.Equal(int64(3), d.ProvisionedThroughput.WriteCapacityUnits)
+	c.Equal(int64(27), d.TableSizeBytes)
+	c.True(*d.StreamSpecification.StreamEnabled)
+	c.NotEmpty(d.LatestStreamLabel)
+	c.Equal(d.TableArn+"/stream/"+d.LatestStreamLabel, d.LatestStreamArn)
+	c.Equal("arn:aws:kms:ddblocal:000000000000:key/my-key", types.StringValue(d.SSEDescription.KMSMasterKeyArn))
+	c.Equal("ENABLED", types.StringValue(d.SSEDescription.Status))
+	c.Equal(TableClassStandard, types.StringValue(d.TableClassSummary.TableClass))
+	c.False(d.DeletionProtectionEnabled)
+
+	c.Len(d.GlobalSecondaryIndexes, 1)
+	gsi := d.GlobalSecondaryIndexes[0]
+	c.Equal(d.TableArn+"/index/by_name", types.StringValue(gsi.IndexArn))
+	c.Equal("ACTIVE", types.StringValue(gsi.IndexStatus))
+	c.Equal(int64(18), *gsi.IndexSizeBytes)
+	c.Equal(int64(2), gsi.ProvisionedThroughput.ReadCapacityUnits)
+
+	onDemand := NewTable("on-demand")
+	onDemand.AttributesDef = map[string]string{"id": "S"}
+	onDemand.BillingMode = types.ToString(BillingModePayPerRequest)
+
+	err = onDemand.CreatePrimaryIndex(&types.CreateTableInput{
+		KeySchema:  []*types.KeySchemaElement{{AttributeName: "id", KeyType: "HASH"}},
+		TableClass: types.ToString(TableClassStandardInfrequentAccess),
+	})
+	c.NoError(err)
+
+	d = onDemand.Description("on-demand")
+	c.Equal(BillingModePayPerRequest, types.StringValue(d.BillingModeSummary.BillingMode))
+	c.Equal(d.CreationDateTime, *d.BillingModeSummary.LastUpdateToPayPerRequestDateTime)
+	c.Equal(int64(0), d.ProvisionedThroughput.ReadCapacityUnits)
+	c.Equal(TableClassStandardInfrequentAccess, types.StringValue(d.TableClassSummary.TableClass))
+	c.Nil(d.StreamSpecification)
+	c.Empty(d.LatestStreamArn)
+	c.Nil(d.SSEDescription)
+}
+
 func TestGetKey(t *testing.T) {
 	c := require.New(t)
 
@@ -326,22 +408,6 @@ func TestGetKey(t *testing.T) {
 	c.NoError(err)
 }
 
-func TestGetKeys(t *testing.T) {
-	c := require.New(t)
-
-	val := GetKeyAt([]string{"gk"}, 0, 0, true)
-	c.Equal(val, "gk")
-
-	val = GetKeyAt([]string{"gk"}, 1, 0, false)
-	c.Equal(val, "gk")
-
-	val = GetKeyAt([]string{"gk"}, 0, 0, true)
-	c.Equal(val, "gk")
-
-	val = GetKeyAt([]string{"gk"}, 1, 0, false)
-	c.Equal(val, "gk")
-}
-
 func TestDeleteItem(t *testing.T) {
 	c := require.New(t)
 
@@ -453,11 +519,12 @@ func TestDeleteIndex(t *testing.T) {
 	}
 
 	index := newTable.Indexes["invert"]
-	index.sortedKeys = []string{"key1", "key2"}
-	newTable.Indexes["invert"] = index
+	c.Equal(int64(3), index.count())
 
 	_, err = newTable.Delete(inp)
 	c.NoError(err)
+	c.Equal(int64(2), index.count())
+	c.NotContains(index.refs, "002.Ivysaur")
 }
 
 func TestSearchData(t *testing.T) {
@@ -468,7 +535,8 @@ func TestSearchData(t *testing.T) {
 
 	queryInput := QueryInput{}
 
-	result, lastItem := newTable.SearchData(queryInput)
+	result, lastItem, err := newTable.SearchData(queryInput)
+	c.NoError(err)
 	c.Equal([]map[string]*types.Item{}, result)
 	c.Equal(map[string]*types.Item{}, lastItem)
 
@@ -492,7 +560,8 @@ func TestSearchData(t *testing.T) {
 		},
 	}
 
-	result, lastItem = newTable.SearchData(queryInput)
+	result, lastItem, err = newTable.SearchData(queryInput)
+	c.NoError(err)
 	c.Equal([]map[string]*types.Item{}, result)
 	c.Equal(map[string]*types.Item{}, lastItem)
 
@@ -502,25 +571,26 @@ func TestSearchData(t *testing.T) {
 	queryInput.ScanIndexForward = true
 
 	newIndex := index{
-		sortedRefs: [][2]string{{"ref1", "ref2"}},
-		sortedKeys: []string{"ref2", "ref1"},
-		refs:       map[string]string{"ref1": "ref2"},
-		Table:      newTable,
+		keys:  newKeyList(),
+		refs:  map[string]string{"ref1": "ref2"},
+		Table: newTable,
 	}
+	newIndex.keys.put(entryKey("ref2", "ref1"), "ref1")
 
 	newTable.Indexes = map[string]*index{
 		"indice": &newIndex,
 	}
 
 	queryInput.Index = "indice"
-	queryInput.started = true
 
-	result, lastItem = newTable.SearchData(queryInput)
+	result, lastItem, err = newTable.SearchData(queryInput)
+	c.NoError(err)
 	c.Equal([]map[string]*types.Item{{}}, result)
 	c.Equal(map[string]*types.Item{}, lastItem)
 
 	queryInput.ExclusiveStartKey = item
-	result, lastItem = newTable.SearchData(queryInput)
+	result, lastItem, err = newTable.SearchData(queryInput)
+	c.NoError(err)
 	c.Equal([]map[string]*types.Item{}, result)
 	c.Equal(map[string]*types.Item{}, lastItem)
 
@@ -587,6 +657,56 @@ func TestUpdate(t *testing.T) {
 	newTable.Clear()
 }
 
+func TestConditionFailedMessage(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	item := createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+
+	_, err = newTable.Put(&types.PutItemInput{Item: item, TableName: &newTable.Name})
+	c.NoError(err)
+
+	key := map[string]*types.Item{
+		"id":   {S: types.ToString("001")},
+		"name": {S: types.ToString("Bulbasaur")},
+	}
+	values := map[string]*types.Item{":type": {S: types.ToString("fire")}}
+
+	message := newTable.ConditionFailedMessage("#t = :type", map[string]string{"#t": "type"}, values, key)
+	c.Equal("conditional request failed", message)
+
+	newTable.ExplainConditions = true
+
+	message = newTable.ConditionFailedMessage("#t = :type", map[string]string{"#t": "type"}, values, key)
+	c.Equal("conditional request failed\n#t = :type → false\n  #t → \"grass\"\n  :type → \"fire\"", message)
+
+	message = newTable.ConditionFailedMessage("#t = :type", map[string]string{"#t": "type"}, values, map[string]*types.Item{})
+	c.Equal("conditional request failed", message)
+
+	message = newTable.ConditionFailedMessage("", nil, values, key)
+	c.Equal("conditional request failed", message)
+
+	_, err = newTable.Put(&types.PutItemInput{
+		Item:                      item,
+		TableName:                 &newTable.Name,
+		ConditionExpression:       types.ToString("attribute_not_exists(id)"),
+		ExpressionAttributeValues: values,
+	})
+	c.EqualError(err, "ConditionalCheckFailedException: conditional request failed\nattribute_not_exists(id) → false\n  id → \"001\"")
+
+	_, err = newTable.Update(&types.UpdateItemInput{
+		Key:                       key,
+		ConditionExpression:       types.ToString("#t = :type"),
+		ExpressionAttributeNames:  map[string]string{"#t": "type"},
+		ExpressionAttributeValues: values,
+		UpdateExpression:          "SET #t = :type",
+	})
+	c.Error(err)
+	c.Contains(err.Error(), "#t = :type → false")
+}
+
 func TestPutItem(t *testing.T) {
 	c := require.New(t)
 
@@ -623,6 +743,36 @@ func TestPutItem(t *testing.T) {
 	c.NoError(err)
 }
 
+func TestTraceKey(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	item := createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+
+	// the key is only built when a tracer receives it
+	c.Nil(newTable.traceKey(item))
+
+	traces := []interpreter.Trace{}
+	newTable.LangInterpreter.Tracer = interpreter.TracerFunc(func(tr interpreter.Trace) {
+		traces = append(traces, tr)
+	})
+
+	c.Equal(map[string]*types.Item{"id": item["id"], "name": item["name"]}, newTable.traceKey(item))
+	c.Empty(newTable.traceKey(map[string]*types.Item{"type": item["type"]}))
+
+	_, err = newTable.Put(&types.PutItemInput{
+		Item:                item,
+		TableName:           &newTable.Name,
+		ConditionExpression: types.ToString("attribute_not_exists(id)"),
+	})
+	c.NoError(err)
+	c.Len(traces, 1)
+	c.Empty(traces[0].Key)
+	c.True(traces[0].Matched)
+}
+
 func TestGetItem(t *testing.T) {
 	c := require.New(t)
 
@@ -639,7 +789,8 @@ func TestGetItem(t *testing.T) {
 		"item": item,
 	}
 
-	resultItem := newTable.getItem("item")
+	resultItem, err := newTable.getItem("item")
+	c.NoError(err)
 	c.Equal(item, resultItem)
 }
 
@@ -656,10 +807,8 @@ func TestGetLastKey(t *testing.T) {
 	})
 
 	newIndex := &index{
-		sortedRefs: [][2]string{{"ref1", "ref2"}},
-		sortedKeys: []string{"ref2", "ref1"},
-		refs:       map[string]string{"ref1": "ref2"},
-		Table:      newTable,
+		refs:  map[string]string{"ref1": "ref2"},
+		Table: newTable,
 	}
 
 	result := newTable.getLastKey(item, 1, 1, 1, 2, newIndex)
@@ -696,18 +845,114 @@ func TestInterpreterMatch(t *testing.T) {
 		ExpressionType: interpreter.ExpressionTypeConditional,
 	}
 
-	newTable.interpreterMatch(matchInput)
+	_, err = newTable.interpreterMatch(matchInput)
+	c.NoError(err)
 
 	matchInput = interpreter.MatchInput{
 		TableName: tableName,
 	}
 
-	c.Panics(func() { newTable.interpreterMatch(matchInput) })
+	_, err = newTable.interpreterMatch(matchInput)
+	c.Error(err)
 
 	newTable.UseNativeInterpreter = false
 	matchInput.Expression = "bad_expression(id)"
+	matchInput.ExpressionType = interpreter.ExpressionTypeFilter
+
+	_, err = newTable.interpreterMatch(matchInput)
+	c.Contains(err.Error(), "ValidationException: Invalid FilterExpression: ")
+
+	matchInput.Expression = "id != :id"
+
+	_, err = newTable.interpreterMatch(matchInput)
+	c.EqualError(err, `ValidationException: Invalid FilterExpression: Syntax error; token: "!", near: "id !="`)
+
+	matchInput.Expression = "begins_with(id)"
 
-	c.Panics(func() { newTable.interpreterMatch(matchInput) })
+	_, err = newTable.interpreterMatch(matchInput)
+	c.EqualError(err, "ValidationException: Invalid FilterExpression: Incorrect number of operands for operator or function; operator or function: begins_with, number of operands: 1")
+}
+
+func TestDifferentialInterpreter(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	mismatches := []interpreter.Mismatch{}
+	newTable.UseNativeInterpreter = true
+	newTable.MismatchReporter = func(m interpreter.Mismatch) {
+		mismatches = append(mismatches, m)
+	}
+
+	native := interpreter.NewNativeInterpreter()
+	c.NoError(native.AddMatcher(tableName, interpreter.ExpressionTypeFilter, "#id = :id", func(item, attributes map[string]*types.Item) bool {
+		return true
+	}))
+	c.NoError(native.AddMatcher(tableName, interpreter.ExpressionTypeFilter, "#type = :type", func(item, attributes map[string]*types.Item) bool {
+		return true
+	}))
+	c.NoError(native.AddUpdater(tableName, "SET #type = :type", func(item, attributes map[string]*types.Item) {
+		item["type"] = attributes[":type"]
+	}))
+	c.NoError(native.AddUpdater(tableName, "SET #name = :type", func(item, attributes map[string]*types.Item) {
+		item["type"] = attributes[":type"]
+	}))
+
+	newTable.NativeInterpreter = *native
+
+	item := createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})
+	matchInput := interpreter.MatchInput{
+		TableName:      tableName,
+		Expression:     "#id = :id",
+		ExpressionType: interpreter.ExpressionTypeFilter,
+		Item:           item,
+		Aliases:        map[string]string{"#id": "id", "#type": "type", "#name": "name"},
+		Attributes: map[string]*types.Item{
+			":id":   {S: types.ToString("001")},
+			":type": {S: types.ToString("fire")},
+		},
+	}
+
+	matched, err := newTable.interpreterMatch(matchInput)
+	c.NoError(err)
+	c.True(matched)
+	c.Empty(mismatches)
+
+	matchInput.Expression = "#type = :type"
+
+	matched, err = newTable.interpreterMatch(matchInput)
+	c.NoError(err)
+	c.True(matched)
+	c.Len(mismatches, 1)
+	c.Equal("true", mismatches[0].Native)
+	c.Equal("false", mismatches[0].Language)
+	c.Contains(mismatches[0].String(), `filter expression "#type = :type" in table "pokemons" differs for item {"id": {S: "001"}`)
+
+	updateInput := interpreter.UpdateInput{
+		TableName:  tableName,
+		Expression: "SET #type = :type",
+		Item:       item,
+		Aliases:    matchInput.Aliases,
+		Attributes: matchInput.Attributes,
+	}
+
+	err = newTable.interpreterUpdate(updateInput)
+	c.NoError(err)
+	c.Len(mismatches, 1)
+	c.Equal("fire", types.StringValue(item["type"].S))
+
+	updateInput.Expression = "SET #name = :type"
+	item["type"] = &types.Item{S: types.ToString("grass")}
+
+	err = newTable.interpreterUpdate(updateInput)
+	c.NoError(err)
+	c.Equal("fire", types.StringValue(item["type"].S))
+	c.Equal("Bulbasaur", types.StringValue(item["name"].S))
+	c.Len(mismatches, 2)
+	c.Equal(interpreter.ExpressionTypeUpdate, mismatches[1].ExpressionType)
+	c.Equal("grass", types.StringValue(mismatches[1].Item["type"].S))
+	c.Contains(mismatches[1].Language, `"name": {S: "fire"}`)
 }
 
 func TestMatchKey(t *testing.T) {
@@ -731,9 +976,18 @@ func TestMatchKey(t *testing.T) {
 		ConditionExpression:    types.ToString("attribute_exists(id)"),
 	}
 
-	expresionType, ok := newTable.matchKey(queryInput, item)
+	expresionType, ok, err := newTable.matchKey(queryInput, item)
+	c.NoError(err)
 	c.True(ok)
 	c.NotNil(expresionType)
+
+	queryInput.Aliases = map[string]string{"#id": "id"}
+	queryInput.FilterExpression = "#id = "
+
+	expresionType, ok, err = newTable.matchKey(queryInput, item)
+	c.EqualError(err, `ValidationException: Invalid FilterExpression: Syntax error; token: "<EOF>", near: "="`)
+	c.False(ok)
+	c.Equal(interpreter.ExpressionTypeFilter, expresionType)
 }
 
 func TestSetAttributeDefinition(t *testing.T) {
@@ -817,10 +1071,36 @@ func TestFetchQueryData(t *testing.T) {
 	c.NoError(err)
 	c.Len(newTable.Data, 6)
 
-	index, sortedKeys := newTable.fetchQueryData(QueryInput{
+	queryInput := QueryInput{
 		Index: "invert",
-	})
+	}
+
+	keys, index, r := newTable.searchKeys(queryInput)
+	c.Equal(newTable.Indexes["invert"], index)
+	c.Equal(6, keys.size())
+	c.Equal("006.Bellsprout", newTable.startNode(queryInput, keys, index, r).value)
+}
+
+func TestIndexEntriesFollowTheItems(t *testing.T) {
+	c := require.New(t)
+
+	newTable, err := createPokemonTable()
+	c.NoError(err)
+
+	newTable.AttributesDef["type"] = "S"
+	byType := newIndex(newTable, indexTypeGlobal, keySchema{HashKey: "type", RangeKey: "id"})
+
+	c.NoError(byType.putData("001.Bulbasaur", createPokemon(pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"})))
+	c.NoError(byType.putData("004.Charmander", createPokemon(pokemon{ID: "004", Type: "fire", Name: "Charmander"})))
+	c.Equal([]string{"fire.004\x00004.Charmander", "grass.001\x00001.Bulbasaur"}, keyListKeys(byType.keys, true))
+
+	c.NoError(byType.updateData("001.Bulbasaur", createPokemon(pokemon{ID: "001", Type: "poison", Name: "Bulbasaur"}), nil))
+	c.Equal([]string{"fire.004\x00004.Charmander", "poison.001\x00001.Bulbasaur"}, keyListKeys(byType.keys, true))
+
+	c.NoError(byType.updateData("004.Charmander", map[string]*types.Item{"id": {S: types.ToString("004")}}, nil))
+	c.Equal(int64(1), byType.count())
 
-	c.NotEmpty(sortedKeys)
-	c.Equal(index.sortedRefs[0][0], "006.Bellsprout")
+	c.NoError(byType.delete("001.Bulbasaur", nil))
+	c.Equal(int64(0), byType.count())
+	c.Empty(byType.refs)
 }
@@ -0,0 +1,446 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	maxDecreasesWithoutWait = 4
+	decreaseWait            = time.Hour
+
+	// revive:disable-next-line
+	nothingToUpdateMsg = "At least one of ProvisionedThroughput, BillingMode, UpdateStreamEnabled, GlobalSecondaryIndexUpdates or SSESpecification or ReplicaUpdates is required"
+	// revive:disable-next-line
+	indexOperationsMsg = "Subscriber limit exceeded: Only 1 online index can be created or deleted simultaneously per table"
+	// revive:disable-next-line
+	payPerRequestThroughputMsg = "One or more parameter values were invalid: Neither ReadCapacityUnits nor WriteCapacityUnits can be specified when BillingMode is PAY_PER_REQUEST"
+	// revive:disable-next-line
+	payPerRequestIndexThroughputMsg = "One or more parameter values were invalid: ProvisionedThroughput should not be specified for index: %s when BillingMode is PAY_PER_REQUEST"
+	// revive:disable-next-line
+	missingThroughputMsg = "One or more parameter values were invalid: ProvisionedThroughput must be specified when BillingMode is PROVISIONED"
+	// revive:disable-next-line
+	missingIndexThroughputMsg = "One or more parameter values were invalid: ProvisionedThroughput must be specified for index: %s"
+	// revive:disable-next-line
+	unchangedThroughputMsg = "The provisioned throughput for the %s will not change. The requested value equals the current value. Current ReadCapacityUnits provisioned for the %s: %d. Requested ReadCapacityUnits: %d. Current WriteCapacityUnits provisioned for the %s: %d. Requested WriteCapacityUnits: %d. Refer to the Amazon DynamoDB Developer Guide for current limits and how to request higher limits."
+	// revive:disable-next-line
+	capacityUnitsMsg = "1 validation error detected: Value '%d' at '%s' failed to satisfy constraint: Member must have value greater than or equal to 1"
+	// revive:disable-next-line
+	decreaseLimitMsg = "Subscriber limit exceeded: Provisioned throughput decreases are limited within a given UTC day. After the first 4 decreases, each subsequent decrease in the same UTC day can be performed at most once every 1 hour. Number of decreases today: %d. Last decrease at %s. Next decrease can be made at %s"
+	// revive:disable-next-line
+	billingModeEnumMsg = "1 validation error detected: Value '%s' at 'billingMode' failed to satisfy constraint: Member must satisfy enum value set: [PROVISIONED, PAY_PER_REQUEST]"
+	// revive:disable-next-line
+	tableClassEnumMsg = "1 validation error detected: Value '%s' at 'tableClass' failed to satisfy constraint: Member must satisfy enum value set: [STANDARD_INFREQUENT_ACCESS, STANDARD]"
+	// revive:disable-next-line
+	streamViewTypeMsg = "One or more parameter values were invalid: StreamViewType must be specified when StreamEnabled is true"
+	streamEnabledMsg  = "Table already has an enabled stream: %s"
+	streamDisabledMsg = "Table already has no enabled stream: %s"
+)
+
+// ApplyTableUpdate applies the changes of an UpdateTable request, the whole request is validated
+// before changing the table
+func (t *Table) ApplyTableUpdate(input *types.UpdateTableInput) error {
+	if err := t.validateTableUpdate(input, t.mergedAttributeDefinitions(input.AttributeDefinitions)); err != nil {
+		return err
+	}
+
+	t.SetAttributeDefinition(input.AttributeDefinitions)
+
+	now := t.now()
+
+	t.applyCapacityUpdate(input, now)
+
+	for _, change := range input.GlobalSecondaryIndexUpdates {
+		if err := t.ApplyIndexChange(change); err != nil {
+			return err
+		}
+	}
+
+	t.applySettingsUpdate(input, now)
+
+	return nil
+}
+
+// applyCapacityUpdate changes the billing mode and the provisioned throughput of the table
+func (t *Table) applyCapacityUpdate(input *types.UpdateTableInput, now time.Time) {
+	if mode := types.StringValue(input.BillingMode); mode != "" && mode != t.billingMode() {
+		t.switchBillingMode(mode, now)
+	}
+
+	if input.ProvisionedThroughput != nil {
+		t.throughput = t.throughput.change(input.ProvisionedThroughput, now)
+	}
+}
+
+// applySettingsUpdate changes the stream, encryption, table class and deletion protection settings
+func (t *Table) applySettingsUpdate(input *types.UpdateTableInput, now time.Time) {
+	if input.StreamSpecification != nil {
+		t.setStream(input.StreamSpecification)
+	}
+
+	if input.SSESpecification != nil {
+		t.sse = input.SSESpecification
+	}
+
+	if class := types.StringValue(input.TableClass); class != "" && class != t.TableClass {
+		t.TableClass = class
+		t.tableClassUpdated = now
+	}
+
+	if input.DeletionProtectionEnabled != nil {
+		t.DeletionProtectionEnabled = *input.DeletionProtectionEnabled
+	}
+}
+
+// mergedAttributeDefinitions returns a copy of the attribute definitions of the table with the given ones
+func (t *Table) mergedAttributeDefinitions(attrs []*types.AttributeDefinition) map[string]string {
+	defs := make(map[string]string, len(t.AttributesDef)+len(attrs))
+
+	for name, typ := range t.AttributesDef {
+		defs[name] = typ
+	}
+
+	for _, attr := range attrs {
+		defs[types.StringValue(attr.AttributeName)] = types.StringValue(attr.AttributeType)
+	}
+
+	return defs
+}
+
+func (t *Table) billingMode() string {
+	if t.isPayPerRequest() {
+		return BillingModePayPerRequest
+	}
+
+	return BillingModeProvisioned
+}
+
+// switchBillingMode changes the billing mode, the on-demand tables and indexes drop their provisioned throughput
+func (t *Table) switchBillingMode(mode string, now time.Time) {
+	t.BillingMode = types.ToString(mode)
+
+	if mode != BillingModePayPerRequest {
+		return
+	}
+
+	t.billingModeUpdated = now
+	t.throughput = nil
+
+	for _, i := range t.Indexes {
+		i.throughput = nil
+	}
+}
+
+func hasTableChanges(input *types.UpdateTableInput) bool {
+	return input.BillingMode != nil ||
+		input.ProvisionedThroughput != nil ||
+		len(input.GlobalSecondaryIndexUpdates) > 0 ||
+		input.StreamSpecification != nil ||
+		input.SSESpecification != nil ||
+		input.TableClass != nil ||
+		input.DeletionProtectionEnabled != nil
+}
+
+func (t *Table) validateTableUpdate(input *types.UpdateTableInput, defs map[string]string) error {
+	if !hasTableChanges(input) {
+		return types.NewError("ValidationException", nothingToUpdateMsg, nil)
+	}
+
+	mode := t.billingMode()
+	if input.BillingMode != nil {
+		mode = types.StringValue(input.BillingMode)
+	}
+
+	if mode != BillingModeProvisioned && mode != BillingModePayPerRequest {
+		return types.NewError("ValidationException", fmt.Sprintf(billingModeEnumMsg, mode), nil)
+	}
+
+	if err := t.validateThroughputUpdate(input, mode); err != nil {
+		return err
+	}
+
+	if err := t.validateIndexUpdates(input.GlobalSecondaryIndexUpdates, mode, defs); err != nil {
+		return err
+	}
+
+	if err := t.validateStreamUpdate(input.StreamSpecification); err != nil {
+		return err
+	}
+
+	return validateTableClass(input.TableClass)
+}
+
+func (t *Table) validateThroughputUpdate(input *types.UpdateTableInput, mode string) error {
+	switching := mode != t.billingMode()
+
+	if mode == BillingModePayPerRequest {
+		if input.ProvisionedThroughput != nil {
+			return types.NewError("ValidationException", payPerRequestThroughputMsg, nil)
+		}
+
+		return nil
+	}
+
+	if input.ProvisionedThroughput == nil {
+		if switching {
+			return types.NewError("ValidationException", missingThroughputMsg, nil)
+		}
+
+		return nil
+	}
+
+	if switching {
+		return validateCapacityUnits(input.ProvisionedThroughput, "provisionedThroughput")
+	}
+
+	return t.throughput.validateChange(input.ProvisionedThroughput, t.now(), "table", "table", "provisionedThroughput")
+}
+
+func (t *Table) validateIndexUpdates(changes []*types.GlobalSecondaryIndexUpdate, mode string, defs map[string]string) error {
+	operations := 0
+	updated := map[string]bool{}
+
+	for _, change := range changes {
+		name, online, err := t.validateIndexChange(change, mode, defs)
+		if err != nil {
+			return err
+		}
+
+		if online {
+			operations++
+		}
+
+		updated[name] = true
+	}
+
+	if operations > 1 {
+		return types.NewError("LimitExceededException", indexOperationsMsg, nil)
+	}
+
+	if mode == BillingModePayPerRequest || mode == t.billingMode() {
+		return nil
+	}
+
+	return t.validateProvisionedIndexes(updated)
+}
+
+// validateIndexChange validates a global secondary index change, it returns the name of the changed index
+// and whether the change creates or deletes the index
+func (t *Table) validateIndexChange(change *types.GlobalSecondaryIndexUpdate, mode string, defs map[string]string) (string, bool, error) {
+	switch {
+	case change.Create != nil:
+		return types.StringValue(change.Create.IndexName), true, validateIndexCreate(change.Create, mode, defs)
+	case change.Delete != nil:
+		name := types.StringValue(change.Delete.IndexName)
+
+		return name, true, t.validateIndexExists(name)
+	case change.Update != nil:
+		return types.StringValue(change.Update.IndexName), false, t.validateIndexThroughput(change.Update, mode)
+	}
+
+	return "", false, nil
+}
+
+// validateProvisionedIndexes checks that the global indexes get their own throughput
+// when the table switches to provisioned capacity
+func (t *Table) validateProvisionedIndexes(updated map[string]bool) error {
+	for name, i := range t.Indexes {
+		if i.typ == indexTypeGlobal && !updated[name] {
+			return types.NewError("ValidationException", fmt.Sprintf(missingIndexThroughputMsg, name), nil)
+		}
+	}
+
+	return nil
+}
+
+func (t *Table) validateIndexExists(name string) error {
+	if _, ok := t.Indexes[name]; !ok {
+		return types.NewError("ResourceNotFoundException", "Requested resource not found", nil)
+	}
+
+	return nil
+}
+
+func validateIndexCreate(create *types.CreateGlobalSecondaryIndexAction, mode string, defs map[string]string) error {
+	name := types.StringValue(create.IndexName)
+
+	if mode == BillingModePayPerRequest && create.ProvisionedThroughput != nil {
+		return types.NewError("ValidationException", fmt.Sprintf(payPerRequestIndexThroughputMsg, name), nil)
+	}
+
+	if mode == BillingModeProvisioned {
+		if create.ProvisionedThroughput == nil {
+			return types.NewError("ValidationException", "No provisioned throughput specified for the global secondary index", nil)
+		}
+
+		if err := validateCapacityUnits(create.ProvisionedThroughput, "globalSecondaryIndexUpdates.create.provisionedThroughput"); err != nil {
+			return err
+		}
+	}
+
+	ks, err := parseKeySchema(create.KeySchema)
+	if err != nil {
+		return err
+	}
+
+	return validateKeyAttributes(defs, ks, "Global Secondary Index ")
+}
+
+func (t *Table) validateIndexThroughput(update *types.UpdateGlobalSecondaryIndexAction, mode string) error {
+	name := types.StringValue(update.IndexName)
+
+	if err := t.validateIndexExists(name); err != nil {
+		return err
+	}
+
+	if mode == BillingModePayPerRequest {
+		return types.NewError("ValidationException", fmt.Sprintf(payPerRequestIndexThroughputMsg, name), nil)
+	}
+
+	if update.ProvisionedThroughput == nil {
+		return types.NewError("ValidationException", fmt.Sprintf(missingIndexThroughputMsg, name), nil)
+	}
+
+	const parameter = "globalSecondaryIndexUpdates.update.provisionedThroughput"
+
+	if mode != t.billingMode() {
+		return validateCapacityUnits(update.ProvisionedThroughput, parameter)
+	}
+
+	return t.Indexes[name].throughput.validateChange(update.ProvisionedThroughput, t.now(), "index "+name, "index", parameter)
+}
+
+func validateCapacityUnits(pt *types.ProvisionedThroughput, parameter string) error {
+	if pt.ReadCapacityUnits < 1 {
+		return types.NewError("ValidationException", fmt.Sprintf(capacityUnitsMsg, pt.ReadCapacityUnits, parameter+".readCapacityUnits"), nil)
+	}
+
+	if pt.WriteCapacityUnits < 1 {
+		return types.NewError("ValidationException", fmt.Sprintf(capacityUnitsMsg, pt.WriteCapacityUnits, parameter+".writeCapacityUnits"), nil)
+	}
+
+	return nil
+}
+
+// validateChange rejects the requests that keep the current capacity and the decreases over the daily limit,
+// DynamoDB allows 4 decreases per UTC day and one more after each hour without decreases
+func (tp *throughput) validateChange(pt *types.ProvisionedThroughput, now time.Time, resource, kind, parameter string) error {
+	if err := validateCapacityUnits(pt, parameter); err != nil {
+		return err
+	}
+
+	if tp == nil {
+		return nil
+	}
+
+	if pt.ReadCapacityUnits == tp.read && pt.WriteCapacityUnits == tp.write {
+		msg := fmt.Sprintf(unchangedThroughputMsg, resource, kind, tp.read, pt.ReadCapacityUnits, kind, tp.write, pt.WriteCapacityUnits)
+
+		return types.NewError("ValidationException", msg, nil)
+	}
+
+	if pt.ReadCapacityUnits >= tp.read && pt.WriteCapacityUnits >= tp.write {
+		return nil
+	}
+
+	decreases := tp.decreasesOn(now)
+	next := tp.lastDecrease.Add(decreaseWait)
+
+	if decreases >= maxDecreasesWithoutWait && now.Before(next) {
+		layout := time.RFC1123
+		msg := fmt.Sprintf(decreaseLimitMsg, decreases, tp.lastDecrease.UTC().Format(layout), next.UTC().Format(layout))
+
+		return types.NewError("LimitExceededException", msg, nil)
+	}
+
+	return nil
+}
+
+// decreasesOn returns the number of decreases in the UTC day of the given time
+func (tp *throughput) decreasesOn(now time.Time) int64 {
+	if tp.lastDecrease.IsZero() || !sameUTCDay(tp.lastDecrease, now) {
+		return 0
+	}
+
+	return tp.decreasesToday
+}
+
+func sameUTCDay(a, b time.Time) bool {
+	ay, am, ad := a.UTC().Date()
+	by, bm, bd := b.UTC().Date()
+
+	return ay == by && am == bm && ad == bd
+}
+
+// change returns the throughput with the new capacity, it records the time of the increases and decreases
+func (tp *throughput) change(pt *types.ProvisionedThroughput, now time.Time) *throughput {
+	next := newThroughput(pt)
+
+	if tp == nil || next == nil {
+		return next
+	}
+
+	next.lastIncrease = tp.lastIncrease
+	next.lastDecrease = tp.lastDecrease
+	next.decreasesToday = tp.decreasesOn(now)
+
+	if pt.ReadCapacityUnits > tp.read || pt.WriteCapacityUnits > tp.write {
+		next.lastIncrease = now
+	}
+
+	if pt.ReadCapacityUnits < tp.read || pt.WriteCapacityUnits < tp.write {
+		next.lastDecrease = now
+		next.decreasesToday++
+	}
+
+	return next
+}
+
+func (t *Table) validateStreamUpdate(spec *types.StreamSpecification) error {
+	if spec == nil {
+		return nil
+	}
+
+	if err := validateStreamSpecification(spec); err != nil {
+		return err
+	}
+
+	enabled := spec.StreamEnabled != nil && *spec.StreamEnabled
+
+	if enabled && t.stream != nil {
+		return types.NewError("ValidationException", fmt.Sprintf(streamEnabledMsg, t.streamArn()), nil)
+	}
+
+	if !enabled && t.stream == nil {
+		return types.NewError("ValidationException", fmt.Sprintf(streamDisabledMsg, t.Arn()), nil)
+	}
+
+	return nil
+}
+
+func validateStreamSpecification(spec *types.StreamSpecification) error {
+	if spec == nil || spec.StreamEnabled == nil || !*spec.StreamEnabled {
+		return nil
+	}
+
+	if types.StringValue(spec.StreamViewType) == "" {
+		return types.NewError("ValidationException", streamViewTypeMsg, nil)
+	}
+
+	return nil
+}
+
+func validateTableClass(class *string) error {
+	if class == nil {
+		return nil
+	}
+
+	switch *class {
+	case TableClassStandard, TableClassStandardInfrequentAccess:
+		return nil
+	}
+
+	return types.NewError("ValidationException", fmt.Sprintf(tableClassEnumMsg, *class), nil)
+}
@@ -0,0 +1,399 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func createSettingsTable(c *require.Assertions, billingMode string) *Table {
+	table := NewTable(tableName)
+	table.AttributesDef = map[string]string{"id": "S", "type": "S"}
+	table.BillingMode = types.ToString(billingMode)
+
+	input := &types.CreateTableInput{
+		KeySchema: []*types.KeySchemaElement{{AttributeName: "id", KeyType: "HASH"}},
+	}
+
+	gsi := &types.GlobalSecondaryIndex{
+		IndexName:  types.ToString("by-type"),
+		KeySchema:  []*types.KeySchemaElement{{AttributeName: "type", KeyType: "HASH"}},
+		Projection: &types.Projection{ProjectionType: types.ToString("ALL")},
+	}
+
+	if billingMode == BillingModeProvisioned {
+		input.ProvisionedThroughput = &types.ProvisionedThroughput{ReadCapacityUnits: 5, WriteCapacityUnits: 5}
+		gsi.ProvisionedThroughput = &types.ProvisionedThroughput{ReadCapacityUnits: 5, WriteCapacityUnits: 5}
+	}
+
+	c.NoError(table.CreatePrimaryIndex(input))
+	c.NoError(table.AddGlobalIndexes([]*types.GlobalSecondaryIndex{gsi}))
+
+	return table
+}
+
+func indexThroughputUpdate(name string, units int64) *types.GlobalSecondaryIndexUpdate {
+	return &types.GlobalSecondaryIndexUpdate{
+		Update: &types.UpdateGlobalSecondaryIndexAction{
+			IndexName:             types.ToString(name),
+			ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: units, WriteCapacityUnits: units},
+		},
+	}
+}
+
+func TestApplyTableUpdateValidation(t *testing.T) {
+	c := require.New(t)
+
+	table := createSettingsTable(c, BillingModePayPerRequest)
+	throughput := &types.ProvisionedThroughput{ReadCapacityUnits: 1, WriteCapacityUnits: 1}
+
+	tests := []struct {
+		name  string
+		input *types.UpdateTableInput
+		err   string
+	}{
+		{
+			name:  "nothing to update",
+			input: &types.UpdateTableInput{AttributeDefinitions: []*types.AttributeDefinition{}},
+			err:   "ValidationException: At least one of ProvisionedThroughput",
+		},
+		{
+			name:  "invalid billing mode",
+			input: &types.UpdateTableInput{BillingMode: types.ToString("FREE")},
+			err:   "Value 'FREE' at 'billingMode' failed to satisfy constraint",
+		},
+		{
+			name:  "throughput in on-demand table",
+			input: &types.UpdateTableInput{ProvisionedThroughput: throughput},
+			err:   "Neither ReadCapacityUnits nor WriteCapacityUnits can be specified when BillingMode is PAY_PER_REQUEST",
+		},
+		{
+			name:  "index throughput in on-demand table",
+			input: &types.UpdateTableInput{GlobalSecondaryIndexUpdates: []*types.GlobalSecondaryIndexUpdate{indexThroughputUpdate("by-type", 1)}},
+			err:   "ProvisionedThroughput should not be specified for index: by-type when BillingMode is PAY_PER_REQUEST",
+		},
+		{
+			name:  "provisioned without throughput",
+			input: &types.UpdateTableInput{BillingMode: types.ToString(BillingModeProvisioned)},
+			err:   "ProvisionedThroughput must be specified when BillingMode is PROVISIONED",
+		},
+		{
+			name:  "provisioned without index throughput",
+			input: &types.UpdateTableInput{BillingMode: types.ToString(BillingModeProvisioned), ProvisionedThroughput: throughput},
+			err:   "ProvisionedThroughput must be specified for index: by-type",
+		},
+		{
+			name: "zero capacity units",
+			input: &types.UpdateTableInput{
+				BillingMode:                 types.ToString(BillingModeProvisioned),
+				ProvisionedThroughput:       &types.ProvisionedThroughput{ReadCapacityUnits: 0, WriteCapacityUnits: 1},
+				GlobalSecondaryIndexUpdates: []*types.GlobalSecondaryIndexUpdate{indexThroughputUpdate("by-type", 1)},
+			},
+			err: "Value '0' at 'provisionedThroughput.readCapacityUnits' failed to satisfy constraint",
+		},
+		{
+			name: "two index operations",
+			input: &types.UpdateTableInput{GlobalSecondaryIndexUpdates: []*types.GlobalSecondaryIndexUpdate{
+				{Delete: &types.DeleteGlobalSecondaryIndexAction{IndexName: types.ToString("by-type")}},
+				{Create: &types.CreateGlobalSecondaryIndexAction{
+					IndexName: types.ToString("by-id"),
+					KeySchema: []*types.KeySchemaElement{{AttributeName: "id", KeyType: "HASH"}},
+				}},
+			}},
+			err: "LimitExceededException: Subscriber limit exceeded: Only 1 online index can be created or deleted simultaneously per table",
+		},
+		{
+			name: "index without attribute definition",
+			input: &types.UpdateTableInput{GlobalSecondaryIndexUpdates: []*types.GlobalSecondaryIndexUpdate{
+				{Create: &types.CreateGlobalSecondaryIndexAction{
+					IndexName: types.ToString("by-name"),
+					KeySchema: []*types.KeySchemaElement{{AttributeName: "name", KeyType: "HASH"}},
+				}},
+			}},
+			err: "Global Secondary Index Hash Key not specified in Attribute Definitions.",
+		},
+		{
+			name:  "missing index",
+			input: &types.UpdateTableInput{GlobalSecondaryIndexUpdates: []*types.GlobalSecondaryIndexUpdate{{Delete: &types.DeleteGlobalSecondaryIndexAction{IndexName: types.ToString("404")}}}},
+			err:   "ResourceNotFoundException: Requested resource not found",
+		},
+		{
+			name:  "stream without view type",
+			input: &types.UpdateTableInput{StreamSpecification: &types.StreamSpecification{StreamEnabled: aws.Bool(true)}},
+			err:   "StreamViewType must be specified when StreamEnabled is true",
+		},
+		{
+			name:  "disabled stream",
+			input: &types.UpdateTableInput{StreamSpecification: &types.StreamSpecification{StreamEnabled: aws.Bool(false)}},
+			err:   "Table already has no enabled stream",
+		},
+		{
+			name:  "invalid table class",
+			input: &types.UpdateTableInput{TableClass: types.ToString("GLACIER")},
+			err:   "Value 'GLACIER' at 'tableClass' failed to satisfy constraint",
+		},
+		{
+			name: "index with a definition in a rejected update",
+			input: &types.UpdateTableInput{
+				AttributeDefinitions: []*types.AttributeDefinition{{AttributeName: types.ToString("name"), AttributeType: types.ToString("S")}},
+				GlobalSecondaryIndexUpdates: []*types.GlobalSecondaryIndexUpdate{
+					{Create: &types.CreateGlobalSecondaryIndexAction{
+						IndexName: types.ToString("by-name"),
+						KeySchema: []*types.KeySchemaElement{{AttributeName: "name", KeyType: "HASH"}},
+					}},
+				},
+				TableClass: types.ToString("GLACIER"),
+			},
+			err: "Value 'GLACIER' at 'tableClass' failed to satisfy constraint",
+		},
+	}
+
+	for _, tt := range tests {
+		err := table.ApplyTableUpdate(tt.input)
+		c.Error(err, tt.name)
+		c.Contains(err.Error(), tt.err, tt.name)
+	}
+
+	d := table.Description(tableName)
+	c.Equal(BillingModePayPerRequest, types.StringValue(d.BillingModeSummary.BillingMode))
+	c.Len(d.GlobalSecondaryIndexes, 1)
+	c.Nil(d.StreamSpecification)
+	c.Equal(map[string]string{"id": "S", "type": "S"}, table.AttributesDef)
+}
+
+func TestApplyTableUpdateIndexValidation(t *testing.T) {
+	c := require.New(t)
+
+	throughput := &types.ProvisionedThroughput{ReadCapacityUnits: 1, WriteCapacityUnits: 1}
+	createIndex := func(pt *types.ProvisionedThroughput, schema ...*types.KeySchemaElement) []*types.GlobalSecondaryIndexUpdate {
+		return []*types.GlobalSecondaryIndexUpdate{{Create: &types.CreateGlobalSecondaryIndexAction{
+			IndexName:             types.ToString("by-id"),
+			KeySchema:             schema,
+			ProvisionedThroughput: pt,
+		}}}
+	}
+	idKey := &types.KeySchemaElement{AttributeName: "id", KeyType: "HASH"}
+
+	tests := []struct {
+		name        string
+		billingMode string
+		changes     []*types.GlobalSecondaryIndexUpdate
+		err         string
+	}{
+		{
+			name:        "create with throughput in on-demand table",
+			billingMode: BillingModePayPerRequest,
+			changes:     createIndex(throughput, idKey),
+			err:         "ProvisionedThroughput should not be specified for index: by-id when BillingMode is PAY_PER_REQUEST",
+		},
+		{
+			name:        "create without throughput in provisioned table",
+			billingMode: BillingModeProvisioned,
+			changes:     createIndex(nil, idKey),
+			err:         "No provisioned throughput specified for the global secondary index",
+		},
+		{
+			name:        "create with zero capacity units",
+			billingMode: BillingModeProvisioned,
+			changes:     createIndex(&types.ProvisionedThroughput{ReadCapacityUnits: 1}, idKey),
+			err:         "Value '0' at 'globalSecondaryIndexUpdates.create.provisionedThroughput.writeCapacityUnits' failed to satisfy constraint",
+		},
+		{
+			name:        "create without hash key",
+			billingMode: BillingModePayPerRequest,
+			changes:     createIndex(nil, &types.KeySchemaElement{AttributeName: "id", KeyType: "RANGE"}),
+			err:         "No Hash Key specified in schema",
+		},
+		{
+			name:        "delete missing index",
+			billingMode: BillingModeProvisioned,
+			changes:     []*types.GlobalSecondaryIndexUpdate{{Delete: &types.DeleteGlobalSecondaryIndexAction{IndexName: types.ToString("404")}}},
+			err:         "ResourceNotFoundException: Requested resource not found",
+		},
+		{
+			name:        "update missing index",
+			billingMode: BillingModeProvisioned,
+			changes:     []*types.GlobalSecondaryIndexUpdate{indexThroughputUpdate("404", 1)},
+			err:         "ResourceNotFoundException: Requested resource not found",
+		},
+		{
+			name:        "update without throughput",
+			billingMode: BillingModeProvisioned,
+			changes:     []*types.GlobalSecondaryIndexUpdate{{Update: &types.UpdateGlobalSecondaryIndexAction{IndexName: types.ToString("by-type")}}},
+			err:         "ProvisionedThroughput must be specified for index: by-type",
+		},
+		{
+			name:        "update with the current throughput",
+			billingMode: BillingModeProvisioned,
+			changes:     []*types.GlobalSecondaryIndexUpdate{indexThroughputUpdate("by-type", 5)},
+			err:         "The provisioned throughput for the index by-type will not change",
+		},
+	}
+
+	for _, tt := range tests {
+		table := createSettingsTable(c, tt.billingMode)
+
+		err := table.ApplyTableUpdate(&types.UpdateTableInput{GlobalSecondaryIndexUpdates: tt.changes})
+		c.Error(err, tt.name)
+		c.Contains(err.Error(), tt.err, tt.name)
+		c.Len(table.Indexes, 1, tt.name)
+	}
+}
+
+func TestThroughputDefaults(t *testing.T) {
+	c := require.New(t)
+
+	c.Nil(newThroughput(nil))
+	c.Nil(newThroughput(nil).describe().LastIncreaseDateTime)
+
+	table := NewTable(tableName)
+	table.Clock = nil
+
+	c.WithinDuration(time.Now(), table.now(), time.Minute)
+
+	stamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	table.Clock = func() time.Time { return stamp }
+
+	c.Equal(stamp, table.now())
+}
+
+func TestApplyTableUpdateBillingMode(t *testing.T) {
+	c := require.New(t)
+
+	table := createSettingsTable(c, BillingModePayPerRequest)
+
+	err := table.ApplyTableUpdate(&types.UpdateTableInput{
+		BillingMode:                 types.ToString(BillingModeProvisioned),
+		ProvisionedThroughput:       &types.ProvisionedThroughput{ReadCapacityUnits: 10, WriteCapacityUnits: 5},
+		GlobalSecondaryIndexUpdates: []*types.GlobalSecondaryIndexUpdate{indexThroughputUpdate("by-type", 2)},
+	})
+	c.NoError(err)
+
+	d := table.Description(tableName)
+	c.Equal(BillingModeProvisioned, types.StringValue(d.BillingModeSummary.BillingMode))
+	c.Equal(int64(10), d.ProvisionedThroughput.ReadCapacityUnits)
+	c.Equal(int64(2), d.GlobalSecondaryIndexes[0].ProvisionedThroughput.WriteCapacityUnits)
+
+	err = table.ApplyTableUpdate(&types.UpdateTableInput{BillingMode: types.ToString(BillingModePayPerRequest)})
+	c.NoError(err)
+
+	d = table.Description(tableName)
+	c.Equal(BillingModePayPerRequest, types.StringValue(d.BillingModeSummary.BillingMode))
+	c.True(d.BillingModeSummary.LastUpdateToPayPerRequestDateTime.After(d.CreationDateTime))
+	c.Equal(int64(0), d.ProvisionedThroughput.ReadCapacityUnits)
+	c.Equal(int64(0), d.GlobalSecondaryIndexes[0].ProvisionedThroughput.ReadCapacityUnits)
+}
+
+func TestApplyTableUpdateThroughput(t *testing.T) {
+	c := require.New(t)
+
+	table := createSettingsTable(c, BillingModeProvisioned)
+
+	err := table.ApplyTableUpdate(&types.UpdateTableInput{
+		ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: 5, WriteCapacityUnits: 5},
+	})
+	c.Contains(err.Error(), "The provisioned throughput for the table will not change")
+
+	err = table.ApplyTableUpdate(&types.UpdateTableInput{
+		GlobalSecondaryIndexUpdates: []*types.GlobalSecondaryIndexUpdate{indexThroughputUpdate("by-type", 5)},
+	})
+	c.Contains(err.Error(), "The provisioned throughput for the index by-type will not change")
+
+	err = table.ApplyTableUpdate(&types.UpdateTableInput{
+		ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: 10, WriteCapacityUnits: 5},
+	})
+	c.NoError(err)
+
+	d := table.Description(tableName)
+	c.NotNil(d.ProvisionedThroughput.LastIncreaseDateTime)
+	c.Nil(d.ProvisionedThroughput.LastDecreaseDateTime)
+
+	for units := int64(9); units > 5; units-- {
+		err = table.ApplyTableUpdate(&types.UpdateTableInput{
+			ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: units, WriteCapacityUnits: 5},
+		})
+		c.NoError(err)
+	}
+
+	d = table.Description(tableName)
+	c.NotNil(d.ProvisionedThroughput.LastDecreaseDateTime)
+	c.Equal(int64(4), d.ProvisionedThroughput.NumberOfDecreasesToday)
+
+	err = table.ApplyTableUpdate(&types.UpdateTableInput{
+		ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: 1, WriteCapacityUnits: 5},
+	})
+	c.Contains(err.Error(), "LimitExceededException: Subscriber limit exceeded: Provisioned throughput decreases are limited")
+
+	table.throughput.lastDecrease = time.Now().Add(-decreaseWait)
+
+	err = table.ApplyTableUpdate(&types.UpdateTableInput{
+		ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: 1, WriteCapacityUnits: 5},
+	})
+	c.NoError(err)
+
+	err = table.ApplyTableUpdate(&types.UpdateTableInput{
+		GlobalSecondaryIndexUpdates: []*types.GlobalSecondaryIndexUpdate{indexThroughputUpdate("by-type", 3)},
+	})
+	c.NoError(err)
+
+	d = table.Description(tableName)
+	c.Equal(int64(3), d.GlobalSecondaryIndexes[0].ProvisionedThroughput.ReadCapacityUnits)
+	c.NotNil(d.GlobalSecondaryIndexes[0].ProvisionedThroughput.LastDecreaseDateTime)
+}
+
+func TestApplyTableUpdateSettings(t *testing.T) {
+	c := require.New(t)
+
+	table := createSettingsTable(c, BillingModePayPerRequest)
+
+	err := table.ApplyTableUpdate(&types.UpdateTableInput{
+		StreamSpecification: &types.StreamSpecification{StreamEnabled: aws.Bool(true), StreamViewType: types.ToString("NEW_IMAGE")},
+	})
+	c.NoError(err)
+
+	d := table.Description(tableName)
+	c.True(*d.StreamSpecification.StreamEnabled)
+
+	label := d.LatestStreamLabel
+	c.NotEmpty(label)
+
+	err = table.ApplyTableUpdate(&types.UpdateTableInput{
+		StreamSpecification: &types.StreamSpecification{StreamEnabled: aws.Bool(true), StreamViewType: types.ToString("NEW_IMAGE")},
+	})
+	c.Contains(err.Error(), "Table already has an enabled stream: "+d.LatestStreamArn)
+
+	err = table.ApplyTableUpdate(&types.UpdateTableInput{
+		StreamSpecification: &types.StreamSpecification{StreamEnabled: aws.Bool(false)},
+	})
+	c.NoError(err)
+
+	d = table.Description(tableName)
+	c.Nil(d.StreamSpecification)
+	c.Equal(label, d.LatestStreamLabel)
+
+	time.Sleep(time.Millisecond)
+
+	err = table.ApplyTableUpdate(&types.UpdateTableInput{
+		StreamSpecification: &types.StreamSpecification{StreamEnabled: aws.Bool(true), StreamViewType: types.ToString("KEYS_ONLY")},
+	})
+	c.NoError(err)
+
+	d = table.Description(tableName)
+	c.NotEqual(label, d.LatestStreamLabel)
+
+	err = table.ApplyTableUpdate(&types.UpdateTableInput{
+		TableClass:                types.ToString(TableClassStandardInfrequentAccess),
+		DeletionProtectionEnabled: aws.Bool(true),
+		SSESpecification:          &types.SSESpecification{Enabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+
+	d = table.Description(tableName)
+	c.Equal(TableClassStandardInfrequentAccess, types.StringValue(d.TableClassSummary.TableClass))
+	c.NotNil(d.TableClassSummary.LastUpdateDateTime)
+	c.True(d.DeletionProtectionEnabled)
+	c.Equal("ENABLED", types.StringValue(d.SSEDescription.Status))
+}
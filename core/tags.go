@@ -0,0 +1,115 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	tagKeyMaxLength   = 128
+	tagValueMaxLength = 256
+	tagReservedPrefix = "aws:"
+
+	// revive:disable-next-line
+	tagKeyLengthMsg = "1 validation error detected: Value '%s' at 'tags.%d.member.key' failed to satisfy constraint: Member must have length less than or equal to 128 and greater than or equal to 1"
+	// revive:disable-next-line
+	tagValueLengthMsg = "1 validation error detected: Value '%s' at 'tags.%d.member.value' failed to satisfy constraint: Member must have length less than or equal to 256"
+	// revive:disable-next-line
+	untagKeyLengthMsg = "1 validation error detected: Value '%s' at 'tagKeys.%d.member' failed to satisfy constraint: Member must have length less than or equal to 128 and greater than or equal to 1"
+	// revive:disable-next-line
+	tagReservedPrefixMsg = "One or more parameter values were invalid: The tag key %s uses the reserved prefix aws:"
+	// revive:disable-next-line
+	tooManyTagsMsg = "The table %s would have %d tags, the limit of tags per resource is %d"
+)
+
+// TagResource adds the tags to the table, the tags with an existing key replace its value. The keys have
+// from 1 to 128 characters, the values up to 256 and the table keeps up to Limits.TagsPerResource tags
+func (t *Table) TagResource(tags []*types.Tag) error {
+	merged := make(map[string]string, len(t.tags)+len(tags))
+
+	for key, value := range t.tags {
+		merged[key] = value
+	}
+
+	for n, tag := range tags {
+		key, value := types.StringValue(tag.Key), types.StringValue(tag.Value)
+
+		if err := validateTag(n+1, key, value); err != nil {
+			return err
+		}
+
+		merged[key] = value
+	}
+
+	if len(merged) > t.Limits.TagsPerResource {
+		return types.NewError("LimitExceededException", fmt.Sprintf(tooManyTagsMsg, t.Name, len(merged), t.Limits.TagsPerResource), nil)
+	}
+
+	t.tags = merged
+
+	return nil
+}
+
+func validateTag(member int, key, value string) error {
+	if n := len([]rune(key)); n < 1 || n > tagKeyMaxLength {
+		return types.NewError("ValidationException", fmt.Sprintf(tagKeyLengthMsg, key, member), nil)
+	}
+
+	if len([]rune(value)) > tagValueMaxLength {
+		return types.NewError("ValidationException", fmt.Sprintf(tagValueLengthMsg, value, member), nil)
+	}
+
+	if strings.HasPrefix(key, tagReservedPrefix) {
+		return types.NewError("ValidationException", fmt.Sprintf(tagReservedPrefixMsg, key), nil)
+	}
+
+	return nil
+}
+
+// UntagResource removes the tags with the keys from the table, the keys without a tag are ignored
+func (t *Table) UntagResource(keys []*string) error {
+	for n, key := range keys {
+		if l := len([]rune(types.StringValue(key))); l < 1 || l > tagKeyMaxLength {
+			return types.NewError("ValidationException", fmt.Sprintf(untagKeyLengthMsg, types.StringValue(key), n+1), nil)
+		}
+	}
+
+	for _, key := range keys {
+		delete(t.tags, types.StringValue(key))
+	}
+
+	return nil
+}
+
+// ListTags returns a page of the tags of the table sorted by key, the page starts after the key of the next
+// token and has up to Limits.TagsPerPage tags. The key of the last tag of the page is returned when there
+// are more tags
+func (t *Table) ListTags(nextToken string) ([]*types.Tag, string) {
+	keys := make([]string, 0, len(t.tags))
+
+	for key := range t.tags {
+		if nextToken == "" || key > nextToken {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	last := ""
+
+	if t.Limits.TagsPerPage > 0 && len(keys) > t.Limits.TagsPerPage {
+		keys = keys[:t.Limits.TagsPerPage]
+		last = keys[len(keys)-1]
+	}
+
+	tags := make([]*types.Tag, 0, len(keys))
+
+	for _, key := range keys {
+		tags = append(tags, &types.Tag{Key: types.ToString(key), Value: types.ToString(t.tags[key])})
+	}
+
+	return tags, last
+}
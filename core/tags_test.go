@@ -0,0 +1,108 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func newTag(key, value string) *types.Tag {
+	return &types.Tag{Key: types.ToString(key), Value: types.ToString(value)}
+}
+
+func TestTagResource(t *testing.T) {
+	c := require.New(t)
+
+	table := createSettingsTable(c, BillingModePayPerRequest)
+
+	c.NoError(table.TagResource([]*types.Tag{newTag("team", "pokedex"), newTag("env", "test")}))
+	c.NoError(table.TagResource([]*types.Tag{newTag("env", "prod")}))
+
+	tags, last := table.ListTags("")
+	c.Equal([]*types.Tag{newTag("env", "prod"), newTag("team", "pokedex")}, tags)
+	c.Empty(last)
+
+	err := table.TagResource([]*types.Tag{newTag("owner", "oak"), newTag(strings.Repeat("k", 129), "")})
+	c.Error(err)
+	c.Contains(err.Error(), "at 'tags.2.member.key' failed to satisfy constraint")
+
+	err = table.TagResource([]*types.Tag{newTag("owner", strings.Repeat("v", 257))})
+	c.Error(err)
+	c.Contains(err.Error(), "at 'tags.1.member.value' failed to satisfy constraint")
+
+	err = table.TagResource([]*types.Tag{newTag("aws:cloudformation:stack-name", "pokemons")})
+	c.Error(err)
+	c.Contains(err.Error(), "uses the reserved prefix aws:")
+
+	tags, _ = table.ListTags("")
+	c.Len(tags, 2)
+
+	c.NoError(table.UntagResource([]*string{types.ToString("env"), types.ToString("unknown")}))
+
+	tags, _ = table.ListTags("")
+	c.Equal([]*types.Tag{newTag("team", "pokedex")}, tags)
+
+	err = table.UntagResource([]*string{types.ToString("")})
+	c.Error(err)
+	c.Contains(err.Error(), "at 'tagKeys.1.member' failed to satisfy constraint")
+}
+
+func TestTagResourceLimits(t *testing.T) {
+	c := require.New(t)
+
+	table := createSettingsTable(c, BillingModePayPerRequest)
+
+	tags := make([]*types.Tag, 0, 50)
+	for n := 0; n < 50; n++ {
+		tags = append(tags, newTag(fmt.Sprintf("key-%02d", n), "value"))
+	}
+
+	c.NoError(table.TagResource(tags))
+
+	err := table.TagResource([]*types.Tag{newTag("key-50", "value")})
+	c.Error(err)
+	c.Contains(err.Error(), "LimitExceededException")
+
+	c.NoError(table.TagResource([]*types.Tag{newTag("key-00", "replaced")}))
+
+	page, last := table.ListTags("")
+	c.Len(page, 10)
+	c.Equal("replaced", types.StringValue(page[0].Value))
+	c.Equal("key-09", last)
+
+	count := len(page)
+
+	for last != "" {
+		page, last = table.ListTags(last)
+		count += len(page)
+	}
+
+	c.Equal(50, count)
+}
+
+func TestCreateTableTags(t *testing.T) {
+	c := require.New(t)
+
+	table := NewTable(tableName)
+	table.AttributesDef = map[string]string{"id": "S"}
+	table.BillingMode = types.ToString(BillingModePayPerRequest)
+
+	input := &types.CreateTableInput{
+		KeySchema: []*types.KeySchemaElement{{AttributeName: "id", KeyType: "HASH"}},
+		Tags:      []*types.Tag{newTag("", "empty")},
+	}
+
+	err := table.CreatePrimaryIndex(input)
+	c.Error(err)
+	c.Contains(err.Error(), "at 'tags.1.member.key' failed to satisfy constraint")
+
+	input.Tags = []*types.Tag{newTag("team", "pokedex")}
+
+	c.NoError(table.CreatePrimaryIndex(input))
+
+	tags, _ := table.ListTags("")
+	c.Equal([]*types.Tag{newTag("team", "pokedex")}, tags)
+}
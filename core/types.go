@@ -17,6 +17,9 @@ var (
 
 	// ErrInvalidAtrributeValue when the attributte value is invalid
 	ErrInvalidAtrributeValue = errors.New("invalid attribute value type")
+
+	// ErrItemMutated when a stored item was modified outside of the table while the mutation detection is active
+	ErrItemMutated = errors.New("minidyn: stored item mutated")
 )
 
 const (
@@ -24,13 +27,14 @@ const (
 	PrimaryIndexName = ""
 )
 
+// copyItem returns a deep copy of the item, it is never nil
 func copyItem(item map[string]*types.Item) map[string]*types.Item {
-	copy := map[string]*types.Item{}
+	out := make(map[string]*types.Item, len(item))
 	for key, val := range item {
-		copy[key] = val
+		out[key] = val.Copy()
 	}
 
-	return copy
+	return out
 }
 
 func mapSliceType(t reflect.Type) string {
@@ -177,3 +177,34 @@ func BenchmarkMapToDynamoDBType(b *testing.B) {
 		c.Equal("L", r)
 	}
 }
+
+func TestCopyItemIsDeep(t *testing.T) {
+	c := require.New(t)
+
+	item := map[string]*types.Item{
+		"id":    {S: types.ToString("001")},
+		"moves": {SS: []*string{types.ToString("tackle")}},
+		"stats": {M: map[string]*types.Item{
+			"hp": {N: types.ToString("45")},
+		}},
+		"evolutions": {L: []*types.Item{{S: types.ToString("Ivysaur")}}},
+		"sprite":     {B: []byte("png")},
+	}
+
+	copied := copyItem(item)
+	c.Equal(item, copied)
+
+	*copied["id"].S = "002"
+	*copied["moves"].SS[0] = "growl"
+	copied["stats"].M["hp"] = &types.Item{N: types.ToString("1")}
+	copied["evolutions"].L[0].S = types.ToString("Venusaur")
+	copied["sprite"].B[0] = 'j'
+
+	c.Equal("001", types.StringValue(item["id"].S))
+	c.Equal("tackle", types.StringValue(item["moves"].SS[0]))
+	c.Equal("45", types.StringValue(item["stats"].M["hp"].N))
+	c.Equal("Ivysaur", types.StringValue(item["evolutions"].L[0].S))
+	c.Equal([]byte("png"), item["sprite"].B)
+
+	c.Equal(map[string]*types.Item{}, copyItem(nil))
+}
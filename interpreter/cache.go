@@ -0,0 +1,125 @@
+package interpreter
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/truora/minidyn/interpreter/language"
+)
+
+// maxCachedExpressions bounds the number of parsed expressions kept by each cache,
+// the cache is reset when it is full
+const maxCachedExpressions = 4096
+
+// parser is the state of a language parser after parsing an expression
+type parser interface {
+	Err() error
+	Errors() []string
+	IsUnsupportedExpression() bool
+}
+
+type parsedExpression struct {
+	node language.Node
+	err  error
+}
+
+// expressionCache keeps the parsed expressions by their text, the parsed nodes are never modified
+// by the evaluator so they can be shared by concurrent evaluations
+type expressionCache struct {
+	mu      sync.RWMutex
+	entries map[string]parsedExpression
+	parse   func(expression string) parsedExpression
+}
+
+func newExpressionCache(parse func(expression string) parsedExpression) *expressionCache {
+	return &expressionCache{entries: map[string]parsedExpression{}, parse: parse}
+}
+
+func (c *expressionCache) get(expression string) parsedExpression {
+	c.mu.RLock()
+	parsed, ok := c.entries[expression]
+	c.mu.RUnlock()
+
+	if ok {
+		return parsed
+	}
+
+	parsed = c.parse(expression)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxCachedExpressions {
+		c.entries = map[string]parsedExpression{}
+	}
+
+	c.entries[expression] = parsed
+
+	return parsed
+}
+
+func (c *expressionCache) len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.entries)
+}
+
+var (
+	conditionCache = newExpressionCache(func(expression string) parsedExpression {
+		p := language.NewParser(language.NewLexer(expression))
+		conditional := p.ParseConditionalExpression()
+
+		if len(p.Errors()) != 0 {
+			return parsedExpression{err: parseError(p)}
+		}
+
+		if conditional.Expression == nil {
+			return parsedExpression{err: newExpressionError(ErrSyntaxError, "The expression can not be empty;")}
+		}
+
+		return parsedExpression{node: conditional}
+	})
+	updateCache = newExpressionCache(func(expression string) parsedExpression {
+		p := language.NewUpdateParser(language.NewLexer(expression))
+		update := p.ParseUpdateExpression()
+
+		if len(p.Errors()) != 0 {
+			return parsedExpression{err: parseError(p)}
+		}
+
+		return parsedExpression{node: update}
+	})
+)
+
+// parseError returns the error of a failed parse, the expressions not supported yet
+// are reported as ErrUnsupportedFeature with every error found
+func parseError(p parser) error {
+	if p.IsUnsupportedExpression() {
+		return newExpressionError(ErrUnsupportedFeature, strings.Join(p.Errors(), "\n"))
+	}
+
+	return newExpressionError(ErrSyntaxError, p.Err().Error())
+}
+
+// ParseCondition returns the parsed key, filter or condition expression,
+// the expressions are parsed once and shared by the following calls
+func ParseCondition(expression string) (*language.ConditionalExpression, error) {
+	parsed := conditionCache.get(expression)
+	if parsed.err != nil {
+		return nil, parsed.err
+	}
+
+	return parsed.node.(*language.ConditionalExpression), nil
+}
+
+// ParseUpdate returns the parsed update expression,
+// the expressions are parsed once and shared by the following calls
+func ParseUpdate(expression string) (*language.UpdateStatement, error) {
+	parsed := updateCache.get(expression)
+	if parsed.err != nil {
+		return nil, parsed.err
+	}
+
+	return parsed.node.(*language.UpdateStatement), nil
+}
@@ -0,0 +1,129 @@
+package interpreter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseConditionIsCached(t *testing.T) {
+	first, err := ParseCondition("a = :a AND b > :b")
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	second, err := ParseCondition("a = :a AND b > :b")
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	if first != second {
+		t.Errorf("expected the cached expression to be reused")
+	}
+
+	_, err = ParseCondition("a = ")
+	if !errors.Is(err, ErrSyntaxError) {
+		t.Errorf("unexpected error; expected=%v, got=%v", ErrSyntaxError, err)
+	}
+
+	_, errAgain := ParseCondition("a = ")
+	if errAgain != err {
+		t.Errorf("expected the parse error to be cached")
+	}
+}
+
+func TestParseUpdateIsCached(t *testing.T) {
+	first, err := ParseUpdate("SET a = :a")
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	second, _ := ParseUpdate("SET a = :a")
+	if first != second {
+		t.Errorf("expected the cached expression to be reused")
+	}
+
+	_, err = ParseUpdate("SET a = ")
+	if !errors.Is(err, ErrSyntaxError) {
+		t.Errorf("unexpected error; expected=%v, got=%v", ErrSyntaxError, err)
+	}
+
+	_, errAgain := ParseUpdate("SET a = ")
+	if errAgain != err {
+		t.Errorf("expected the parse error to be cached")
+	}
+}
+
+func TestParseEmptyCondition(t *testing.T) {
+	_, err := ParseCondition("")
+	if !errors.Is(err, ErrSyntaxError) {
+		t.Errorf("unexpected error; expected=%v, got=%v", ErrSyntaxError, err)
+	}
+
+	var exprErr *ExpressionError
+	if !errors.As(err, &exprErr) || exprErr.Message != "The expression can not be empty;" {
+		t.Errorf("unexpected error message %v", err)
+	}
+}
+
+type fakeParser struct {
+	errors      []string
+	unsupported bool
+}
+
+func (p fakeParser) Err() error {
+	return errors.New(p.errors[0])
+}
+
+func (p fakeParser) Errors() []string {
+	return p.errors
+}
+
+func (p fakeParser) IsUnsupportedExpression() bool {
+	return p.unsupported
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		parser   fakeParser
+		expected error
+		message  string
+	}{
+		{fakeParser{errors: []string{"first", "second"}}, ErrSyntaxError, "first"},
+		{fakeParser{errors: []string{"first", "second"}, unsupported: true}, ErrUnsupportedFeature, "first\nsecond"},
+	}
+
+	for _, tt := range tests {
+		err := parseError(tt.parser)
+		if !errors.Is(err, tt.expected) {
+			t.Errorf("unexpected error; expected=%v, got=%v", tt.expected, err)
+		}
+
+		var exprErr *ExpressionError
+		if !errors.As(err, &exprErr) || exprErr.Message != tt.message {
+			t.Errorf("unexpected error message; expected=%q, got=%v", tt.message, err)
+		}
+	}
+}
+
+func TestExpressionCacheIsBounded(t *testing.T) {
+	parsed := 0
+	cache := newExpressionCache(func(expression string) parsedExpression {
+		parsed++
+
+		return parsedExpression{}
+	})
+
+	for i := 0; i <= maxCachedExpressions; i++ {
+		cache.get(string(rune(i)))
+	}
+
+	if cache.len() != 1 {
+		t.Errorf("expected the full cache to be reset, got %d entries", cache.len())
+	}
+
+	cache.get(string(rune(maxCachedExpressions)))
+
+	if parsed != maxCachedExpressions+1 {
+		t.Errorf("unexpected number of parsed expressions; expected=%d, got=%d", maxCachedExpressions+1, parsed)
+	}
+}
@@ -0,0 +1,121 @@
+package interpreter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/truora/minidyn/types"
+)
+
+// Mismatch describes an expression with a native override that the language interpreter
+// evaluates differently, it is reported by the tables in differential mode
+type Mismatch struct {
+	TableName      string
+	ExpressionType ExpressionType
+	Expression     string
+	// Item is the evaluated item, before the update in the update expressions
+	Item map[string]*types.Item
+	// Native is the outcome of the native interpreter
+	Native string
+	// Language is the outcome of the language interpreter
+	Language string
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf(
+		"%s expression %q in table %q differs for item %s; native=%s, language=%s",
+		m.ExpressionType,
+		m.Expression,
+		m.TableName,
+		FormatItem(m.Item),
+		m.Native,
+		m.Language,
+	)
+}
+
+// MismatchReporter receives the differences found by the differential mode
+type MismatchReporter func(Mismatch)
+
+// FormatItem returns a readable representation of the item with its attributes sorted by name
+func FormatItem(item map[string]*types.Item) string {
+	names := make([]string, 0, len(item))
+	for name := range item {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	attrs := make([]string, 0, len(names))
+	for _, name := range names {
+		attrs = append(attrs, fmt.Sprintf("%q: %s", name, formatAttribute(item[name])))
+	}
+
+	return "{" + strings.Join(attrs, ", ") + "}"
+}
+
+func formatAttribute(v *types.Item) string {
+	if v == nil {
+		return "nil"
+	}
+
+	if scalar, ok := formatScalar(v); ok {
+		return scalar
+	}
+
+	return formatCollection(v)
+}
+
+// formatScalar returns the representation of the strings, numbers, booleans, nulls and binaries
+func formatScalar(v *types.Item) (string, bool) {
+	switch {
+	case v.S != nil:
+		return fmt.Sprintf("{S: %q}", *v.S), true
+	case v.N != nil:
+		return fmt.Sprintf("{N: %s}", *v.N), true
+	case v.BOOL != nil:
+		return fmt.Sprintf("{BOOL: %t}", *v.BOOL), true
+	case v.NULL != nil:
+		return "{NULL: true}", true
+	case v.B != nil:
+		return fmt.Sprintf("{B: %x}", v.B), true
+	}
+
+	return "", false
+}
+
+// formatCollection returns the representation of the sets, lists and maps, it is {} for an empty attribute
+func formatCollection(v *types.Item) string {
+	switch {
+	case v.SS != nil:
+		return fmt.Sprintf("{SS: %q}", stringValues(v.SS))
+	case v.NS != nil:
+		return fmt.Sprintf("{NS: %s}", stringValues(v.NS))
+	case v.BS != nil:
+		return fmt.Sprintf("{BS: %x}", v.BS)
+	case v.L != nil:
+		return "{L: [" + formatList(v.L) + "]}"
+	case v.M != nil:
+		return "{M: " + FormatItem(v.M) + "}"
+	}
+
+	return "{}"
+}
+
+func formatList(l []*types.Item) string {
+	items := make([]string, 0, len(l))
+	for _, item := range l {
+		items = append(items, formatAttribute(item))
+	}
+
+	return strings.Join(items, ", ")
+}
+
+func stringValues(values []*string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		out = append(out, types.StringValue(v))
+	}
+
+	return out
+}
@@ -0,0 +1,60 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/truora/minidyn/types"
+)
+
+func TestFormatItem(t *testing.T) {
+	tests := []struct {
+		attr     *types.Item
+		expected string
+	}{
+		{attr: nil, expected: "nil"},
+		{attr: &types.Item{}, expected: "{}"},
+		{attr: &types.Item{S: types.ToString("a \"b\"")}, expected: `{S: "a \"b\""}`},
+		{attr: &types.Item{N: types.ToString("1.5")}, expected: "{N: 1.5}"},
+		{attr: &types.Item{BOOL: types.ToBool(false)}, expected: "{BOOL: false}"},
+		{attr: &types.Item{NULL: types.ToBool(true)}, expected: "{NULL: true}"},
+		{attr: &types.Item{B: []byte("hi")}, expected: "{B: 6869}"},
+		{attr: &types.Item{SS: []*string{types.ToString("a"), types.ToString("b")}}, expected: `{SS: ["a" "b"]}`},
+		{attr: &types.Item{NS: []*string{types.ToString("1"), types.ToString("2")}}, expected: "{NS: [1 2]}"},
+		{attr: &types.Item{BS: [][]byte{[]byte("a"), []byte("b")}}, expected: "{BS: [61 62]}"},
+		{attr: &types.Item{L: []*types.Item{{N: types.ToString("1")}, nil}}, expected: "{L: [{N: 1}, nil]}"},
+		{attr: &types.Item{L: []*types.Item{}}, expected: "{L: []}"},
+		{
+			attr:     &types.Item{M: map[string]*types.Item{"b": {S: types.ToString("x")}, "a": {N: types.ToString("2")}}},
+			expected: `{M: {"a": {N: 2}, "b": {S: "x"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		formatted := FormatItem(map[string]*types.Item{"attr": tt.attr})
+
+		expected := `{"attr": ` + tt.expected + "}"
+		if formatted != expected {
+			t.Errorf("expected %s, got %s", expected, formatted)
+		}
+	}
+
+	if formatted := FormatItem(nil); formatted != "{}" {
+		t.Errorf("expected {}, got %s", formatted)
+	}
+}
+
+func TestMismatchString(t *testing.T) {
+	m := Mismatch{
+		TableName:      "pokemons",
+		ExpressionType: ExpressionTypeConditional,
+		Expression:     "attribute_exists(#id)",
+		Item:           map[string]*types.Item{"id": {S: types.ToString("001")}},
+		Native:         "true",
+		Language:       "false",
+	}
+
+	expected := `conditional expression "attribute_exists(#id)" in table "pokemons" differs for item {"id": {S: "001"}}; native=true, language=false`
+	if m.String() != expected {
+		t.Errorf("expected %s, got %s", expected, m.String())
+	}
+}
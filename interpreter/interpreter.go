@@ -11,8 +11,31 @@ var (
 	ErrSyntaxError = errors.New("syntax error")
 	// ErrUnsupportedFeature when an expression or attribute type in not yet supported by the interpreter
 	ErrUnsupportedFeature = errors.New("unsupported expression or attribute type")
+	// ErrDuplicateRegistration when a native function is registered for an expression equivalent to a registered one
+	ErrDuplicateRegistration = errors.New("duplicate native expression")
 )
 
+// ExpressionError is returned when an expression can not be parsed or evaluated,
+// it wraps ErrSyntaxError or ErrUnsupportedFeature
+type ExpressionError struct {
+	err error
+	// Message describes the error with the wording used by DynamoDB when possible
+	Message string
+}
+
+func newExpressionError(err error, msg string) *ExpressionError {
+	return &ExpressionError{err: err, Message: msg}
+}
+
+func (e *ExpressionError) Error() string {
+	return e.err.Error() + ": " + e.Message
+}
+
+// Unwrap returns the wrapped error
+func (e *ExpressionError) Unwrap() error {
+	return e.err
+}
+
 // ExpressionType type of the evaluated expression
 type ExpressionType string
 
@@ -23,8 +46,25 @@ const (
 	ExpressionTypeFilter ExpressionType = "filter"
 	// ExpressionTypeConditional expression used for conditional writes
 	ExpressionTypeConditional ExpressionType = "conditional"
+	// ExpressionTypeUpdate expression used to modify items
+	ExpressionTypeUpdate ExpressionType = "update"
+	// ExpressionTypeProjection expression used to select the returned attributes
+	ExpressionTypeProjection ExpressionType = "projection"
 )
 
+var parameterNames = map[ExpressionType]string{
+	ExpressionTypeKey:         "KeyConditionExpression",
+	ExpressionTypeFilter:      "FilterExpression",
+	ExpressionTypeConditional: "ConditionExpression",
+	ExpressionTypeUpdate:      "UpdateExpression",
+	ExpressionTypeProjection:  "ProjectionExpression",
+}
+
+// ParameterName returns the name of the request parameter that holds this kind of expression
+func (t ExpressionType) ParameterName() string {
+	return parameterNames[t]
+}
+
 // MatchInput parameters to use match function
 type MatchInput struct {
 	TableName      string
@@ -33,6 +73,8 @@ type MatchInput struct {
 	Item           map[string]*types.Item
 	Attributes     map[string]*types.Item
 	Aliases        map[string]string
+	// Key is the primary key of the item, it is only used by the traces
+	Key map[string]*types.Item
 }
 
 // UpdateInput parameters to use Update function
@@ -42,6 +84,8 @@ type UpdateInput struct {
 	Item       map[string]*types.Item
 	Attributes map[string]*types.Item
 	Aliases    map[string]string
+	// Key is the primary key of the item, it is only used by the traces
+	Key map[string]*types.Item
 }
 
 // Interpreter types expression interpreter interface
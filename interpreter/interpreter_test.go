@@ -0,0 +1,26 @@
+package interpreter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExpressionError(t *testing.T) {
+	err := newExpressionError(ErrUnsupportedFeature, "the SET expression is not supported yet")
+
+	expected := "unsupported expression or attribute type: the SET expression is not supported yet"
+	if err.Error() != expected {
+		t.Errorf("unexpected message; expected=%q, got=%q", expected, err.Error())
+	}
+
+	if !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("expected the error to wrap %v", ErrUnsupportedFeature)
+	}
+
+	_, parseErr := ParseCondition("a = ")
+
+	expected = "syntax error: Syntax error; token: \"<EOF>\", near: \"=\""
+	if parseErr == nil || parseErr.Error() != expected {
+		t.Errorf("unexpected message; expected=%q, got=%v", expected, parseErr)
+	}
+}
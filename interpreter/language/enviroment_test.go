@@ -34,3 +34,88 @@ func TestApply(t *testing.T) {
 		t.Errorf("Expected 3 items, got %d", len(item))
 	}
 }
+
+func TestApplyRemoved(t *testing.T) {
+	item := map[string]*types.Item{
+		"a": {S: types.ToString("a")},
+		"b": {S: types.ToString("b")},
+	}
+
+	env := NewEnvironment()
+
+	err := env.AddAttributes(item)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	env.Remove("a")
+	env.Apply(item, map[string]string{}, map[string]bool{})
+
+	if _, ok := item["a"]; ok {
+		t.Errorf("expected attribute a to be removed")
+	}
+
+	if len(item) != 1 {
+		t.Errorf("Expected 1 item, got %d", len(item))
+	}
+}
+
+func TestLazyAttributes(t *testing.T) {
+	item := map[string]*types.Item{
+		"a":   {S: types.ToString("a")},
+		"n":   {N: types.ToString("not a number")},
+		"rmv": {S: types.ToString("rmv")},
+	}
+
+	env := NewEnvironment()
+	env.AddLazyAttributes(item)
+
+	if len(env.store) != 0 {
+		t.Fatalf("expected no converted attributes, got %d", len(env.store))
+	}
+
+	obj := env.Get("a")
+	if obj.Inspect() != "a" {
+		t.Errorf("unexpected value. got=%v, want=%v", obj.Inspect(), "a")
+	}
+
+	if len(env.store) != 1 || env.Err() != nil {
+		t.Errorf("expected only the read attribute to be converted, got %d", len(env.store))
+	}
+
+	env.Remove("rmv")
+	env.Apply(item, map[string]string{}, map[string]bool{})
+
+	if _, ok := item["rmv"]; ok {
+		t.Errorf("expected attribute rmv to be removed")
+	}
+
+	obj = env.Get("n")
+	if !isError(obj) || env.Err() == nil {
+		t.Errorf("expected a conversion error, got=%v", obj.Inspect())
+	}
+}
+
+func TestLazyAttributesKeepStoredValues(t *testing.T) {
+	env := NewEnvironment()
+	env.Set("a", &String{Value: "stored"})
+
+	env.AddLazyAttributes(map[string]*types.Item{
+		"a": {S: types.ToString("lazy")},
+		"b": {S: types.ToString("b")},
+	})
+
+	if len(env.pending) != 1 {
+		t.Fatalf("expected only the missing attribute to be pending, got %d", len(env.pending))
+	}
+
+	obj := env.Get("a")
+	if obj.Inspect() != "stored" {
+		t.Errorf("unexpected value. got=%v, want=%v", obj.Inspect(), "stored")
+	}
+
+	obj = env.Get("b")
+	if obj.Inspect() != "b" {
+		t.Errorf("unexpected value. got=%v, want=%v", obj.Inspect(), "b")
+	}
+}
@@ -10,13 +10,22 @@ import (
 // Environment represents the execution enviroment
 type Environment struct {
 	store     map[string]Object
+	pending   map[string]*types.Item
 	Aliases   map[string]string
 	toCompact []Object
+	removed   map[string]bool
+	err       error
 }
 
 // NewEnvironment creates a new enviroment
 func NewEnvironment() *Environment {
-	return &Environment{store: map[string]Object{}, Aliases: map[string]string{}, toCompact: []Object{}}
+	return &Environment{
+		store:     map[string]Object{},
+		pending:   map[string]*types.Item{},
+		Aliases:   map[string]string{},
+		toCompact: []Object{},
+		removed:   map[string]bool{},
+	}
 }
 
 // AddAttributes adds the types attributes to the environment
@@ -33,6 +42,48 @@ func (e *Environment) AddAttributes(attributes map[string]*types.Item) error {
 	return nil
 }
 
+// AddLazyAttributes adds the types attributes to the environment,
+// the attributes are converted to objects only when the evaluated expression reads them
+func (e *Environment) AddLazyAttributes(attributes map[string]*types.Item) {
+	for name, value := range attributes {
+		if _, ok := e.store[name]; ok {
+			continue
+		}
+
+		e.pending[name] = value
+	}
+}
+
+// Err returns the error found converting the lazy attributes
+func (e *Environment) Err() error {
+	return e.err
+}
+
+func (e *Environment) lookup(name string) (Object, bool) {
+	obj, ok := e.store[name]
+	if ok {
+		return obj, true
+	}
+
+	val, ok := e.pending[name]
+	if !ok {
+		return nil, false
+	}
+
+	delete(e.pending, name)
+
+	obj, err := MapToObject(val)
+	if err != nil {
+		e.err = err
+
+		return newError("%s", err.Error()), true
+	}
+
+	e.store[name] = obj
+
+	return obj, true
+}
+
 // Get gets the value of the variable in the environment
 func (e *Environment) Get(name string) Object {
 	n := name
@@ -41,7 +92,7 @@ func (e *Environment) Get(name string) Object {
 		n = alias
 	}
 
-	obj, ok := e.store[n]
+	obj, ok := e.lookup(n)
 	if ok {
 		return obj
 	}
@@ -73,7 +124,7 @@ func (e *Environment) getFromIndexes(names []string, size int) Object {
 
 	ok := false
 
-	obj, ok = e.store[names[0]]
+	obj, ok = e.lookup(names[0])
 	if !ok {
 		return UNDEFINED
 	}
@@ -115,6 +166,8 @@ func (e *Environment) Set(name string, val Object) Object {
 	}
 
 	e.store[n] = val
+	delete(e.pending, n)
+	delete(e.removed, n)
 
 	return val
 }
@@ -127,11 +180,13 @@ func (e *Environment) Remove(name string) {
 		n = alias
 	}
 
-	_, ok := e.store[n]
-	if ok {
-		delete(e.store, n)
+	_, stored := e.store[n]
+	_, pending := e.pending[n]
 
-		return
+	if stored || pending {
+		delete(e.store, n)
+		delete(e.pending, n)
+		e.removed[n] = true
 	}
 }
 
@@ -166,12 +221,20 @@ func (e *Environment) Apply(item map[string]*types.Item, aliases map[string]stri
 		vItem := v.ToDynamoDB()
 		item[k] = &vItem
 	}
+
+	for k := range e.removed {
+		delete(item, k)
+	}
 }
 
 // Set assigns the value of the variable in the environment
 func (e *Environment) String() string {
 	out := []string{}
 
+	for n := range e.pending {
+		e.lookup(n)
+	}
+
 	for n, v := range e.store {
 		out = append(out, n+" => "+v.Inspect())
 	}
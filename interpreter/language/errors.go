@@ -0,0 +1,31 @@
+package language
+
+import (
+	"fmt"
+)
+
+// SyntaxError is an unexpected token found by the parser,
+// the message matches the one returned by DynamoDB
+type SyntaxError struct {
+	// Token is the unexpected token, <EOF> when the expression ended too early
+	Token string
+	// Near is the expression text around the unexpected token
+	Near string
+	// Pos is the offset of the unexpected token in the expression
+	Pos int
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("Syntax error; token: %q, near: %q", e.Token, e.Near)
+}
+
+// ReservedKeywordError is returned when a reserved word is used as an attribute name
+type ReservedKeywordError struct {
+	Keyword string
+	// Pos is the offset of the keyword in the expression
+	Pos int
+}
+
+func (e *ReservedKeywordError) Error() string {
+	return "Attribute name is a reserved keyword; reserved keyword: " + e.Keyword
+}
@@ -2,6 +2,7 @@ package language
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -30,7 +31,7 @@ func Eval(n Node, env *Environment) Object {
 	case *CallExpression:
 		return evalFunctionCall(node, env)
 	case *Identifier:
-		return evalIdentifier(node, env, true)
+		return evalIdentifier(node, env)
 	}
 
 	return newError("unsupported expression: %s", n.String())
@@ -53,7 +54,7 @@ func EvalUpdate(n Node, env *Environment) Object {
 	case *CallExpression:
 		return evalUpdateFunctionCall(node, env)
 	case *Identifier:
-		return evalIdentifier(node, env, true)
+		return evalIdentifier(node, env)
 	}
 
 	return newError("unsupported expression: %s", n.String())
@@ -63,6 +64,28 @@ func newError(format string, a ...interface{}) *Error {
 	return &Error{Message: fmt.Sprintf(format, a...)}
 }
 
+func newTypeMismatchError(format string, a ...interface{}) *Error {
+	return &Error{Message: fmt.Sprintf(format, a...), typeMismatch: true}
+}
+
+func newUnsupportedError(format string, a ...interface{}) *Error {
+	return &Error{Message: fmt.Sprintf(format, a...), Unsupported: true}
+}
+
+func isTypeMismatch(obj Object) bool {
+	err, ok := obj.(*Error)
+
+	return ok && err.typeMismatch
+}
+
+// isValuePlaceholder checks if the operand is an expression attribute value,
+// the rest of the operands are attribute paths or functions
+func isValuePlaceholder(exp Expression) bool {
+	identifier, ok := exp.(*Identifier)
+
+	return ok && strings.HasPrefix(identifier.Value, ":")
+}
+
 func isError(obj Object) bool {
 	if obj != nil {
 		return obj.Type() == ObjectTypeError
@@ -177,7 +200,12 @@ func evalInfixParts(node *InfixExpression, env *Environment) Object {
 		return right
 	}
 
-	return evalInfixExpression(node.Operator, left, right)
+	result := evalInfixExpression(node.Operator, left, right)
+	if isTypeMismatch(result) && (!isValuePlaceholder(node.Left) || !isValuePlaceholder(node.Right)) {
+		return FALSE
+	}
+
+	return result
 }
 
 func checkSyntaxInfixParts(node *InfixExpression) Object {
@@ -211,9 +239,9 @@ func evalInfixExpression(operator string, left, right Object) Object {
 	case operator == "<>":
 		return nativeBoolToBooleanObject(!equalObject(left, right))
 	case !matchTypes(left.Type(), left, right):
-		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeMismatchError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeMismatchError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
@@ -243,6 +271,11 @@ func evalComparableInfixExpression(operator string, left, right Object) Object {
 		return evalNullInfixExpression(operator, left, right)
 	}
 
+	// the values of different types are never equal nor ordered
+	if left.Type() != right.Type() {
+		return nativeBoolToBooleanObject(operator == "<>")
+	}
+
 	switch left.Type() {
 	case ObjectTypeNumber:
 		return evalNumberInfixExpression(operator, left, right)
@@ -351,19 +384,9 @@ func equalObject(left, right Object) bool {
 	return reflect.DeepEqual(left, right)
 }
 
-func evalIdentifier(node *Identifier, env *Environment, toplevel bool) Object {
-	attributeName := strings.ToUpper(node.Token.Literal)
-
-	if toplevel && IsReservedWord(attributeName) {
-		return newError(fmt.Sprintf("reserved word %s found in expression", attributeName))
-	}
-
-	val := env.Get(node.Value)
-	if isError(val) {
-		return val
-	}
-
-	return val
+// evalIdentifier returns the value of the attribute, the reserved words are rejected by the parser
+func evalIdentifier(node *Identifier, env *Environment) Object {
+	return env.Get(node.Value)
 }
 
 func evalIndex(node *IndexExpression, env *Environment) Object {
@@ -442,6 +465,10 @@ func (i indexAccessor) Set(container, val Object) Object {
 		return UNDEFINED
 	}
 
+	if isUndefined(container) {
+		return newError(invalidDocumentPathMsg)
+	}
+
 	return newError("index assignation for %q type is not supported", container.Type())
 }
 
@@ -471,6 +498,10 @@ func (i indexAccessor) Remove(container Object) Object {
 		return UNDEFINED
 	}
 
+	if isUndefined(container) {
+		return newError(invalidDocumentPathMsg)
+	}
+
 	return newError("index removal for %q type is not supported", container.Type())
 }
 
@@ -508,7 +539,7 @@ func evalIndexObj(identifierExpression Expression, env *Environment) (Object, Ob
 		return nil, newError("identifier expected: got %q", identifierExpression.String())
 	}
 
-	obj := evalIdentifier(identifier, env, true)
+	obj := evalIdentifier(identifier, env)
 	switch obj.(type) {
 	case *List:
 		return obj, nil
@@ -551,7 +582,7 @@ func evalListIndexValue(node *Identifier, env *Environment) (int64, Object) {
 		return int64(n), nil
 	}
 
-	obj := evalIdentifier(node, env, true)
+	obj := evalIdentifier(node, env)
 	if isError(obj) {
 		return 0, obj
 	}
@@ -565,7 +596,7 @@ func evalListIndexValue(node *Identifier, env *Environment) (int64, Object) {
 }
 
 func evalMapIndexValue(node *Identifier, env *Environment) (string, Object) {
-	obj := evalIdentifier(node, env, false)
+	obj := evalIdentifier(node, env)
 	if isError(obj) {
 		return "", obj
 	}
@@ -584,36 +615,55 @@ func evalMapIndexValue(node *Identifier, env *Environment) (string, Object) {
 }
 
 func evalBetween(node *BetweenExpression, env *Environment) Object {
-	val := evalBetweenOperand(node.Left, env)
-	if isError(val) {
-		return val
+	operands := make([]Object, 0, len(node.Range)+1)
+	undefined := false
+
+	for _, exp := range []Expression{node.Left, node.Range[0], node.Range[1]} {
+		obj := evalBetweenOperand(exp, env)
+		if isError(obj) {
+			return obj
+		}
+
+		undefined = undefined || isUndefined(obj)
+		operands = append(operands, obj)
 	}
 
-	min := evalBetweenOperand(node.Range[0], env)
-	if isError(min) {
-		return min
+	if undefined {
+		return FALSE
 	}
 
-	max := evalBetweenOperand(node.Range[1], env)
-	if isError(max) {
-		return max
+	val, min, max := operands[0], operands[1], operands[2]
+
+	if errObj := checkBetweenBounds(min, max); errObj != nil {
+		return errObj
 	}
 
-	if isUndefined(val) || isUndefined(min) || isUndefined(max) {
+	if val.Type() != min.Type() {
 		return FALSE
 	}
 
-	if !matchTypes(val.Type(), val, min, max) {
+	return compareRange(val, min, max)
+}
+
+// checkBetweenBounds returns an error when the bounds have different types or the lower bound is greater than the upper bound
+func checkBetweenBounds(min, max Object) Object {
+	if !matchTypes(min.Type(), min, max) {
 		return newError("mismatch type: BETWEEN operands must have the same type")
 	}
 
-	b := compareRange(val, min, max)
+	if evalComparableInfixExpression(">", min, max) == TRUE {
+		return newError(
+			"The BETWEEN operator requires upper bound to be greater than or equal to lower bound; lower bound operand: AttributeValue: %s, upper bound operand: AttributeValue: %s",
+			attributeValueString(min),
+			attributeValueString(max),
+		)
+	}
 
-	return b
+	return nil
 }
 
 func evalIn(node *InExpression, env *Environment) Object {
-	val := evalIdentifierOperand(node.Left, env)
+	val := evalOperand(node.Left, env)
 	if isError(val) {
 		return val
 	}
@@ -621,7 +671,7 @@ func evalIn(node *InExpression, env *Environment) Object {
 	rangeObjects := &List{Value: make([]Object, 0, len(node.Range))}
 
 	for _, exp := range node.Range {
-		obj := evalIdentifierOperand(exp, env)
+		obj := evalOperand(exp, env)
 		if isError(obj) {
 			return obj
 		}
@@ -664,59 +714,88 @@ func compareRange(value, min, max Object) Object {
 	return newError("unsupported type: between do not support comparing %s", value.Type())
 }
 
+// evalBetweenOperand evaluates the operands of BETWEEN, the attribute paths of other types than N, S and B
+// are treated as missing because they can not be in a range
 func evalBetweenOperand(exp Expression, env *Environment) Object {
-	identifier, ok := exp.(*Identifier)
-	if !ok {
-		return newError("identifier expected: got %q", exp.String())
+	val := evalOperand(exp, env)
+	if val.Type() == ObjectTypeError {
+		return val
 	}
 
-	val := evalIdentifier(identifier, env, true)
-	if val.Type() == ObjectTypeError {
+	if comparableTypes[val.Type()] || isUndefined(val) {
 		return val
 	}
 
-	if !comparableTypes[val.Type()] && !isUndefined(val) {
+	if isValuePlaceholder(exp) {
 		return newError("unexpected type: %q should be a comparable type(N,S,B) got %q", exp.String(), val.Type())
 	}
 
-	return val
+	return UNDEFINED
 }
 
-func evalIdentifierOperand(exp Expression, env *Environment) Object {
-	identifier, ok := exp.(*Identifier)
-	if !ok {
-		return newError("identifier expected: got %q", exp.String())
+// evalOperand evaluates the operands of BETWEEN and IN: the attribute paths, the values and the size function
+func evalOperand(exp Expression, env *Environment) Object {
+	switch e := exp.(type) {
+	case *Identifier, *IndexExpression:
+		return Eval(exp, env)
+	case *CallExpression:
+		if fn, ok := e.Function.(*Identifier); ok && fn.Value == "size" {
+			return Eval(exp, env)
+		}
 	}
 
-	val := evalIdentifier(identifier, env, true)
-	if val.Type() == ObjectTypeError {
-		return val
+	return newError("operand expected: got %q", exp.String())
+}
+
+// attributeValueString returns the value with the format used by DynamoDB in the error messages
+func attributeValueString(obj Object) string {
+	if bin, ok := obj.(*Binary); ok {
+		return fmt.Sprintf("{B:%s}", base64.StdEncoding.EncodeToString(bin.Value))
 	}
 
-	return val
+	return fmt.Sprintf("{%s:%s}", obj.Type(), obj.Inspect())
 }
 
 func evalFunctionCall(node *CallExpression, env *Environment) Object {
+	funcObj, errObj := conditionFunction(node, env)
+	if errObj != nil {
+		return errObj
+	}
+
+	args := evalExpressions(node.Arguments, env)
+	if len(args) == 1 && isError(args[0]) {
+		return args[0]
+	}
+
+	result := funcObj.Value(args...)
+	if isTypeMismatch(result) && funcObj.Mismatch != nil && !isValuePlaceholder(node.Arguments[0]) {
+		return funcObj.Mismatch
+	}
+
+	return result
+}
+
+// conditionFunction returns the function called in a condition expression, the update functions are rejected
+func conditionFunction(node *CallExpression, env *Environment) (*Function, Object) {
 	fn := evalFunctionCallIdentifer(node, env)
 	if isError(fn) {
-		return fn
+		return nil, fn
 	}
 
 	funcObj, ok := fn.(*Function)
 	if !ok {
-		return newError("invalid function call; expression: " + node.String())
+		return nil, newError("invalid function call; expression: " + node.String())
 	}
 
 	if funcObj.ForUpdate {
-		return newError("the function is not allowed in an condition expression; function: " + funcObj.Name)
+		return nil, newError("the function is not allowed in an condition expression; function: " + funcObj.Name)
 	}
 
-	args := evalExpressions(node.Arguments, env)
-	if len(args) == 1 && isError(args[0]) {
-		return args[0]
+	if errObj := checkFunctionOperands(funcObj, node); errObj != nil {
+		return nil, errObj
 	}
 
-	return fn.(*Function).Value(args...)
+	return funcObj, nil
 }
 
 func evalUpdateFunctionCall(node *CallExpression, env *Environment) Object {
@@ -734,6 +813,10 @@ func evalUpdateFunctionCall(node *CallExpression, env *Environment) Object {
 		return newError("the function is not allowed in an update expression; function: " + funcObj.Name)
 	}
 
+	if errObj := checkFunctionOperands(funcObj, node); errObj != nil {
+		return errObj
+	}
+
 	args := evalUpdateExpressions(node.Arguments, env)
 	if len(args) == 1 && isError(args[0]) {
 		return args[0]
@@ -742,6 +825,15 @@ func evalUpdateFunctionCall(node *CallExpression, env *Environment) Object {
 	return fn.(*Function).Value(args...)
 }
 
+// checkFunctionOperands returns an error when the function is called with the wrong number of arguments
+func checkFunctionOperands(fn *Function, node *CallExpression) *Error {
+	if len(node.Arguments) == fn.Operands {
+		return nil
+	}
+
+	return newError("Incorrect number of operands for operator or function; operator or function: %s, number of operands: %d", fn.Name, len(node.Arguments))
+}
+
 func evalFunctionCallIdentifer(node *CallExpression, env *Environment) Object {
 	functionIdentifier, ok := node.Function.(*Identifier)
 	if !ok {
@@ -759,17 +851,22 @@ func evalFunctionCallIdentifer(node *CallExpression, env *Environment) Object {
 }
 
 func evalUpdateExpression(node *UpdateExpression, env *Environment) Object {
-	if len(node.Expressions) == 0 {
-		return newError(node.TokenLiteral() + " expression must have at least one action")
+	actions, errObj := updateActions(node)
+	if errObj != nil {
+		return errObj
 	}
 
-	for _, act := range node.Expressions {
-		action, ok := act.(*ActionExpression)
-		if !ok {
-			return newError("invalid infix action")
-		}
+	if errObj := checkOverlappingPaths(actions, env); isError(errObj) {
+		return errObj
+	}
+
+	values, errObj := evalSetValues(actions, env)
+	if errObj != nil {
+		return errObj
+	}
 
-		result := evalAction(action, env)
+	for i, action := range actions {
+		result := evalAction(action, values[i], env)
 		if isError(result) {
 			return result
 		}
@@ -780,16 +877,52 @@ func evalUpdateExpression(node *UpdateExpression, env *Environment) Object {
 	return UNDEFINED
 }
 
-func evalActionSet(node *ActionExpression, env *Environment) Object {
-	val := EvalUpdate(node.Right, env)
-	if isError(val) {
-		return val
+// updateActions returns the actions of the update expression, it must have at least one action
+func updateActions(node *UpdateExpression) ([]*ActionExpression, Object) {
+	if len(node.Expressions) == 0 {
+		return nil, newError(node.TokenLiteral() + " expression must have at least one action")
 	}
 
+	actions := make([]*ActionExpression, 0, len(node.Expressions))
+
+	for _, act := range node.Expressions {
+		action, ok := act.(*ActionExpression)
+		if !ok {
+			return nil, newError("invalid infix action")
+		}
+
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
+
+// evalSetValues computes the values of the SET actions, DynamoDB evaluates every value
+// with the item before the update so the values are computed before applying any action
+func evalSetValues(actions []*ActionExpression, env *Environment) ([]Object, Object) {
+	values := make([]Object, len(actions))
+
+	for i, action := range actions {
+		if action.Token.Type != SET {
+			continue
+		}
+
+		val := EvalUpdate(action.Right, env)
+		if isError(val) {
+			return nil, val
+		}
+
+		values[i] = copyObject(val)
+	}
+
+	return values, nil
+}
+
+func evalActionSet(node *ActionExpression, val Object, env *Environment) Object {
 	id, ok := node.Left.(*Identifier)
 	if ok {
 		// We need to validate left hand side is not a keyword
-		obj := evalIdentifier(id, env, true)
+		obj := evalIdentifier(id, env)
 		if isError(obj) {
 			return obj
 		}
@@ -818,28 +951,21 @@ func evalActionAdd(node *ActionExpression, env *Environment) Object {
 		return val
 	}
 
-	id, ok := node.Left.(*Identifier)
-	if ok {
-		// We need to validate left hand side is not a keyword
-		obj := evalIdentifier(id, env, true)
-		if isError(obj) {
-			return obj
-		}
-
-		if obj == UNDEFINED {
-			env.Set(id.Value, val)
-			return obj
-		}
+	obj := evalActionPath(node.Left, env)
+	if isError(obj) {
+		return obj
+	}
 
-		addObj, ok := obj.(AppendableObject)
-		if !ok {
-			return newError("an operand in the update expression has an incorrect data type")
-		}
+	if obj == UNDEFINED {
+		return evalAssign(node.Left, val, env)
+	}
 
-		return addObj.Add(val)
+	addObj, ok := obj.(AppendableObject)
+	if !ok {
+		return newError("an operand in the update expression has an incorrect data type")
 	}
 
-	return UNDEFINED
+	return addObj.Add(val)
 }
 
 func evalActionDelete(node *ActionExpression, env *Environment) Object {
@@ -848,35 +974,52 @@ func evalActionDelete(node *ActionExpression, env *Environment) Object {
 		return val
 	}
 
-	id, ok := node.Left.(*Identifier)
-	if ok {
-		// We need to validate left hand side is not a keyword
-		obj := evalIdentifier(id, env, true)
-		if isError(obj) {
-			return obj
-		}
+	obj := evalActionPath(node.Left, env)
+	if isError(obj) {
+		return obj
+	}
 
-		if obj == UNDEFINED {
-			env.Set(id.Value, val)
-			return obj
-		}
+	// deleting elements from a missing set does not change the item
+	if obj == UNDEFINED {
+		return UNDEFINED
+	}
 
-		addObj, ok := obj.(DetachableObject)
-		if !ok {
-			return newError("an operand in the update expression has an incorrect data type")
-		}
+	addObj, ok := obj.(DetachableObject)
+	if !ok {
+		return newError("an operand in the update expression has an incorrect data type")
+	}
+
+	return addObj.Delete(val)
+}
 
-		return addObj.Delete(val)
+// evalActionPath returns the current value of the attribute changed by the action
+func evalActionPath(path Expression, env *Environment) Object {
+	switch n := path.(type) {
+	case *Identifier:
+		return evalIdentifier(n, env)
+	case *IndexExpression:
+		return evalIndex(n, env)
 	}
 
-	return UNDEFINED
+	return newError("invalid document path: %s", path.String())
+}
+
+// evalAssign sets the value of the attribute or the nested attribute
+func evalAssign(path Expression, val Object, env *Environment) Object {
+	if id, ok := path.(*Identifier); ok {
+		env.Set(id.Value, val)
+
+		return UNDEFINED
+	}
+
+	return evalAssignIndex(path, []int{}, val, env)
 }
 
 func evalActionRemove(node *ActionExpression, env *Environment) Object {
 	id, ok := node.Left.(*Identifier)
 	if ok {
 		// We need to validate left hand side is not a keyword
-		val := evalIdentifier(id, env, true)
+		val := evalIdentifier(id, env)
 		if isError(val) {
 			return val
 		}
@@ -888,38 +1031,41 @@ func evalActionRemove(node *ActionExpression, env *Environment) Object {
 
 	indexField, ok := node.Left.(*IndexExpression)
 	if ok {
-		positions, o, errObj := evalIndexPositions(indexField, env)
-		if isError(errObj) {
-			return errObj
-		}
+		return evalRemoveIndex(indexField, env)
+	}
 
-		var (
-			obj = o
-			pos indexAccessor
-		)
+	return newError("invalid remove to: %s", node.String())
+}
 
-		for i := len(positions) - 1; i > 0; i-- {
-			pos = positions[i]
-			obj = pos.Get(obj)
-		}
+// evalRemoveIndex removes the nested attribute, the lists are compacted after applying every action
+func evalRemoveIndex(node *IndexExpression, env *Environment) Object {
+	positions, obj, errObj := evalIndexPositions(node, env)
+	if isError(errObj) {
+		return errObj
+	}
 
-		errObj = positions[0].Remove(obj)
-		if isError(errObj) {
-			return errObj
+	for i := len(positions) - 1; i > 0; i-- {
+		obj = positions[i].Get(obj)
+		if isError(obj) {
+			return obj
 		}
+	}
 
-		env.MarkToCompact(obj)
-
-		return UNDEFINED
+	errObj = positions[0].Remove(obj)
+	if isError(errObj) {
+		return errObj
 	}
 
-	return newError("invalid remove to: %s", node.String())
+	env.MarkToCompact(obj)
+
+	return UNDEFINED
 }
 
-func evalAction(node *ActionExpression, env *Environment) Object {
+// evalAction applies the action, val is the value already evaluated for the SET actions
+func evalAction(node *ActionExpression, val Object, env *Environment) Object {
 	switch node.Token.Type {
 	case SET:
-		return evalActionSet(node, env)
+		return evalActionSet(node, val, env)
 	case ADD:
 		return evalActionAdd(node, env)
 	case REMOVE:
@@ -939,14 +1085,14 @@ func evalInfixUpdate(node *InfixExpression, env *Environment) Object {
 			return errObj
 		}
 
-		return &Number{Value: augend.Value + addend.Value}
+		return addNumbers(augend.Value, addend.Value)
 	case "-":
 		minuend, subtrahend, errObj := evalArithmeticTerms(node, env)
 		if isError(errObj) {
 			return errObj
 		}
 
-		return &Number{Value: minuend.Value - subtrahend.Value}
+		return addNumbers(minuend.Value, -subtrahend.Value)
 	}
 
 	return newError("unknown operator: %s", node.Operator)
@@ -972,6 +1118,9 @@ func evalAssignIndex(n Expression, i []int, val Object, env *Environment) Object
 		}
 
 		obj = pos.Get(obj)
+		if isError(obj) {
+			return obj
+		}
 	}
 
 	return UNDEFINED
@@ -988,6 +1137,10 @@ func evalArithmeticTerms(node *InfixExpression, env *Environment) (*Number, *Num
 		return nil, nil, rightTerm
 	}
 
+	if isUndefined(leftTerm) || isUndefined(rightTerm) {
+		return nil, nil, newError(missingAttributeMsg)
+	}
+
 	leftNumber, ok := leftTerm.(*Number)
 	if !ok {
 		return nil, nil, newError("invalid operation: %s %s %s", leftTerm.Type(), node.Operator, rightTerm.Type())
@@ -73,6 +73,9 @@ func TestEval(t *testing.T) {
 		{":txtB BETWEEN :txtA AND :txtC", TRUE},
 		{":nullField = :txtB", FALSE},
 		{":binB BETWEEN :binA AND :binC", TRUE},
+		{":y BETWEEN :txtA AND :txtC", FALSE},
+		{":notFound BETWEEN :x AND :z", FALSE},
+		{":hashA.:a BETWEEN :x AND :z", FALSE},
 		// Map
 		{":hashA = :hashB", FALSE},
 		{":hashA = :hashA", TRUE},
@@ -221,6 +224,8 @@ func TestEvalFunctions(t *testing.T) {
 		{"contains(:binSet, :bin)", TRUE},
 		{"contains(:numSet, :num)", TRUE},
 		{"attribute_type(:s, :type)", TRUE},
+		{"begins_with(:numList[0], :prefix)", FALSE},
+		{"contains(:numList[0], :element)", FALSE},
 	}
 
 	env := NewEnvironment()
@@ -242,6 +247,11 @@ func TestEvalFunctions(t *testing.T) {
 				{S: types.ToString("c")},
 			},
 		},
+		":numList": {
+			L: []*types.Item{
+				{N: types.ToString("1")},
+			},
+		},
 		":strSet": {
 			SS: []*string{types.ToString("a"), types.ToString("a"), types.ToString("b")},
 		},
@@ -312,6 +322,8 @@ func startEvalUpdateEnv(t *testing.T) *Environment {
 		":two": {
 			N: types.ToString("2"),
 		},
+		":tenth": {N: types.ToString("0.1")},
+		":fifth": {N: types.ToString("0.2")},
 		":tools": {L: []*types.Item{
 			{S: types.ToString("Chisel")},
 			{S: types.ToString("Hammer")},
@@ -426,6 +438,9 @@ func TestEvalSetUpdate(t *testing.T) {
 			&Map{Value: map[string]Object{"lvl1": &Map{Value: map[string]Object{"lvl2": &Number{Value: 0}}}, "lvl1.lvl2": &Number{Value: 1}}},
 			false,
 		},
+		{"SET :decimal = :tenth + :fifth", ":decimal", &Number{Value: 0.3}, true},
+		{"SET :nestedMap.lvl1.lvl2 = :nestedMap.lvl1.lvl2 - :one, :one = :two", ":nestedMap", &Map{Value: map[string]Object{"lvl1": &Map{Value: map[string]Object{"lvl2": &Number{Value: -1}}}}}, false},
+		{"SET :one = :two, :two = :one", ":two", &Number{Value: 1}, false},
 		{"SET :x = :val REMOVE :val", ":x", &String{Value: "text"}, true},
 		{"SET :x = :val REMOVE :val", ":val", UNDEFINED, true},
 	}
@@ -450,6 +465,60 @@ func TestEvalSetUpdate(t *testing.T) {
 	}
 }
 
+func TestEvalSetBeyondListLength(t *testing.T) {
+	tests := []struct {
+		input    string
+		envField string
+		expected Object
+	}{
+		{"SET :list[5] = :two", ":list", &List{Value: []Object{&Number{Value: 0}, &Number{Value: 2}}}},
+		{"SET :matrix[0][3] = :one", ":matrix", &List{Value: []Object{&List{Value: []Object{&Number{Value: 0}, &Number{Value: 1}}}}}},
+		{"SET :list[1] = :one, :list[7] = :two", ":list", &List{Value: []Object{&Number{Value: 0}, &Number{Value: 1}, &Number{Value: 2}}}},
+	}
+
+	for _, tt := range tests {
+		env := startEvalUpdateEnv(t)
+
+		result := testEvalUpdate(t, tt.input, env)
+		if isError(result) {
+			t.Fatalf("error evaluating update %q, env=%s, %s", tt.input, env.String(), result.Inspect())
+		}
+
+		result = env.Get(tt.envField)
+
+		if result.Inspect() != tt.expected.Inspect() {
+			t.Errorf("result has wrong value for %q in %q. got=%v, want=%v", tt.envField, tt.input, result.Inspect(), tt.expected.Inspect())
+		}
+	}
+}
+
+func TestEvalUpdateUnsupported(t *testing.T) {
+	env := startEvalUpdateEnv(t)
+
+	err := env.AddAttributes(map[string]*types.Item{
+		":big": {N: types.ToString("9007199254740993")},
+	})
+	if err != nil {
+		t.Fatalf("error adding attributes %#v", err)
+	}
+
+	result := testEvalUpdate(t, "SET :sum = :big + :one", env)
+
+	errObj, ok := result.(*Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", result, result)
+	}
+
+	if !errObj.Unsupported {
+		t.Errorf("the error must be unsupported: %s", errObj.Message)
+	}
+
+	expected := "arithmetic with numbers from 9007199254740992 is not supported"
+	if errObj.Message != expected {
+		t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+	}
+}
+
 func TestEvalAddUpdate(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -462,6 +531,8 @@ func TestEvalAddUpdate(t *testing.T) {
 		{"ADD :binSet :bin", ":binSet", &BinarySet{Value: [][]byte{[]byte("a"), []byte("b"), []byte("c")}}, boolFalse},
 		{"ADD :strSet :val", ":strSet", &StringSet{Value: map[string]bool{"a": boolTrue, "b": boolTrue, "text": boolTrue}}, boolFalse},
 		{"ADD newVal :val", ":val", &String{Value: "text"}, boolFalse},
+		{"ADD :nestedMap.lvl1.lvl2 :two", ":nestedMap", &Map{Value: map[string]Object{"lvl1": &Map{Value: map[string]Object{"lvl2": &Number{Value: 2}}}}}, boolFalse},
+		{"ADD :nestedMap.lvl1.other :one", ":nestedMap", &Map{Value: map[string]Object{"lvl1": &Map{Value: map[string]Object{"lvl2": &Number{Value: 0}, "other": &Number{Value: 1}}}}}, boolFalse},
 	}
 
 	env := startEvalUpdateEnv(t)
@@ -527,6 +598,7 @@ func TestEvalDELETEUpdate(t *testing.T) {
 		{"DELETE :binSet :binA", ":binSet", &BinarySet{Value: [][]byte{[]byte("b")}}, boolFalse},
 		{"DELETE :strSet :a", ":strSet", &StringSet{Value: map[string]bool{"b": boolTrue}}, boolFalse},
 		{"DELETE :numSet :two", ":numSet", &NumberSet{Value: map[float64]bool{4: boolTrue}}, boolFalse},
+		{"DELETE notFound :strSet", "notFound", UNDEFINED, boolFalse},
 	}
 
 	env := startEvalUpdateEnv(t)
@@ -619,37 +691,55 @@ func TestErrorHandling(t *testing.T) {
 			"mismatch type: BETWEEN operands must have the same type",
 		},
 		{
-			":y.:str",
+			":y BETWEEN :z AND :x",
+			"The BETWEEN operator requires upper bound to be greater than or equal to lower bound; lower bound operand: AttributeValue: {N:26}, upper bound operand: AttributeValue: {N:24}",
+		},
+		{
+			":binA BETWEEN :binB AND :binA",
+			"The BETWEEN operator requires upper bound to be greater than or equal to lower bound; lower bound operand: AttributeValue: {B:Yg==}, upper bound operand: AttributeValue: {B:YQ==}",
+		},
+		{
+			":y.:str BETWEEN :x AND :z",
 			"index operator not supported for \"N\"",
 		},
 		{
-			"ROLE BETWEEN :x AND :str",
-			"reserved word ROLE found in expression",
+			"begins_with(:y.:str, :str)",
+			"index operator not supported for \"N\"",
+		},
+		{
+			":y.:str",
+			"index operator not supported for \"N\"",
 		},
 		{
 			"list_append(:list,:x)",
 			"the function is not allowed in an condition expression; function: list_append",
 		},
 		{
-			"ROLE IN (:x, :str)",
-			"reserved word ROLE found in expression",
+			"begins_with(:str)",
+			"Incorrect number of operands for operator or function; operator or function: begins_with, number of operands: 1",
 		},
 		{
-			":x IN (ROLE, :str)",
-			"reserved word ROLE found in expression",
+			"attribute_exists()",
+			"Incorrect number of operands for operator or function; operator or function: attribute_exists, number of operands: 0",
+		},
+		{
+			"contains(:list, :str, :x)",
+			"Incorrect number of operands for operator or function; operator or function: contains, number of operands: 3",
 		},
 	}
 
 	env := NewEnvironment()
 
 	err := env.AddAttributes(map[string]*types.Item{
-		":a":   {BOOL: &boolTrue},
-		":b":   {BOOL: &boolFalse},
-		":x":   {N: types.ToString("24")},
-		":y":   {N: types.ToString("25")},
-		":z":   {N: types.ToString("26")},
-		":str": {S: types.ToString("TEXT")},
-		":nil": {NULL: &boolTrue},
+		":a":    {BOOL: &boolTrue},
+		":b":    {BOOL: &boolFalse},
+		":x":    {N: types.ToString("24")},
+		":y":    {N: types.ToString("25")},
+		":z":    {N: types.ToString("26")},
+		":str":  {S: types.ToString("TEXT")},
+		":nil":  {NULL: &boolTrue},
+		":binA": {B: []byte("a")},
+		":binB": {B: []byte("b")},
 		":list": {L: []*types.Item{
 			{S: types.ToString("a")},
 		}},
@@ -680,43 +770,33 @@ func TestEvalUpdateReservedKeywords(t *testing.T) {
 	}{
 		{
 			"SET status = :status",
-			"reserved word STATUS found in expression",
+			"Attribute name is a reserved keyword; reserved keyword: status",
 		},
 		{
 			"REMOVE status,keys,hash",
-			"reserved word STATUS found in expression",
+			"Attribute name is a reserved keyword; reserved keyword: status",
 		},
 		{
 			"ADD avg 5",
-			"reserved word AVG found in expression",
+			"Attribute name is a reserved keyword; reserved keyword: avg",
 		},
 		{
 			"DELETE keys :keys",
-			"reserved word KEYS found in expression",
+			"Attribute name is a reserved keyword; reserved keyword: keys",
 		},
 	}
 
-	env := NewEnvironment()
-
-	err := env.AddAttributes(map[string]*types.Item{
-		":status": {S: types.ToString("healthy")},
-		":keys":   {SS: []*string{types.ToString("Key"), types.ToString("Another Key")}},
-	})
-	if err != nil {
-		panic(err)
-	}
-
 	for i, tt := range tests {
-		evaluated := testEvalUpdate(t, tt.input, env)
+		p := NewUpdateParser(NewLexer(tt.input))
+		p.ParseUpdateExpression()
 
-		errObj, ok := evaluated.(*Error)
-		if !ok {
-			t.Errorf("(%d) no error object returned for %s. got=%T(%+v)", i, tt.input, evaluated, evaluated)
+		if p.Err() == nil {
+			t.Errorf("(%d) no error returned for %s", i, tt.input)
 			continue
 		}
 
-		if errObj.Message != tt.expectedMessage {
-			t.Errorf("wrong error message for %s. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
+		if p.Err().Error() != tt.expectedMessage {
+			t.Errorf("wrong error message for %s. expected=%q, got=%q", tt.input, tt.expectedMessage, p.Err().Error())
 		}
 	}
 }
@@ -728,26 +808,47 @@ func TestEvalReservedKeywords(t *testing.T) {
 	}{
 		{
 			"size = :x",
-			"reserved word SIZE found in expression",
+			"Attribute name is a reserved keyword; reserved keyword: size",
 		},
 		{
 			"hash = :x",
-			"reserved word HASH found in expression",
+			"Attribute name is a reserved keyword; reserved keyword: hash",
+		},
+		{
+			"ROLE BETWEEN :x AND :str",
+			"Attribute name is a reserved keyword; reserved keyword: ROLE",
+		},
+		{
+			"ROLE IN (:x, :str)",
+			"Attribute name is a reserved keyword; reserved keyword: ROLE",
 		},
 		{
-			":obj.size = :x",
-			"",
+			":x IN (ROLE, :str)",
+			"Attribute name is a reserved keyword; reserved keyword: ROLE",
 		},
 	}
 
+	for i, tt := range tests {
+		p := NewParser(NewLexer(tt.input))
+		p.ParseConditionalExpression()
+
+		if p.Err() == nil {
+			t.Errorf("(%d) no error returned for %s", i, tt.input)
+			continue
+		}
+
+		if p.Err().Error() != tt.expectedMessage {
+			t.Errorf("wrong error message for %s. expected=%q, got=%q", tt.input, tt.expectedMessage, p.Err().Error())
+		}
+	}
+
 	env := NewEnvironment()
 
 	err := env.AddAttributes(map[string]*types.Item{
-		":y":   {N: types.ToString("25")},
-		":str": {S: types.ToString("TEXT")},
+		":x": {N: types.ToString("25")},
 		":obj": {
 			M: map[string]*types.Item{
-				"a": {BOOL: &boolTrue},
+				"size": {N: types.ToString("25")},
 			},
 		},
 	})
@@ -755,27 +856,9 @@ func TestEvalReservedKeywords(t *testing.T) {
 		panic(err)
 	}
 
-	for i, tt := range tests {
-		evaluated := testEval(t, tt.input, env)
-
-		if tt.expectedMessage == "" {
-			errObj, ok := evaluated.(*Error)
-			if ok {
-				t.Errorf("(%d) error not expected for %q. got=%s", i, tt.input, errObj.Message)
-			}
-
-			continue
-		}
-
-		errObj, ok := evaluated.(*Error)
-		if !ok {
-			t.Errorf("(%d) no error object returned for %s. got=%T(%+v)", i, tt.input, evaluated, evaluated)
-			continue
-		}
-
-		if errObj.Message != tt.expectedMessage {
-			t.Errorf("wrong error message for %s. expected=%q, got=%q", tt.input, tt.expectedMessage, errObj.Message)
-		}
+	evaluated := testEval(t, ":obj.size = :x", env)
+	if evaluated != TRUE {
+		t.Errorf("the reserved words are allowed in nested attributes. got=%s", evaluated.Inspect())
 	}
 }
 
@@ -867,6 +950,26 @@ func TestEvalErrors(t *testing.T) {
 			},
 			expected: UNDEFINED,
 		},
+		{
+			inputs: []interface{}{
+				&ActionExpression{
+					Token: Token{Type: ADD, Literal: "ADD"},
+					Left: &CallExpression{
+						Token:     Token{Type: IDENT, Literal: "size"},
+						Function:  &Identifier{Value: "size", Token: Token{Type: IDENT, Literal: "size"}},
+						Arguments: []Expression{&Identifier{Value: ":val", Token: Token{Type: IDENT, Literal: ":val"}}},
+					},
+					Right: &Identifier{Value: ":one", Token: Token{Type: IDENT, Literal: ":one"}},
+				},
+				env,
+			},
+			function: func(args ...interface{}) Object {
+				arg := args[0].(*ActionExpression)
+				arg1 := args[1].(*Environment)
+				return evalActionAdd(arg, arg1)
+			},
+			expected: newError("invalid document path: size(:val)"),
+		},
 	}
 
 	for i, tt := range testCases {
@@ -908,7 +1011,7 @@ func TestUpdateEvalSyntaxError(t *testing.T) {
 			"invalid operation: S - S",
 		},
 		{
-			"SET x = :val - :one + :one",
+			"SET x = :val - :one",
 			"invalid operation: S - N",
 		},
 		{
@@ -917,11 +1020,35 @@ func TestUpdateEvalSyntaxError(t *testing.T) {
 		},
 		{
 			"SET h.bar = :one",
-			"index assignation for \"NULL\" type is not supported",
+			"The document path provided in the update expression is invalid for update",
 		},
 		{
 			"SET notFound.bar = :one",
-			"index assignation for \"NULL\" type is not supported",
+			"The document path provided in the update expression is invalid for update",
+		},
+		{
+			"SET x = notFound + :one",
+			"The provided expression refers to an attribute that does not exist in the item",
+		},
+		{
+			"SET x = list_append(notFound, :list)",
+			"The provided expression refers to an attribute that does not exist in the item",
+		},
+		{
+			"SET x = :one, x = :val",
+			"Two document paths overlap with each other; must remove or rewrite one of these paths; path one: [x], path two: [x]",
+		},
+		{
+			"SET :h.a = :one REMOVE :h",
+			"Two document paths overlap with each other; must remove or rewrite one of these paths; path one: [:h, a], path two: [:h]",
+		},
+		{
+			"SET :list[0] = :one REMOVE :list.a",
+			"Two document paths conflict with each other; must remove or rewrite one of these paths; path one: [:list, [0]], path two: [:list, a]",
+		},
+		{
+			"REMOVE :h.notFound.a",
+			"The document path provided in the update expression is invalid for update",
 		},
 		{
 			"ADD :voidVal :one",
@@ -951,6 +1078,10 @@ func TestUpdateEvalSyntaxError(t *testing.T) {
 			"DELETE :list sizze(:x)",
 			"invalid function name; function: sizze",
 		},
+		{
+			"SET :val = if_not_exists(:val)",
+			"Incorrect number of operands for operator or function; operator or function: if_not_exists, number of operands: 1",
+		},
 	}
 
 	env := NewEnvironment()
@@ -1025,7 +1156,7 @@ func testEval(t *testing.T, input string, env *Environment) Object {
 	conditional := p.ParseConditionalExpression()
 
 	if len(p.errors) != 0 {
-		t.Fatalf("parsing %q failed: %s", input, strings.Join(p.errors, ";\n"))
+		t.Fatalf("parsing %q failed: %s", input, strings.Join(p.Errors(), ";\n"))
 	}
 
 	return Eval(conditional, env)
@@ -1037,7 +1168,7 @@ func testEvalUpdate(t *testing.T, input string, env *Environment) Object {
 	update := p.ParseUpdateExpression()
 
 	if len(p.errors) != 0 {
-		t.Fatalf("parsing %q failed: %s", input, strings.Join(p.errors, ";\n"))
+		t.Fatalf("parsing %q failed: %s", input, strings.Join(p.Errors(), ";\n"))
 	}
 
 	return EvalUpdate(update, env)
@@ -0,0 +1,140 @@
+package language
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/truora/minidyn/types"
+)
+
+// Explanation is the evaluation tree of a conditional expression,
+// each node holds a sub-expression and the value it evaluated to
+type Explanation struct {
+	Expression string
+	Value      string
+	Children   []*Explanation
+}
+
+// String returns the tree with one sub-expression per line, the operands are indented under their expression
+func (e *Explanation) String() string {
+	var out strings.Builder
+
+	e.write(&out, 0)
+
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+func (e *Explanation) write(out *strings.Builder, depth int) {
+	out.WriteString(strings.Repeat("  ", depth))
+	out.WriteString(e.Expression)
+	out.WriteString(" → ")
+	out.WriteString(e.Value)
+	out.WriteString("\n")
+
+	for _, child := range e.Children {
+		child.write(out, depth+1)
+	}
+}
+
+// Explain evaluates the conditional expression against the item and the attribute values,
+// it returns the value of every sub-expression to show why the expression did or did not match
+func Explain(expression string, item, values map[string]*types.Item, aliases map[string]string) (*Explanation, error) {
+	p := NewParser(NewLexer(expression))
+	conditional := p.ParseConditionalExpression()
+
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+
+	if conditional.Expression == nil {
+		return nil, &SyntaxError{Token: "<EOF>", Near: strings.TrimSpace(expression)}
+	}
+
+	env := NewEnvironment()
+	env.Aliases = aliases
+
+	if err := env.AddAttributes(item); err != nil {
+		return nil, err
+	}
+
+	if err := env.AddAttributes(values); err != nil {
+		return nil, err
+	}
+
+	return ExplainNode(conditional, env), nil
+}
+
+// ExplainNode evaluates the node and its operands in the environment
+func ExplainNode(node Node, env *Environment) *Explanation {
+	if conditional, ok := node.(*ConditionalExpression); ok {
+		node = conditional.Expression
+	}
+
+	e := &Explanation{
+		Expression: explainText(node),
+		Value:      explainValue(Eval(node, env)),
+	}
+
+	for _, operand := range explainOperands(node) {
+		e.Children = append(e.Children, ExplainNode(operand, env))
+	}
+
+	return e
+}
+
+func explainText(node Node) string {
+	switch n := node.(type) {
+	case *PrefixExpression:
+		return n.Operator + " " + operandText(n.Right)
+	case *InfixExpression:
+		return operandText(n.Left) + " " + n.Operator + " " + operandText(n.Right)
+	case *InExpression:
+		return strings.TrimSuffix(strings.TrimPrefix(n.String(), "("), ")")
+	}
+
+	return node.String()
+}
+
+// operandText wraps the operands that are operations in parentheses
+func operandText(node Node) string {
+	switch node.(type) {
+	case *PrefixExpression, *InfixExpression, *InExpression, *BetweenExpression:
+		return "(" + explainText(node) + ")"
+	}
+
+	return node.String()
+}
+
+func explainOperands(node Node) []Expression {
+	switch n := node.(type) {
+	case *PrefixExpression:
+		return []Expression{n.Right}
+	case *InfixExpression:
+		return []Expression{n.Left, n.Right}
+	case *BetweenExpression:
+		return []Expression{n.Left, n.Range[0], n.Range[1]}
+	case *InExpression:
+		return append([]Expression{n.Left}, n.Range...)
+	case *CallExpression:
+		return n.Arguments
+	}
+
+	return nil
+}
+
+func explainValue(obj Object) string {
+	switch v := obj.(type) {
+	case nil:
+		return "<undefined>"
+	case *Null:
+		if v.IsUndefined {
+			return "<undefined>"
+		}
+	case *String:
+		return strconv.Quote(v.Value)
+	case *Error:
+		return "error: " + v.Message
+	}
+
+	return strings.Join(strings.Fields(obj.Inspect()), " ")
+}
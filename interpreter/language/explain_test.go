@@ -0,0 +1,95 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/truora/minidyn/types"
+)
+
+func TestExplain(t *testing.T) {
+	item := map[string]*types.Item{
+		"pk":   {S: types.ToString("001")},
+		"lvl":  {N: types.ToString("5")},
+		"tags": {SS: []*string{types.ToString("a")}},
+	}
+	values := map[string]*types.Item{
+		":lvl": {N: types.ToString("10")},
+		":min": {N: types.ToString("1")},
+		":tag": {S: types.ToString("a")},
+	}
+	aliases := map[string]string{"#pk": "pk", "#sk": "sk"}
+
+	tests := map[string]string{
+		"attribute_exists(#sk) AND lvl > :lvl": `attribute_exists(#sk) AND (lvl > :lvl) → false
+  attribute_exists(#sk) → false
+    #sk → <undefined>
+  lvl > :lvl → false
+    lvl → 5
+    :lvl → 10`,
+		"NOT contains(tags, :tag) OR lvl BETWEEN :min AND :lvl": `(NOT contains(tags, :tag)) OR (lvl BETWEEN :min AND :lvl) → true
+  NOT contains(tags, :tag) → false
+    contains(tags, :tag) → true
+      tags → [ a ]<SS>
+      :tag → "a"
+  lvl BETWEEN :min AND :lvl → true
+    lvl → 5
+    :min → 1
+    :lvl → 10`,
+		"#pk IN (:tag, :min)": `#pk IN (:tag, :min) → false
+  #pk → "001"
+  :tag → "a"
+  :min → 1`,
+	}
+
+	for expression, expected := range tests {
+		explanation, err := Explain(expression, item, values, aliases)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", expression, err)
+		}
+
+		if explanation.String() != expected {
+			t.Errorf("unexpected explanation for %q.\ngot:\n%s\nwant:\n%s", expression, explanation, expected)
+		}
+	}
+
+	errors := map[string]string{
+		"a !=": `Syntax error; token: "!", near: "a !="`,
+		"":     `Syntax error; token: "<EOF>", near: ""`,
+		"   ":  `Syntax error; token: "<EOF>", near: ""`,
+	}
+
+	for expression, expected := range errors {
+		_, err := Explain(expression, item, values, aliases)
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error for %q. got=%v, want=%q", expression, err, expected)
+		}
+	}
+}
+
+func TestExplainValues(t *testing.T) {
+	item := map[string]*types.Item{
+		"pk":      {S: types.ToString("001")},
+		"nothing": {NULL: types.ToBool(true)},
+	}
+
+	explanation, err := Explain("nothing = :null AND :t < :n", item, map[string]*types.Item{
+		":null": {NULL: types.ToBool(true)},
+		":t":    {BOOL: types.ToBool(true)},
+		":n":    {N: types.ToString("1")},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := `(nothing = :null) AND (:t < :n) → error: type mismatch: BOOL < N
+  nothing = :null → true
+    nothing → null
+    :null → null
+  :t < :n → error: type mismatch: BOOL < N
+    :t → true
+    :n → 1`
+
+	if explanation.String() != expected {
+		t.Errorf("unexpected explanation.\ngot:\n%s\nwant:\n%s", explanation, expected)
+	}
+}
@@ -12,6 +12,10 @@ type Function struct {
 	Name      string
 	Value     func(...Object) Object
 	ForUpdate bool
+	// Operands is the number of arguments of the function
+	Operands int
+	// Mismatch is the result when the attribute path has a type that is not supported by the function
+	Mismatch Object
 }
 
 // Inspect returns the readable value of the object
@@ -32,36 +36,47 @@ func (fn *Function) ToDynamoDB() types.Item {
 var (
 	functions = map[string]*Function{
 		"attribute_exists": &Function{
-			Name:  "attribute_exists",
-			Value: attributeExists,
+			Name:     "attribute_exists",
+			Operands: 1,
+			Value:    attributeExists,
 		},
 		"attribute_not_exists": &Function{
-			Name:  "attribute_not_exists",
-			Value: attributeNotExists,
+			Name:     "attribute_not_exists",
+			Operands: 1,
+			Value:    attributeNotExists,
 		},
 		"attribute_type": &Function{
-			Name:  "attribute_type",
-			Value: attributeType,
+			Name:     "attribute_type",
+			Operands: 2,
+			Value:    attributeType,
 		},
 		"begins_with": &Function{
-			Name:  "begins_with",
-			Value: beginsWith,
+			Name:     "begins_with",
+			Operands: 2,
+			Value:    beginsWith,
+			Mismatch: FALSE,
 		},
 		"contains": &Function{
-			Name:  "contains",
-			Value: contains,
+			Name:     "contains",
+			Operands: 2,
+			Value:    contains,
+			Mismatch: FALSE,
 		},
 		"size": &Function{
-			Name:  "size",
-			Value: objectSize,
+			Name:     "size",
+			Operands: 1,
+			Value:    objectSize,
+			Mismatch: UNDEFINED,
 		},
 		"if_not_exists": &Function{
 			Name:      "if_not_exists",
+			Operands:  2,
 			Value:     ifNotExists,
 			ForUpdate: true,
 		},
 		"list_append": &Function{
 			Name:      "list_append",
+			Operands:  2,
 			Value:     listAppend,
 			ForUpdate: true,
 		},
@@ -71,13 +86,13 @@ var (
 func attributeExists(args ...Object) Object {
 	path := args[0]
 
-	return nativeBoolToBooleanObject(path.Type() != ObjectTypeNull)
+	return nativeBoolToBooleanObject(!isUndefined(path))
 }
 
 func attributeNotExists(args ...Object) Object {
 	path := args[0]
 
-	return nativeBoolToBooleanObject(path.Type() == ObjectTypeNull)
+	return nativeBoolToBooleanObject(isUndefined(path))
 }
 
 func attributeType(args ...Object) Object {
@@ -100,9 +115,13 @@ func beginsWith(args ...Object) Object {
 	path := args[0]
 	substr := args[1]
 
+	if substr.Type() != ObjectTypeString && substr.Type() != ObjectTypeBinary {
+		return newError("invalid substr type %s", substr.Type())
+	}
+
 	if path.Type() == ObjectTypeString {
 		if substr.Type() != ObjectTypeString {
-			return newError("invalid substr type %s", substr.Type())
+			return newTypeMismatchError("invalid substr type %s", substr.Type())
 		}
 
 		return nativeBoolToBooleanObject(strings.HasPrefix(path.Inspect(), substr.Inspect()))
@@ -110,7 +129,7 @@ func beginsWith(args ...Object) Object {
 
 	if path.Type() == ObjectTypeBinary {
 		if substr.Type() != ObjectTypeBinary {
-			return newError("invalid substr type %s", substr.Type())
+			return newTypeMismatchError("invalid substr type %s", substr.Type())
 		}
 
 		binarySubstr, _ := substr.(*Binary)
@@ -119,7 +138,7 @@ func beginsWith(args ...Object) Object {
 		return nativeBoolToBooleanObject(bytes.HasPrefix(binaryPath.Value, binarySubstr.Value))
 	}
 
-	return newError("invalid type %s", path.Type())
+	return newTypeMismatchError("invalid type %s", path.Type())
 }
 
 func contains(args ...Object) Object {
@@ -128,16 +147,30 @@ func contains(args ...Object) Object {
 
 	container, ok := path.(ContainerObject)
 	if !ok {
-		return newError("contains is not supported for path=%s", path.Type())
+		return newTypeMismatchError("contains is not supported for path=%s", path.Type())
 	}
 
 	if !container.CanContain(operand.Type()) {
-		return newError("contains is not supported for path=%s operand=%s", path.Type(), operand.Type())
+		if !isScalarOperand(operand) {
+			return newError("contains is not supported for path=%s operand=%s", path.Type(), operand.Type())
+		}
+
+		return newTypeMismatchError("contains is not supported for path=%s operand=%s", path.Type(), operand.Type())
 	}
 
 	return nativeBoolToBooleanObject(container.Contains(operand))
 }
 
+// isScalarOperand reports if the operand can be an element of a string or a set
+func isScalarOperand(obj Object) bool {
+	switch obj.Type() {
+	case ObjectTypeString, ObjectTypeNumber, ObjectTypeBinary:
+		return true
+	}
+
+	return false
+}
+
 func objectSize(args ...Object) Object {
 	path := args[0]
 
@@ -150,15 +183,25 @@ func objectSize(args ...Object) Object {
 		bin, _ := path.(*Binary)
 
 		return &Number{Value: float64(len(bin.Value))}
+	case ObjectTypeList:
+		return &Number{Value: float64(len(path.(*List).Value))}
+	case ObjectTypeMap:
+		return &Number{Value: float64(len(path.(*Map).Value))}
+	case ObjectTypeStringSet:
+		return &Number{Value: float64(len(path.(*StringSet).Value))}
+	case ObjectTypeNumberSet:
+		return &Number{Value: float64(len(path.(*NumberSet).Value))}
+	case ObjectTypeBinarySet:
+		return &Number{Value: float64(len(path.(*BinarySet).Value))}
 	}
 
-	return newError("type not supported: size %s", path.Type())
+	return newTypeMismatchError("type not supported: size %s", path.Type())
 }
 
 func ifNotExists(args ...Object) Object {
 	obj := args[0]
 
-	if obj == nil || obj.Type() == ObjectTypeNull {
+	if isUndefined(obj) {
 		return args[1]
 	}
 
@@ -166,6 +209,10 @@ func ifNotExists(args ...Object) Object {
 }
 
 func listAppend(args ...Object) Object {
+	if isUndefined(args[0]) || isUndefined(args[1]) {
+		return newError(missingAttributeMsg)
+	}
+
 	value1 := args[0]
 	if value1.Type() != ObjectTypeList {
 		return newError("list_append is not supported for list1=%s", value1.Type())
@@ -179,5 +226,8 @@ func listAppend(args ...Object) Object {
 	list1 := value1.(*List)
 	list2 := value2.(*List)
 
-	return &List{Value: append(list1.Value, list2.Value...)}
+	values := make([]Object, 0, len(list1.Value)+len(list2.Value))
+	values = append(values, list1.Value...)
+
+	return &List{Value: append(values, list2.Value...)}
 }
@@ -0,0 +1,270 @@
+package language
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/truora/minidyn/types"
+)
+
+var (
+	// ErrInvalidLegacyParameter when a legacy parameter cannot be translated into an expression
+	// revive:disable-next-line
+	ErrInvalidLegacyParameter = errors.New("One or more parameter values were invalid")
+)
+
+type legacyBuilder func(name Expression, values []Expression) Expression
+
+type legacyOperator struct {
+	args  int
+	build legacyBuilder
+}
+
+var legacyOperators = map[string]legacyOperator{
+	"EQ":           {args: 1, build: legacyInfix(EQ)},
+	"NE":           {args: 1, build: legacyInfix(NotEQ)},
+	"LE":           {args: 1, build: legacyInfix(LTE)},
+	"LT":           {args: 1, build: legacyInfix(LT)},
+	"GE":           {args: 1, build: legacyInfix(GTE)},
+	"GT":           {args: 1, build: legacyInfix(GT)},
+	"NOT_NULL":     {args: 0, build: legacyCall("attribute_exists")},
+	"NULL":         {args: 0, build: legacyCall("attribute_not_exists")},
+	"CONTAINS":     {args: 1, build: legacyCall("contains")},
+	"NOT_CONTAINS": {args: 1, build: legacyNot(legacyCall("contains"))},
+	"BEGINS_WITH":  {args: 1, build: legacyCall("begins_with")},
+	"IN":           {args: -1, build: legacyIn},
+	"BETWEEN":      {args: 2, build: legacyBetween},
+}
+
+var legacyActions = map[string]TokenType{
+	"PUT":    SET,
+	"ADD":    ADD,
+	"DELETE": DELETE,
+}
+
+// Legacy translates the legacy parameters (Expected, AttributeUpdates, KeyConditions,
+// QueryFilter and ScanFilter) into expression ASTs. The attribute names
+// and values are replaced with placeholders registered in Names and Values
+type Legacy struct {
+	Names  map[string]string
+	Values map[string]*types.Item
+}
+
+// NewLegacy creates a new legacy parameters translator
+func NewLegacy() *Legacy {
+	return &Legacy{
+		Names:  map[string]string{},
+		Values: map[string]*types.Item{},
+	}
+}
+
+// Expected translates the Expected parameter into a conditional expression
+func (lg *Legacy) Expected(expected map[string]*types.ExpectedAttributeValue, conditionalOperator string) (*ConditionalExpression, error) {
+	conditions := make([]Expression, 0, len(expected))
+
+	for _, attr := range sortedLegacyKeys(expected) {
+		exp, err := lg.expectedCondition(attr, expected[attr])
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = append(conditions, exp)
+	}
+
+	return lg.conditional(conditions, conditionalOperator)
+}
+
+// Conditions translates the KeyConditions, QueryFilter or ScanFilter parameters into a conditional expression
+func (lg *Legacy) Conditions(conditions map[string]*types.Condition, conditionalOperator string) (*ConditionalExpression, error) {
+	exps := make([]Expression, 0, len(conditions))
+
+	for _, attr := range sortedLegacyKeys(conditions) {
+		cond := conditions[attr]
+
+		exp, err := lg.comparison(attr, types.StringValue(cond.ComparisonOperator), cond.AttributeValueList)
+		if err != nil {
+			return nil, err
+		}
+
+		exps = append(exps, exp)
+	}
+
+	return lg.conditional(exps, conditionalOperator)
+}
+
+// AttributeUpdates translates the AttributeUpdates parameter into an update expression
+func (lg *Legacy) AttributeUpdates(updates map[string]*types.AttributeValueUpdate) (*UpdateStatement, error) {
+	actions := make([]Expression, 0, len(updates))
+
+	for _, attr := range sortedLegacyKeys(updates) {
+		action, err := lg.updateAction(attr, updates[attr])
+		if err != nil {
+			return nil, err
+		}
+
+		actions = append(actions, action)
+	}
+
+	update := &UpdateExpression{Token: Token{Type: SET, Literal: SET}, Expressions: actions}
+
+	return &UpdateStatement{Token: update.Token, Expression: update}, nil
+}
+
+func (lg *Legacy) expectedCondition(attr string, expected *types.ExpectedAttributeValue) (Expression, error) {
+	if expected.ComparisonOperator != nil {
+		if expected.Value != nil || expected.Exists != nil {
+			return nil, fmt.Errorf("%w: Value or Exists cannot be used with ComparisonOperator for Attribute: %s", ErrInvalidLegacyParameter, attr)
+		}
+
+		return lg.comparison(attr, *expected.ComparisonOperator, expected.AttributeValueList)
+	}
+
+	return lg.existsCondition(attr, expected)
+}
+
+// existsCondition translates the Value and Exists fields, Exists defaults to true and requires the Value
+func (lg *Legacy) existsCondition(attr string, expected *types.ExpectedAttributeValue) (Expression, error) {
+	exists := expected.Exists == nil || *expected.Exists
+
+	switch {
+	case exists && expected.Value == nil:
+		return nil, fmt.Errorf("%w: Value must be provided when Exists is %s for Attribute: %s", ErrInvalidLegacyParameter, existsString(expected.Exists), attr)
+	case !exists && expected.Value != nil:
+		return nil, fmt.Errorf("%w: Value cannot be used when Exists is false for Attribute: %s", ErrInvalidLegacyParameter, attr)
+	case !exists:
+		return lg.comparison(attr, "NULL", nil)
+	}
+
+	return lg.comparison(attr, "EQ", []*types.Item{expected.Value})
+}
+
+func (lg *Legacy) comparison(attr, operator string, values []*types.Item) (Expression, error) {
+	op, ok := legacyOperators[operator]
+	if !ok {
+		return nil, fmt.Errorf("%w: Unsupported operator on ComparisonOperator: %s", ErrInvalidLegacyParameter, operator)
+	}
+
+	if (op.args >= 0 && len(values) != op.args) || (op.args < 0 && len(values) == 0) {
+		return nil, fmt.Errorf("%w: Invalid number of argument(s) for the %s ComparisonOperator", ErrInvalidLegacyParameter, operator)
+	}
+
+	args := make([]Expression, 0, len(values))
+	for _, val := range values {
+		args = append(args, lg.value(val))
+	}
+
+	return op.build(lg.name(attr), args), nil
+}
+
+func (lg *Legacy) updateAction(attr string, update *types.AttributeValueUpdate) (Expression, error) {
+	action := "PUT"
+	if update.Action != nil {
+		action = *update.Action
+	}
+
+	typ, ok := legacyActions[action]
+	if !ok {
+		return nil, fmt.Errorf("%w: Unsupported action on AttributeUpdates: %s", ErrInvalidLegacyParameter, action)
+	}
+
+	if update.Value == nil {
+		if typ != DELETE {
+			return nil, fmt.Errorf("%w: Only DELETE action is allowed when no attribute value is specified", ErrInvalidLegacyParameter)
+		}
+
+		return &ActionExpression{Token: Token{Type: REMOVE, Literal: REMOVE}, Left: lg.name(attr)}, nil
+	}
+
+	return &ActionExpression{Token: Token{Type: typ, Literal: string(typ)}, Left: lg.name(attr), Right: lg.value(update.Value)}, nil
+}
+
+func (lg *Legacy) conditional(conditions []Expression, conditionalOperator string) (*ConditionalExpression, error) {
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	operator := AND
+	if conditionalOperator == OR {
+		operator = OR
+	} else if conditionalOperator != "" && conditionalOperator != AND {
+		return nil, fmt.Errorf("%w: Unsupported ConditionalOperator: %s", ErrInvalidLegacyParameter, conditionalOperator)
+	}
+
+	exp := conditions[0]
+
+	for _, cond := range conditions[1:] {
+		exp = &InfixExpression{Token: Token{Type: TokenType(operator), Literal: operator}, Left: exp, Operator: operator, Right: cond}
+	}
+
+	return &ConditionalExpression{Token: Token{Type: IDENT}, Expression: exp}, nil
+}
+
+func (lg *Legacy) name(attr string) *Identifier {
+	placeholder := "#legacy" + strconv.Itoa(len(lg.Names))
+	lg.Names[placeholder] = attr
+
+	return newLegacyIdentifier(placeholder)
+}
+
+func (lg *Legacy) value(val *types.Item) *Identifier {
+	placeholder := ":legacy" + strconv.Itoa(len(lg.Values))
+	lg.Values[placeholder] = val
+
+	return newLegacyIdentifier(placeholder)
+}
+
+func newLegacyIdentifier(literal string) *Identifier {
+	return &Identifier{Token: Token{Type: IDENT, Literal: literal}, Value: literal}
+}
+
+func legacyInfix(operator string) legacyBuilder {
+	return func(name Expression, values []Expression) Expression {
+		return &InfixExpression{Token: Token{Type: TokenType(operator), Literal: operator}, Left: name, Operator: operator, Right: values[0]}
+	}
+}
+
+func legacyCall(function string) legacyBuilder {
+	return func(name Expression, values []Expression) Expression {
+		return &CallExpression{
+			Token:     Token{Type: LPAREN, Literal: "("},
+			Function:  newLegacyIdentifier(function),
+			Arguments: append([]Expression{name}, values...),
+		}
+	}
+}
+
+func legacyNot(build legacyBuilder) legacyBuilder {
+	return func(name Expression, values []Expression) Expression {
+		return &PrefixExpression{Token: Token{Type: NOT, Literal: NOT}, Operator: NOT, Right: build(name, values)}
+	}
+}
+
+func legacyIn(name Expression, values []Expression) Expression {
+	return &InExpression{Token: Token{Type: IN, Literal: IN}, Left: name, Range: values}
+}
+
+func legacyBetween(name Expression, values []Expression) Expression {
+	return &BetweenExpression{Token: Token{Type: BETWEEN, Literal: BETWEEN}, Left: name, Range: [2]Expression{values[0], values[1]}}
+}
+
+func existsString(exists *bool) string {
+	if exists == nil {
+		return "null"
+	}
+
+	return strconv.FormatBool(*exists)
+}
+
+func sortedLegacyKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,187 @@
+package language
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/truora/minidyn/types"
+)
+
+func TestLegacyConditions(t *testing.T) {
+	val := &types.Item{S: types.ToString("a")}
+	other := &types.Item{S: types.ToString("b")}
+
+	tests := []struct {
+		operator string
+		values   []*types.Item
+		expected string
+	}{
+		{"EQ", []*types.Item{val}, "(#legacy0 = :legacy0)"},
+		{"NE", []*types.Item{val}, "(#legacy0 <> :legacy0)"},
+		{"LE", []*types.Item{val}, "(#legacy0 <= :legacy0)"},
+		{"LT", []*types.Item{val}, "(#legacy0 < :legacy0)"},
+		{"GE", []*types.Item{val}, "(#legacy0 >= :legacy0)"},
+		{"GT", []*types.Item{val}, "(#legacy0 > :legacy0)"},
+		{"NOT_NULL", nil, "attribute_exists(#legacy0)"},
+		{"NULL", nil, "attribute_not_exists(#legacy0)"},
+		{"CONTAINS", []*types.Item{val}, "contains(#legacy0, :legacy0)"},
+		{"NOT_CONTAINS", []*types.Item{val}, "(NOTcontains(#legacy0, :legacy0))"},
+		{"BEGINS_WITH", []*types.Item{val}, "begins_with(#legacy0, :legacy0)"},
+		{"IN", []*types.Item{val, other}, "(#legacy0 IN (:legacy0, :legacy1))"},
+		{"BETWEEN", []*types.Item{val, other}, "#legacy0 BETWEEN :legacy0 AND :legacy1"},
+	}
+
+	for _, tt := range tests {
+		lg := NewLegacy()
+
+		conditional, err := lg.Conditions(map[string]*types.Condition{
+			"name": {ComparisonOperator: types.ToString(tt.operator), AttributeValueList: tt.values},
+		}, "")
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", tt.operator, err)
+		}
+
+		if conditional.String() != tt.expected {
+			t.Errorf("%s: expected=%q, got=%q", tt.operator, tt.expected, conditional.String())
+		}
+
+		if lg.Names["#legacy0"] != "name" {
+			t.Errorf("%s: attribute name placeholder was not registered", tt.operator)
+		}
+
+		if len(lg.Values) != len(tt.values) {
+			t.Errorf("%s: expected %d value placeholders, got %d", tt.operator, len(tt.values), len(lg.Values))
+		}
+	}
+}
+
+func TestLegacyConditionsErrors(t *testing.T) {
+	tests := []map[string]*types.Condition{
+		{"name": {ComparisonOperator: types.ToString("LIKE")}},
+		{"name": {ComparisonOperator: types.ToString("EQ")}},
+		{"name": {ComparisonOperator: types.ToString("BETWEEN"), AttributeValueList: []*types.Item{{S: types.ToString("a")}}}},
+		{"name": {ComparisonOperator: types.ToString("IN")}},
+	}
+
+	for _, conditions := range tests {
+		_, err := NewLegacy().Conditions(conditions, "")
+		if !errors.Is(err, ErrInvalidLegacyParameter) {
+			t.Errorf("expected invalid legacy parameter error, got %v", err)
+		}
+	}
+
+	_, err := NewLegacy().Conditions(map[string]*types.Condition{
+		"name": {ComparisonOperator: types.ToString("NULL")},
+	}, "XOR")
+	if !errors.Is(err, ErrInvalidLegacyParameter) {
+		t.Errorf("expected invalid conditional operator error, got %v", err)
+	}
+}
+
+func TestLegacyExpected(t *testing.T) {
+	lg := NewLegacy()
+
+	conditional, err := lg.Expected(map[string]*types.ExpectedAttributeValue{
+		"a": {Value: &types.Item{S: types.ToString("a")}},
+		"b": {Exists: &boolFalse},
+		"c": {ComparisonOperator: types.ToString("NOT_NULL")},
+	}, "OR")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := "(((#legacy0 = :legacy0) OR attribute_not_exists(#legacy1)) OR attribute_exists(#legacy2))"
+	if conditional.String() != expected {
+		t.Errorf("expected=%q, got=%q", expected, conditional.String())
+	}
+
+	env := NewEnvironment()
+
+	err = env.AddAttributes(lg.Values)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	env.Aliases = lg.Names
+
+	if Eval(conditional, env) != TRUE {
+		t.Errorf("expected the translated condition to match")
+	}
+
+	conditional, err = lg.Expected(nil, "")
+	if conditional != nil || err != nil {
+		t.Errorf("expected no condition for an empty Expected, got %v, %v", conditional, err)
+	}
+}
+
+func TestLegacyExpectedErrors(t *testing.T) {
+	tests := []*types.ExpectedAttributeValue{
+		{},
+		{Exists: &boolTrue},
+		{Exists: &boolFalse, Value: &types.Item{S: types.ToString("a")}},
+		{ComparisonOperator: types.ToString("EQ"), Value: &types.Item{S: types.ToString("a")}},
+	}
+
+	for _, expected := range tests {
+		_, err := NewLegacy().Expected(map[string]*types.ExpectedAttributeValue{"a": expected}, "")
+		if !errors.Is(err, ErrInvalidLegacyParameter) {
+			t.Errorf("expected invalid legacy parameter error, got %v", err)
+		}
+	}
+}
+
+func TestLegacyAttributeUpdates(t *testing.T) {
+	lg := NewLegacy()
+
+	update, err := lg.AttributeUpdates(map[string]*types.AttributeValueUpdate{
+		"a": {Value: &types.Item{S: types.ToString("new")}},
+		"b": {Action: types.ToString("ADD"), Value: &types.Item{N: types.ToString("1")}},
+		"c": {Action: types.ToString("DELETE")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	env := NewEnvironment()
+	env.Aliases = lg.Names
+
+	err = env.AddAttributes(map[string]*types.Item{
+		"b": {N: types.ToString("2")},
+		"c": {S: types.ToString("old")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	err = env.AddAttributes(lg.Values)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	result := EvalUpdate(update, env)
+	if isError(result) {
+		t.Fatalf("unexpected error %v", result.Inspect())
+	}
+
+	if env.Get("a").Inspect() != "new" {
+		t.Errorf("expected a to be set, got %s", env.Get("a").Inspect())
+	}
+
+	if env.Get("b").Inspect() != "3" {
+		t.Errorf("expected b to be incremented, got %s", env.Get("b").Inspect())
+	}
+
+	if env.Get("c") != UNDEFINED {
+		t.Errorf("expected c to be removed, got %s", env.Get("c").Inspect())
+	}
+
+	_, err = NewLegacy().AttributeUpdates(map[string]*types.AttributeValueUpdate{"a": {Action: types.ToString("PUT")}})
+	if !errors.Is(err, ErrInvalidLegacyParameter) {
+		t.Errorf("expected invalid legacy parameter error, got %v", err)
+	}
+
+	_, err = NewLegacy().AttributeUpdates(map[string]*types.AttributeValueUpdate{"a": {Action: types.ToString("REPLACE"), Value: &types.Item{S: types.ToString("a")}}})
+	if !errors.Is(err, ErrInvalidLegacyParameter) {
+		t.Errorf("expected invalid legacy parameter error, got %v", err)
+	}
+}
@@ -83,10 +83,22 @@ func (l *Lexer) manageGreaterThanToken() Token {
 
 // NextToken look up for the next token
 func (l *Lexer) NextToken() Token {
-	var tok Token
-
 	l.skipWhitespace()
 
+	pos := l.position
+	if pos > len(l.input) {
+		pos = len(l.input)
+	}
+
+	tok := l.readToken()
+	tok.Pos = pos
+
+	return tok
+}
+
+func (l *Lexer) readToken() Token {
+	var tok Token
+
 	single, ok := singleChar[l.ch]
 	if ok {
 		tok = newToken(single, l.ch)
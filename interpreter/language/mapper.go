@@ -132,3 +132,20 @@ func mapAttributeToNumberSet(val *types.Item) (Object, error) {
 		Value: ns,
 	}, nil
 }
+
+// copyObject returns a copy of the collections, the scalar values are returned as they are
+func copyObject(obj Object) Object {
+	switch obj.(type) {
+	case *List, *Map, *StringSet, *NumberSet, *BinarySet:
+		item := obj.ToDynamoDB()
+
+		val, err := MapToObject(&item)
+		if err != nil {
+			return newError("%s", err.Error())
+		}
+
+		return val
+	}
+
+	return obj
+}
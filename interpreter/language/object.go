@@ -3,6 +3,8 @@ package language
 import (
 	"bytes"
 	"fmt"
+	"math"
+	"math/big"
 	"sort"
 	"strconv"
 	"strings"
@@ -128,11 +130,35 @@ func (i *Number) Add(obj Object) Object {
 		return newError("Incorrect operand type for operator or function; operator: ADD, operand type: %s", obj.Type())
 	}
 
-	i.Value += n.Value
+	sum := addNumbers(i.Value, n.Value)
+	if isError(sum) {
+		return sum
+	}
+
+	i.Value = sum.(*Number).Value
 
 	return UNDEFINED
 }
 
+// maxExactNumber is the first integer that float64 numbers may round, 2^53 + 1 is parsed as 2^53
+const maxExactNumber = 1 << 53
+
+// addNumbers adds the numbers as decimals, 0.1 + 0.2 is 0.3 like in DynamoDB,
+// the numbers that float64 rounds are reported as unsupported instead of storing a different value
+func addNumbers(a, b float64) Object {
+	x, _ := new(big.Rat).SetString(numToString(a))
+	y, _ := new(big.Rat).SetString(numToString(b))
+	sum, _ := x.Add(x, y).Float64()
+
+	for _, v := range []float64{a, b, sum} {
+		if math.Abs(v) >= maxExactNumber {
+			return newUnsupportedError("arithmetic with numbers from %d is not supported", int64(maxExactNumber))
+		}
+	}
+
+	return &Number{Value: sum}
+}
+
 // Boolean is the representation of boolean
 type Boolean struct {
 	Value bool
@@ -217,6 +243,11 @@ func (n *Null) ToDynamoDB() types.Item {
 // Error is the representation of errors
 type Error struct {
 	Message string
+	// typeMismatch marks the errors caused by the type of an operand, they are only reported
+	// for the values of the expression because the attribute paths with other types do not match
+	typeMismatch bool
+	// Unsupported marks the valid expressions that the interpreter cannot evaluate like DynamoDB
+	Unsupported bool
 }
 
 // Type returns the object type
@@ -418,6 +449,10 @@ func (l *List) ToDynamoDB() types.Item {
 
 // Get returns the contained object in the position
 func (l *List) Get(position int64) Object {
+	if position < 0 || position >= int64(len(l.Value)) {
+		return UNDEFINED
+	}
+
 	obj := l.Value[position]
 	if obj == nil {
 		return UNDEFINED
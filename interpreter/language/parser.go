@@ -2,14 +2,16 @@ package language
 
 import (
 	"fmt"
+	"strings"
 )
 
 // Parser represent the interpreter parser
 type Parser struct {
 	l         *Lexer
+	prevToken Token
 	curToken  Token
 	peekToken Token
-	errors    []string
+	errors    []error
 
 	unsupported bool
 
@@ -59,7 +61,7 @@ var precedences = map[TokenType]int{
 func NewParser(l *Lexer) *Parser {
 	p := &Parser{
 		l:      l,
-		errors: []string{},
+		errors: []error{},
 	}
 
 	p.prefixParseFns = map[TokenType]prefixParseFn{}
@@ -93,7 +95,7 @@ func NewParser(l *Lexer) *Parser {
 func NewUpdateParser(l *Lexer) *Parser {
 	p := &Parser{
 		l:      l,
-		errors: []string{},
+		errors: []error{},
 	}
 
 	p.prefixParseFns = map[TokenType]prefixParseFn{}
@@ -120,21 +122,103 @@ func NewUpdateParser(l *Lexer) *Parser {
 	return p
 }
 
+// NewProjectionParser creates a new parser for projection expressions
+func NewProjectionParser(l *Lexer) *Parser {
+	p := &Parser{
+		l:      l,
+		errors: []error{},
+	}
+
+	p.prefixParseFns = map[TokenType]prefixParseFn{}
+	p.registerPrefix(IDENT, p.parseIdentifier)
+
+	p.infixParseFns = make(map[TokenType]infixParseFn)
+	p.registerInfix(LBRACKET, p.parseIndexExpression)
+	p.registerInfix(DOT, p.parseIndexExpression)
+
+	// Read two tokens, so curToken and peekToken are both set
+	p.nextToken()
+	p.nextToken()
+
+	return p
+}
+
 // IsUnsupportedExpression return if the parsed expression is not a supported feature
 func (p *Parser) IsUnsupportedExpression() bool {
 	return p.unsupported
 }
 
 func (p *Parser) parseIdentifier() Expression {
+	p.checkReservedWord()
+
 	return &Identifier{Token: p.curToken, Value: p.curToken.Literal}
 }
 
-// Errors returns the errors found while parsing
+// checkReservedWord reports the reserved words used as top level attribute names,
+// the function names and the nested attributes are allowed
+func (p *Parser) checkReservedWord() {
+	tok := p.curToken
+	if tok.Type != IDENT || p.peekTokenIs(LPAREN) || p.prevToken.Type == DOT || !IsReservedWord(strings.ToUpper(tok.Literal)) {
+		return
+	}
+
+	p.errors = append(p.errors, &ReservedKeywordError{Keyword: tok.Literal, Pos: tok.Pos})
+}
+
+// Errors returns the messages of the errors found while parsing
 func (p *Parser) Errors() []string {
-	return p.errors
+	msgs := make([]string, len(p.errors))
+
+	for i, err := range p.errors {
+		msgs[i] = err.Error()
+	}
+
+	return msgs
+}
+
+// Err returns the first error found while parsing, the syntax errors are *SyntaxError
+// and the reserved words used as attribute names are *ReservedKeywordError
+func (p *Parser) Err() error {
+	if len(p.errors) == 0 {
+		return nil
+	}
+
+	return p.errors[0]
+}
+
+// syntaxError records an unexpected token, prev and next are the tokens around it
+func (p *Parser) syntaxError(tok, prev, next Token) {
+	literal := tok.Literal
+	if tok.Type == EOF {
+		literal = "<EOF>"
+	}
+
+	start := tok.Pos
+	if prev.Type != "" {
+		start = prev.Pos
+	}
+
+	end := tok.End()
+	if next.End() > end {
+		end = next.End()
+	}
+
+	p.errors = append(p.errors, &SyntaxError{
+		Token: literal,
+		Near:  strings.TrimSpace(p.l.input[start:end]),
+		Pos:   tok.Pos,
+	})
+}
+
+// peekError records the peek token as unexpected
+func (p *Parser) peekError() {
+	lookahead := *p.l
+
+	p.syntaxError(p.peekToken, p.curToken, lookahead.NextToken())
 }
 
 func (p *Parser) nextToken() {
+	p.prevToken = p.curToken
 	p.curToken = p.peekToken
 	p.peekToken = p.l.NextToken()
 }
@@ -144,8 +228,7 @@ func (p *Parser) ParseConditionalExpression() *ConditionalExpression {
 	stmt := &ConditionalExpression{Token: p.curToken}
 
 	if p.curToken.Type == IDENT && p.peekToken.Type == EOF {
-		msg := fmt.Sprintf("Syntax error; token: <EOF>, near: %q", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.peekError()
 
 		return stmt
 	}
@@ -170,15 +253,14 @@ func (p *Parser) parseGroupedExpression() Expression {
 	return exp
 }
 
-func (p *Parser) noPrefixParseFnError(t TokenType) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", t)
-	p.errors = append(p.errors, msg)
+func (p *Parser) noPrefixParseFnError() {
+	p.syntaxError(p.curToken, p.prevToken, p.peekToken)
 }
 
 func (p *Parser) parseExpression(precedence int) Expression {
 	prefix, ok := p.prefixParseFns[p.curToken.Type]
 	if !ok {
-		p.noPrefixParseFnError(p.curToken.Type)
+		p.noPrefixParseFnError()
 
 		return nil
 	}
@@ -323,9 +405,30 @@ func (p *Parser) ParseUpdateExpression() *UpdateStatement {
 	return stmt
 }
 
+// ParseProjectionExpression it tokenizes the projection expression and returns a ProjectionExpression
+func (p *Parser) ParseProjectionExpression() *ProjectionExpression {
+	stmt := &ProjectionExpression{Token: p.curToken, Paths: []Expression{}}
+
+	if p.curToken.Type == EOF {
+		return stmt
+	}
+
+	stmt.Paths = append(stmt.Paths, p.parseExpression(precedenceValueLowset))
+
+	for p.peekTokenIs(COMMA) {
+		p.nextToken()
+		p.nextToken()
+
+		stmt.Paths = append(stmt.Paths, p.parseExpression(precedenceValueLowset))
+	}
+
+	p.expectPeek(EOF)
+
+	return stmt
+}
+
 func (p *Parser) parseUnsupportedExpression() Expression {
-	msg := fmt.Sprintf("the %s expression is not supported yet", p.curToken.Type)
-	p.errors = append(p.errors, msg)
+	p.errors = append(p.errors, fmt.Errorf("the %s expression is not supported yet", p.curToken.Type))
 
 	p.unsupported = true
 
@@ -354,12 +457,35 @@ func (p *Parser) parseAction(token Token) *ActionExpression {
 	if token.Type != REMOVE {
 		p.nextToken()
 
-		action.Right = p.parseExpression(precedenceValueLowset)
+		action.Right = p.parseActionValue(token)
 	}
 
 	return action
 }
 
+// parseActionValue parses the value of an action, the SET values have at most one + or - operator
+func (p *Parser) parseActionValue(token Token) Expression {
+	if token.Type != SET {
+		return p.parseExpression(precedenceValueLowset)
+	}
+
+	value := p.parseExpression(precedenceValueOperators)
+
+	if p.peekTokenIs(PLUS) || p.peekTokenIs(MINUS) {
+		p.nextToken()
+
+		value = p.parseInfixExpression(value)
+	}
+
+	if p.peekTokenIs(PLUS) || p.peekTokenIs(MINUS) {
+		p.peekError()
+
+		return nil
+	}
+
+	return value
+}
+
 func (p *Parser) parseActions(token Token) []Expression {
 	actions := []Expression{}
 
@@ -415,7 +541,7 @@ func (p *Parser) tokenIsOneOf(ts []TokenType) bool {
 
 func (p *Parser) expectPeek(t TokenType) bool {
 	if !p.peekTokenIs(t) {
-		p.peekError(t)
+		p.peekError()
 
 		return false
 	}
@@ -425,12 +551,6 @@ func (p *Parser) expectPeek(t TokenType) bool {
 	return true
 }
 
-func (p *Parser) peekError(t TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead",
-		t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
-}
-
 func (p *Parser) registerPrefix(tokenType TokenType, fn prefixParseFn) {
 	p.prefixParseFns[tokenType] = fn
 }
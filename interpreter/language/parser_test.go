@@ -16,7 +16,7 @@ func TestIdentifierExpression(t *testing.T) {
 		t.Fatalf("condition expression with only an identifier must fail")
 	}
 
-	expect := "Syntax error; token: <EOF>, near: \"foobar\""
+	expect := "Syntax error; token: \"<EOF>\", near: \"foobar\""
 	if errors[0] != expect {
 		t.Fatalf("expect error to be equal to %s got=%s", expect, errors[0])
 	}
@@ -286,19 +286,19 @@ func TestParsingErrors(t *testing.T) {
 	}{
 		{
 			"a != b",
-			"no prefix parse function for ILLEGAL found",
+			`Syntax error; token: "!", near: "a !="`,
 		},
 		{
 			"=a",
-			"no prefix parse function for = found",
+			`Syntax error; token: "=", near: "=a"`,
 		},
 		{
 			"size(a",
-			"expected next token to be ), got EOF instead",
+			`Syntax error; token: "<EOF>", near: "a"`,
 		},
 		{
 			"b BETWEEN a c",
-			"expected next token to be AND, got IDENT instead",
+			`Syntax error; token: "c", near: "a c"`,
 		},
 	}
 
@@ -312,7 +312,7 @@ func TestParsingErrors(t *testing.T) {
 			return
 		}
 
-		actual := p.errors[0]
+		actual := p.Errors()[0]
 		if actual != tt.expected {
 			t.Errorf("expected=%q, got=%q", tt.expected, actual)
 		}
@@ -507,6 +507,43 @@ func TestParsingUnsupportedExpressions(t *testing.T) {
 	}
 }
 
+func TestParsingProjectionExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"a", "a"},
+		{"a, #b", "a, #b"},
+		{"a.b, c[1], #d.e[2]", "(a[b]), (c[1]), ((#d[e])[2])"},
+	}
+
+	for _, tt := range tests {
+		l := NewLexer(tt.input)
+		p := NewProjectionParser(l)
+		projection := p.ParseProjectionExpression()
+		checkParserErrors(t, p)
+
+		if projection.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, projection.String())
+		}
+	}
+}
+
+func TestParsingProjectionExpressionErrors(t *testing.T) {
+	inputs := []string{"a b", "a,", "a = :b"}
+
+	for _, input := range inputs {
+		l := NewLexer(input)
+		p := NewProjectionParser(l)
+		p.ParseProjectionExpression()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected errors parsing %q", input)
+		}
+	}
+}
+
 func BenchmarkParser(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		l := NewLexer(`attribute_exists(:b) AND begins_with(:b, #s) OR #c`)
@@ -518,3 +555,69 @@ func BenchmarkParser(b *testing.B) {
 		}
 	}
 }
+
+func TestSyntaxError(t *testing.T) {
+	tests := map[string]string{
+		"foobar":             `Syntax error; token: "<EOF>", near: "foobar"`,
+		"a = :a AND":         `Syntax error; token: "<EOF>", near: "AND"`,
+		"#a != :a":           `Syntax error; token: "!", near: "#a !="`,
+		"attribute_exists(b": `Syntax error; token: "<EOF>", near: "b"`,
+		"a = :a AND (b":      `Syntax error; token: "<EOF>", near: "b"`,
+		"my-attr = :a":       `Syntax error; token: "-", near: "my-attr"`,
+		"#a = :a  OR  OR":    `Syntax error; token: "OR", near: "OR  OR"`,
+		"#name = :a":         "",
+	}
+
+	for input, expected := range tests {
+		p := NewParser(NewLexer(input))
+		p.ParseConditionalExpression()
+
+		actual := ""
+		if err := p.Err(); err != nil {
+			actual = err.Error()
+		}
+
+		if actual != expected {
+			t.Errorf("unexpected syntax error for %q. got=%q, want=%q", input, actual, expected)
+		}
+	}
+
+	p := NewParser(NewLexer("a = :a AND !b"))
+	p.ParseConditionalExpression()
+
+	syntaxErr, ok := p.Err().(*SyntaxError)
+	if !ok || syntaxErr.Pos != 11 || syntaxErr.Token != "!" {
+		t.Errorf("unexpected syntax error %#v", p.Err())
+	}
+}
+
+func TestReservedKeywordError(t *testing.T) {
+	tests := map[string]string{
+		"name = :a":             "Attribute name is a reserved keyword; reserved keyword: name",
+		"a.Status = :a":         "",
+		"size(a) > :a":          "",
+		"#name = :a AND b = :b": "",
+	}
+
+	for input, expected := range tests {
+		p := NewParser(NewLexer(input))
+		p.ParseConditionalExpression()
+
+		actual := ""
+		if err := p.Err(); err != nil {
+			actual = err.Error()
+		}
+
+		if actual != expected {
+			t.Errorf("unexpected error for %q. got=%q, want=%q", input, actual, expected)
+		}
+	}
+
+	p := NewUpdateParser(NewLexer("SET #a = :a, data = :b"))
+	p.ParseUpdateExpression()
+
+	reservedErr, ok := p.Err().(*ReservedKeywordError)
+	if !ok || reservedErr.Keyword != "data" || reservedErr.Pos != 13 {
+		t.Errorf("unexpected error %#v", p.Err())
+	}
+}
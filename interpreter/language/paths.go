@@ -0,0 +1,86 @@
+package language
+
+import (
+	"strings"
+)
+
+const (
+	invalidDocumentPathMsg = "The document path provided in the update expression is invalid for update"
+	missingAttributeMsg    = "The provided expression refers to an attribute that does not exist in the item"
+)
+
+// documentPath returns the names of the attribute path, the list positions are returned as [n]
+func documentPath(exp Expression, env *Environment) []string {
+	switch n := exp.(type) {
+	case *Identifier:
+		return []string{resolveAlias(n.Value, env)}
+	case *IndexExpression:
+		path := documentPath(n.Left, env)
+		if path == nil {
+			return nil
+		}
+
+		if n.Type == ObjectTypeList {
+			return append(path, "["+n.Index.String()+"]")
+		}
+
+		return append(path, resolveAlias(n.Index.String(), env))
+	}
+
+	return nil
+}
+
+func resolveAlias(name string, env *Environment) string {
+	if alias, ok := env.Aliases[name]; ok {
+		return alias
+	}
+
+	return name
+}
+
+// checkOverlappingPaths rejects the actions that change the same attribute or one of its parents,
+// DynamoDB does not define an order to apply them
+func checkOverlappingPaths(actions []*ActionExpression, env *Environment) Object {
+	paths := make([][]string, 0, len(actions))
+
+	for _, action := range actions {
+		path := documentPath(action.Left, env)
+		if path == nil {
+			continue
+		}
+
+		for _, other := range paths {
+			if errObj := comparePaths(other, path); isError(errObj) {
+				return errObj
+			}
+		}
+
+		paths = append(paths, path)
+	}
+
+	return UNDEFINED
+}
+
+func comparePaths(one, two []string) Object {
+	for i := 0; i < len(one) && i < len(two); i++ {
+		if one[i] == two[i] {
+			continue
+		}
+
+		if isListPosition(one[i]) != isListPosition(two[i]) {
+			return newError("Two document paths conflict with each other; must remove or rewrite one of these paths; path one: %s, path two: %s", formatPath(one), formatPath(two))
+		}
+
+		return UNDEFINED
+	}
+
+	return newError("Two document paths overlap with each other; must remove or rewrite one of these paths; path one: %s, path two: %s", formatPath(one), formatPath(two))
+}
+
+func isListPosition(name string) bool {
+	return strings.HasPrefix(name, "[")
+}
+
+func formatPath(path []string) string {
+	return "[" + strings.Join(path, ", ") + "]"
+}
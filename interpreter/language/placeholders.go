@@ -0,0 +1,88 @@
+package language
+
+import "strings"
+
+// Placeholders holds the expression attribute names (#name) and
+// the expression attribute values (:value) referenced in an expression
+type Placeholders struct {
+	Names  map[string]bool
+	Values map[string]bool
+}
+
+// NewPlaceholders creates an empty set of placeholders
+func NewPlaceholders() *Placeholders {
+	return &Placeholders{
+		Names:  map[string]bool{},
+		Values: map[string]bool{},
+	}
+}
+
+// Collect walks the node adding the placeholders used in it
+func (ph *Placeholders) Collect(n Node) {
+	switch node := n.(type) {
+	case *ConditionalExpression:
+		ph.collectExpressions(node.Expression)
+	case *UpdateStatement:
+		ph.collectExpressions(node.Expression)
+	case *ProjectionExpression:
+		ph.collectExpressions(node.Paths...)
+	case *UpdateExpression:
+		ph.collectExpressions(node.Expressions...)
+	case *ActionExpression:
+		ph.collectExpressions(node.Left, node.Right)
+	case *Identifier:
+		ph.add(node.Value)
+	default:
+		ph.collectOperands(n)
+	}
+}
+
+// collectOperands adds the placeholders used in the operands of the operator and function nodes
+func (ph *Placeholders) collectOperands(n Node) {
+	switch node := n.(type) {
+	case *PrefixExpression:
+		ph.collectExpressions(node.Right)
+	case *InfixExpression:
+		ph.collectExpressions(node.Left, node.Right)
+	case *IndexExpression:
+		ph.collectExpressions(node.Left, node.Index)
+	case *CallExpression:
+		ph.collectExpressions(node.Arguments...)
+	case *BetweenExpression:
+		ph.collectExpressions(node.Left, node.Range[0], node.Range[1])
+	case *InExpression:
+		ph.collectExpressions(node.Left)
+		ph.collectExpressions(node.Range...)
+	}
+}
+
+// CollectTokens adds the placeholders found by the lexer in the input,
+// it is useful when the input cannot be parsed
+func (ph *Placeholders) CollectTokens(input string) {
+	l := NewLexer(input)
+
+	for tok := l.NextToken(); tok.Type != EOF; tok = l.NextToken() {
+		if tok.Type == IDENT {
+			ph.add(tok.Literal)
+		}
+	}
+}
+
+func (ph *Placeholders) collectExpressions(exps ...Expression) {
+	for _, exp := range exps {
+		if exp == nil {
+			continue
+		}
+
+		ph.Collect(exp)
+	}
+}
+
+func (ph *Placeholders) add(literal string) {
+	switch {
+	case strings.HasPrefix(literal, "#"):
+		ph.Names[literal] = true
+	case strings.HasPrefix(literal, ":"):
+		ph.Values[literal] = true
+	}
+}
@@ -0,0 +1,85 @@
+package language
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlaceholdersCollect(t *testing.T) {
+	tests := []struct {
+		input  string
+		parse  func(l *Lexer) (*Parser, Node)
+		names  map[string]bool
+		values map[string]bool
+	}{
+		{
+			input: "#a = :a AND begins_with(#b.c, :b) OR #d[1] BETWEEN :c AND :d OR NOT #e IN (:e, :f)",
+			parse: func(l *Lexer) (*Parser, Node) {
+				p := NewParser(l)
+				return p, p.ParseConditionalExpression()
+			},
+			names: map[string]bool{"#a": true, "#b": true, "#d": true, "#e": true},
+			values: map[string]bool{
+				":a": true, ":b": true, ":c": true, ":d": true, ":e": true, ":f": true,
+			},
+		},
+		{
+			input: "SET #a = :a + :b, #b = if_not_exists(#b, :c) REMOVE #c",
+			parse: func(l *Lexer) (*Parser, Node) {
+				p := NewUpdateParser(l)
+				return p, p.ParseUpdateExpression()
+			},
+			names:  map[string]bool{"#a": true, "#b": true, "#c": true},
+			values: map[string]bool{":a": true, ":b": true, ":c": true},
+		},
+	}
+
+	for _, tt := range tests {
+		p, node := tt.parse(NewLexer(tt.input))
+		checkParserErrors(t, p)
+
+		ph := NewPlaceholders()
+		ph.Collect(node)
+
+		if !reflect.DeepEqual(ph.Names, tt.names) {
+			t.Errorf("unexpected names for %q: got=%v", tt.input, ph.Names)
+		}
+
+		if !reflect.DeepEqual(ph.Values, tt.values) {
+			t.Errorf("unexpected values for %q: got=%v", tt.input, ph.Values)
+		}
+	}
+}
+
+func TestPlaceholdersProjection(t *testing.T) {
+	p := NewProjectionParser(NewLexer("#a.b, c[1], #d"))
+	projection := p.ParseProjectionExpression()
+	checkParserErrors(t, p)
+
+	ph := NewPlaceholders()
+	ph.Collect(projection)
+
+	expected := map[string]bool{"#a": true, "#d": true}
+	if !reflect.DeepEqual(ph.Names, expected) {
+		t.Errorf("expected=%v, got=%v", expected, ph.Names)
+	}
+
+	if len(ph.Values) != 0 {
+		t.Errorf("expected no values, got=%v", ph.Values)
+	}
+}
+
+func TestPlaceholdersCollectTokens(t *testing.T) {
+	ph := NewPlaceholders()
+	ph.CollectTokens("#a = :a AND AND #b")
+
+	expectedNames := map[string]bool{"#a": true, "#b": true}
+	if !reflect.DeepEqual(ph.Names, expectedNames) {
+		t.Errorf("expected=%v, got=%v", expectedNames, ph.Names)
+	}
+
+	expectedValues := map[string]bool{":a": true}
+	if !reflect.DeepEqual(ph.Values, expectedValues) {
+		t.Errorf("expected=%v, got=%v", expectedValues, ph.Values)
+	}
+}
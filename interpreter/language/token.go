@@ -7,6 +7,13 @@ type TokenType string
 type Token struct {
 	Type    TokenType
 	Literal string
+	// Pos is the offset of the token in the expression
+	Pos int
+}
+
+// End returns the offset of the first byte after the token
+func (t Token) End() int {
+	return t.Pos + len(t.Literal)
 }
 
 const (
@@ -0,0 +1,22 @@
+package language
+
+// FailedExpression returns the sub-expression that made the conditional expression evaluate to false,
+// the operands of AND are followed while the OR and NOT expressions are reported as a whole
+func FailedExpression(node Node, env *Environment) Node {
+	switch n := node.(type) {
+	case *ConditionalExpression:
+		return FailedExpression(n.Expression, env)
+	case *InfixExpression:
+		if n.Operator != "AND" {
+			return n
+		}
+
+		if Eval(n.Left, env) != TRUE {
+			return FailedExpression(n.Left, env)
+		}
+
+		return FailedExpression(n.Right, env)
+	}
+
+	return node
+}
@@ -0,0 +1,42 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/truora/minidyn/types"
+)
+
+func TestFailedExpression(t *testing.T) {
+	env := NewEnvironment()
+
+	err := env.AddAttributes(map[string]*types.Item{
+		"a":  {S: types.ToString("a")},
+		"n":  {N: types.ToString("1")},
+		":a": {S: types.ToString("a")},
+		":n": {N: types.ToString("2")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	tests := map[string]string{
+		"a = :a AND n = :n":                          "(n = :n)",
+		"n = :n AND a = :a":                          "(n = :n)",
+		"a = :a AND (n > :n OR attribute_exists(b))": "((n > :n) OR attribute_exists(b))",
+		"a = :a AND NOT attribute_exists(n)":         "(NOTattribute_exists(n))",
+		"attribute_exists(b)":                        "attribute_exists(b)",
+	}
+
+	for input, expected := range tests {
+		p := NewParser(NewLexer(input))
+		conditional := p.ParseConditionalExpression()
+
+		if len(p.Errors()) != 0 {
+			t.Fatalf("parsing %q failed: %s", input, p.Err())
+		}
+
+		if failed := FailedExpression(conditional, env).String(); failed != expected {
+			t.Errorf("unexpected failed expression for %q. got=%q, want=%q", input, failed, expected)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package interpreter
 import (
 	"errors"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/truora/minidyn/types"
@@ -186,6 +187,59 @@ func TestLanguageUpdate(t *testing.T) {
 			output:      nil,
 			expectedErr: ErrSyntaxError,
 		},
+		{
+			name: "values evaluated before the update",
+			input: UpdateInput{
+				TableName:  "test",
+				Expression: "SET a = m.c, m.c = a, m.l[5] = :a ADD m.n :a",
+				Item: map[string]*types.Item{
+					"a": {N: types.ToString("1")},
+					"m": {M: map[string]*types.Item{
+						"c": {N: types.ToString("2")},
+						"l": {L: []*types.Item{}},
+					}},
+				},
+				Attributes: map[string]*types.Item{
+					":a": {N: types.ToString("0.1")},
+				},
+			},
+			output: map[string]*types.Item{
+				"a": {N: types.ToString("2")},
+				"m": {M: map[string]*types.Item{
+					"c": {N: types.ToString("1")},
+					"l": {L: []*types.Item{{N: types.ToString("0.1")}}},
+					"n": {N: types.ToString("0.1")},
+				}},
+			},
+		},
+		{
+			name: "overlapping paths",
+			input: UpdateInput{
+				TableName:  "test",
+				Expression: "SET m.c = :a REMOVE m",
+				Item: map[string]*types.Item{
+					"m": {M: map[string]*types.Item{}},
+				},
+				Attributes: map[string]*types.Item{
+					":a": {N: types.ToString("1")},
+				},
+			},
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name: "number precision",
+			input: UpdateInput{
+				TableName:  "test",
+				Expression: "SET a = a + :a",
+				Item: map[string]*types.Item{
+					"a": {N: types.ToString("9007199254740993")},
+				},
+				Attributes: map[string]*types.Item{
+					":a": {N: types.ToString("1")},
+				},
+			},
+			expectedErr: ErrUnsupportedFeature,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -194,3 +248,110 @@ func TestLanguageUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestLanguageMatchConvertsOnlyReadAttributes(t *testing.T) {
+	interpreter := Language{}
+	input := MatchInput{
+		TableName:  "test",
+		Expression: "a = :a",
+		Item: map[string]*types.Item{
+			"a": {S: types.ToString("a")},
+			"n": {N: types.ToString("not a number")},
+		},
+		Attributes: map[string]*types.Item{":a": {S: types.ToString("a")}},
+	}
+
+	matched, err := interpreter.Match(input)
+	if err != nil || !matched {
+		t.Errorf("unexpected result; matched=%v, err=%v", matched, err)
+	}
+
+	input.Expression = "n = :a"
+
+	_, err = interpreter.Match(input)
+	if !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("unexpected error; expected=%v, got=%v", ErrUnsupportedFeature, err)
+	}
+}
+
+func BenchmarkLanguageMatchWideItem(b *testing.B) {
+	item := map[string]*types.Item{}
+
+	for i := 0; i < 100; i++ {
+		item["attr"+strconv.Itoa(i)] = &types.Item{M: map[string]*types.Item{
+			"value": {N: types.ToString(strconv.Itoa(i))},
+			"tags":  {SS: []*string{types.ToString("a"), types.ToString("b")}},
+		}}
+	}
+
+	interpreter := Language{}
+	input := MatchInput{
+		TableName:  "test",
+		Expression: "attr10.#v = :v",
+		Item:       item,
+		Aliases:    map[string]string{"#v": "value"},
+		Attributes: map[string]*types.Item{":v": {N: types.ToString("10")}},
+	}
+
+	for i := 0; i < b.N; i++ {
+		matched, err := interpreter.Match(input)
+		if err != nil || !matched {
+			b.Fatalf("unexpected result; matched=%v, err=%v", matched, err)
+		}
+	}
+}
+
+func TestLanguageTracer(t *testing.T) {
+	traces := []Trace{}
+	interpreter := Language{
+		Tracer: TracerFunc(func(tr Trace) {
+			traces = append(traces, tr)
+		}),
+	}
+
+	input := MatchInput{
+		TableName:      "test",
+		Expression:     "a = :a AND n = :n",
+		ExpressionType: ExpressionTypeFilter,
+		Item: map[string]*types.Item{
+			"a": {S: types.ToString("a")},
+			"n": {N: types.ToString("1")},
+		},
+		Attributes: map[string]*types.Item{
+			":a": {S: types.ToString("a")},
+			":n": {N: types.ToString("2")},
+		},
+		Key: map[string]*types.Item{"a": {S: types.ToString("a")}},
+	}
+
+	matched, err := interpreter.Match(input)
+	if err != nil || matched {
+		t.Fatalf("unexpected result; matched=%v, err=%v", matched, err)
+	}
+
+	err = interpreter.Update(UpdateInput{
+		TableName:  "test",
+		Expression: "SET n = :n",
+		Item:       input.Item,
+		Attributes: input.Attributes,
+		Key:        input.Key,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	expected := []string{
+		`language filter expression "a = :a AND n = :n" in table "test" with key {"a": {S: "a"}}: not matched at (n = :n)`,
+		`language update expression "SET n = :n" in table "test" with key {"a": {S: "a"}}: updated`,
+	}
+
+	if len(traces) != len(expected) {
+		t.Fatalf("unexpected traces %v", traces)
+	}
+
+	for i, tr := range traces {
+		if tr.String() != expected[i] {
+			t.Errorf("unexpected trace. got=%q, want=%q", tr.String(), expected[i])
+		}
+	}
+}
@@ -2,9 +2,9 @@ package interpreter
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 
+	"github.com/truora/minidyn/interpreter/language"
 	"github.com/truora/minidyn/types"
 )
 
@@ -14,12 +14,17 @@ type UpdaterFunc func(map[string]*types.Item, map[string]*types.Item)
 // MatcherFunc function used to filter data
 type MatcherFunc func(map[string]*types.Item, map[string]*types.Item) bool
 
-// Native simple interpreter using pure go functions
+// Native simple interpreter using pure go functions, the functions are registered
+// by the normalized syntax tree of their expressions so the whitespace, parentheses
+// and alias differences between the registered and the evaluated expressions are ignored
 type Native struct {
 	filterExpressions    map[string]MatcherFunc
 	keyExpressions       map[string]MatcherFunc
 	writeCondExpressions map[string]MatcherFunc
 	updateExpressions    map[string]UpdaterFunc
+	// Tracer receives every evaluated expression when it is set,
+	// including the ones without a registered function
+	Tracer Tracer
 }
 
 // NewNativeInterpreter returns a new native interpreter
@@ -34,84 +39,264 @@ func NewNativeInterpreter() *Native {
 
 // Match evalute the item with given expression and attributes
 func (ni *Native) Match(input MatchInput) (bool, error) {
-	matcher, err := ni.getMatcher(input.TableName, input.Expression, input.ExpressionType)
-	if err != nil {
-		return false, err
+	matched := false
+
+	matcher, err := ni.getMatcher(input)
+	if err == nil {
+		matched = matcher(input.Item, input.Attributes)
+	}
+
+	if ni.Tracer != nil {
+		ni.Tracer.Trace(Trace{
+			TableName:      input.TableName,
+			ExpressionType: input.ExpressionType,
+			Expression:     input.Expression,
+			Interpreter:    InterpreterNative,
+			Key:            input.Key,
+			Matched:        matched,
+			Err:            err,
+		})
 	}
 
-	return matcher(input.Item, input.Attributes), nil
+	return matched, err
 }
 
 // Update change the item with given expression and attributes
 func (ni *Native) Update(input UpdateInput) error {
-	updater, found := ni.updateExpressions[input.TableName+"|"+hashExpressionKey(input.Expression)]
-	if !found {
-		return fmt.Errorf(
-			"%w: updater not found for %q expression in table %q",
-			ErrUnsupportedFeature,
-			input.Expression,
-			input.TableName,
-		)
+	updater, err := ni.getUpdater(input)
+	if err == nil {
+		updater(input.Item, input.Attributes)
 	}
 
-	updater(input.Item, input.Attributes)
+	if ni.Tracer != nil {
+		ni.Tracer.Trace(Trace{
+			TableName:      input.TableName,
+			ExpressionType: ExpressionTypeUpdate,
+			Expression:     input.Expression,
+			Interpreter:    InterpreterNative,
+			Key:            input.Key,
+			Err:            err,
+		})
+	}
 
-	return nil
+	return err
 }
 
-func (ni *Native) getMatcher(tablename, expression string, kind ExpressionType) (MatcherFunc, error) {
-	var (
-		matcher MatcherFunc
-		found   bool
+func (ni *Native) getUpdater(input UpdateInput) (UpdaterFunc, error) {
+	for _, key := range updateKeys(input.Expression, input.Aliases) {
+		if updater, found := ni.updateExpressions[input.TableName+"|"+key]; found {
+			return updater, nil
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"%w: updater not found for %q expression in table %q",
+		ErrUnsupportedFeature,
+		input.Expression,
+		input.TableName,
 	)
+}
 
+func (ni *Native) matchers(kind ExpressionType) map[string]MatcherFunc {
 	switch kind {
 	case ExpressionTypeKey:
-		matcher, found = ni.keyExpressions[tablename+"|"+hashExpressionKey(expression)]
+		return ni.keyExpressions
 	case ExpressionTypeFilter:
-		matcher, found = ni.filterExpressions[tablename+"|"+hashExpressionKey(expression)]
+		return ni.filterExpressions
 	case ExpressionTypeConditional:
-		matcher, found = ni.writeCondExpressions[tablename+"|"+hashExpressionKey(expression)]
+		return ni.writeCondExpressions
+	}
+
+	return nil
+}
+
+func (ni *Native) getMatcher(input MatchInput) (MatcherFunc, error) {
+	var (
+		matcher MatcherFunc
+		found   bool
+	)
+
+	matchers := ni.matchers(input.ExpressionType)
+
+	for _, key := range conditionKeys(input.Expression, input.Aliases) {
+		matcher, found = matchers[input.TableName+"|"+key]
+		if found {
+			break
+		}
 	}
 
 	if !found {
 		return matcher, fmt.Errorf(
 			"%w: matcher %q not found for %q expression in table %q",
 			ErrUnsupportedFeature,
-			string(kind),
-			expression,
-			tablename,
+			string(input.ExpressionType),
+			input.Expression,
+			input.TableName,
 		)
 	}
 
 	return matcher, nil
 }
 
-func hashExpressionKey(s string) string {
-	out := strings.Split(strings.TrimSpace(s), "")
-	sort.Strings(out)
+// conditionKeys returns the keys of a condition expression, the expressions that the language
+// interpreter can not parse are keyed by their text with the whitespace normalized
+func conditionKeys(expr string, aliases map[string]string) []string {
+	conditional, err := ParseCondition(expr)
+	if err != nil {
+		return []string{strings.Join(strings.Fields(expr), " ")}
+	}
 
-	return strings.Join(out, "")
+	return expressionKeys(conditional, aliases)
 }
 
-// AddUpdater add expression updater to use on key or filter queries
-func (ni *Native) AddUpdater(tablename string, expr string, updater UpdaterFunc) {
-	ni.updateExpressions[tablename+"|"+hashExpressionKey(expr)] = updater
+// updateKeys returns the keys of an update expression like conditionKeys
+func updateKeys(expr string, aliases map[string]string) []string {
+	update, err := ParseUpdate(expr)
+	if err != nil {
+		return []string{strings.Join(strings.Fields(expr), " ")}
+	}
+
+	return expressionKeys(update, aliases)
 }
 
-// AddMatcher add expression matcher to use on key or filter queries
-func (ni *Native) AddMatcher(tablename string, t ExpressionType, expr string, matcher MatcherFunc) {
-	// TODO validate the expresion(expr)
-	key := hashExpressionKey(expr)
+// expressionKeys returns the keys used to look up the registered functions, the key with
+// the aliases replaced by the attribute names is preferred over the one with the aliases as they are
+func expressionKeys(node language.Node, aliases map[string]string) []string {
+	key := normalizeExpression(node, nil)
+	if len(aliases) == 0 {
+		return []string{key}
+	}
 
-	switch t {
-	case ExpressionTypeKey:
-		ni.keyExpressions[tablename+"|"+key] = matcher
-	case ExpressionTypeFilter:
-		ni.filterExpressions[tablename+"|"+key] = matcher
-	case ExpressionTypeConditional:
-		ni.writeCondExpressions[tablename+"|"+key] = matcher
-	default:
-		panic("NativeInterpreter: unsupported expression type")
+	resolved := normalizeExpression(node, aliases)
+	if resolved == key {
+		return []string{key}
+	}
+
+	return []string{resolved, key}
+}
+
+// normalizeExpression prints the syntax tree with a fixed spacing and one pair of
+// parentheses for each operation, the aliases are replaced by their attribute names
+func normalizeExpression(node language.Node, aliases map[string]string) string {
+	switch n := node.(type) {
+	case *language.ConditionalExpression:
+		return normalizeExpression(n.Expression, aliases)
+	case *language.UpdateStatement:
+		return normalizeExpression(n.Expression, aliases)
+	case *language.UpdateExpression:
+		return normalizeActions(n, aliases)
+	case *language.ActionExpression:
+		return normalizeAction(n, aliases)
+	case *language.Identifier:
+		return normalizeIdentifier(n, aliases)
+	case nil:
+		return ""
+	}
+
+	return normalizeOperation(node, aliases)
+}
+
+func normalizeActions(update *language.UpdateExpression, aliases map[string]string) string {
+	actions := make([]string, 0, len(update.Expressions))
+	for _, action := range update.Expressions {
+		actions = append(actions, normalizeExpression(action, aliases))
+	}
+
+	return strings.Join(actions, " ")
+}
+
+func normalizeAction(action *language.ActionExpression, aliases map[string]string) string {
+	if action.Right == nil {
+		return action.Token.Literal + " " + normalizeExpression(action.Left, aliases)
+	}
+
+	return action.Token.Literal + " " + normalizeExpression(action.Left, aliases) + " " + normalizeExpression(action.Right, aliases)
+}
+
+func normalizeIdentifier(ident *language.Identifier, aliases map[string]string) string {
+	if name, ok := aliases[ident.Value]; ok {
+		return name
+	}
+
+	return ident.Value
+}
+
+// normalizeOperation prints the operators and the function calls with their operands
+func normalizeOperation(node language.Node, aliases map[string]string) string {
+	normalize := func(n language.Node) string {
+		return normalizeExpression(n, aliases)
+	}
+
+	switch n := node.(type) {
+	case *language.PrefixExpression:
+		return "(" + n.Operator + " " + normalize(n.Right) + ")"
+	case *language.InfixExpression:
+		return "(" + normalize(n.Left) + " " + n.Operator + " " + normalize(n.Right) + ")"
+	case *language.IndexExpression:
+		return normalizeIndex(n, aliases)
+	case *language.BetweenExpression:
+		return "(" + normalize(n.Left) + " BETWEEN " + normalize(n.Range[0]) + " AND " + normalize(n.Range[1]) + ")"
+	case *language.InExpression:
+		return "(" + normalize(n.Left) + " IN " + normalizeList(n.Range, aliases) + ")"
+	case *language.CallExpression:
+		return normalize(n.Function) + normalizeList(n.Arguments, aliases)
+	}
+
+	return node.String()
+}
+
+func normalizeIndex(index *language.IndexExpression, aliases map[string]string) string {
+	if index.Type == language.ObjectTypeMap {
+		return "(" + normalizeExpression(index.Left, aliases) + "." + normalizeExpression(index.Index, aliases) + ")"
+	}
+
+	return "(" + normalizeExpression(index.Left, aliases) + "[" + normalizeExpression(index.Index, aliases) + "])"
+}
+
+func normalizeList(expressions []language.Expression, aliases map[string]string) string {
+	items := make([]string, 0, len(expressions))
+	for _, e := range expressions {
+		items = append(items, normalizeExpression(e, aliases))
+	}
+
+	return "(" + strings.Join(items, ", ") + ")"
+}
+
+// AddUpdater add expression updater to use on key or filter queries. Registering an expression equivalent
+// to a registered one replaces its updater and returns ErrDuplicateRegistration to report the collision
+func (ni *Native) AddUpdater(tablename string, expr string, updater UpdaterFunc) error {
+	key := tablename + "|" + updateKeys(expr, nil)[0]
+	_, registered := ni.updateExpressions[key]
+
+	ni.updateExpressions[key] = updater
+
+	if registered {
+		return duplicateRegistrationError(tablename, expr)
+	}
+
+	return nil
+}
+
+// AddMatcher add expression matcher to use on key or filter queries. Registering an expression equivalent
+// to a registered one replaces its matcher and returns ErrDuplicateRegistration to report the collision
+func (ni *Native) AddMatcher(tablename string, t ExpressionType, expr string, matcher MatcherFunc) error {
+	matchers := ni.matchers(t)
+	if matchers == nil {
+		return fmt.Errorf("%w: native matchers for %q expressions", ErrUnsupportedFeature, string(t))
 	}
+
+	key := tablename + "|" + conditionKeys(expr, nil)[0]
+	_, registered := matchers[key]
+
+	matchers[key] = matcher
+
+	if registered {
+		return duplicateRegistrationError(tablename, expr)
+	}
+
+	return nil
+}
+
+func duplicateRegistrationError(tablename, expr string) error {
+	return fmt.Errorf("%w: %q replaces the function of an equivalent expression in table %q", ErrDuplicateRegistration, expr, tablename)
 }
@@ -2,6 +2,8 @@ package interpreter
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/truora/minidyn/types"
@@ -44,9 +46,9 @@ func TestNativeMatch(t *testing.T) {
 		t.Error("match without a defined expression should fail")
 	}
 
-	native.AddMatcher("test", ExpressionTypeConditional, ":a = a", func(m1, m2 map[string]*types.Item) bool {
+	mustRegister(t, native.AddMatcher("test", ExpressionTypeConditional, ":a = a", func(m1, m2 map[string]*types.Item) bool {
 		return true
-	})
+	}))
 
 	matched, err := native.Match(input)
 	if err != nil {
@@ -67,9 +69,9 @@ func TestAddMatcher(t *testing.T) {
 	}
 
 	for _, etype := range etypes {
-		native.AddMatcher("test", etype, ":a = a", func(m1, m2 map[string]*types.Item) bool {
+		mustRegister(t, native.AddMatcher("test", etype, ":a = a", func(m1, m2 map[string]*types.Item) bool {
 			return true
-		})
+		}))
 	}
 
 	if len(native.keyExpressions) != 1 {
@@ -85,7 +87,7 @@ func TestAddMatcher(t *testing.T) {
 	}
 
 	for _, etype := range etypes {
-		matcher, err := native.getMatcher("test", ":a = a", etype)
+		matcher, err := native.getMatcher(MatchInput{TableName: "test", Expression: ":a = a", ExpressionType: etype})
 		if err != nil {
 			t.Errorf("get %s should not fail but got %s error", etype, err)
 		}
@@ -114,7 +116,7 @@ func TestNativeUpdate(t *testing.T) {
 
 	input := UpdateInput{
 		TableName:  "test",
-		Expression: "set a = :b",
+		Expression: "SET a = :b",
 		Item:       item,
 		Attributes: map[string]*types.Item{
 			":b": {
@@ -130,9 +132,9 @@ func TestNativeUpdate(t *testing.T) {
 		t.Error("update without a defined expression should fail")
 	}
 
-	native.AddUpdater("test", "set a = :b", func(m1, m2 map[string]*types.Item) {
+	mustRegister(t, native.AddUpdater("test", "SET a = :b", func(m1, m2 map[string]*types.Item) {
 		m1["a"] = m2[":b"]
-	})
+	}))
 
 	err = native.Update(input)
 	if err != nil {
@@ -143,3 +145,240 @@ func TestNativeUpdate(t *testing.T) {
 		t.Error("item should have been updated")
 	}
 }
+
+func TestNativeNormalizedExpressions(t *testing.T) {
+	native := NewNativeInterpreter()
+	mustRegister(t, native.AddMatcher("test", ExpressionTypeFilter, "a = :b AND begins_with(c, :d)", func(m1, m2 map[string]*types.Item) bool {
+		return true
+	}))
+	mustRegister(t, native.AddMatcher("test", ExpressionTypeFilter, "#a = :b", func(m1, m2 map[string]*types.Item) bool {
+		return true
+	}))
+
+	tests := map[string]struct {
+		aliases map[string]string
+		found   bool
+	}{
+		"a = :b AND begins_with(c, :d)":             {found: true},
+		"  ((a=:b)) AND (begins_with( c ,:d ))":     {found: true},
+		"#x = :b AND begins_with(#y, :d)":           {aliases: map[string]string{"#x": "a", "#y": "c"}, found: true},
+		"#a = :b":                                   {aliases: map[string]string{"#a": "status"}, found: true},
+		"b = :a AND begins_with(c, :d)":             {},
+		"a = :b AND begins_with(d, :c)":             {},
+		"#y = :b AND begins_with(#x, :d)":           {aliases: map[string]string{"#x": "a", "#y": "c"}},
+		"a = :b AND begins_with(c, :d) AND e = :f":  {},
+		"a = :b AND (begins_with(c, :d) OR e = :f)": {},
+	}
+
+	for expression, tt := range tests {
+		_, err := native.Match(MatchInput{
+			TableName:      "test",
+			Expression:     expression,
+			Aliases:        tt.aliases,
+			ExpressionType: ExpressionTypeFilter,
+		})
+		if tt.found != (err == nil) {
+			t.Errorf("unexpected result for %q; found=%v, err=%v", expression, tt.found, err)
+		}
+	}
+
+	mustRegister(t, native.AddUpdater("test", "SET a = :a REMOVE b", func(m1, m2 map[string]*types.Item) {}))
+
+	err := native.Update(UpdateInput{TableName: "test", Expression: "REMOVE #b SET a=:a", Aliases: map[string]string{"#b": "b"}})
+	if !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("the order of the actions should be kept; got=%v", err)
+	}
+
+	err = native.Update(UpdateInput{TableName: "test", Expression: "SET #a = :a  REMOVE b", Aliases: map[string]string{"#a": "a"}})
+	if err != nil {
+		t.Errorf("update with an equivalent expression should not fail; got=%v", err)
+	}
+}
+
+func TestNativeRegistrations(t *testing.T) {
+	native := NewNativeInterpreter()
+
+	// the language interpreter can not parse the reserved word, the expression is keyed by its text
+	mustRegister(t, native.AddMatcher("test", ExpressionTypeFilter, "status = :s", func(m1, m2 map[string]*types.Item) bool { return true }))
+	mustRegister(t, native.AddUpdater("test", "SET status = :s", func(m1, m2 map[string]*types.Item) { m1["status"] = m2[":s"] }))
+
+	matched, err := native.Match(MatchInput{TableName: "test", ExpressionType: ExpressionTypeFilter, Expression: " status  =  :s"})
+	if err != nil || !matched {
+		t.Errorf("the unparsable expression should match, err: %v", err)
+	}
+
+	item := map[string]*types.Item{}
+
+	err = native.Update(UpdateInput{
+		TableName:  "test",
+		Expression: "SET status =\t:s",
+		Item:       item,
+		Attributes: map[string]*types.Item{":s": {S: types.ToString("active")}},
+	})
+	if err != nil || types.StringValue(item["status"].S) != "active" {
+		t.Errorf("the unparsable expression should update, err: %v", err)
+	}
+
+	mustRegister(t, native.AddMatcher("other", ExpressionTypeKey, "a = :a", func(m1, m2 map[string]*types.Item) bool { return true }))
+	mustRegister(t, native.AddMatcher("test", ExpressionTypeFilter, "a = :a", func(m1, m2 map[string]*types.Item) bool { return true }))
+	mustRegister(t, native.AddMatcher("test", ExpressionTypeKey, "a = :a", func(m1, m2 map[string]*types.Item) bool { return true }))
+	mustRegister(t, native.AddUpdater("test", "SET a = :a", func(m1, m2 map[string]*types.Item) {}))
+
+	err = native.AddMatcher("test", ExpressionTypeUpdate, "a = :a", func(m1, m2 map[string]*types.Item) bool { return true })
+	if !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("the update expressions should not have matchers, err: %v", err)
+	}
+}
+
+func TestNativeDuplicateRegistrations(t *testing.T) {
+	native := NewNativeInterpreter()
+
+	mustRegister(t, native.AddMatcher("test", ExpressionTypeKey, "a = :a", func(m1, m2 map[string]*types.Item) bool { return true }))
+	mustRegister(t, native.AddUpdater("test", "SET a = :a", func(m1, m2 map[string]*types.Item) {}))
+	mustRegister(t, native.AddMatcher("test", ExpressionTypeFilter, "status = :s", func(m1, m2 map[string]*types.Item) bool { return true }))
+
+	// the expressions written differently collide when they normalize to the same key
+	duplicates := []func() error{
+		func() error {
+			return native.AddMatcher("test", ExpressionTypeKey, "(a) = :a", func(m1, m2 map[string]*types.Item) bool { return false })
+		},
+		func() error {
+			return native.AddUpdater("test", "SET  a = :a", func(m1, m2 map[string]*types.Item) { m1["a"] = m2[":a"] })
+		},
+		func() error {
+			return native.AddMatcher("test", ExpressionTypeFilter, "status  =\t:s", func(m1, m2 map[string]*types.Item) bool { return false })
+		},
+	}
+
+	for i, register := range duplicates {
+		err := register()
+		if !errors.Is(err, ErrDuplicateRegistration) {
+			t.Errorf("(%d) the duplicate registration should be reported, err: %v", i, err)
+		}
+	}
+
+	err := native.AddMatcher("test", ExpressionTypeKey, "((a)) = :a", func(m1, m2 map[string]*types.Item) bool { return false })
+	expected := `duplicate native expression: "((a)) = :a" replaces the function of an equivalent expression in table "test"`
+
+	if err == nil || err.Error() != expected {
+		t.Errorf("unexpected error. got=%v, want=%q", err, expected)
+	}
+
+	// the last registered functions are used
+	matched, err := native.Match(MatchInput{TableName: "test", ExpressionType: ExpressionTypeKey, Expression: "a = :a"})
+	if err != nil || matched {
+		t.Errorf("the last registered matcher should be used, err: %v", err)
+	}
+
+	matched, err = native.Match(MatchInput{TableName: "test", ExpressionType: ExpressionTypeFilter, Expression: "status = :s"})
+	if err != nil || matched {
+		t.Errorf("the last registered matcher should be used, err: %v", err)
+	}
+
+	item := map[string]*types.Item{}
+
+	err = native.Update(UpdateInput{TableName: "test", Expression: "SET a = :a", Item: item, Attributes: map[string]*types.Item{":a": {S: types.ToString("b")}}})
+	if err != nil || types.StringValue(item["a"].S) != "b" {
+		t.Errorf("the last registered updater should be used, err: %v", err)
+	}
+}
+
+func TestNormalizeExpression(t *testing.T) {
+	aliases := map[string]string{"#a": "a", "#m": "m"}
+
+	conditions := map[string]string{
+		"#a = :a":                          "(a = :a)",
+		"NOT a <> :a":                      "(NOT (a <> :a))",
+		"#m.k[1] < :n":                     "(((m.k)[1]) < :n)",
+		"a BETWEEN :x AND :y":              "(a BETWEEN :x AND :y)",
+		"#a IN (:x, :y)":                   "(a IN (:x, :y))",
+		"size(#m) > :n OR contains(a, :x)": "((size(m) > :n) OR contains(a, :x))",
+	}
+
+	for expression, expected := range conditions {
+		conditional, err := ParseCondition(expression)
+		if err != nil {
+			t.Fatalf("parsing %q failed: %s", expression, err)
+		}
+
+		if actual := normalizeExpression(conditional, aliases); actual != expected {
+			t.Errorf("unexpected normalization of %q. got=%q, want=%q", expression, actual, expected)
+		}
+	}
+
+	updates := map[string]string{
+		"SET #a = :a + :b, b = :b REMOVE #m.k": "SET a (:a + :b) SET b :b REMOVE (m.k)",
+		"ADD a :n DELETE b :s":                 "ADD a :n DELETE b :s",
+	}
+
+	for expression, expected := range updates {
+		update, err := ParseUpdate(expression)
+		if err != nil {
+			t.Fatalf("parsing %q failed: %s", expression, err)
+		}
+
+		if actual := normalizeExpression(update, aliases); actual != expected {
+			t.Errorf("unexpected normalization of %q. got=%q, want=%q", expression, actual, expected)
+		}
+	}
+
+	if normalizeExpression(nil, aliases) != "" {
+		t.Error("the missing expressions should be empty")
+	}
+}
+
+func mustRegister(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected registration error %s", err)
+	}
+}
+
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Helper() {}
+
+func (l *testLogger) Logf(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestNativeTracer(t *testing.T) {
+	logger := &testLogger{}
+
+	native := NewNativeInterpreter()
+	native.Tracer = NewTestTracer(logger)
+	mustRegister(t, native.AddMatcher("test", ExpressionTypeConditional, "a = :a", func(m1, m2 map[string]*types.Item) bool {
+		return true
+	}))
+
+	input := MatchInput{
+		TableName:      "test",
+		Expression:     "a = :a",
+		ExpressionType: ExpressionTypeConditional,
+		Key:            map[string]*types.Item{"a": {S: types.ToString("a")}},
+	}
+
+	_, err := native.Match(input)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	input.Expression = "b = :a"
+
+	_, err = native.Match(input)
+	if !errors.Is(err, ErrUnsupportedFeature) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := []string{
+		`native conditional expression "a = :a" in table "test" with key {"a": {S: "a"}}: matched`,
+		`native conditional expression "b = :a" in table "test" with key {"a": {S: "a"}}: error: unsupported expression or attribute type: matcher "conditional" not found for "b = :a" expression in table "test"`,
+	}
+
+	if strings.Join(logger.lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected traces %q", logger.lines)
+	}
+}
@@ -0,0 +1,41 @@
+package interpreter
+
+import (
+	"github.com/truora/minidyn/interpreter/language"
+)
+
+// Placeholders returns the expression attribute names and values referenced in the expression,
+// parsed reports if they were taken from the expression AST. When the expression has syntax errors
+// the placeholders are taken from the expression tokens instead.
+func Placeholders(expression string, typ ExpressionType) (placeholders *language.Placeholders, parsed bool) {
+	placeholders = language.NewPlaceholders()
+
+	var (
+		node language.Node
+		p    *language.Parser
+	)
+
+	l := language.NewLexer(expression)
+
+	switch typ {
+	case ExpressionTypeUpdate:
+		p = language.NewUpdateParser(l)
+		node = p.ParseUpdateExpression()
+	case ExpressionTypeProjection:
+		p = language.NewProjectionParser(l)
+		node = p.ParseProjectionExpression()
+	default:
+		p = language.NewParser(l)
+		node = p.ParseConditionalExpression()
+	}
+
+	if len(p.Errors()) != 0 {
+		placeholders.CollectTokens(expression)
+
+		return placeholders, false
+	}
+
+	placeholders.Collect(node)
+
+	return placeholders, true
+}
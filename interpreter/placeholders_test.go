@@ -0,0 +1,66 @@
+package interpreter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlaceholders(t *testing.T) {
+	tests := []struct {
+		expression string
+		typ        ExpressionType
+		names      map[string]bool
+		values     map[string]bool
+		parsed     bool
+	}{
+		{
+			expression: "#id = :id AND begins_with(#n, :prefix)",
+			typ:        ExpressionTypeKey,
+			names:      map[string]bool{"#id": true, "#n": true},
+			values:     map[string]bool{":id": true, ":prefix": true},
+			parsed:     true,
+		},
+		{
+			expression: "SET #t = :t REMOVE #old",
+			typ:        ExpressionTypeUpdate,
+			names:      map[string]bool{"#t": true, "#old": true},
+			values:     map[string]bool{":t": true},
+			parsed:     true,
+		},
+		{
+			expression: "#a.b, c",
+			typ:        ExpressionTypeProjection,
+			names:      map[string]bool{"#a": true},
+			values:     map[string]bool{},
+			parsed:     true,
+		},
+		{
+			expression: "#a = = :a",
+			typ:        ExpressionTypeFilter,
+			names:      map[string]bool{"#a": true},
+			values:     map[string]bool{":a": true},
+			parsed:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		ph, parsed := Placeholders(tt.expression, tt.typ)
+		if parsed != tt.parsed {
+			t.Errorf("%q: expected parsed=%v, got=%v", tt.expression, tt.parsed, parsed)
+		}
+
+		if !reflect.DeepEqual(ph.Names, tt.names) {
+			t.Errorf("%q: expected names=%v, got=%v", tt.expression, tt.names, ph.Names)
+		}
+
+		if !reflect.DeepEqual(ph.Values, tt.values) {
+			t.Errorf("%q: expected values=%v, got=%v", tt.expression, tt.values, ph.Values)
+		}
+	}
+}
+
+func TestExpressionTypeParameterName(t *testing.T) {
+	if name := ExpressionTypeFilter.ParameterName(); name != "FilterExpression" {
+		t.Errorf("unexpected parameter name %q", name)
+	}
+}
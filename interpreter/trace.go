@@ -0,0 +1,107 @@
+package interpreter
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// InterpreterNative names the native interpreter in the traces
+	InterpreterNative = "native"
+	// InterpreterLanguage names the language interpreter in the traces
+	InterpreterLanguage = "language"
+)
+
+// Trace describes one evaluation of an expression against an item
+type Trace struct {
+	TableName      string
+	ExpressionType ExpressionType
+	Expression     string
+	// Interpreter is the interpreter that evaluated the expression
+	Interpreter string
+	// Key is the primary key of the evaluated item
+	Key map[string]*types.Item
+	// Matched is the result of the key, filter and condition expressions
+	Matched bool
+	// FailedExpression is the sub-expression that made the match fail
+	FailedExpression string
+	// Err is the evaluation error, the native interpreter reports the expressions without a registered function
+	// before falling back to the language interpreter
+	Err error
+}
+
+func (tr Trace) result() string {
+	switch {
+	case tr.Err != nil:
+		return "error: " + tr.Err.Error()
+	case tr.ExpressionType == ExpressionTypeUpdate:
+		return "updated"
+	case tr.Matched:
+		return "matched"
+	case tr.FailedExpression != "":
+		return "not matched at " + tr.FailedExpression
+	}
+
+	return "not matched"
+}
+
+func (tr Trace) String() string {
+	return fmt.Sprintf(
+		"%s %s expression %q in table %q with key %s: %s",
+		tr.Interpreter,
+		tr.ExpressionType,
+		tr.Expression,
+		tr.TableName,
+		FormatItem(tr.Key),
+		tr.result(),
+	)
+}
+
+// Fields returns the trace as alternating keys and values, the format used by the structured loggers like log/slog
+func (tr Trace) Fields() []interface{} {
+	return []interface{}{
+		"table", tr.TableName,
+		"interpreter", tr.Interpreter,
+		"type", string(tr.ExpressionType),
+		"expression", tr.Expression,
+		"key", FormatItem(tr.Key),
+		"result", tr.result(),
+	}
+}
+
+// Tracer receives the evaluations of the interpreters
+type Tracer interface {
+	Trace(Trace)
+}
+
+// TracerFunc adapts a function to the Tracer interface
+type TracerFunc func(Trace)
+
+// Trace calls the function
+func (f TracerFunc) Trace(tr Trace) {
+	f(tr)
+}
+
+// NewLogTracer returns a tracer that writes one line per evaluation in the logger
+func NewLogTracer(logger *log.Logger) Tracer {
+	return TracerFunc(func(tr Trace) {
+		logger.Println(tr.String())
+	})
+}
+
+// TestLogger is the part of testing.TB used by the test tracer
+type TestLogger interface {
+	Helper()
+	Logf(format string, args ...interface{})
+}
+
+// NewTestTracer returns a tracer that writes the evaluations in the test log,
+// they are only shown when the test fails or runs in verbose mode
+func NewTestTracer(tb TestLogger) Tracer {
+	return TracerFunc(func(tr Trace) {
+		tb.Helper()
+		tb.Logf("%s", tr)
+	})
+}
@@ -0,0 +1,65 @@
+package interpreter
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/truora/minidyn/types"
+)
+
+func TestLogTracer(t *testing.T) {
+	var buf bytes.Buffer
+
+	tracer := NewLogTracer(log.New(&buf, "", 0))
+
+	tracer.Trace(Trace{
+		TableName:      "test",
+		ExpressionType: ExpressionTypeFilter,
+		Expression:     "a = :a",
+		Interpreter:    InterpreterLanguage,
+		Key:            map[string]*types.Item{"a": {S: types.ToString("a")}},
+		Matched:        true,
+	})
+
+	// the traces without a key are written with an empty one
+	tracer.Trace(Trace{
+		TableName:      "test",
+		ExpressionType: ExpressionTypeConditional,
+		Expression:     "b = :b",
+		Interpreter:    InterpreterNative,
+		Err:            errors.New("boom"),
+	})
+
+	expected := `language filter expression "a = :a" in table "test" with key {"a": {S: "a"}}: matched
+native conditional expression "b = :b" in table "test" with key {}: error: boom
+`
+
+	if buf.String() != expected {
+		t.Errorf("unexpected log output %q", buf.String())
+	}
+}
+
+func TestTraceFields(t *testing.T) {
+	tr := Trace{
+		TableName:      "test",
+		ExpressionType: ExpressionTypeKey,
+		Expression:     "a = :a",
+		Interpreter:    InterpreterLanguage,
+	}
+
+	expected := []interface{}{
+		"table", "test",
+		"interpreter", InterpreterLanguage,
+		"type", "key",
+		"expression", "a = :a",
+		"key", "{}",
+		"result", "not matched",
+	}
+
+	if fields := tr.Fields(); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("unexpected fields %v", fields)
+	}
+}
@@ -0,0 +1,106 @@
+package types
+
+// Copy returns a deep copy of the attribute value, nested maps, lists and sets are not shared with the original
+func (i *Item) Copy() *Item {
+	if i == nil {
+		return nil
+	}
+
+	return &Item{
+		B:    CopyBytes(i.B),
+		BOOL: CopyBool(i.BOOL),
+		BS:   CopyBytesSlice(i.BS),
+		L:    CopyList(i.L),
+		M:    CopyItem(i.M),
+		N:    CopyString(i.N),
+		NS:   CopyStringSlice(i.NS),
+		NULL: CopyBool(i.NULL),
+		S:    CopyString(i.S),
+		SS:   CopyStringSlice(i.SS),
+	}
+}
+
+// CopyItem returns a deep copy of the item
+func CopyItem(item map[string]*Item) map[string]*Item {
+	if item == nil {
+		return nil
+	}
+
+	out := make(map[string]*Item, len(item))
+	for key, val := range item {
+		out[key] = val.Copy()
+	}
+
+	return out
+}
+
+// CopyList returns a deep copy of the list of attribute values
+func CopyList(list []*Item) []*Item {
+	if list == nil {
+		return nil
+	}
+
+	out := make([]*Item, len(list))
+	for i, val := range list {
+		out[i] = val.Copy()
+	}
+
+	return out
+}
+
+// CopyString returns a new pointer with the same string value
+func CopyString(str *string) *string {
+	if str == nil {
+		return nil
+	}
+
+	return ToString(*str)
+}
+
+// CopyBool returns a new pointer with the same boolean value
+func CopyBool(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+
+	out := *b
+
+	return &out
+}
+
+// CopyBytes returns a copy of the byte slice
+func CopyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	return append([]byte{}, b...)
+}
+
+// CopyBytesSlice returns a deep copy of the binary set
+func CopyBytesSlice(bs [][]byte) [][]byte {
+	if bs == nil {
+		return nil
+	}
+
+	out := make([][]byte, len(bs))
+	for i, b := range bs {
+		out[i] = CopyBytes(b)
+	}
+
+	return out
+}
+
+// CopyStringSlice returns a deep copy of the string or number set
+func CopyStringSlice(ss []*string) []*string {
+	if ss == nil {
+		return nil
+	}
+
+	out := make([]*string, len(ss))
+	for i, s := range ss {
+		out[i] = CopyString(s)
+	}
+
+	return out
+}
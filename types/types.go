@@ -3,6 +3,7 @@ package types
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awsutil"
 	"github.com/aws/aws-sdk-go/private/protocol"
@@ -93,10 +94,174 @@ type ProvisionedThroughput struct {
 	WriteCapacityUnits int64    `min:"1" type:"long" required:"true"`
 }
 
+// ProvisionedThroughputDescription represents the provisioned throughput settings of a table or index,
+// including the times of the last capacity changes
+type ProvisionedThroughputDescription struct {
+	_                      struct{}   `type:"structure"`
+	LastDecreaseDateTime   *time.Time `type:"timestamp"`
+	LastIncreaseDateTime   *time.Time `type:"timestamp"`
+	NumberOfDecreasesToday int64      `type:"long"`
+	ReadCapacityUnits      int64      `type:"long"`
+	WriteCapacityUnits     int64      `type:"long"`
+}
+
+// BillingModeSummary represents the billing mode of a table
+type BillingModeSummary struct {
+	_                                 struct{}   `type:"structure"`
+	BillingMode                       *string    `type:"string" enum:"BillingMode"`
+	LastUpdateToPayPerRequestDateTime *time.Time `type:"timestamp"`
+}
+
+// StreamSpecification represents the DynamoDB Streams configuration of a table
+type StreamSpecification struct {
+	_              struct{} `type:"structure"`
+	StreamEnabled  *bool    `type:"boolean" required:"true"`
+	StreamViewType *string  `type:"string" enum:"StreamViewType"`
+}
+
+// SSESpecification represents the settings used to enable the server-side encryption of a table
+type SSESpecification struct {
+	_              struct{} `type:"structure"`
+	Enabled        *bool    `type:"boolean"`
+	KMSMasterKeyID *string  `type:"string"`
+	SSEType        *string  `type:"string" enum:"SSEType"`
+}
+
+// SSEDescription represents the server-side encryption status of a table
+type SSEDescription struct {
+	_               struct{} `type:"structure"`
+	KMSMasterKeyArn *string  `type:"string"`
+	SSEType         *string  `type:"string" enum:"SSEType"`
+	Status          *string  `type:"string" enum:"SSEStatus"`
+}
+
+// TableClassSummary represents the table class of a table
+type TableClassSummary struct {
+	_                  struct{}   `type:"structure"`
+	LastUpdateDateTime *time.Time `type:"timestamp"`
+	TableClass         *string    `type:"string" enum:"TableClass"`
+}
+
 // CreateTableInput input to create a table
 type CreateTableInput struct {
-	ProvisionedThroughput *ProvisionedThroughput `type:"structure"`
-	KeySchema             []*KeySchemaElement    `min:"1" type:"list" required:"true"`
+	ProvisionedThroughput     *ProvisionedThroughput `type:"structure"`
+	KeySchema                 []*KeySchemaElement    `min:"1" type:"list" required:"true"`
+	StreamSpecification       *StreamSpecification   `type:"structure"`
+	SSESpecification          *SSESpecification      `type:"structure"`
+	TableClass                *string                `type:"string" enum:"TableClass"`
+	DeletionProtectionEnabled *bool                  `type:"boolean"`
+	Tags                      []*Tag                 `type:"list"`
+}
+
+// Tag is a key and value pair of the tags of a table
+type Tag struct {
+	Key   *string `min:"1" type:"string" required:"true"`
+	Value *string `type:"string" required:"true"`
+}
+
+// UpdateTableInput input to update a table
+type UpdateTableInput struct {
+	AttributeDefinitions        []*AttributeDefinition        `type:"list"`
+	BillingMode                 *string                       `type:"string" enum:"BillingMode"`
+	ProvisionedThroughput       *ProvisionedThroughput        `type:"structure"`
+	GlobalSecondaryIndexUpdates []*GlobalSecondaryIndexUpdate `type:"list"`
+	StreamSpecification         *StreamSpecification          `type:"structure"`
+	SSESpecification            *SSESpecification             `type:"structure"`
+	TableClass                  *string                       `type:"string" enum:"TableClass"`
+	DeletionProtectionEnabled   *bool                         `type:"boolean"`
+}
+
+// RestoreTableFromBackupInput input to restore a table from a backup, the overrides replace
+// the settings and indexes of the backup
+type RestoreTableFromBackupInput struct {
+	BackupArn                     *string                 `type:"string" required:"true"`
+	TargetTableName               *string                 `min:"3" type:"string" required:"true"`
+	BillingModeOverride           *string                 `type:"string" enum:"BillingMode"`
+	GlobalSecondaryIndexOverride  []*GlobalSecondaryIndex `type:"list"`
+	LocalSecondaryIndexOverride   []*LocalSecondaryIndex  `type:"list"`
+	ProvisionedThroughputOverride *ProvisionedThroughput  `type:"structure"`
+	SSESpecificationOverride      *SSESpecification       `type:"structure"`
+}
+
+// RestoreTableToPointInTimeInput input to restore a table to a point in time, the overrides replace
+// the settings and indexes of the source table
+type RestoreTableToPointInTimeInput struct {
+	SourceTableArn                *string                 `type:"string"`
+	SourceTableName               *string                 `min:"3" type:"string"`
+	TargetTableName               *string                 `min:"3" type:"string" required:"true"`
+	RestoreDateTime               *time.Time              `type:"timestamp"`
+	UseLatestRestorableTime       *bool                   `type:"boolean"`
+	BillingModeOverride           *string                 `type:"string" enum:"BillingMode"`
+	GlobalSecondaryIndexOverride  []*GlobalSecondaryIndex `type:"list"`
+	LocalSecondaryIndexOverride   []*LocalSecondaryIndex  `type:"list"`
+	ProvisionedThroughputOverride *ProvisionedThroughput  `type:"structure"`
+	SSESpecificationOverride      *SSESpecification       `type:"structure"`
+}
+
+// ExportTableToPointInTimeInput input to export the items of a table at a point in time to a S3 bucket
+type ExportTableToPointInTimeInput struct {
+	TableArn       *string    `type:"string" required:"true"`
+	S3Bucket       *string    `type:"string" required:"true"`
+	S3BucketOwner  *string    `type:"string"`
+	S3Prefix       *string    `type:"string"`
+	S3SseAlgorithm *string    `type:"string" enum:"S3SseAlgorithm"`
+	S3SseKmsKeyID  *string    `min:"1" type:"string"`
+	ExportFormat   *string    `type:"string" enum:"ExportFormat"`
+	ExportType     *string    `type:"string" enum:"ExportType"`
+	ExportTime     *time.Time `type:"timestamp"`
+	ClientToken    *string    `type:"string"`
+}
+
+// S3BucketSource is the bucket and the key prefix of the files of an import
+type S3BucketSource struct {
+	S3Bucket      *string `type:"string" required:"true"`
+	S3BucketOwner *string `type:"string"`
+	S3KeyPrefix   *string `type:"string"`
+}
+
+// CsvOptions are the delimiter and the header of the CSV files of an import
+type CsvOptions struct {
+	Delimiter  *string   `min:"1" type:"string"`
+	HeaderList []*string `min:"1" type:"list"`
+}
+
+// InputFormatOptions are the options of the format of the files of an import
+type InputFormatOptions struct {
+	Csv *CsvOptions `type:"structure"`
+}
+
+// ImportTableInput input to import the items of a table from the files of a S3 bucket, the table is created
+// with its own parameters
+type ImportTableInput struct {
+	ClientToken          *string             `type:"string"`
+	InputCompressionType *string             `type:"string" enum:"InputCompressionType"`
+	InputFormat          *string             `type:"string" required:"true" enum:"InputFormat"`
+	InputFormatOptions   *InputFormatOptions `type:"structure"`
+	S3BucketSource       *S3BucketSource     `type:"structure" required:"true"`
+}
+
+// PointInTimeRecoveryDescription represents the point in time recovery settings of a table
+type PointInTimeRecoveryDescription struct {
+	_                          struct{}   `type:"structure"`
+	EarliestRestorableDateTime *time.Time `type:"timestamp"`
+	LatestRestorableDateTime   *time.Time `type:"timestamp"`
+	PointInTimeRecoveryStatus  *string    `type:"string" enum:"PointInTimeRecoveryStatus"`
+}
+
+// ContinuousBackupsDescription represents the continuous backups and point in time recovery settings of a table
+type ContinuousBackupsDescription struct {
+	_                              struct{}                        `type:"structure"`
+	ContinuousBackupsStatus        *string                         `type:"string" required:"true" enum:"ContinuousBackupsStatus"`
+	PointInTimeRecoveryDescription *PointInTimeRecoveryDescription `type:"structure"`
+}
+
+// RestoreSummary represents the details of the restore of a table
+type RestoreSummary struct {
+	_                 struct{}  `type:"structure"`
+	RestoreDateTime   time.Time `type:"timestamp" required:"true"`
+	RestoreInProgress bool      `type:"boolean" required:"true"`
+	SourceBackupArn   *string   `min:"37" type:"string"`
+	SourceTableArn    *string   `type:"string"`
 }
 
 // Projection represents attributes that are copied (projected) from the table into an index
@@ -155,15 +320,16 @@ type LocalSecondaryIndex struct {
 
 // GlobalSecondaryIndexDescription represents the properties of a global secondary index.
 type GlobalSecondaryIndexDescription struct {
-	_              struct{}           `type:"structure"`
-	Backfilling    *bool              `type:"boolean"`
-	IndexArn       *string            `type:"string"`
-	IndexName      *string            `min:"3" type:"string"`
-	IndexSizeBytes *int64             `type:"long"`
-	IndexStatus    *string            `type:"string" enum:"IndexStatus"`
-	ItemCount      int64              `type:"long"`
-	KeySchema      []KeySchemaElement `min:"1" type:"list"`
-	Projection     *Projection        `type:"structure"`
+	_                     struct{}                          `type:"structure"`
+	Backfilling           *bool                             `type:"boolean"`
+	IndexArn              *string                           `type:"string"`
+	IndexName             *string                           `min:"3" type:"string"`
+	IndexSizeBytes        *int64                            `type:"long"`
+	IndexStatus           *string                           `type:"string" enum:"IndexStatus"`
+	ItemCount             int64                             `type:"long"`
+	KeySchema             []KeySchemaElement                `min:"1" type:"list"`
+	Projection            *Projection                       `type:"structure"`
+	ProvisionedThroughput *ProvisionedThroughputDescription `type:"structure"`
 }
 
 // LocalSecondaryIndexDescription represents the properties of a local secondary index.
@@ -230,16 +396,17 @@ func (s *ConditionalCheckFailedException) RequestID() string {
 
 // PutItemInput represents the input of a PutItem operation.
 type PutItemInput struct {
-	_                           struct{}          `type:"structure"`
-	ConditionExpression         *string           `type:"string"`
-	ConditionalOperator         *string           `type:"string" enum:"ConditionalOperator"`
-	ExpressionAttributeNames    map[string]string `type:"map"`
-	ExpressionAttributeValues   map[string]*Item  `type:"map"`
-	Item                        map[string]*Item  `type:"map" required:"true"`
-	ReturnConsumedCapacity      *string           `type:"string" enum:"ReturnConsumedCapacity"`
-	ReturnItemCollectionMetrics *string           `type:"string" enum:"ReturnItemCollectionMetrics"`
-	ReturnValues                *string           `type:"string" enum:"ReturnValue"`
-	TableName                   *string           `min:"3" type:"string" required:"true"`
+	_                           struct{}                           `type:"structure"`
+	ConditionExpression         *string                            `type:"string"`
+	ConditionalOperator         *string                            `type:"string" enum:"ConditionalOperator"`
+	Expected                    map[string]*ExpectedAttributeValue `type:"map"`
+	ExpressionAttributeNames    map[string]string                  `type:"map"`
+	ExpressionAttributeValues   map[string]*Item                   `type:"map"`
+	Item                        map[string]*Item                   `type:"map" required:"true"`
+	ReturnConsumedCapacity      *string                            `type:"string" enum:"ReturnConsumedCapacity"`
+	ReturnItemCollectionMetrics *string                            `type:"string" enum:"ReturnItemCollectionMetrics"`
+	ReturnValues                *string                            `type:"string" enum:"ReturnValue"`
+	TableName                   *string                            `min:"3" type:"string" required:"true"`
 }
 
 // UpdateItemInput represents the input of an UpdateItem operation.
@@ -289,24 +456,40 @@ type ExpectedAttributeValue struct {
 type AttributeValueUpdate struct {
 	_      struct{} `type:"structure"`
 	Action *string  `type:"string" enum:"AttributeAction"`
-	Value  Item     `type:"structure"`
+	Value  *Item    `type:"structure"`
+}
+
+// Condition represents the selection criteria of the legacy KeyConditions, QueryFilter and ScanFilter parameters
+type Condition struct {
+	_                  struct{} `type:"structure"`
+	AttributeValueList []*Item  `type:"list"`
+	ComparisonOperator *string  `type:"string" required:"true" enum:"ComparisonOperator"`
 }
 
 // TableDescription represents the properties of a table.
 type TableDescription struct {
-	_                      struct{}                          `type:"structure"`
-	GlobalSecondaryIndexes []GlobalSecondaryIndexDescription `type:"list"`
-	GlobalTableVersion     string                            `type:"string"`
-	ItemCount              int64                             `type:"long"`
-	KeySchema              []KeySchemaElement                `min:"1" type:"list"`
-	LatestStreamArn        string                            `min:"37" type:"string"`
-	LatestStreamLabel      string                            `type:"string"`
-	LocalSecondaryIndexes  []LocalSecondaryIndexDescription  `type:"list"`
-	TableArn               string                            `type:"string"`
-	TableID                string                            `type:"string"`
-	TableName              string                            `min:"3" type:"string"`
-	TableSizeBytes         int64                             `type:"long"`
-	TableStatus            string                            `type:"string" enum:"TableStatus"`
+	_                         struct{}                          `type:"structure"`
+	AttributeDefinitions      []*AttributeDefinition            `type:"list"`
+	BillingModeSummary        *BillingModeSummary               `type:"structure"`
+	CreationDateTime          time.Time                         `type:"timestamp"`
+	DeletionProtectionEnabled bool                              `type:"boolean"`
+	GlobalSecondaryIndexes    []GlobalSecondaryIndexDescription `type:"list"`
+	GlobalTableVersion        string                            `type:"string"`
+	ItemCount                 int64                             `type:"long"`
+	KeySchema                 []KeySchemaElement                `min:"1" type:"list"`
+	LatestStreamArn           string                            `min:"37" type:"string"`
+	LatestStreamLabel         string                            `type:"string"`
+	LocalSecondaryIndexes     []LocalSecondaryIndexDescription  `type:"list"`
+	ProvisionedThroughput     *ProvisionedThroughputDescription `type:"structure"`
+	RestoreSummary            *RestoreSummary                   `type:"structure"`
+	SSEDescription            *SSEDescription                   `type:"structure"`
+	StreamSpecification       *StreamSpecification              `type:"structure"`
+	TableArn                  string                            `type:"string"`
+	TableClassSummary         *TableClassSummary                `type:"structure"`
+	TableID                   string                            `type:"string"`
+	TableName                 string                            `min:"3" type:"string"`
+	TableSizeBytes            int64                             `type:"long"`
+	TableStatus               string                            `type:"string" enum:"TableStatus"`
 }
 
 // ToString returns the pointer of a string
@@ -322,3 +505,8 @@ func StringValue(str *string) string {
 
 	return *str
 }
+
+// ToBool returns the pointer of a bool
+func ToBool(b bool) *bool {
+	return &b
+}